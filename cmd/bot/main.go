@@ -2,21 +2,55 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+
 	"github.com/tuor4eg/ip_accounting_bot/config"
 	"github.com/tuor4eg/ip_accounting_bot/internal/app"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/metrics"
+	apirunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/api_runner"
+	invoicerunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/invoice_runner"
+	metricsrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/metrics_runner"
+	recurringrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/recurring_runner"
 	telegramrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/telegram_runner"
 	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
 	"github.com/tuor4eg/ip_accounting_bot/internal/storage/postgres"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/sqlite"
 	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
 	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
 	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
 )
 
+// botStore is what the bot needs from a storage backend; postgres, sqlite and memstore implement it.
+type botStore interface {
+	app.Store
+	domain.IdentityStore
+	service.IncomeStore
+	service.PaymentStore
+	service.TokenStore
+	service.LinkStore
+	service.RecurringStore
+	service.ChartStore
+	service.ReportStore
+	service.SearchStore
+	service.InvoiceStore
+	service.ActStore
+	service.TaxPaymentStore
+	domain.ChatStore
+	telegramrunner.UpdateStore
+	GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error)
+	Ping(ctx context.Context) error
+}
+
+var errDatabaseURLNotSet = errors.New("DATABASE_URL is not set")
+
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -31,11 +65,11 @@ func main() {
 
 	a := app.New(cfg)
 
-	// Create PostgreSQL storage
-	store, err := postgres.Open(ctx, cfg.DatabaseURL)
+	// Create storage: PostgreSQL, SQLite or file-backed memstore, by the scheme of DATABASE_URL.
+	store, pool, err := openStore(ctx, a, cfg)
 
 	if err != nil {
-		log.Fatalf("app: postgres store error: %v", err)
+		log.Fatalf("app: store error: %v", err)
 	}
 
 	defer func() {
@@ -44,15 +78,15 @@ func main() {
 		}
 	}()
 
-	// Configure crypto keys for PostgreSQL storage
-	if err := store.SetCryptoKeys(cfg.HMACKey, 1, cfg.AEADKey, 1); err != nil {
+	// Configure crypto keys for the storage
+	if err := app.ConfigureCryptoKeys(store, cfg); err != nil {
 		log.Fatalf("app: set crypto keys error: %v", err)
 	}
 
 	// Log crypto keys status
 	if store.HasCryptoKeys() {
-		log.Printf("PostgreSQL store configured with HMAC key ID: %d, AEAD key ID: %d",
-			store.GetHMACKid(), store.GetAEADKid())
+		log.Printf("%s store configured with HMAC key ID: %d, AEAD key ID: %d (known HMAC kids: %v)",
+			cfg.DatabaseBackend, store.GetHMACKid(), store.GetAEADKid(), store.HMACKids())
 	}
 
 	income := service.NewIncomeService(store)
@@ -62,10 +96,34 @@ func main() {
 		income.SumIncomes,
 		payment.SumPayments,
 		tax.NewDefaultProvider())
+	tokens := service.NewTokenService(store, nil)
+	links := service.NewLinkService(store, nil)
+	charts := service.NewChartService(store, tax.NewDefaultProvider())
+	reports := service.NewReportService(store, tax.NewDefaultProvider())
+	search := service.NewSearchService(store)
+	invoices := service.NewInvoiceService(store, nil)
+	acts := service.NewActService(store, nil)
+	taxPayments := service.NewTaxPaymentService(store, total, nil)
+	recurring := service.NewRecurringService(store, nil)
+
+	a.SetStore(store).SetIncomeUsecase(income).SetPaymentUsecase(payment).SetTotalUsecase(total).SetTokenUsecase(tokens).SetLinkUsecase(links).
+		SetRecurringUsecase(recurring).SetChartUsecase(charts).SetReportUsecase(reports).
+		SetSearchUsecase(search).SetInvoiceUsecase(invoices).SetActUsecase(acts).
+		SetTaxPaymentUsecase(taxPayments)
+
+	// Invoice and act PDFs need fonts with Cyrillic; without them both are sent as text.
+	if renderer, err := app.NewDocumentRenderer(cfg); err != nil {
+		log.Printf("invoice and act files disabled: %v", err)
+	} else {
+		a.SetDocumentRenderer(renderer)
+	}
 
-	a.SetStore(store).SetIncomeUsecase(income).SetPaymentUsecase(payment).SetTotalUsecase(total)
+	retry := telegram.DefaultRetryPolicy()
+	retry.MaxAttempts = cfg.TelegramRetryAttempts
 
-	tg := telegram.New(cfg.TelegramToken, nil)
+	tg := telegram.New(cfg.TelegramToken, nil).
+		SetRetryPolicy(retry).
+		SetRateLimiter(telegram.NewRateLimiter(cfg.TelegramSendRate, telegram.DefaultChatInterval))
 
 	botDeps, err := a.BotDeps()
 
@@ -73,9 +131,78 @@ func main() {
 		log.Fatalf("app: bot deps error: %v", err)
 	}
 
-	a.Register(telegramrunner.NewRunner(tg).SetBotDeps(botDeps))
+	tgRunner := telegramrunner.NewRunner(tg).SetBotDeps(botDeps).SetWorkers(cfg.TelegramWorkers).SetUpdateStore(store).SetChatStore(store)
+
+	// Metrics and health probes are optional: enabled only when METRICS_ADDR is set.
+	if cfg.MetricsAddr != "" {
+		m := metrics.New()
+		if pool != nil {
+			m.ObservePool(pool)
+		}
+
+		botDeps.Observer = m
+		tg.SetObserver(m)
+		tgRunner.SetObserver(m)
+
+		a.Register(metricsrunner.NewRunner(cfg.MetricsAddr).
+			SetMetrics(m).
+			AddReadyCheck("db", store.Ping).
+			AddReadyCheck("telegram", m.PollReady(metrics.DefaultPollStaleAfter)))
+	}
+
+	a.Register(tgRunner)
+
+	// Recurring incomes: missed dates are caught up on start, then due rules are checked hourly.
+	a.Register(recurringrunner.NewRunner(recurring).SetNotifier(tgRunner))
+
+	// Overdue invoices: reminded of on start and then at most once a week each.
+	a.Register(invoicerunner.NewRunner(invoices).SetNotifier(tgRunner))
+
+	// REST API is optional: enabled only when API_ADDR is set.
+	if cfg.APIAddr != "" {
+		a.Register(apirunner.NewRunner(cfg.APIAddr).SetBotDeps(botDeps))
+	}
 
 	if err := a.Run(ctx); err != nil {
 		log.Fatalf("app: run error: %v", err)
 	}
 }
+
+// openStore opens the backend selected by DATABASE_URL. The PostgreSQL pool is returned
+// for pool metrics; it is nil for SQLite, which applies its own migrations on open, and
+// for the file-backed memstore, which has no schema.
+func openStore(ctx context.Context, a *app.App, cfg *config.Config) (botStore, *pgxpool.Pool, error) {
+	switch cfg.DatabaseBackend {
+	case config.BackendSQLite:
+		store, err := sqlite.Open(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return store, nil, nil
+
+	case config.BackendMemstore:
+		store, err := memstore.Open(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return store, nil, nil
+
+	case config.BackendPostgres:
+		store, err := postgres.Open(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Refuse to start against an outdated or drifted schema (or migrate it, with MIGRATE_ON_START).
+		if err := a.EnsureSchema(ctx, store.Pool); err != nil {
+			_ = store.Close(ctx)
+			return nil, nil, err
+		}
+
+		return store, store.Pool, nil
+	}
+
+	return nil, nil, errDatabaseURLNotSet
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/config"
+	"github.com/tuor4eg/ip_accounting_bot/internal/app"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	clirunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/cli_runner"
+	recurringrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/recurring_runner"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/postgres"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/sqlite"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+// cliStore is what the CLI needs from a storage backend; memstore, postgres and sqlite implement it.
+type cliStore interface {
+	app.Store
+	domain.IdentityStore
+	service.IncomeStore
+	service.PaymentStore
+	service.TokenStore
+	service.LinkStore
+	service.RecurringStore
+	service.ChartStore
+	service.ReportStore
+	service.SearchStore
+	service.InvoiceStore
+	service.ActStore
+	service.TaxPaymentStore
+	GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error)
+}
+
+// ipbot-cli feeds bot commands from stdin to the same dispatcher the Telegram runner uses.
+// Interactive when stdin is a terminal, script mode otherwise:
+//
+//	ipbot-cli
+//	ipbot-cli < commands.txt
+//
+// Uses an in-memory store unless DATABASE_URL is set (postgres:// or sqlite:path).
+func main() {
+	var (
+		user = flag.String("user", clirunner.DefaultExternalID, "CLI user id (transport \"cli\")")
+		now  = flag.String("now", "", "freeze the current date, YYYY-MM-DD (default: real clock)")
+	)
+	flag.Parse()
+
+	if err := run(*user, *now); err != nil {
+		fmt.Fprintln(os.Stderr, "ipbot-cli:", err)
+		os.Exit(1)
+	}
+}
+
+func run(user, nowFlag string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadCLI()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	// Replies go to stdout, so logs are kept on stderr and quiet by default.
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	clock := time.Now
+	if nowFlag != "" {
+		fixed, err := time.Parse("2006-01-02", nowFlag)
+		if err != nil {
+			return fmt.Errorf("-now: %w", err)
+		}
+		clock = func() time.Time { return fixed }
+	}
+
+	store, err := openStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := store.Close(ctx); err != nil {
+			slog.Warn("failed to close store", "error", err)
+		}
+	}()
+
+	income := service.NewIncomeService(store)
+	payment := service.NewPaymentService(store)
+	total := service.NewTotalService(
+		store.GetUserScheme,
+		income.SumIncomes,
+		payment.SumPayments,
+		tax.NewDefaultProvider())
+	tokens := service.NewTokenService(store, clock)
+	links := service.NewLinkService(store, clock)
+	charts := service.NewChartService(store, tax.NewDefaultProvider())
+	reports := service.NewReportService(store, tax.NewDefaultProvider())
+	search := service.NewSearchService(store)
+	invoices := service.NewInvoiceService(store, clock)
+	acts := service.NewActService(store, clock)
+	taxPayments := service.NewTaxPaymentService(store, total, clock)
+	recurring := service.NewRecurringService(store, clock)
+
+	a := app.New(cfg)
+	a.SetStore(store).SetIncomeUsecase(income).SetPaymentUsecase(payment).SetTotalUsecase(total).SetTokenUsecase(tokens).SetLinkUsecase(links).
+		SetRecurringUsecase(recurring).SetChartUsecase(charts).SetReportUsecase(reports).
+		SetSearchUsecase(search).SetInvoiceUsecase(invoices).SetActUsecase(acts).
+		SetTaxPaymentUsecase(taxPayments)
+
+	// There is no scheduler in the CLI: recurring incomes due by now are added on start.
+	recurringrunner.NewRunner(recurring).RunOnce(ctx)
+
+	botDeps, err := a.BotDeps()
+	if err != nil {
+		return fmt.Errorf("bot deps: %w", err)
+	}
+	botDeps.Now = clock
+
+	a.Register(clirunner.NewRunner(os.Stdin, os.Stdout).
+		SetBotDeps(botDeps).
+		SetExternalID(user).
+		SetLanguage(localeFromEnv()).
+		SetInteractive(isTerminal(os.Stdin)))
+
+	return a.Run(ctx)
+}
+
+// localeFromEnv returns the POSIX locale of messages, e.g. "en_US.UTF-8".
+func localeFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// openStore returns the store selected by DATABASE_URL (postgres, sqlite or file-backed memstore), otherwise a fresh in-memory store.
+func openStore(ctx context.Context, cfg *config.Config) (cliStore, error) {
+	var (
+		store cliStore
+		err   error
+	)
+
+	switch cfg.DatabaseBackend {
+	case config.BackendPostgres:
+		store, err = postgres.Open(ctx, cfg.DatabaseURL)
+	case config.BackendSQLite:
+		store, err = sqlite.Open(ctx, cfg.DatabaseURL)
+	case config.BackendMemstore:
+		store, err = memstore.Open(ctx, cfg.DatabaseURL)
+	default:
+		return memstore.NewStore(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.DatabaseBackend, err)
+	}
+
+	if err := app.ConfigureCryptoKeys(store, cfg); err != nil {
+		_ = store.Close(ctx)
+		return nil, fmt.Errorf("crypto keys: %w", err)
+	}
+
+	return store, nil
+}
+
+// isTerminal reports whether f is a character device (an interactive terminal).
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
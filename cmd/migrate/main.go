@@ -2,19 +2,35 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"text/tabwriter"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/tuor4eg/ip_accounting_bot/config"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/sqlite"
 	"github.com/tuor4eg/ip_accounting_bot/migrations"
 	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
 )
 
+const usage = `usage: migrate [flags] [command]
+
+commands:
+  up [N]     apply N pending migrations (all by default)
+  down [N]   roll back N applied migrations (1 by default)
+  redo       roll back the last applied migration and apply it again
+  status     list migrations and their state
+  verify     fail if an applied migration file has changed or is missing
+
+flags (before or after the command):
+`
+
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
@@ -25,16 +41,47 @@ func main() {
 	logging.InitFromEnv(cfg.LogLevel, cfg.LogFormat)
 
 	// Flags
+	fset := flag.NewFlagSet("migrate", flag.ExitOnError)
 	var (
-		dir     = flag.String("dir", "sql", "migrations directory inside embedded FS")
-		timeout = flag.Duration("timeout", 5*time.Minute, "overall timeout for applying migrations")
+		dir     = fset.String("dir", "sql", "migrations directory inside embedded FS")
+		timeout = fset.Duration("timeout", 5*time.Minute, "overall timeout for applying migrations")
+		dryRun  = fset.Bool("dry-run", false, "print what up, down or redo would do without changing the database")
 	)
-	flag.Parse()
+	fset.Usage = func() {
+		fmt.Fprint(fset.Output(), usage)
+		fset.PrintDefaults()
+	}
+
+	// Flags may come before and after the command: "migrate --dry-run down 2" or "migrate down 2 --dry-run".
+	cmd, args := "up", parseInterspersed(fset, os.Args[1:])
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+
+	steps, err := parseSteps(cmd, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fset.Usage()
+		os.Exit(2)
+	}
 
 	// Context with overall timeout
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
 
+	if cfg.DatabaseBackend == config.BackendMemstore {
+		fmt.Println("OK: memstore keeps no schema, there is nothing to migrate")
+		return
+	}
+
+	if cfg.DatabaseBackend == config.BackendSQLite {
+		if err := runSQLite(ctx, cfg.DatabaseURL, cmd, *dryRun); err != nil {
+			slog.Error("migrate "+cmd+" failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Connect pool
 	cfgDb, err := pgxpool.ParseConfig(cfg.DatabaseURL)
 	if err != nil {
@@ -54,13 +101,153 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Apply missing migrations
-	applied, err := migrations.ApplyUp(ctx, pool, migrations.FS, *dir)
-	if err != nil {
-		slog.Error("apply up migrations failed", "err", err)
+	m := migrations.NewMigrator(pool, migrations.FS, *dir).SetDryRun(*dryRun)
+
+	if err := run(ctx, m, cmd, steps, *dryRun); err != nil {
+		slog.Error("migrate "+cmd+" failed", "err", err, "dir", *dir)
+		pool.Close()
 		os.Exit(1)
 	}
+}
+
+// parseInterspersed parses flags anywhere among the arguments and returns the positional ones.
+func parseInterspersed(fset *flag.FlagSet, args []string) []string {
+	var pos []string
+
+	for {
+		_ = fset.Parse(args)
+		args = fset.Args()
+
+		if len(args) == 0 {
+			return pos
+		}
+
+		pos, args = append(pos, args[0]), args[1:]
+	}
+}
+
+// parseSteps validates the command and its optional step count.
+func parseSteps(cmd string, args []string) (int, error) {
+	switch cmd {
+	case "up", "down":
+	case "redo", "status", "verify":
+		if len(args) > 0 {
+			return 0, fmt.Errorf("%s takes no arguments", cmd)
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unknown command %q", cmd)
+	}
+
+	if len(args) > 1 {
+		return 0, fmt.Errorf("%s takes at most one argument", cmd)
+	}
+
+	if len(args) == 0 {
+		if cmd == "down" {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s: N must be a positive number, got %q", cmd, args[0])
+	}
+
+	return n, nil
+}
+
+func run(ctx context.Context, m *migrations.Migrator, cmd string, steps int, dryRun bool) error {
+	prefix := "OK"
+	if dryRun {
+		prefix = "DRY RUN"
+	}
+
+	switch cmd {
+	case "up":
+		applied, err := m.Up(ctx, steps)
+		printMigrations(prefix+": apply", applied)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: applied %d migration(s)\n", prefix, len(applied))
+
+	case "down":
+		rolled, err := m.Down(ctx, steps)
+		printMigrations(prefix+": roll back", rolled)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: rolled back %d migration(s)\n", prefix, len(rolled))
+
+	case "redo":
+		mg, err := m.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: redone %s\n", prefix, mg.Name)
+
+	case "status":
+		sts, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(sts)
+
+	case "verify":
+		if err := m.Verify(ctx); err != nil {
+			if errors.Is(err, migrations.ErrChecksumMismatch) || errors.Is(err, migrations.ErrMigrationFileMissing) {
+				fmt.Println("FAIL:", err)
+			}
+			return err
+		}
+		fmt.Println("OK: applied migrations match their files")
+	}
+
+	return nil
+}
+
+// runSQLite handles a sqlite: DATABASE_URL. The SQLite store applies its embedded migrations
+// and verifies checksums whenever it is opened, so only up and verify make sense here.
+func runSQLite(ctx context.Context, dsn, cmd string, dryRun bool) error {
+	if dryRun || (cmd != "up" && cmd != "verify") {
+		return fmt.Errorf("%s (dry-run: %v) is not supported for sqlite: the schema is migrated when the store is opened", cmd, dryRun)
+	}
+
+	store, err := sqlite.Open(ctx, dsn)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("OK: sqlite schema is up to date")
+
+	return store.Close(ctx)
+}
+
+func printMigrations(prefix string, ms []migrations.Migration) {
+	for _, mg := range ms {
+		fmt.Printf("%s %s\n", prefix, mg.Name)
+	}
+}
+
+func printStatus(sts []migrations.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tDOWN\tNAME")
+
+	for _, st := range sts {
+		at := "-"
+		if !st.AppliedAt.IsZero() {
+			at = st.AppliedAt.Format(time.DateTime)
+		}
+
+		down := "no"
+		if st.HasDown {
+			down = "yes"
+		}
+
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\t%s\n", st.Version, st.State, at, down, st.Name)
+	}
 
-	slog.Info("migrations applied", "count", applied, "dir", *dir)
-	fmt.Printf("OK: applied %d migration(s)\n", applied)
+	_ = w.Flush()
 }
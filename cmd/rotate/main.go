@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/config"
+	"github.com/tuor4eg/ip_accounting_bot/internal/app"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/postgres"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
+)
+
+// rotate re-encrypts pii.telegram rows to the active AEAD kid (AEAD_KID).
+// Old keys must be listed in AEAD_PREV_KEYS so existing rows can be decrypted.
+// Rows are processed in user_id order; the cursor is printed after every batch
+// and optionally written to -state, so an interrupted run can be resumed.
+// HMAC identities are not rehashed here: they are upgraded lazily on lookup.
+func main() {
+	// Only DATABASE_URL and the keys are needed: no TELEGRAM_TOKEN.
+	cfg, err := config.LoadDB()
+	if err != nil {
+		panic(err)
+	}
+
+	// Logs from env: LOG_LEVEL, LOG_FORMAT
+	logging.InitFromEnv(cfg.LogLevel, cfg.LogFormat)
+
+	// Flags
+	var (
+		batch     = flag.Int("batch", 500, "rows per transaction")
+		after     = flag.Int64("after", 0, "resume after this user_id")
+		stateFile = flag.String("state", "", "file to keep the resume cursor in (optional)")
+		timeout   = flag.Duration("timeout", 30*time.Minute, "overall timeout for the rotation")
+	)
+	flag.Parse()
+
+	if *batch <= 0 {
+		slog.Error("batch must be positive", "batch", *batch)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	store, err := postgres.Open(ctx, cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("failed to open store", "err", err)
+		os.Exit(1)
+	}
+	defer func() { _ = store.Close(context.Background()) }()
+
+	if err := app.ConfigureCryptoKeys(store, cfg); err != nil {
+		slog.Error("failed to configure crypto keys", "err", err)
+		os.Exit(1)
+	}
+
+	cursor := *after
+
+	if *stateFile != "" {
+		saved, err := readCursor(*stateFile)
+		if err != nil {
+			slog.Error("failed to read state file", "file", *stateFile, "err", err)
+			os.Exit(1)
+		}
+
+		cursor = max(cursor, saved)
+	}
+
+	total, err := store.CountPIITelegramToRotate(ctx)
+	if err != nil {
+		slog.Error("failed to count rows", "err", err)
+		os.Exit(1)
+	}
+
+	slog.Info("rotation started", "aead_kid", store.GetAEADKid(), "pending", total, "after", cursor)
+
+	done := int64(0)
+
+	for {
+		n, last, err := store.RotatePIITelegramBatch(ctx, cursor, *batch)
+		if err != nil {
+			slog.Error("rotation batch failed", "after", cursor, "err", err)
+			fmt.Printf("FAILED: resume with -after %d\n", cursor)
+			os.Exit(1)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		done += int64(n)
+		cursor = last
+
+		if *stateFile != "" {
+			if err := writeCursor(*stateFile, cursor); err != nil {
+				slog.Error("failed to write state file", "file", *stateFile, "err", err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("rotated %d/%d (cursor user_id=%d)\n", done, total, cursor)
+	}
+
+	if *stateFile != "" {
+		if err := os.Remove(*stateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("failed to remove state file", "file", *stateFile, "err", err)
+		}
+	}
+
+	slog.Info("rotation finished", "rotated", done, "aead_kid", store.GetAEADKid())
+	fmt.Printf("OK: rotated %d row(s) to AEAD kid %d\n", done, store.GetAEADKid())
+}
+
+// readCursor returns the saved cursor, or 0 if the file does not exist yet.
+func readCursor(path string) (int64, error) {
+	b, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
+
+func writeCursor(path string, cursor int64) error {
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(cursor, 10)+"\n"), 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
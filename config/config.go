@@ -1,15 +1,98 @@
 package config
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
+const (
+	defaultTelegramWorkers = 4
+	maxTelegramWorkers     = 256
+
+	defaultTelegramRetryAttempts = 3
+	maxTelegramRetryAttempts     = 10
+	defaultTelegramSendRate      = 30
+	maxTelegramSendRate          = 1000
+
+	// DejaVu ships with most Linux distributions (fonts-dejavu-core) and covers Cyrillic.
+	defaultPDFFont     = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+	defaultPDFFontBold = "/usr/share/fonts/truetype/dejavu/DejaVuSans-Bold.ttf"
+)
+
+// Load reads the bot configuration from the environment (and .env if present).
 func Load() (*Config, error) {
 	const op = "config.Load"
 
+	c, err := load()
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if c.TelegramToken == "" {
+		return nil, validate.Wrap(op, ErrTelegramTokenNotSet)
+	}
+	if c.HMACKey == "" {
+		return nil, validate.Wrap(op, ErrHMACKeyNotSet)
+	}
+	if c.AEADKey == "" {
+		return nil, validate.Wrap(op, ErrAEADKeyNotSet)
+	}
+
+	return c, nil
+}
+
+// LoadCLI reads the configuration for the local CLI. The Telegram token is not needed,
+// and crypto keys are required only when DATABASE_URL selects a database (postgres or sqlite).
+func LoadCLI() (*Config, error) {
+	const op = "config.LoadCLI"
+
+	c, err := load()
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if c.DatabaseURL != "" {
+		if c.HMACKey == "" {
+			return nil, validate.Wrap(op, ErrHMACKeyNotSet)
+		}
+		if c.AEADKey == "" {
+			return nil, validate.Wrap(op, ErrAEADKeyNotSet)
+		}
+	}
+
+	return c, nil
+}
+
+// LoadDB reads the configuration for tools that work on the database only (key rotation):
+// DATABASE_URL and the crypto keys are required, the Telegram token is not.
+func LoadDB() (*Config, error) {
+	const op = "config.LoadDB"
+
+	c, err := load()
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if c.DatabaseURL == "" {
+		return nil, validate.Wrap(op, ErrDatabaseURLNotSet)
+	}
+	if c.HMACKey == "" {
+		return nil, validate.Wrap(op, ErrHMACKeyNotSet)
+	}
+	if c.AEADKey == "" {
+		return nil, validate.Wrap(op, ErrAEADKeyNotSet)
+	}
+
+	return c, nil
+}
+
+// load reads and parses all variables without checking which of them are required.
+func load() (*Config, error) {
+	const op = "config.load"
+
 	_ = godotenv.Load()
 
 	// Get log level from environment, default to "info" if not set
@@ -29,18 +112,56 @@ func Load() (*Config, error) {
 		LogLevel:      logLevel,
 		LogFormat:     logFormat,
 		DatabaseURL:   os.Getenv("DATABASE_URL"),
+		APIAddr:       os.Getenv("API_ADDR"),
+		MetricsAddr:   os.Getenv("METRICS_ADDR"),
 		HMACKey:       os.Getenv("HMAC_KEY"),
 		AEADKey:       os.Getenv("AEAD_KEY"),
+		PDFFont:       os.Getenv("PDF_FONT"),
+		PDFFontBold:   os.Getenv("PDF_FONT_BOLD"),
 	}
 
-	if c.TelegramToken == "" {
-		return nil, validate.Wrap(op, ErrTelegramTokenNotSet)
+	if c.PDFFont == "" {
+		c.PDFFont = defaultPDFFont
 	}
-	if c.HMACKey == "" {
-		return nil, validate.Wrap(op, ErrHMACKeyNotSet)
+
+	if c.PDFFontBold == "" {
+		c.PDFFontBold = defaultPDFFontBold
 	}
-	if c.AEADKey == "" {
-		return nil, validate.Wrap(op, ErrAEADKeyNotSet)
+
+	var err error
+
+	if c.HMACKid, err = parseKid(os.Getenv("HMAC_KID")); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	if c.AEADKid, err = parseKid(os.Getenv("AEAD_KID")); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	if c.HMACPrevKeys, err = parseKeyList(os.Getenv("HMAC_PREV_KEYS")); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	if c.AEADPrevKeys, err = parseKeyList(os.Getenv("AEAD_PREV_KEYS")); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	if c.TelegramWorkers, err = parsePositiveInt(os.Getenv("TELEGRAM_WORKERS"), defaultTelegramWorkers, maxTelegramWorkers); err != nil {
+		return nil, validate.Wrap(op, fmt.Errorf("TELEGRAM_WORKERS: %w", err))
+	}
+	if c.TelegramRetryAttempts, err = parsePositiveInt(os.Getenv("TELEGRAM_RETRY_ATTEMPTS"), defaultTelegramRetryAttempts, maxTelegramRetryAttempts); err != nil {
+		return nil, validate.Wrap(op, fmt.Errorf("TELEGRAM_RETRY_ATTEMPTS: %w", err))
+	}
+	if c.TelegramSendRate, err = parsePositiveInt(os.Getenv("TELEGRAM_SEND_RATE"), defaultTelegramSendRate, maxTelegramSendRate); err != nil {
+		return nil, validate.Wrap(op, fmt.Errorf("TELEGRAM_SEND_RATE: %w", err))
+	}
+	if c.DatabaseBackend, err = parseDatabaseBackend(c.DatabaseURL); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	if c.MigrateOnStart, err = parseBool(os.Getenv("MIGRATE_ON_START")); err != nil {
+		return nil, validate.Wrap(op, fmt.Errorf("MIGRATE_ON_START: %w", err))
+	}
+	if _, dup := c.HMACPrevKeys[c.HMACKid]; dup {
+		return nil, validate.Wrap(op, ErrDuplicateKid)
+	}
+	if _, dup := c.AEADPrevKeys[c.AEADKid]; dup {
+		return nil, validate.Wrap(op, ErrDuplicateKid)
 	}
 
 	return c, nil
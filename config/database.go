@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// Storage backends selected by the scheme of DATABASE_URL.
+const (
+	BackendPostgres = "postgres" // postgres:// or postgresql://
+	BackendSQLite   = "sqlite"   // sqlite:path/to/file.db
+	BackendMemstore = "memstore" // memstore:path/to/dir, in memory with a journal on disk
+)
+
+// parseDatabaseBackend returns the backend of a DATABASE_URL; empty URL means none.
+func parseDatabaseBackend(url string) (string, error) {
+	const op = "config.parseDatabaseBackend"
+
+	url = strings.TrimSpace(url)
+
+	if url == "" {
+		return "", nil
+	}
+
+	scheme, _, ok := strings.Cut(url, ":")
+	if !ok {
+		return "", validate.Wrap(op, ErrUnsupportedDatabaseURL)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "postgres", "postgresql":
+		return BackendPostgres, nil
+	case "sqlite":
+		return BackendSQLite, nil
+	case "memstore":
+		return BackendMemstore, nil
+	}
+
+	return "", validate.Wrap(op, ErrUnsupportedDatabaseURL)
+}
@@ -3,7 +3,14 @@ package config
 import "errors"
 
 var (
-	ErrTelegramTokenNotSet = errors.New("TELEGRAM_TOKEN is not set")
-	ErrHMACKeyNotSet       = errors.New("HMAC_KEY is not set")
-	ErrAEADKeyNotSet       = errors.New("AEAD_KEY is not set")
+	ErrTelegramTokenNotSet    = errors.New("TELEGRAM_TOKEN is not set")
+	ErrDatabaseURLNotSet      = errors.New("DATABASE_URL is not set")
+	ErrHMACKeyNotSet          = errors.New("HMAC_KEY is not set")
+	ErrAEADKeyNotSet          = errors.New("AEAD_KEY is not set")
+	ErrInvalidKid             = errors.New("key id must be a positive integer")
+	ErrInvalidKeyList         = errors.New("key list must look like kid:key,kid:key")
+	ErrDuplicateKid           = errors.New("previous key list repeats the active kid")
+	ErrInvalidPositiveInt     = errors.New("value must be a positive integer within limits")
+	ErrInvalidBool            = errors.New("value must be true or false")
+	ErrUnsupportedDatabaseURL = errors.New("DATABASE_URL must start with postgres://, postgresql://, sqlite: or memstore:")
 )
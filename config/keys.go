@@ -0,0 +1,60 @@
+package config
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// parseKid parses a key id; empty means the default kid 1.
+func parseKid(s string) (int16, error) {
+	const op = "config.parseKid"
+
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		return 1, nil
+	}
+
+	v, err := strconv.Atoi(s)
+
+	if err != nil || v <= 0 || v > math.MaxInt16 {
+		return 0, validate.Wrap(op, ErrInvalidKid)
+	}
+
+	return int16(v), nil
+}
+
+// parseKeyList parses "kid:key,kid:key" into a map. Empty input yields an empty map.
+// Keys must not contain commas.
+func parseKeyList(s string) (map[int16]string, error) {
+	const op = "config.parseKeyList"
+
+	out := make(map[int16]string)
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+
+		if item == "" {
+			continue
+		}
+
+		kidStr, key, ok := strings.Cut(item, ":")
+
+		if !ok || key == "" {
+			return nil, validate.Wrap(op, ErrInvalidKeyList)
+		}
+
+		kid, err := parseKid(kidStr)
+
+		if err != nil || strings.TrimSpace(kidStr) == "" {
+			return nil, validate.Wrap(op, ErrInvalidKeyList)
+		}
+
+		out[kid] = key
+	}
+
+	return out, nil
+}
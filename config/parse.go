@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// parsePositiveInt parses a positive integer not above max; empty means def.
+func parsePositiveInt(s string, def, max int) (int, error) {
+	const op = "config.parsePositiveInt"
+
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		return def, nil
+	}
+
+	v, err := strconv.Atoi(s)
+
+	if err != nil || v <= 0 || v > max {
+		return 0, validate.Wrap(op, ErrInvalidPositiveInt)
+	}
+
+	return v, nil
+}
+
+// parseBool parses true/false, 1/0, yes/no or on/off; empty means false.
+func parseBool(s string) (bool, error) {
+	const op = "config.parseBool"
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "0", "false", "no", "off":
+		return false, nil
+	case "1", "true", "yes", "on":
+		return true, nil
+	}
+
+	return false, validate.Wrap(op, ErrInvalidBool)
+}
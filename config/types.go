@@ -6,6 +6,28 @@ type Config struct {
 	LogLevel      string `env:"LOG_LEVEL"`
 	LogFormat     string `env:"LOG_FORMAT"`
 	DatabaseURL   string `env:"DATABASE_URL"`
-	HMACKey       string `env:"HMAC_KEY"`
-	AEADKey       string `env:"AEAD_KEY"`
+	// DatabaseBackend is BackendPostgres or BackendSQLite, from the scheme of DatabaseURL; empty if it is unset.
+	DatabaseBackend string
+	HMACKey         string `env:"HMAC_KEY"`
+	HMACKid         int16  `env:"HMAC_KID"`
+	AEADKey         string `env:"AEAD_KEY"`
+	AEADKid         int16  `env:"AEAD_KID"`
+	// Previous key versions kept for reading rows written before a rotation (kid -> key).
+	HMACPrevKeys map[int16]string `env:"HMAC_PREV_KEYS"`
+	AEADPrevKeys map[int16]string `env:"AEAD_PREV_KEYS"`
+	// APIAddr is the listen address of the REST API (e.g. ":8080"); empty disables it.
+	APIAddr string `env:"API_ADDR"`
+	// MetricsAddr is the listen address of /metrics, /healthz and /readyz (e.g. ":9090"); empty disables it.
+	MetricsAddr string `env:"METRICS_ADDR"`
+	// TelegramWorkers is how many updates are processed in parallel (per-chat order is kept).
+	TelegramWorkers int `env:"TELEGRAM_WORKERS"`
+	// TelegramRetryAttempts is how many times a failed Bot API request is tried (1 disables retries).
+	TelegramRetryAttempts int `env:"TELEGRAM_RETRY_ATTEMPTS"`
+	// TelegramSendRate is the global limit of outgoing messages per second.
+	TelegramSendRate int `env:"TELEGRAM_SEND_RATE"`
+	// PDFFont and PDFFontBold are the TrueType fonts invoices are set in; they need Cyrillic.
+	PDFFont     string `env:"PDF_FONT"`
+	PDFFontBold string `env:"PDF_FONT_BOLD"`
+	// MigrateOnStart applies pending migrations on start instead of refusing to start.
+	MigrateOnStart bool `env:"MIGRATE_ON_START"`
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/tuor4eg/ip_accounting_bot/config"
 	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
 )
@@ -49,7 +50,26 @@ func (a *App) BotDeps() (*bot.BotDeps, error) {
 		return nil, validate.Wrap(op, ErrStoreDoesNotImplementIdentityStore)
 	}
 
-	return bot.NewBotDeps(ids, a.income, a.payment, a.total, time.Now), nil
+	// Optional: stores that link messages to entries enable editing entries by editing messages.
+	msgs, _ := a.store.(domain.MessageEntryStore)
+	// Optional: stores that keep a per-user language enable /lang.
+	langs, _ := a.store.(domain.LanguageStore)
+
+	deps := bot.NewBotDeps(ids, a.income, a.payment, a.total, a.tokens, a.links, msgs, langs, time.Now)
+	deps.Recurring = a.recurring
+	deps.Chart = a.chart
+	deps.Report = a.report
+	deps.Search = a.search
+	deps.Invoices = a.invoices
+	deps.Acts = a.acts
+	deps.TaxPayments = a.taxPayments
+	deps.Documents = a.documents
+	// Optional: total usecases that project the year enable /forecast.
+	deps.Forecast, _ = a.total.(domain.ForecastUsecase)
+	// Optional: total usecases that know the limits of the scheme enable /limits and /add warnings.
+	deps.Limits, _ = a.total.(domain.LimitsUsecase)
+
+	return deps, nil
 }
 
 func (a *App) Run(ctx context.Context) error {
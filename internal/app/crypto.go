@@ -0,0 +1,30 @@
+package app
+
+import (
+	"github.com/tuor4eg/ip_accounting_bot/config"
+	"github.com/tuor4eg/ip_accounting_bot/internal/cryptostore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// ConfigureCryptoKeys loads the active key pair and all previous key versions from cfg into cs.
+func ConfigureCryptoKeys(cs cryptostore.CryptoStore, cfg *config.Config) error {
+	const op = "app.ConfigureCryptoKeys"
+
+	for kid, key := range cfg.HMACPrevKeys {
+		if err := cs.AddHMACKey(kid, key); err != nil {
+			return validate.Wrap(op, err)
+		}
+	}
+
+	for kid, key := range cfg.AEADPrevKeys {
+		if err := cs.AddAEADKey(kid, key); err != nil {
+			return validate.Wrap(op, err)
+		}
+	}
+
+	if err := cs.SetCryptoKeys(cfg.HMACKey, cfg.HMACKid, cfg.AEADKey, cfg.AEADKid); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"github.com/tuor4eg/ip_accounting_bot/config"
+	"github.com/tuor4eg/ip_accounting_bot/internal/document"
+	"github.com/tuor4eg/ip_accounting_bot/internal/pdf"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// NewDocumentRenderer loads the fonts of PDF_FONT and PDF_FONT_BOLD for invoices and acts.
+func NewDocumentRenderer(cfg *config.Config) (*document.Renderer, error) {
+	const op = "app.NewDocumentRenderer"
+
+	regular, err := pdf.LoadFont(cfg.PDFFont)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	bold, err := pdf.LoadFont(cfg.PDFFontBold)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return document.NewRenderer(regular, bold), nil
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/migrations"
+)
+
+const (
+	codeMigrationsUpToDate    = "migrations_up_to_date"
+	codeMigrationsApplied     = "migrations_applied"
+	codeMigrationsPending     = "migrations_pending"
+	codeMigrationsDrift       = "migrations_drift"
+	codeMigrationsCheckFailed = "migrations_check_failed"
+)
+
+// migrationsDir is the directory of the embedded migrations, as in cmd/migrate.
+const migrationsDir = "sql"
+
+// EnsureSchema refuses to start against a database whose schema is behind the embedded
+// migrations or differs from them. With MIGRATE_ON_START pending migrations are applied
+// instead, under the same advisory lock as cmd/migrate; a changed or missing applied
+// migration always fails.
+func (a *App) EnsureSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	const op = "app.EnsureSchema"
+
+	m := migrations.NewMigrator(pool, migrations.FS, migrationsDir)
+
+	if a.cfg.MigrateOnStart {
+		applied, err := m.Up(ctx, 0)
+
+		if err != nil {
+			a.logMigrationError(err)
+
+			return validate.Wrap(op, err)
+		}
+
+		if len(applied) > 0 {
+			a.log.Info("migrations applied on start", "code", codeMigrationsApplied,
+				"count", len(applied), "last", applied[len(applied)-1].Name)
+
+			return nil
+		}
+
+		a.log.Info("database schema is up to date", "code", codeMigrationsUpToDate)
+
+		return nil
+	}
+
+	pending, err := m.Check(ctx)
+
+	if err != nil {
+		if len(pending) > 0 {
+			names := make([]string, 0, len(pending))
+			for _, mg := range pending {
+				names = append(names, mg.Name)
+			}
+
+			a.log.Error("database has pending migrations: run migrate or set MIGRATE_ON_START=true",
+				"code", codeMigrationsPending, "pending", names)
+		} else {
+			a.logMigrationError(err)
+		}
+
+		return validate.Wrap(op, err)
+	}
+
+	a.log.Info("database schema is up to date", "code", codeMigrationsUpToDate)
+
+	return nil
+}
+
+// logMigrationError logs a failed check or auto-apply with a code telling drift from other failures.
+func (a *App) logMigrationError(err error) {
+	if errors.Is(err, migrations.ErrChecksumMismatch) || errors.Is(err, migrations.ErrMigrationFileMissing) {
+		a.log.Error("applied migrations differ from the embedded ones", "code", codeMigrationsDrift, "error", err)
+
+		return
+	}
+
+	a.log.Error("migrations check failed", "code", codeMigrationsCheckFailed, "error", err)
+}
@@ -21,3 +21,73 @@ func (a *App) SetTotalUsecase(u domain.TotalUsecase) *App {
 	a.total = u
 	return a
 }
+
+// SetTokenUsecase injects domain API token usecase into the App and returns the App for chaining.
+// Optional: without it /token replies that the API is disabled.
+func (a *App) SetTokenUsecase(u domain.TokenUsecase) *App {
+	a.tokens = u
+	return a
+}
+
+// SetLinkUsecase injects domain link usecase into the App and returns the App for chaining.
+// Optional: without it /link and /unlink reply that linking is disabled.
+func (a *App) SetLinkUsecase(u domain.LinkUsecase) *App {
+	a.links = u
+	return a
+}
+
+// SetRecurringUsecase injects domain recurring income usecase into the App and returns the App for chaining.
+// Optional: without it /recurring replies that recurring incomes are disabled.
+func (a *App) SetRecurringUsecase(u domain.RecurringUsecase) *App {
+	a.recurring = u
+	return a
+}
+
+// SetChartUsecase injects domain chart usecase into the App and returns the App for chaining.
+// Optional: without it /chart replies that charts are disabled.
+func (a *App) SetChartUsecase(u domain.ChartUsecase) *App {
+	a.chart = u
+	return a
+}
+
+// SetReportUsecase injects domain report usecase into the App and returns the App for chaining.
+// Optional: without it /report replies that reports are disabled.
+func (a *App) SetReportUsecase(u domain.ReportUsecase) *App {
+	a.report = u
+	return a
+}
+
+// SetSearchUsecase injects domain search usecase into the App and returns the App for chaining.
+// Optional: without it /find replies that search is disabled.
+func (a *App) SetSearchUsecase(u domain.SearchUsecase) *App {
+	a.search = u
+	return a
+}
+
+// SetInvoiceUsecase injects domain invoice usecase into the App and returns the App for chaining.
+// Optional: without it /client, /requisites and /invoice reply that invoicing is disabled.
+func (a *App) SetInvoiceUsecase(u domain.InvoiceUsecase) *App {
+	a.invoices = u
+	return a
+}
+
+// SetActUsecase injects domain act usecase into the App and returns the App for chaining.
+// Optional: without it /act and /acts reply that acts are disabled.
+func (a *App) SetActUsecase(u domain.ActUsecase) *App {
+	a.acts = u
+	return a
+}
+
+// SetTaxPaymentUsecase injects domain tax payment usecase into the App and returns the App for chaining.
+// Optional: without it /pay replies that payment orders are disabled.
+func (a *App) SetTaxPaymentUsecase(u domain.TaxPaymentUsecase) *App {
+	a.taxPayments = u
+	return a
+}
+
+// SetDocumentRenderer injects the renderer of invoice and act files into the App and returns the App for chaining.
+// Optional: without it invoices and acts are replied with as text only.
+func (a *App) SetDocumentRenderer(r domain.DocumentRenderer) *App {
+	a.documents = r
+	return a
+}
@@ -9,11 +9,21 @@ import (
 
 // App is the main application that manages all components
 type App struct {
-	cfg     *config.Config
-	runners []Runner
-	log     *slog.Logger
-	store   Store
-	income  domain.IncomeUsecase
-	payment domain.PaymentUsecase
-	total   domain.TotalUsecase
+	cfg         *config.Config
+	runners     []Runner
+	log         *slog.Logger
+	store       Store
+	income      domain.IncomeUsecase
+	payment     domain.PaymentUsecase
+	total       domain.TotalUsecase
+	tokens      domain.TokenUsecase
+	links       domain.LinkUsecase
+	recurring   domain.RecurringUsecase
+	chart       domain.ChartUsecase
+	report      domain.ReportUsecase
+	search      domain.SearchUsecase
+	invoices    domain.InvoiceUsecase
+	acts        domain.ActUsecase
+	taxPayments domain.TaxPaymentUsecase
+	documents   domain.DocumentRenderer
 }
@@ -9,7 +9,7 @@ import (
 
 // NewBotDeps wires dependencies for the bot.
 // If now is nil, time.Now will be used.
-func NewBotDeps(identities domain.IdentityStore, income domain.IncomeUsecase, payment domain.PaymentUsecase, total domain.TotalUsecase, now func() time.Time) *BotDeps {
+func NewBotDeps(identities domain.IdentityStore, income domain.IncomeUsecase, payment domain.PaymentUsecase, total domain.TotalUsecase, tokens domain.TokenUsecase, links domain.LinkUsecase, messages domain.MessageEntryStore, langs domain.LanguageStore, now func() time.Time) *BotDeps {
 	if now == nil {
 		now = time.Now
 	}
@@ -18,6 +18,10 @@ func NewBotDeps(identities domain.IdentityStore, income domain.IncomeUsecase, pa
 		Income:     income,
 		Payment:    payment,
 		Total:      total,
+		Tokens:     tokens,
+		Links:      links,
+		Messages:   messages,
+		Langs:      langs,
 		Now:        now,
 	}
 }
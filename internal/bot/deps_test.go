@@ -0,0 +1,105 @@
+package bot_test
+
+import (
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+// depsOption replaces or adds a dependency of the BotDeps built by newTestDeps.
+type depsOption func(deps *bot.BotDeps, store *memstore.Store)
+
+// newTestDeps returns BotDeps over a fresh memstore: identities and incomes are real,
+// payments and totals are mocks and the clock is fixedNow. Options wire in the rest.
+func newTestDeps(opts ...depsOption) (*bot.BotDeps, *memstore.Store) {
+	store := memstore.NewStore()
+
+	deps := &bot.BotDeps{
+		Identities: store,
+		Income:     service.NewIncomeService(store),
+		Payment:    &mockPaymentService{},
+		Total:      &mockTotalService{},
+		Now:        fixedNow,
+	}
+
+	for _, opt := range opts {
+		opt(deps, store)
+	}
+
+	return deps, store
+}
+
+// clockOf reads deps.Now on every call, so services see the clock whatever the option order.
+func clockOf(deps *bot.BotDeps) func() time.Time {
+	return func() time.Time { return deps.Now() }
+}
+
+// withClock makes *now the current time; a test moves the clock by assigning to it.
+func withClock(now *time.Time) depsOption {
+	return func(deps *bot.BotDeps, _ *memstore.Store) {
+		deps.Now = func() time.Time { return *now }
+	}
+}
+
+// withNow fixes the current time at now.
+func withNow(now time.Time) depsOption {
+	return withClock(&now)
+}
+
+// withPayments replaces the payment mock with the real service.
+func withPayments() depsOption {
+	return func(deps *bot.BotDeps, store *memstore.Store) {
+		deps.Payment = service.NewPaymentService(store)
+	}
+}
+
+// withTaxTotals wires real payments and the tax totals service behind totals, forecast and limits.
+func withTaxTotals() depsOption {
+	return func(deps *bot.BotDeps, store *memstore.Store) {
+		income := service.NewIncomeService(store)
+		payment := service.NewPaymentService(store)
+		total := service.NewTotalService(store.GetUserScheme, income.SumIncomes, payment.SumPayments, tax.NewDefaultProvider())
+
+		deps.Income = income
+		deps.Payment = payment
+		deps.Total = total
+		deps.Forecast = total
+		deps.Limits = total
+	}
+}
+
+func withMessages() depsOption {
+	return func(deps *bot.BotDeps, store *memstore.Store) {
+		deps.Messages = store
+	}
+}
+
+func withLangs() depsOption {
+	return func(deps *bot.BotDeps, store *memstore.Store) {
+		deps.Langs = store
+	}
+}
+
+func withLinks() depsOption {
+	return func(deps *bot.BotDeps, store *memstore.Store) {
+		deps.Links = service.NewLinkService(store, clockOf(deps))
+	}
+}
+
+// withRecurring wires the recurring service; tests reach RunDue via deps.Recurring.(*service.RecurringService).
+func withRecurring() depsOption {
+	return func(deps *bot.BotDeps, store *memstore.Store) {
+		deps.Recurring = service.NewRecurringService(store, clockOf(deps))
+	}
+}
+
+// withInvoices wires the invoice service and a stub document renderer.
+func withInvoices() depsOption {
+	return func(deps *bot.BotDeps, store *memstore.Store) {
+		deps.Invoices = service.NewInvoiceService(store, clockOf(deps))
+		deps.Documents = stubRenderer{}
+	}
+}
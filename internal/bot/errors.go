@@ -6,6 +6,7 @@ import "errors"
 var (
 	ErrBadInput                  = errors.New("bad input")
 	ErrAmountIsZero              = errors.New("amount is zero")
+	ErrFutureDate                = errors.New("date is in the future")
 	ErrUnknownCommand            = errors.New("unknown command")
 	ErrServiceDoesNotSupportUndo = errors.New("service does not support undo")
 )
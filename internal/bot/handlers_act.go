@@ -0,0 +1,231 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleAct issues acts of completed work and sends them again:
+//
+//	/act invoice <invoice id>                          — an act for an invoice
+//	/act income <income id> <client id> [description]  — an act for an income
+//	/act <id>                                          — show an act (and send the files again)
+//
+// Transports that can send files get the act as a PDF and as a DOCX to edit. An invoice
+// or an income has one act; asking for another one sends the issued act again.
+func HandleAct(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleAct"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Acts == nil || deps.Invoices == nil {
+		return ActDisabledText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	sub, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	sub = strings.ToLower(sub)
+	rest = strings.TrimSpace(rest)
+
+	var (
+		act  domain.Act
+		same func(domain.Act) bool // matches the act issued before for the same entry
+	)
+
+	switch sub {
+	case "invoice":
+		invoiceID, parseErr := strconv.ParseInt(rest, 10, 64)
+		if parseErr != nil || invoiceID <= 0 {
+			return ActUsageText(lang), nil
+		}
+
+		act, err = deps.Acts.ActForInvoice(ctx, userID, invoiceID)
+		same = func(a domain.Act) bool { return a.InvoiceID == invoiceID }
+
+		if errors.Is(err, domain.ErrEntryNotFound) {
+			return InvoiceNotFoundText(lang, invoiceID), nil
+		}
+
+	case "income":
+		incomeArg, rest, _ := strings.Cut(rest, " ")
+		clientArg, desc, _ := strings.Cut(strings.TrimSpace(rest), " ")
+
+		incomeID, err1 := strconv.ParseInt(incomeArg, 10, 64)
+		clientID, err2 := strconv.ParseInt(clientArg, 10, 64)
+		if err1 != nil || err2 != nil || incomeID <= 0 || clientID <= 0 {
+			return ActUsageText(lang), nil
+		}
+
+		// Tell a missing client from a missing income.
+		_, err = deps.Invoices.GetClient(ctx, userID, clientID)
+
+		switch {
+		case errors.Is(err, domain.ErrEntryNotFound):
+			return ClientNotFoundText(lang, clientID), nil
+		case err != nil:
+			return "", validate.Wrap(op, err)
+		}
+
+		act, err = deps.Acts.ActForIncome(ctx, userID, incomeID, clientID, desc)
+		same = func(a domain.Act) bool { return a.IncomeID == incomeID }
+
+		if errors.Is(err, domain.ErrEntryNotFound) {
+			return ActIncomeNotFoundText(lang, incomeID), nil
+		}
+
+	default:
+		actID, parseErr := strconv.ParseInt(sub, 10, 64)
+		if parseErr != nil || actID <= 0 || rest != "" {
+			return ActUsageText(lang), nil
+		}
+
+		act, err = deps.Acts.GetAct(ctx, userID, actID)
+
+		switch {
+		case errors.Is(err, domain.ErrEntryNotFound):
+			return ActNotFoundText(lang, actID), nil
+		case err != nil:
+			return "", validate.Wrap(op, err)
+		}
+
+		if err := attachActFiles(ctx, deps, userID, act); err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		return ActText(lang, act), nil
+	}
+
+	exists := errors.Is(err, domain.ErrActExists)
+
+	switch {
+	case errors.Is(err, domain.ErrRequisitesMissing):
+		return InvoiceNoRequisitesText(lang), nil
+	case exists:
+		if act, err = findAct(ctx, deps, userID, same); err != nil {
+			return "", validate.Wrap(op, err)
+		}
+	case err != nil:
+		return "", validate.Wrap(op, err)
+	}
+
+	if err := attachActFiles(ctx, deps, userID, act); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	if exists {
+		return ActExistsText(lang, act), nil
+	}
+
+	return ActIssuedText(lang, act), nil
+}
+
+// HandleActs lists the registry of acts of a year, the current one by default:
+// /acts [year|all].
+func HandleActs(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleActs"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Acts == nil || deps.Invoices == nil {
+		return ActDisabledText(lang), nil
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	year := now().UTC().Year()
+
+	switch arg := strings.ToLower(strings.TrimSpace(args)); arg {
+	case "":
+	case "all":
+		year = 0
+	default:
+		y, err := strconv.Atoi(arg)
+		if err != nil || y < 1970 || y > year {
+			return ActUsageText(lang), nil
+		}
+		year = y
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	acts, err := deps.Acts.ListActs(ctx, userID, year)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return ActListText(lang, acts, year), nil
+}
+
+// findAct returns the act of userID matching match, the one issued before for the
+// same invoice or income.
+func findAct(ctx context.Context, deps *BotDeps, userID int64, match func(domain.Act) bool) (domain.Act, error) {
+	const op = "bot.findAct"
+
+	acts, err := deps.Acts.ListActs(ctx, userID, 0)
+	if err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	for _, act := range acts {
+		if match(act) {
+			return act, nil
+		}
+	}
+
+	return domain.Act{}, validate.Wrap(op, domain.ErrEntryNotFound)
+}
+
+// attachActFiles attaches the act as a PDF and as a DOCX if the transport can send
+// files and a renderer is configured.
+func attachActFiles(ctx context.Context, deps *BotDeps, userID int64, act domain.Act) error {
+	const op = "bot.attachActFiles"
+
+	attach, ok := documentSlot(ctx)
+	if !ok || deps.Documents == nil {
+		return nil
+	}
+
+	seller, err := deps.Invoices.Requisites(ctx, userID)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	name := fmt.Sprintf("act-%d-%d", act.IssuedOn.Year(), act.Number)
+
+	pdf, err := deps.Documents.ActPDF(act, seller)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	docx, err := deps.Documents.ActDOCX(act, seller)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	attach(Document{Name: name + ".pdf", Data: pdf})
+	attach(Document{Name: name + ".docx", Data: docx})
+
+	return nil
+}
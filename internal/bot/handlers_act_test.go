@@ -0,0 +1,129 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+)
+
+func TestHandleAct_IssueResendAndRegistry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	deps, store := newTestDeps(withClock(&now), withInvoices())
+	deps.Acts = service.NewActService(store, deps.Now)
+
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	run := func(ctx context.Context, text string) string {
+		t.Helper()
+
+		reply, _, err := bot.DispatchCommand(ctx, text, "", "telegram", "1", deps)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		return reply
+	}
+
+	expect := func(text string, want ...string) {
+		t.Helper()
+
+		reply := run(ctx, text)
+		for _, w := range want {
+			if !strings.Contains(reply, w) {
+				t.Errorf("%s: reply lacks %q:\n%s", text, w, reply)
+			}
+		}
+	}
+
+	expect("/client add Acme LLC", "Client #1 added")
+	expect("/act invoice 1", "Invoice #1 not found")
+	expect("/act invoice x", "Usage:")
+	expect("/acts", "No acts")
+
+	for _, cmd := range []string{
+		"/requisites name IP Ivanov",
+		"/requisites inn 500100732259",
+		"/requisites bank Sberbank",
+		"/requisites bik 044525225",
+		"/requisites account 40802810100000000001",
+	} {
+		run(ctx, cmd)
+	}
+
+	run(ctx, "/invoice new 1 1000 Website")
+
+	// The act is sent as a PDF to sign and a DOCX to edit.
+	docCtx, documents := bot.WithDocumentSlot(ctx)
+
+	reply := run(docCtx, "/act invoice 1")
+	for _, want := range []string{"Act issued", "Act #1 (No. 1 of Aug 10, 2025)", "Customer: Acme LLC", "₽1,000.00", "For invoice #1 (No. 1 of Aug 10, 2025)", "Website"} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("act invoice: reply lacks %q:\n%s", want, reply)
+		}
+	}
+	docs := documents()
+	if len(docs) != 2 || docs[0].Name != "act-2025-1.pdf" || docs[1].Name != "act-2025-1.docx" || string(docs[1].Data) != "PK act 500100732259" {
+		t.Fatalf("documents = %+v", docs)
+	}
+
+	// Asking again sends the same act, not a new one.
+	docCtx, documents = bot.WithDocumentSlot(ctx)
+
+	reply = run(docCtx, "/act invoice 1")
+	if !strings.Contains(reply, "already issued") || !strings.Contains(reply, "Act #1 (No. 1 of") {
+		t.Errorf("act invoice again: reply = %s", reply)
+	}
+	if docs := documents(); len(docs) != 2 || docs[0].Name != "act-2025-1.pdf" {
+		t.Fatalf("documents again = %+v", docs)
+	}
+
+	expect("/add 5000 consulting", "Income added")
+	expect("/act income 1 2", "Client #2 not found")
+	expect("/act income 9 1", "Income #9 not found")
+	expect("/act income 1 1 Consulting in July", "Act #2 (No. 2 of Aug 10, 2025)", "₽5,000.00", "For income #1", "Consulting in July")
+	expect("/act income 1 1", "already issued", "Act #2")
+
+	docCtx, documents = bot.WithDocumentSlot(ctx)
+
+	reply = run(docCtx, "/act 2")
+	if !strings.Contains(reply, "Act #2") {
+		t.Errorf("act 2: reply = %s", reply)
+	}
+	if docs := documents(); len(docs) != 2 || docs[0].Name != "act-2025-2.pdf" {
+		t.Fatalf("documents of act 2 = %+v", docs)
+	}
+	expect("/act 3", "Act #3 not found")
+
+	expect("/acts", "Acts of 2025:", "• #2 No. 2 of Aug 10, 2025, Acme LLC: ₽5,000.00 (income #1)", "• #1 No. 1 of Aug 10, 2025, Acme LLC: ₽1,000.00 (invoice #1)")
+	expect("/acts 2024", "No acts")
+	expect("/acts all", "Acts:", "#1", "#2")
+	expect("/acts 2030", "Usage:")
+
+	// Acts are numbered anew each year.
+	now = time.Date(2026, 1, 12, 12, 0, 0, 0, time.UTC)
+
+	run(ctx, "/invoice new 1 300")
+	expect("/act invoice 2", "Act #3 (No. 1 of Jan 12, 2026)")
+}
+
+func TestHandleAct_Disabled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	deps, _ := newTestDeps(withClock(&now), withInvoices())
+
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	for _, text := range []string{"/act invoice 1", "/acts"} {
+		reply, _, err := bot.DispatchCommand(ctx, text, "", "telegram", "1", deps)
+		if err != nil || !strings.Contains(reply, "not configured") {
+			t.Errorf("%s: reply = %q, err = %v", text, reply, err)
+		}
+	}
+}
@@ -4,13 +4,21 @@ import (
 	"context"
 	"time"
 
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
 func HandleAdd(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
 	const op = "bot.HandleAdd"
 
-	amount, note, err := ParseAmountAndNote(args)
+	// Use UTC "now" unless a date is given; storage casts to DATE.
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+	t := now()
+
+	amount, at, note, err := ParseEntryArgs(args, t, t)
 
 	if err != nil {
 		return "", validate.Wrap(op, err)
@@ -27,17 +35,28 @@ func HandleAdd(ctx context.Context, deps *BotDeps, transport, externalID string,
 		return "", validate.Wrap(op, err)
 	}
 
-	// Use UTC "now"; storage casts to DATE.
-	now := time.Now
-	if deps.Now != nil {
-		now = deps.Now
-	}
-	at := now().UTC()
-
 	// Persist income.
-	if err := deps.Income.AddIncome(ctx, userID, at, amount, note); err != nil {
+	if _, err := deps.Income.AddIncome(ctx, userID, at, amount, note); err != nil {
 		return "", validate.Wrap(op, err)
 	}
 
-	return AddSuccessText(amount, at, note), nil
+	lang := i18n.FromContext(ctx)
+	reply := AddSuccessText(lang, amount, at, note)
+
+	if deps.Limits == nil {
+		return reply, nil
+	}
+
+	// Limits are yearly: an income dated in a past year is checked against that whole year.
+	ref := t.UTC()
+	if at.Year() < ref.Year() {
+		ref = time.Date(at.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	// The income is saved already: a failed check only drops the warnings.
+	if limits, err := deps.Limits.Limits(ctx, userID, ref); err == nil {
+		reply += LimitWarningsText(lang, limits)
+	}
+
+	return reply, nil
 }
@@ -5,12 +5,21 @@ import (
 	"time"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
 func HandleAddContrib(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
 	const op = "bot.HandleAddContrib"
-	amount, note, err := ParseAmountAndNote(args)
+
+	// Use UTC "now" unless a date is given; storage casts to DATE.
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+	t := now()
+
+	amount, at, note, err := ParseEntryArgs(args, t, t)
 
 	if err != nil {
 		return "", validate.Wrap(op, err)
@@ -23,17 +32,10 @@ func HandleAddContrib(ctx context.Context, deps *BotDeps, transport, externalID
 		return "", validate.Wrap(op, err)
 	}
 
-	// Use UTC "now"; storage casts to DATE.
-	now := time.Now
-	if deps.Now != nil {
-		now = deps.Now
-	}
-	at := now().UTC()
-
 	// Persist Contribution.
-	if err := deps.Payment.AddPayment(ctx, userID, at, amount, note, domain.PaymentType(domain.PaymentTypeContrib)); err != nil {
+	if _, err := deps.Payment.AddPayment(ctx, userID, at, amount, note, domain.PaymentType(domain.PaymentTypeContrib)); err != nil {
 		return "", validate.Wrap(op, err)
 	}
 
-	return AddContribSuccessText(amount, at, note), nil
+	return AddContribSuccessText(i18n.FromContext(ctx), amount, at, note), nil
 }
@@ -2,11 +2,13 @@ package bot_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
 	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
 	"github.com/tuor4eg/ip_accounting_bot/internal/service"
 	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
 )
@@ -14,14 +16,30 @@ import (
 // Mock structures for tests
 type mockPaymentService struct{}
 
-func (m *mockPaymentService) AddPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType domain.PaymentType) error {
+func (m *mockPaymentService) AddPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType domain.PaymentType) (int64, error) {
+	return 1, nil
+}
+
+func (m *mockPaymentService) UpdatePayment(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) error {
 	return nil
 }
 
+func (m *mockPaymentService) ListPayments(ctx context.Context, userID int64, from, to time.Time) ([]domain.Payment, error) {
+	return nil, nil
+}
+
 func (m *mockPaymentService) UndoLastYear(ctx context.Context, userID int64, now time.Time, paymentType domain.PaymentType) (int64, time.Time, string, domain.PaymentType, bool, error) {
 	return 0, time.Time{}, "", "", false, nil
 }
 
+func (m *mockPaymentService) UndoPayment(ctx context.Context, userID, id int64, now time.Time, paymentType domain.PaymentType) (int64, time.Time, string, bool, error) {
+	return 0, time.Time{}, "", false, nil
+}
+
+func (m *mockPaymentService) GetPayment(ctx context.Context, userID, id int64) (domain.Payment, bool, error) {
+	return domain.Payment{}, false, nil
+}
+
 type mockTotalService struct{}
 
 func (m *mockTotalService) SumQuarter(ctx context.Context, userID int64, now time.Time) (domain.Totals, error) {
@@ -68,7 +86,7 @@ func TestHandleAdd_UsesTextTemplate(t *testing.T) {
 	// expected string — exactly through your template
 	amount := int64(1050) // 10р 50к
 	at := fixedNow()      // date in response is formatted as YYYY-MM-DD
-	want := bot.AddSuccessText(amount, at, note)
+	want := bot.AddSuccessText(i18n.RU, amount, at, note)
 
 	if reply != want {
 		t.Fatalf("unexpected reply:\n--- got ---\n%s\n--- want ---\n%s", reply, want)
@@ -98,7 +116,7 @@ func TestHandleUndo_NoIncome_UsesTextTemplate(t *testing.T) {
 		t.Fatalf("HandleUndo error: %v", err)
 	}
 
-	want := bot.UndoNoIncomeText()
+	want := bot.UndoNoIncomeText(i18n.RU)
 	if reply != want {
 		t.Fatalf("unexpected reply:\n--- got ---\n%s\n--- want ---\n%s", reply, want)
 	}
@@ -135,9 +153,23 @@ func TestHandleUndo_Success_UsesTextTemplate(t *testing.T) {
 	amount := int64(200) // 2 rubles = 200 kopecks
 	at := fixedNow()
 	note := "комментарий"
-	want := bot.UndoSuccessText(amount, at, note)
+	want := bot.UndoSuccessText(i18n.RU, amount, at, note)
 
 	if reply != want {
 		t.Fatalf("unexpected reply:\n--- got ---\n%s\n--- want ---\n%s", reply, want)
 	}
 }
+
+func TestAddSuccessText_EscapesNote(t *testing.T) {
+	t.Parallel()
+
+	got := bot.AddSuccessText(i18n.RU, 100, fixedNow(), `<b <a href="x">& co`)
+
+	if strings.Contains(got, "<b") || strings.Contains(got, "<a") {
+		t.Fatalf("note markup leaked into reply: %q", got)
+	}
+
+	if !strings.Contains(got, "&lt;b &lt;a href=\"x\"&gt;&amp; co") {
+		t.Fatalf("note is not escaped: %q", got)
+	}
+}
@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/chart"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleChart replies with the monthly income of a year (the current one by default):
+//
+//	/chart [year]
+//
+// Transports that can send photos get a PNG bar chart with the text as its caption;
+// the others get the months as text.
+func HandleChart(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleChart"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Chart == nil {
+		return ChartDisabledText(lang), nil
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	year := now().UTC().Year()
+
+	if arg := strings.TrimSpace(args); arg != "" {
+		y, err := strconv.Atoi(arg)
+		if err != nil || y < 1970 || y > year {
+			return ChartUsageText(lang), nil
+		}
+		year = y
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	monthly, err := deps.Chart.MonthlyIncome(ctx, userID, year)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	attach, ok := photoSlot(ctx)
+
+	if !ok || monthly.Total() == 0 {
+		return ChartText(lang, monthly), nil
+	}
+
+	png, err := chart.MonthlyPNG(monthly)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	attach(Photo{Name: "income-" + strconv.Itoa(year) + ".png", PNG: png})
+
+	return ChartCaptionText(lang, monthly), nil
+}
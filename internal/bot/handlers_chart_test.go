@@ -0,0 +1,76 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+func TestHandleChart_PhotoOrText(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	deps.Chart = service.NewChartService(store, tax.NewDefaultProvider())
+
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addIncomes(t, store, uid, map[time.Time]int64{
+		time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC): 500_00,
+		time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC):  1_000_00,
+		time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC): 2_000_00,
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC):  10_000_00,
+	})
+
+	// A transport without photos gets the months as text.
+	reply, _, err := bot.DispatchCommand(ctx, "/chart", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("chart: %v", err)
+	}
+	for _, want := range []string{"Income by month, 2025", "• March: ₽3,000.00, tax ₽180.00", "• June: ₽10,000.00, tax ₽600.00"} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("reply lacks %q:\n%s", want, reply)
+		}
+	}
+
+	// With a photo slot the chart is attached and the reply is its caption.
+	photoCtx, photo := bot.WithPhotoSlot(ctx)
+
+	reply, _, err = bot.DispatchCommand(photoCtx, "/chart 2024", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("chart 2024: %v", err)
+	}
+
+	p, ok := photo()
+	if !ok || p.Name != "income-2024.png" || len(p.PNG) == 0 {
+		t.Fatalf("photo = %q, %d bytes, %v", p.Name, len(p.PNG), ok)
+	}
+	if !strings.Contains(reply, "₽500.00") || strings.Contains(reply, "February") {
+		t.Errorf("caption:\n%s", reply)
+	}
+
+	// An empty year sends no chart.
+	photoCtx, photo = bot.WithPhotoSlot(ctx)
+
+	reply, _, err = bot.DispatchCommand(photoCtx, "/chart 2023", "", "telegram", "1", deps)
+	if _, ok := photo(); err != nil || ok || !strings.Contains(reply, "No income in 2023") {
+		t.Errorf("empty year: %q, photo %v, %v", reply, ok, err)
+	}
+
+	for _, args := range []string{"2026", "next", "1969"} {
+		reply, _, err := bot.DispatchCommand(ctx, "/chart "+args, "", "telegram", "1", deps)
+		if err != nil || !strings.Contains(reply, "Usage: /chart") {
+			t.Errorf("/chart %s = %q, %v; want usage", args, reply, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// DispatchEdit applies an edited command message to the entry the original message created.
+// Only /add, /add_contrib and /add_advance are editable; anything else is not handled,
+// and transports should stay silent about it. The message must be set with domain.WithMessageRef.
+func DispatchEdit(
+	ctx context.Context,
+	text string,
+	self string,
+	transport string,
+	externalID string,
+	deps *BotDeps,
+) (reply string, handled bool, err error) {
+	const op = "bot.DispatchEdit"
+
+	cmd, args, ok := ParseSlashCommand(text, self)
+	if !ok {
+		return "", false, nil
+	}
+
+	var kind domain.EntryKind
+
+	switch cmd {
+	case "add":
+		kind = domain.EntryKindIncome
+	case "add_contrib":
+		kind = domain.EntryKindContrib
+	case "add_advance":
+		kind = domain.EntryKindAdvance
+	default:
+		return "", false, nil
+	}
+
+	ref, ok := domain.MessageRefFrom(ctx)
+	if !ok || deps.Messages == nil {
+		return "", false, nil
+	}
+
+	start := time.Now()
+
+	reply, err = HandleEdit(ctx, deps, transport, externalID, ref, kind, args)
+	observeCommand(deps, cmd+"_edit", start, err)
+
+	if err != nil {
+		return "", true, validate.Wrap(op, err)
+	}
+
+	return reply, true, nil
+}
+
+// HandleEdit re-parses the edited arguments and updates the linked entry.
+// Without an explicit date the entry gets the date the original message was sent.
+func HandleEdit(ctx context.Context, deps *BotDeps, transport, externalID string, ref domain.MessageRef, kind domain.EntryKind, args string) (string, error) {
+	const op = "bot.HandleEdit"
+
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+	t := now()
+
+	defaultAt := t
+	if !ref.SentAt.IsZero() {
+		defaultAt = ref.SentAt
+	}
+
+	amount, at, note, err := ParseEntryArgs(args, defaultAt, t)
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	if err := validateEntryInput(amount, note); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	entry, found, err := deps.Messages.FindMessageEntry(ctx, userID, ref)
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	if !found {
+		return EditNotFoundText(i18n.FromContext(ctx)), nil
+	}
+
+	if entry.Kind != kind {
+		return EditKindMismatchText(i18n.FromContext(ctx)), nil
+	}
+
+	if entry.Kind == domain.EntryKindIncome {
+		err = deps.Income.UpdateIncome(ctx, userID, entry.ID, at, amount, note)
+	} else {
+		err = deps.Payment.UpdatePayment(ctx, userID, entry.ID, at, amount, note)
+	}
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return EditSuccessText(i18n.FromContext(ctx), kind, amount, at, note), nil
+}
+
+// HandleEditByID corrects an entry by its ID, as /find shows it, for /edit, /edit_contrib
+// and /edit_advance:
+//
+//	/edit #id [date] [amount] [note]
+//
+// Without an explicit date the entry keeps its date. kind must match the entry.
+func HandleEditByID(ctx context.Context, deps *BotDeps, transport, externalID string, kind domain.EntryKind, args string) (string, error) {
+	const op = "bot.HandleEditByID"
+
+	lang := i18n.FromContext(ctx)
+
+	idArg, rest := strings.TrimSpace(args), ""
+	if i := strings.IndexAny(idArg, " \t\n"); i >= 0 {
+		idArg, rest = idArg[:i], idArg[i+1:]
+	}
+
+	id, ok := parseEntryID(idArg)
+	if !ok || strings.TrimSpace(rest) == "" {
+		return EditUsageText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	var entryAt time.Time
+
+	if kind == domain.EntryKindIncome {
+		income, found, err := deps.Income.GetIncome(ctx, userID, id)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+		ok, entryAt = found, income.At
+	} else {
+		payment, found, err := deps.Payment.GetPayment(ctx, userID, id)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+		ok, entryAt = found && domain.EntryKind(payment.Type) == kind, payment.At
+	}
+
+	if !ok {
+		return EntryNotFoundText(lang, kind, id), nil
+	}
+
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	amount, at, note, err := ParseEntryArgs(rest, entryAt, now())
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	if err := validateEntryInput(amount, note); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	if kind == domain.EntryKindIncome {
+		err = deps.Income.UpdateIncome(ctx, userID, id, at, amount, note)
+	} else {
+		err = deps.Payment.UpdatePayment(ctx, userID, id, at, amount, note)
+	}
+
+	// Undone between the lookup and the update.
+	if errors.Is(err, domain.ErrEntryNotFound) {
+		return EntryNotFoundText(lang, kind, id), nil
+	}
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return EditSuccessText(lang, kind, amount, at, note), nil
+}
@@ -0,0 +1,149 @@
+package bot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+func messageCtx(key string, sentAt time.Time) context.Context {
+	return domain.WithMessageRef(context.Background(), domain.MessageRef{
+		Transport: domain.TransportTelegram,
+		Key:       key,
+		SentAt:    sentAt,
+	})
+}
+
+func listIncomes(t *testing.T, deps *bot.BotDeps, externalID string) []domain.Income {
+	t.Helper()
+
+	ctx := context.Background()
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, domain.TransportTelegram, externalID, 0)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	items, err := deps.Income.ListIncomes(ctx, userID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ListIncomes: %v", err)
+	}
+
+	return items
+}
+
+func TestDispatchEdit_UpdatesLinkedIncome(t *testing.T) {
+	t.Parallel()
+
+	deps, _ := newTestDeps(withPayments(), withMessages())
+	sentAt := time.Date(2025, 8, 9, 18, 0, 0, 0, time.UTC)
+	ctx := messageCtx("42:10", sentAt)
+
+	if _, _, err := bot.DispatchCommand(ctx, "/add 1000 заказ", "", domain.TransportTelegram, "42", deps); err != nil {
+		t.Fatalf("DispatchCommand: %v", err)
+	}
+
+	reply, handled, err := bot.DispatchEdit(ctx, "/add 1200 заказ #42", "", domain.TransportTelegram, "42", deps)
+	if err != nil || !handled {
+		t.Fatalf("DispatchEdit: handled=%v err=%v", handled, err)
+	}
+
+	// Without an explicit date the entry keeps the day the message was sent.
+	day := time.Date(2025, 8, 9, 0, 0, 0, 0, time.UTC)
+	if want := bot.EditSuccessText(i18n.RU, domain.EntryKindIncome, 120000, day, "заказ #42"); reply != want {
+		t.Fatalf("unexpected reply:\n--- got ---\n%s\n--- want ---\n%s", reply, want)
+	}
+
+	items := listIncomes(t, deps, "42")
+	if len(items) != 1 || items[0].Amount != 120000 || items[0].Note != "заказ #42" || !items[0].At.Equal(day) {
+		t.Fatalf("incomes after edit = %+v", items)
+	}
+
+	if _, _, err := bot.DispatchEdit(ctx, "/add 01.08.2025 1200 заказ #42", "", domain.TransportTelegram, "42", deps); err != nil {
+		t.Fatalf("DispatchEdit with date: %v", err)
+	}
+
+	items = listIncomes(t, deps, "42")
+	if len(items) != 1 || !items[0].At.Equal(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("incomes after date edit = %+v", items)
+	}
+}
+
+func TestDispatchEdit_Rejects(t *testing.T) {
+	t.Parallel()
+
+	deps, _ := newTestDeps(withPayments(), withMessages())
+	ctx := messageCtx("42:11", time.Time{})
+
+	if _, _, err := bot.DispatchCommand(ctx, "/add 1000", "", domain.TransportTelegram, "42", deps); err != nil {
+		t.Fatalf("DispatchCommand: %v", err)
+	}
+
+	// Not an entry command: edits are ignored.
+	if _, handled, _ := bot.DispatchEdit(ctx, "/total", "", domain.TransportTelegram, "42", deps); handled {
+		t.Fatalf("edit of /total should not be handled")
+	}
+
+	// Another user cannot edit the entry.
+	reply, _, err := bot.DispatchEdit(ctx, "/add 5000", "", domain.TransportTelegram, "43", deps)
+	if err != nil || reply != bot.EditNotFoundText(i18n.RU) {
+		t.Fatalf("foreign edit: reply=%q err=%v", reply, err)
+	}
+
+	reply, _, err = bot.DispatchEdit(ctx, "/add_contrib 1000", "", domain.TransportTelegram, "42", deps)
+	if err != nil || reply != bot.EditKindMismatchText(i18n.RU) {
+		t.Fatalf("kind change: reply=%q err=%v", reply, err)
+	}
+
+	_, _, err = bot.DispatchEdit(ctx, "/add 2025-08-11 1000", "", domain.TransportTelegram, "42", deps)
+	if !errors.Is(err, bot.ErrFutureDate) {
+		t.Fatalf("future date err = %v, want ErrFutureDate", err)
+	}
+
+	if _, _, err := bot.DispatchCommand(context.Background(), "/undo", "", domain.TransportTelegram, "42", deps); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+
+	_, _, err = bot.DispatchEdit(ctx, "/add 2000", "", domain.TransportTelegram, "42", deps)
+	if !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Fatalf("edit after undo err = %v, want ErrEntryNotFound", err)
+	}
+}
+
+func TestParseEntryArgs_Date(t *testing.T) {
+	t.Parallel()
+
+	now := fixedNow()
+
+	tests := []struct {
+		args   string
+		at     time.Time
+		amount int64
+		note   string
+	}{
+		{"1000 заказ", now, 100000, "заказ"},
+		{"2025-08-01 1000", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), 100000, ""},
+		{"01.08.2025 10р 50к аванс", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), 1050, "аванс"},
+		{"10.08.2025 1000", time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC), 100000, ""},
+	}
+
+	for _, tt := range tests {
+		amount, at, note, err := bot.ParseEntryArgs(tt.args, now, now)
+		if err != nil {
+			t.Fatalf("ParseEntryArgs(%q): %v", tt.args, err)
+		}
+
+		if amount != tt.amount || !at.Equal(tt.at) || note != tt.note {
+			t.Fatalf("ParseEntryArgs(%q) = %d, %v, %q", tt.args, amount, at, note)
+		}
+	}
+
+	if _, _, _, err := bot.ParseEntryArgs("11.08.2025 1000", now, now); !errors.Is(err, bot.ErrFutureDate) {
+		t.Fatalf("future date err = %v", err)
+	}
+}
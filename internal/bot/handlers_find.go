@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// findLimit is the number of matches /find shows.
+const findLimit = 20
+
+// HandleFind lists incomes and payments whose note contains the text, newest first:
+//
+//	/find <text> [2025 | 2025-q2 | 2025-03]
+//
+// The search ignores case and reads ё as е. A last word that is a period limits the search
+// to it; otherwise all entries up to today are searched.
+func HandleFind(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleFind"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Search == nil {
+		return FindDisabledText(lang), nil
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	today := period.Day(now())
+
+	query, from, to, ok := parseFindArgs(args, today)
+	if !ok {
+		return FindUsageText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	// One extra match tells whether there are more than shown.
+	matches, err := deps.Search.FindNotes(ctx, userID, query, from, to, findLimit+1)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	more := len(matches) > findLimit
+	if more {
+		matches = matches[:findLimit]
+	}
+
+	return FindText(lang, query, matches, more), nil
+}
+
+// parseFindArgs splits "<text> [period]". The period is cut at today; a period that starts
+// after today makes the arguments invalid.
+func parseFindArgs(args string, today time.Time) (query string, from, to time.Time, ok bool) {
+	query = strings.TrimSpace(args)
+	from, to = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC), today
+
+	if i := strings.LastIndexAny(query, " \t\n"); i > 0 {
+		if pFrom, pTo, isPeriod := parseReportPeriod(strings.ToLower(query[i+1:])); isPeriod {
+			if pFrom.After(today) {
+				return "", time.Time{}, time.Time{}, false
+			}
+			query, from, to = strings.TrimSpace(query[:i]), pFrom, minTime(pTo, today)
+		}
+	}
+
+	if query == "" {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	return query, from, to, true
+}
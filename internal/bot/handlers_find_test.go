@@ -0,0 +1,205 @@
+package bot_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+)
+
+func TestHandleFind_MatchesNotesWithIDs(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	deps.Search = service.NewSearchService(store)
+
+	ctx := i18n.WithLang(context.Background(), i18n.RU)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jan, err := store.InsertIncome(ctx, uid, time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), 10_000_00, "Заказ #42 для <Ёлки>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adv, err := store.InsertPayment(ctx, uid, time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC), 1_000_00, "аванс по ЗАКАЗУ #42", domain.PaymentTypeAdvance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply, _, err := bot.DispatchCommand(ctx, "/find заказ", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	// Newest first, with the ledger and ID of each entry; notes are escaped.
+	advLine := fmt.Sprintf("• аванс <code>#%d</code> · 25.04.2025", adv)
+	janLine := fmt.Sprintf("• поступление <code>#%d</code> · 10.01.2025", jan)
+
+	if i, j := strings.Index(reply, advLine), strings.Index(reply, janLine); i < 0 || j < i {
+		t.Errorf("reply lacks %q before %q:\n%s", advLine, janLine, reply)
+	}
+	if !strings.Contains(reply, "Заказ #42 для &lt;Ёлки&gt;") {
+		t.Errorf("note not shown escaped:\n%s", reply)
+	}
+
+	// ё is read as е; a trailing period narrows the search.
+	reply, _, err = bot.DispatchCommand(ctx, "/find елки 2025-q1", "", "telegram", "1", deps)
+	if err != nil || !strings.Contains(reply, janLine) || strings.Contains(reply, "аванс") {
+		t.Errorf("find елки 2025-q1 = %q, %v", reply, err)
+	}
+
+	reply, _, err = bot.DispatchCommand(ctx, "/find заказ 2025-02", "", "telegram", "1", deps)
+	if err != nil || !strings.Contains(reply, "ничего не найдено") {
+		t.Errorf("find in February = %q, %v", reply, err)
+	}
+
+	// A lone period is the text to find; a period in the future is rejected.
+	reply, _, _ = bot.DispatchCommand(ctx, "/find 2025", "", "telegram", "1", deps)
+	if !strings.Contains(reply, "«2025»") {
+		t.Errorf("find 2025 = %q", reply)
+	}
+
+	for _, args := range []string{"", "заказ 2026"} {
+		reply, _, err := bot.DispatchCommand(ctx, "/find "+args, "", "telegram", "1", deps)
+		if err != nil || !strings.Contains(reply, "Формат: /find") {
+			t.Errorf("/find %s = %q, %v; want usage", args, reply, err)
+		}
+	}
+}
+
+func TestHandleFind_ShowsNewestAndSaysThereAreMore(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	deps.Search = service.NewSearchService(store)
+
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for d := 1; d <= 25; d++ {
+		if _, err := store.InsertIncome(ctx, uid, time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC), int64(d)*100, fmt.Sprintf("hosting %d", d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reply, _, err := bot.DispatchCommand(ctx, "/find HOSTING", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	if n := strings.Count(reply, "\n• income "); n != 20 {
+		t.Errorf("shown %d matches, want 20:\n%s", n, reply)
+	}
+	if !strings.Contains(reply, "hosting 25") || strings.Contains(reply, "hosting 5\n") {
+		t.Errorf("not the newest matches:\n%s", reply)
+	}
+	if !strings.Contains(reply, "Showing the newest 20") {
+		t.Errorf("reply does not say there are more:\n%s", reply)
+	}
+}
+
+func TestHandleUndo_ByIDFromFind(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Last year, out of reach of a plain /undo.
+	old, err := store.InsertIncome(ctx, uid, time.Date(2024, 11, 3, 0, 0, 0, 0, time.UTC), 500_00, "order #7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adv, err := store.InsertPayment(ctx, uid, time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC), 100_00, "", domain.PaymentTypeAdvance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		desc, externalID, cmd, want string
+	}{
+		{"other user", "2", fmt.Sprintf("/undo #%d", old), bot.EntryNotFoundText(i18n.EN, domain.EntryKindIncome, old)},
+		{"not an id", "1", "/undo last", bot.UndoBadIDText(i18n.EN)},
+		{"wrong ledger", "1", fmt.Sprintf("/undo_contrib #%d", adv), bot.EntryNotFoundText(i18n.EN, domain.EntryKindContrib, adv)},
+		{"income", "1", fmt.Sprintf("/undo #%d", old), bot.UndoSuccessText(i18n.EN, 500_00, time.Date(2024, 11, 3, 0, 0, 0, 0, time.UTC), "order #7")},
+		{"income again", "1", fmt.Sprintf("/undo %d", old), bot.EntryNotFoundText(i18n.EN, domain.EntryKindIncome, old)},
+		{"advance", "1", fmt.Sprintf("/undo_advance #%d", adv), bot.UndoAdvanceSuccessText(i18n.EN, 100_00, time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC), "")},
+	}
+
+	for _, tc := range cases {
+		reply, _, err := bot.DispatchCommand(ctx, tc.cmd, "", "telegram", tc.externalID, deps)
+		if err != nil || reply != tc.want {
+			t.Errorf("%s: %s = %q, %v; want %q", tc.desc, tc.cmd, reply, err, tc.want)
+		}
+	}
+
+	if sum, err := store.SumIncomes(ctx, uid, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)); err != nil || sum != 0 {
+		t.Errorf("SumIncomes after undo = %d, %v; want 0", sum, err)
+	}
+}
+
+func TestHandleEditByID(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	id, err := store.InsertIncome(ctx, uid, day, 10_000_00, "order #42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contrib, err := store.InsertPayment(ctx, uid, day, 100_00, "", domain.PaymentTypeContrib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		desc, externalID, cmd, want string
+	}{
+		{"no values", "1", fmt.Sprintf("/edit #%d", id), bot.EditUsageText(i18n.EN)},
+		{"other user", "2", fmt.Sprintf("/edit #%d 1", id), bot.EntryNotFoundText(i18n.EN, domain.EntryKindIncome, id)},
+		{"wrong ledger", "1", fmt.Sprintf("/edit_advance #%d 1", contrib), bot.EntryNotFoundText(i18n.EN, domain.EntryKindAdvance, contrib)},
+		// Without a date the entry keeps its own.
+		{"income", "1", fmt.Sprintf("/edit #%d 12000 order #43", id), bot.EditSuccessText(i18n.EN, domain.EntryKindIncome, 12_000_00, day, "order #43")},
+		{"contribution", "1", fmt.Sprintf("/edit_contrib #%d 2025-02-01 150", contrib), bot.EditSuccessText(i18n.EN, domain.EntryKindContrib, 150_00, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), "")},
+	}
+
+	for _, tc := range cases {
+		reply, _, err := bot.DispatchCommand(ctx, tc.cmd, "", "telegram", tc.externalID, deps)
+		if err != nil || reply != tc.want {
+			t.Errorf("%s: %s = %q, %v; want %q", tc.desc, tc.cmd, reply, err, tc.want)
+		}
+	}
+
+	income, ok, err := store.GetIncome(ctx, uid, id)
+	if err != nil || !ok || income.Amount != 12_000_00 || income.Note != "order #43" || !income.At.Equal(day) {
+		t.Errorf("income after edit = %+v, %v, %v", income, ok, err)
+	}
+}
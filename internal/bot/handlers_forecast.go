@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleForecast projects the income and the tax of the current year to its end.
+func HandleForecast(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleForecast"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Forecast == nil {
+		return ForecastDisabledText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	forecast, err := deps.Forecast.Forecast(ctx, userID, now().UTC())
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return ForecastText(lang, forecast), nil
+}
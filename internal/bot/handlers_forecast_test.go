@@ -0,0 +1,99 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+func TestHandleForecast_SeasonalRangeAndVATWarning(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Last year a quarter of the income came by July 1.
+	for _, in := range []struct {
+		at     time.Time
+		amount int64
+	}{
+		{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 10_000_000_00},
+		{time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), 30_000_000_00},
+		{time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), 40_000_000_00},
+	} {
+		if _, err := store.InsertIncome(ctx, uid, in.at, in.amount, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reply, _, err := bot.DispatchCommand(ctx, "/forecast", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+
+	// Estimates: year-to-date run-rate, the last 90 days (nothing) and last year's shape (x4).
+	for _, want := range []string{
+		"Forecast for 2025",
+		"₽80,219,780.21 (₽40,000,000.00 – ₽160,000,000.00)",
+		"last year's income",
+		"1% extra contribution: ₽300,888.00", // capped for 2025
+		"expected to exceed the VAT threshold of ₽60,000,000.00",
+	} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("reply lacks %q:\n%s", want, reply)
+		}
+	}
+
+	if strings.Contains(reply, "USN limit") {
+		t.Errorf("unexpected USN limit warning:\n%s", reply)
+	}
+}
+
+func TestHandleForecast_RunRateOnly(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.InsertIncome(ctx, uid, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 600_000_00, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	reply, _, err := bot.DispatchCommand(ctx, "/forecast", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+
+	if strings.Contains(reply, "–") || strings.Contains(reply, "⚠️") {
+		t.Errorf("a single estimate should give no range and no warnings:\n%s", reply)
+	}
+	if !strings.Contains(reply, "with last year's income") {
+		t.Errorf("reply lacks the run-rate basis:\n%s", reply)
+	}
+}
+
+func TestHandleForecast_Disabled(t *testing.T) {
+	t.Parallel()
+
+	deps, _ := newTestDeps(withNow(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	deps.Forecast = nil
+
+	reply, _, err := bot.DispatchCommand(i18n.WithLang(context.Background(), i18n.EN), "/forecast", "", "telegram", "1", deps)
+	if err != nil || !strings.Contains(reply, "not configured") {
+		t.Fatalf("reply = %q, %v", reply, err)
+	}
+}
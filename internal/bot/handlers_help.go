@@ -1,10 +1,13 @@
 package bot
 
-import "context"
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
 
 // HandleHelp returns the response text for the /help command.
 // Transport-agnostic; the router/runner is responsible for delivery.
 func HandleHelp(ctx context.Context) string {
-	_ = ctx
-	return HelpText()
+	return HelpText(i18n.FromContext(ctx))
 }
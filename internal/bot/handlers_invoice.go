@@ -0,0 +1,479 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/money"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleClient manages the clients invoices are issued to:
+//
+//	/client add <name>                        — add a client
+//	/client set <id> inn|kpp|address <value>  — fill in its details
+//	/client <id>                              — show a client
+func HandleClient(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleClient"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Invoices == nil {
+		return InvoiceDisabledText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	sub, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	rest = strings.TrimSpace(rest)
+
+	switch strings.ToLower(sub) {
+	case "add":
+		if rest == "" {
+			return ClientUsageText(lang), nil
+		}
+
+		client, err := deps.Invoices.AddClient(ctx, userID, domain.Counterparty{Name: rest})
+
+		switch {
+		case errors.Is(err, domain.ErrClientExists):
+			return ClientExistsText(lang, rest), nil
+		case err != nil:
+			return "", validate.Wrap(op, err)
+		}
+
+		return ClientAddedText(lang, client), nil
+
+	case "set":
+		idArg, rest, _ := strings.Cut(rest, " ")
+		field, value, _ := strings.Cut(strings.TrimSpace(rest), " ")
+		field, value = strings.ToLower(field), strings.TrimSpace(value)
+
+		clientID, err := strconv.ParseInt(idArg, 10, 64)
+		if err != nil || clientID <= 0 {
+			return ClientUsageText(lang), nil
+		}
+
+		client, err := deps.Invoices.GetClient(ctx, userID, clientID)
+
+		switch {
+		case errors.Is(err, domain.ErrEntryNotFound):
+			return ClientNotFoundText(lang, clientID), nil
+		case err != nil:
+			return "", validate.Wrap(op, err)
+		}
+
+		switch field {
+		case "inn":
+			client.INN = value
+		case "kpp":
+			client.KPP = value
+		case "address":
+			client.Address = value
+		default:
+			return ClientUsageText(lang), nil
+		}
+
+		client, err = deps.Invoices.UpdateClient(ctx, userID, client)
+
+		switch {
+		case errors.Is(err, validate.ErrInvalidRequisite):
+			return RequisiteInvalidText(lang, field), nil
+		case err != nil:
+			return "", validate.Wrap(op, err)
+		}
+
+		return ClientText(lang, client), nil
+	}
+
+	clientID, err := strconv.ParseInt(sub, 10, 64)
+	if err != nil || clientID <= 0 || rest != "" {
+		return ClientUsageText(lang), nil
+	}
+
+	client, err := deps.Invoices.GetClient(ctx, userID, clientID)
+
+	switch {
+	case errors.Is(err, domain.ErrEntryNotFound):
+		return ClientNotFoundText(lang, clientID), nil
+	case err != nil:
+		return "", validate.Wrap(op, err)
+	}
+
+	return ClientText(lang, client), nil
+}
+
+// HandleClients lists the clients of the user.
+func HandleClients(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleClients"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Invoices == nil {
+		return InvoiceDisabledText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	clients, err := deps.Invoices.ListClients(ctx, userID)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return ClientListText(lang, clients), nil
+}
+
+// requisiteFields are the names of the requisites accepted by /requisites.
+var requisiteFields = []string{"name", "inn", "ogrnip", "address", "bank", "bik", "account", "corr"}
+
+// requisiteField returns where /requisites <field> is kept in r.
+func requisiteField(r *domain.Requisites, field string) *string {
+	switch field {
+	case "name":
+		return &r.Name
+	case "inn":
+		return &r.INN
+	case "ogrnip":
+		return &r.OGRNIP
+	case "address":
+		return &r.Address
+	case "bank":
+		return &r.Bank
+	case "bik":
+		return &r.BIK
+	case "account":
+		return &r.Account
+	case "corr":
+		return &r.CorrAccount
+	default:
+		return nil
+	}
+}
+
+// HandleRequisites shows or sets the details of the user's business printed on invoices:
+//
+//	/requisites                  — show them
+//	/requisites <field> <value>  — set one; "-" clears it
+func HandleRequisites(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleRequisites"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Invoices == nil {
+		return InvoiceDisabledText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	req, err := deps.Invoices.Requisites(ctx, userID)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	field, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	field, value = strings.ToLower(field), strings.TrimSpace(value)
+
+	if field == "" {
+		return RequisitesText(lang, req), nil
+	}
+
+	target := requisiteField(&req, field)
+	if target == nil || value == "" {
+		return RequisitesUsageText(lang), nil
+	}
+
+	if value == "-" {
+		value = ""
+	}
+	*target = value
+
+	req, err = deps.Invoices.SetRequisites(ctx, userID, req)
+
+	switch {
+	case errors.Is(err, validate.ErrInvalidRequisite):
+		return RequisiteInvalidText(lang, field), nil
+	case err != nil:
+		return "", validate.Wrap(op, err)
+	}
+
+	return RequisitesText(lang, req), nil
+}
+
+// HandleInvoice issues invoices and records their payments:
+//
+//	/invoice new <client id> <amount> [due <date>] [description]  — issue an invoice
+//	/invoice <id>                                                 — show it (and send the PDF again)
+//	/invoice paid <id> [date] [amount]                            — add the income that paid it
+//
+// Transports that can send files get the invoice as a PDF. The chat of the command is
+// remembered so that reminders of overdue invoices reach it.
+func HandleInvoice(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleInvoice"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Invoices == nil {
+		return InvoiceDisabledText(lang), nil
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, domain.ChatIDFrom(ctx))
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	sub, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	sub = strings.ToLower(sub)
+	rest = strings.TrimSpace(rest)
+
+	if sub == "new" {
+		draft, err := parseNewInvoice(rest)
+		if err != nil {
+			return InvoiceUsageText(lang), nil
+		}
+
+		inv, err := deps.Invoices.IssueInvoice(ctx, userID, draft)
+
+		switch {
+		case errors.Is(err, domain.ErrEntryNotFound):
+			return ClientNotFoundText(lang, draft.Client.ID), nil
+		case errors.Is(err, domain.ErrRequisitesMissing):
+			return InvoiceNoRequisitesText(lang), nil
+		case errors.Is(err, validate.ErrInvalidDate):
+			return InvoiceBadDueText(lang), nil
+		case err != nil:
+			return "", validate.Wrap(op, err)
+		}
+
+		if err := attachInvoicePDF(ctx, deps, userID, inv); err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		return InvoiceIssuedText(lang, inv), nil
+	}
+
+	paid := sub == "paid"
+	if paid {
+		sub, rest, _ = strings.Cut(rest, " ")
+	}
+
+	invoiceID, err := strconv.ParseInt(sub, 10, 64)
+	if err != nil || invoiceID <= 0 || (!paid && rest != "") {
+		return InvoiceUsageText(lang), nil
+	}
+
+	inv, err := deps.Invoices.GetInvoice(ctx, userID, invoiceID)
+
+	switch {
+	case errors.Is(err, domain.ErrEntryNotFound):
+		return InvoiceNotFoundText(lang, invoiceID), nil
+	case err != nil:
+		return "", validate.Wrap(op, err)
+	}
+
+	if !paid {
+		if err := attachInvoicePDF(ctx, deps, userID, inv); err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		return InvoiceText(lang, inv, now()), nil
+	}
+
+	at, amount, err := parseInvoicePayment(rest, now())
+	if err != nil {
+		if errors.Is(err, ErrFutureDate) {
+			return "", validate.Wrap(op, err)
+		}
+		return InvoiceUsageText(lang), nil
+	}
+
+	after, incomeID, err := deps.Invoices.PayInvoice(ctx, userID, invoiceID, at, amount, InvoicePaymentNote(lang, inv))
+
+	switch {
+	case errors.Is(err, domain.ErrInvoicePaid):
+		return InvoiceAlreadyPaidText(lang, invoiceID), nil
+	case errors.Is(err, domain.ErrOverpayment):
+		return InvoiceOverpaymentText(lang, inv), nil
+	case err != nil:
+		return "", validate.Wrap(op, err)
+	}
+
+	reply := InvoicePaidText(lang, after, incomeID, after.Paid-inv.Paid, at)
+
+	if deps.Limits == nil {
+		return reply, nil
+	}
+
+	// As after /add: the income is saved already, a failed check only drops the warnings.
+	ref := now().UTC()
+	if at.Year() < ref.Year() {
+		ref = time.Date(at.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	if limits, err := deps.Limits.Limits(ctx, userID, ref); err == nil {
+		reply += LimitWarningsText(lang, limits)
+	}
+
+	return reply, nil
+}
+
+// HandleInvoices lists the invoices of the user, newest first: /invoices [unpaid].
+func HandleInvoices(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleInvoices"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Invoices == nil {
+		return InvoiceDisabledText(lang), nil
+	}
+
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	var unpaid bool
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+	case "unpaid":
+		unpaid = true
+	default:
+		return InvoiceUsageText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	invoices, err := deps.Invoices.ListInvoices(ctx, userID, unpaid)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return InvoiceListText(lang, invoices, unpaid, now()), nil
+}
+
+// attachInvoicePDF attaches the invoice as a PDF if the transport can send files and
+// a renderer is configured.
+func attachInvoicePDF(ctx context.Context, deps *BotDeps, userID int64, inv domain.Invoice) error {
+	const op = "bot.attachInvoicePDF"
+
+	attach, ok := documentSlot(ctx)
+	if !ok || deps.Documents == nil {
+		return nil
+	}
+
+	seller, err := deps.Invoices.Requisites(ctx, userID)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	b, err := deps.Documents.InvoicePDF(inv, seller)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	attach(Document{Name: fmt.Sprintf("invoice-%d-%d.pdf", inv.IssuedOn.Year(), inv.Number), Data: b})
+
+	return nil
+}
+
+// parseNewInvoice parses "<client id> <amount> [due <date>] [description]"; the due
+// date may also follow "до".
+func parseNewInvoice(args string) (domain.Invoice, error) {
+	const op = "bot.parseNewInvoice"
+
+	idArg, rest, _ := strings.Cut(args, " ")
+
+	clientID, err := strconv.ParseInt(idArg, 10, 64)
+	if err != nil || clientID <= 0 {
+		return domain.Invoice{}, validate.Wrap(op, ErrBadInput)
+	}
+
+	amount, desc, err := ParseAmountAndNote(rest)
+	if err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	if err := validateEntryInput(amount, desc); err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	inv := domain.Invoice{Client: domain.Counterparty{ID: clientID}, Amount: amount}
+
+	if word, tail, _ := strings.Cut(desc, " "); strings.EqualFold(word, "due") || strings.EqualFold(word, "до") {
+		dateArg, tail, _ := strings.Cut(strings.TrimSpace(tail), " ")
+
+		due, ok := parseEntryDate(dateArg)
+		if !ok {
+			return domain.Invoice{}, validate.Wrap(op, ErrBadInput)
+		}
+
+		inv.DueOn, desc = due, strings.TrimSpace(tail)
+	}
+
+	inv.Description = desc
+
+	return inv, nil
+}
+
+// parseInvoicePayment parses "[date] [amount]" of /invoice paid: the day of now and
+// amount 0 (what is left) by default.
+func parseInvoicePayment(args string, now time.Time) (time.Time, int64, error) {
+	const op = "bot.parseInvoicePayment"
+
+	nowUTC := now.UTC()
+	today := time.Date(nowUTC.Year(), nowUTC.Month(), nowUTC.Day(), 0, 0, 0, 0, time.UTC)
+	at := today
+
+	if first, rest, _ := strings.Cut(args, " "); first != "" {
+		if d, ok := parseEntryDate(first); ok {
+			at, args = d, strings.TrimSpace(rest)
+		}
+	}
+
+	if at.After(today) {
+		return time.Time{}, 0, validate.Wrap(op, ErrFutureDate)
+	}
+
+	if args == "" {
+		return at, 0, nil
+	}
+
+	amount, err := money.ParseAmount(args)
+	if err != nil || amount <= 0 {
+		return time.Time{}, 0, validate.Wrap(op, ErrBadInput)
+	}
+
+	return at, amount, nil
+}
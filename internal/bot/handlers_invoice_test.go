@@ -0,0 +1,147 @@
+package bot_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+// stubRenderer renders documents as the INN of the seller, to check what the bot attaches.
+type stubRenderer struct{}
+
+func (stubRenderer) InvoicePDF(inv domain.Invoice, seller domain.Requisites) ([]byte, error) {
+	return []byte("%PDF invoice " + seller.INN), nil
+}
+
+func (stubRenderer) ActPDF(act domain.Act, seller domain.Requisites) ([]byte, error) {
+	return []byte("%PDF act " + seller.INN), nil
+}
+
+func (stubRenderer) ActDOCX(act domain.Act, seller domain.Requisites) ([]byte, error) {
+	return []byte("PK act " + seller.INN), nil
+}
+
+func TestHandleInvoice_IssueAndPayInParts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	deps, store := newTestDeps(withClock(&now), withInvoices())
+
+	ctx := domain.WithChatID(i18n.WithLang(context.Background(), i18n.EN), 777)
+
+	run := func(text string) string {
+		t.Helper()
+
+		reply, _, err := bot.DispatchCommand(ctx, text, "", "telegram", "1", deps)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		return reply
+	}
+
+	expect := func(text string, want ...string) {
+		t.Helper()
+
+		reply := run(text)
+		for _, w := range want {
+			if !strings.Contains(reply, w) {
+				t.Errorf("%s: reply lacks %q:\n%s", text, w, reply)
+			}
+		}
+	}
+
+	expect("/client add Acme LLC", "Client #1 added: Acme LLC")
+	expect("/client add acme llc", "already exists")
+	expect("/client set 1 inn 7707083893", "INN: 7707083893")
+	expect("/client set 1 inn 7707083894", "INN is invalid")
+	expect("/client set 2 inn 7707083893", "Client #2 not found")
+	expect("/clients", "• #1 Acme LLC, INN 7707083893")
+
+	// Without the requisites there is nothing to print on the invoice.
+	expect("/invoice new 1 1000", "Set your requisites first")
+
+	expect("/requisites account 40802810100000000001", "Account is invalid")
+	for _, cmd := range []string{
+		"/requisites name IP Ivanov",
+		"/requisites inn 500100732259",
+		"/requisites bank Sberbank",
+		"/requisites bik 044525225",
+	} {
+		expect(cmd, "To issue invoices, fill in:")
+	}
+	expect("/requisites account 40802810100000000001", "Account: 40802810100000000001")
+
+	expect("/invoice new 2 1000", "Client #2 not found")
+	expect("/invoice new 1 1000 due 2025-08-01", "cannot be before today")
+	expect("/invoice new 1 zero", "Usage:")
+
+	// A transport that sends files gets the PDF with the reply as its caption.
+	docCtx, documents := bot.WithDocumentSlot(ctx)
+
+	reply, _, err := bot.DispatchCommand(docCtx, "/invoice new 1 1 000 due 20.08.2025 Website", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("invoice new: %v", err)
+	}
+	for _, want := range []string{"Invoice #1 (No. 1 of Aug 10, 2025)", "Client: Acme LLC", "₽1,000.00", "Due: Aug 20, 2025", "Website", "/invoice paid 1"} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("invoice new: reply lacks %q:\n%s", want, reply)
+		}
+	}
+	if docs := documents(); len(docs) != 1 || docs[0].Name != "invoice-2025-1.pdf" || string(docs[0].Data) != "%PDF invoice 500100732259" {
+		t.Fatalf("documents = %+v", docs)
+	}
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reminders of overdue invoices go to the chat the invoice was issued in.
+	if chatID, ok, err := store.GetTelegramChatID(ctx, uid); err != nil || !ok || chatID != 777 {
+		t.Fatalf("GetTelegramChatID = %d, %v, %v; want 777", chatID, ok, err)
+	}
+
+	expect("/invoice paid 1 09.08.2025 400", "Payment of invoice #1 added as income #", "₽400.00", "Aug 9, 2025", "Paid ₽400.00, left ₽600.00")
+	expect("/invoice paid 1 700", "Only ₽600.00 is left to pay on invoice #1")
+
+	if _, _, err := bot.DispatchCommand(ctx, "/invoice paid 1 2025-08-11", "", "telegram", "1", deps); !errors.Is(err, bot.ErrFutureDate) {
+		t.Fatalf("future payment: err = %v, want ErrFutureDate", err)
+	}
+
+	now = now.AddDate(0, 0, 15)
+
+	expect("/invoices unpaid", "Unpaid invoices:", "• #1 No. 1 of Aug 10, 2025, Acme LLC: ₽1,000.00, ₽600.00 left, ⚠️ overdue since Aug 20, 2025")
+	expect("/invoice 1", "Paid ₽400.00, left ₽600.00", "Overdue: it was due Aug 20, 2025")
+
+	expect("/invoice paid 1", "₽600.00", "Paid in full")
+	expect("/invoice paid 1", "Invoice #1 is already paid in full")
+	expect("/invoices unpaid", "All invoices are paid")
+	expect("/invoices", "• #1 No. 1 of Aug 10, 2025, Acme LLC: ₽1,000.00 ✅")
+	expect("/invoice 9", "Invoice #9 not found")
+
+	// The payments are incomes with a note naming the invoice.
+	sum, err := store.SumIncomes(ctx, uid, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), now)
+	if err != nil || sum != 1_000_00 {
+		t.Fatalf("SumIncomes = %d, %v; want the whole invoice", sum, err)
+	}
+}
+
+func TestHandleInvoice_Disabled(t *testing.T) {
+	t.Parallel()
+
+	deps, _ := newTestDeps(withLangs())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	for _, cmd := range []string{"/invoice new 1 100", "/invoices", "/client add Acme", "/clients", "/requisites"} {
+		reply, _, err := bot.DispatchCommand(ctx, cmd, "", "telegram", "1", deps)
+		if err != nil || !strings.Contains(reply, "not configured") {
+			t.Errorf("%s = %q, %v; want disabled", cmd, reply, err)
+		}
+	}
+}
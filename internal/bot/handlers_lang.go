@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleLang shows or changes the reply language:
+// /lang, /lang ru|en, /lang auto (follow the transport again).
+func HandleLang(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
+	const op = "bot.HandleLang"
+
+	if deps.Langs == nil {
+		return LangDisabledText(i18n.FromContext(ctx)), nil
+	}
+
+	arg := strings.ToLower(strings.TrimSpace(args))
+
+	if arg == "" {
+		_, chosen := storedLang(ctx, deps, transport, externalID)
+		return LangCurrentText(i18n.FromContext(ctx), !chosen), nil
+	}
+
+	var lang i18n.Lang
+
+	if arg != "auto" {
+		l, ok := i18n.Parse(arg)
+		if !ok || string(l) != arg {
+			return LangUnknownText(i18n.FromContext(ctx)), nil
+		}
+		lang = l
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	if err := deps.Langs.SetUserLang(ctx, userID, string(lang)); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	if lang == "" {
+		return LangAutoText(langHint(ctx)), nil
+	}
+
+	return LangSetText(lang), nil
+}
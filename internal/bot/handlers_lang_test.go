@@ -0,0 +1,70 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+// dispatch resolves the language the way transports do and runs one command.
+func dispatch(t *testing.T, deps *bot.BotDeps, hint i18n.Lang, text string) string {
+	t.Helper()
+
+	ctx := bot.ResolveLang(context.Background(), deps, "telegram", "1", hint)
+
+	reply, _, err := bot.DispatchCommand(ctx, text, "", "telegram", "1", deps)
+	if err != nil {
+		return bot.ReplyForError(i18n.FromContext(ctx), err)
+	}
+
+	return reply
+}
+
+func TestResolveLang_HintThenOverride(t *testing.T) {
+	t.Parallel()
+
+	deps, _ := newTestDeps(withLangs())
+
+	if got := dispatch(t, deps, i18n.EN, "/add 1234.5 order"); !strings.HasPrefix(got, "✅ Income added: ₽1,234.50") {
+		t.Fatalf("english hint: %q", got)
+	}
+
+	if got := dispatch(t, deps, i18n.EN, "/lang ru"); got != bot.LangSetText(i18n.RU) {
+		t.Fatalf("/lang ru: %q", got)
+	}
+
+	// The explicit choice wins over the Telegram language.
+	if got := dispatch(t, deps, i18n.EN, "/add abc"); got != bot.BadAmountHintText(i18n.RU) {
+		t.Fatalf("after /lang ru: %q", got)
+	}
+
+	if got := dispatch(t, deps, i18n.EN, "/lang"); got != bot.LangCurrentText(i18n.RU, false) {
+		t.Fatalf("/lang: %q", got)
+	}
+
+	if got := dispatch(t, deps, i18n.EN, "/lang auto"); got != bot.LangAutoText(i18n.EN) {
+		t.Fatalf("/lang auto: %q", got)
+	}
+
+	if got := dispatch(t, deps, i18n.EN, "/lang"); got != bot.LangCurrentText(i18n.EN, true) {
+		t.Fatalf("/lang after auto: %q", got)
+	}
+}
+
+func TestHandleLang_Unknown(t *testing.T) {
+	t.Parallel()
+
+	deps, _ := newTestDeps(withLangs())
+
+	if got := dispatch(t, deps, "", "/lang de"); got != bot.LangUnknownText(i18n.RU) {
+		t.Fatalf("/lang de: %q", got)
+	}
+
+	deps.Langs = nil
+	if got := dispatch(t, deps, "", "/lang en"); got != bot.LangDisabledText(i18n.RU) {
+		t.Fatalf("disabled: %q", got)
+	}
+}
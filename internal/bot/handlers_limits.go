@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleLimits shows how much of the yearly income limits is used and the VAT rate that applies.
+func HandleLimits(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleLimits"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Limits == nil {
+		return LimitsDisabledText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	limits, err := deps.Limits.Limits(ctx, userID, now().UTC())
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return LimitsText(lang, limits), nil
+}
@@ -0,0 +1,143 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+)
+
+func addIncomes(t *testing.T, store *memstore.Store, uid int64, incomes map[time.Time]int64) {
+	t.Helper()
+
+	for at, amount := range incomes {
+		if _, err := store.InsertIncome(context.Background(), uid, at, amount, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHandleAdd_WarnsNearAndAboveVATThreshold(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addIncomes(t, store, uid, map[time.Time]int64{time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC): 47_000_000_00})
+
+	reply, _, err := bot.DispatchCommand(ctx, "/add 1000000", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if !strings.Contains(reply, "80% of the VAT threshold of ₽60,000,000.00 is used") {
+		t.Errorf("reply lacks the near warning:\n%s", reply)
+	}
+	if strings.Contains(reply, "USN limit") {
+		t.Errorf("unexpected USN limit warning:\n%s", reply)
+	}
+
+	reply, _, err = bot.DispatchCommand(ctx, "/add 13000000", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if !strings.Contains(reply, "exceeds the VAT threshold of ₽60,000,000.00") {
+		t.Errorf("reply lacks the crossed warning:\n%s", reply)
+	}
+
+	// An income of a past year is checked against that year, which had no VAT threshold.
+	reply, _, err = bot.DispatchCommand(ctx, "/add 2024-12-30 1000", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if strings.Contains(reply, "⚠️") || strings.Contains(reply, "⛔") {
+		t.Errorf("unexpected warning for 2024:\n%s", reply)
+	}
+}
+
+func TestHandleLimits_VATFromNextMonthAndHigherRate(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addIncomes(t, store, uid, map[time.Time]int64{
+		time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC): 70_000_000_00,  // above 60 mln: 5% from April
+		time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC): 1_050_000_00,   // VAT 50,000.00
+		time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC): 190_000_000_00, // VAT 9,047,619.04; above 250 mln: 7% from June
+		time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC): 1_070_000_00,   // VAT 70,000.00
+	})
+
+	reply, _, err := bot.DispatchCommand(ctx, "/limits", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("limits: %v", err)
+	}
+
+	for _, want := range []string{
+		"Limits for 2025",
+		"USN limit ₽450,000,000.00: 58% used, ₽187,880,000.00 left",
+		"VAT threshold ₽60,000,000.00: 436% used",
+		"VAT: 7% since Jun 1, 2025",
+		"exceeds ₽250,000,000.00: the higher VAT rate",
+	} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("limits reply lacks %q:\n%s", want, reply)
+		}
+	}
+
+	reply, _, err = bot.DispatchCommand(ctx, "/total", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("total: %v", err)
+	}
+
+	// Quarter 3 has no income yet; the year carries April and May at 5% and June at 7%.
+	if !strings.Contains(reply, "VAT 7%: ₽0.00") || !strings.Contains(reply, "VAT 7%: ₽9,167,619.04") {
+		t.Errorf("total reply lacks VAT:\n%s", reply)
+	}
+}
+
+func TestHandleLimits_PreviousYearMakesVATPayer(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addIncomes(t, store, uid, map[time.Time]int64{
+		time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC):  21_000_000_00, // above 2026's 20 mln threshold
+		time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC): 105_000_00,
+	})
+
+	reply, _, err := bot.DispatchCommand(ctx, "/limits", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("limits: %v", err)
+	}
+	if !strings.Contains(reply, "VAT: 5% since Jan 1, 2026") || strings.Contains(reply, "⚠️") {
+		t.Errorf("limits reply:\n%s", reply)
+	}
+
+	reply, _, err = bot.DispatchCommand(ctx, "/total", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("total: %v", err)
+	}
+	if !strings.Contains(reply, "VAT 5%: ₽5,000.00") {
+		t.Errorf("total reply lacks VAT:\n%s", reply)
+	}
+}
@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleLink issues a one-time code (no args) or redeems a code issued on another transport.
+func HandleLink(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
+	const op = "bot.HandleLink"
+
+	if deps.Links == nil {
+		return LinkDisabledText(i18n.FromContext(ctx)), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	code := strings.TrimSpace(args)
+
+	if code == "" {
+		code, expiresAt, err := deps.Links.IssueLinkCode(ctx, userID)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		return LinkCodeText(i18n.FromContext(ctx), code, expiresAt), nil
+	}
+
+	_, err = deps.Links.RedeemLinkCode(ctx, userID, transport, externalID, code)
+
+	switch {
+	case err == nil:
+		return LinkSuccessText(i18n.FromContext(ctx)), nil
+	case errors.Is(err, domain.ErrLinkCodeInvalid):
+		return LinkCodeInvalidText(i18n.FromContext(ctx)), nil
+	case errors.Is(err, domain.ErrAlreadyLinked):
+		return LinkAlreadyText(i18n.FromContext(ctx)), nil
+	case errors.Is(err, domain.ErrLinkHasEntries):
+		return LinkHasEntriesText(i18n.FromContext(ctx)), nil
+	default:
+		return "", validate.Wrap(op, err)
+	}
+}
+
+// HandleUnlink detaches the current identity (no args) or all identities of the given transport.
+func HandleUnlink(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
+	const op = "bot.HandleUnlink"
+
+	if deps.Links == nil {
+		return LinkDisabledText(i18n.FromContext(ctx)), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	target := strings.ToLower(strings.TrimSpace(args))
+	targetExternalID := ""
+
+	if target == "" {
+		target = transport
+		targetExternalID = externalID
+	}
+
+	// API access is bound to tokens; it is removed by revoking them.
+	if target == domain.TransportAPI {
+		return UnlinkAPIText(i18n.FromContext(ctx)), nil
+	}
+
+	n, err := deps.Links.Unlink(ctx, userID, target, targetExternalID)
+
+	switch {
+	case err == nil && n == 0:
+		return UnlinkNothingText(i18n.FromContext(ctx), target), nil
+	case err == nil:
+		return UnlinkSuccessText(i18n.FromContext(ctx), target, n), nil
+	case errors.Is(err, domain.ErrLastIdentity):
+		return UnlinkLastText(i18n.FromContext(ctx)), nil
+	default:
+		return "", validate.Wrap(op, err)
+	}
+}
@@ -0,0 +1,126 @@
+package bot_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+)
+
+var linkCodeRe = regexp.MustCompile(`/link ([A-Z0-9]{4}-[A-Z0-9]{4})`)
+
+func issueCode(t *testing.T, deps *bot.BotDeps, transport, externalID string) string {
+	t.Helper()
+
+	reply, err := bot.HandleLink(context.Background(), deps, transport, externalID, "")
+	if err != nil {
+		t.Fatalf("HandleLink issue: %v", err)
+	}
+
+	m := linkCodeRe.FindStringSubmatch(reply)
+	if m == nil {
+		t.Fatalf("no code in reply: %s", reply)
+	}
+
+	return m[1]
+}
+
+func TestHandleLink_RedeemSharesAccount(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := fixedNow()
+	deps, store := newTestDeps(withClock(&now), withLinks())
+
+	code := issueCode(t, deps, "telegram", "1")
+
+	// Lowercase and without the dash is accepted too.
+	reply, err := bot.HandleLink(ctx, deps, "cli", "local", "  "+code[:4]+code[5:]+" ")
+	if err != nil {
+		t.Fatalf("HandleLink redeem: %v", err)
+	}
+	if reply != bot.LinkSuccessText(i18n.RU) {
+		t.Fatalf("unexpected reply: %s", reply)
+	}
+
+	tgUser, _ := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	cliUser, _ := store.UpsertIdentity(ctx, "cli", "local", 0)
+	if tgUser != cliUser {
+		t.Fatalf("identities not linked: telegram=%d cli=%d", tgUser, cliUser)
+	}
+
+	// Single use.
+	reply, _ = bot.HandleLink(ctx, deps, "cli", "other", code)
+	if reply != bot.LinkCodeInvalidText(i18n.RU) {
+		t.Fatalf("reused code: %s", reply)
+	}
+}
+
+func TestHandleLink_Refusals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := fixedNow()
+	deps, _ := newTestDeps(withClock(&now), withLinks())
+
+	// Expired code.
+	code := issueCode(t, deps, "telegram", "1")
+	now = now.Add(service.LinkCodeTTL)
+	if reply, _ := bot.HandleLink(ctx, deps, "cli", "local", code); reply != bot.LinkCodeInvalidText(i18n.RU) {
+		t.Fatalf("expired code: %s", reply)
+	}
+
+	// Own code.
+	code = issueCode(t, deps, "telegram", "1")
+	if reply, _ := bot.HandleLink(ctx, deps, "telegram", "1", code); reply != bot.LinkAlreadyText(i18n.RU) {
+		t.Fatalf("own code: %s", reply)
+	}
+
+	// Redeeming account already has entries.
+	if _, err := bot.HandleAdd(ctx, deps, "cli", "busy", "100"); err != nil {
+		t.Fatalf("HandleAdd: %v", err)
+	}
+	if reply, _ := bot.HandleLink(ctx, deps, "cli", "busy", code); reply != bot.LinkHasEntriesText(i18n.RU) {
+		t.Fatalf("account with entries: %s", reply)
+	}
+}
+
+func TestHandleUnlink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := fixedNow()
+	deps, store := newTestDeps(withClock(&now), withLinks())
+
+	// A lone identity cannot be unlinked.
+	if reply, _ := bot.HandleUnlink(ctx, deps, "telegram", "1", ""); reply != bot.UnlinkLastText(i18n.RU) {
+		t.Fatalf("last identity: %s", reply)
+	}
+
+	code := issueCode(t, deps, "telegram", "1")
+	if _, err := bot.HandleLink(ctx, deps, "cli", "local", code); err != nil {
+		t.Fatalf("HandleLink: %v", err)
+	}
+
+	tgUser, _ := store.UpsertIdentity(ctx, "telegram", "1", 0)
+
+	reply, err := bot.HandleUnlink(ctx, deps, "telegram", "1", "cli")
+	if err != nil {
+		t.Fatalf("HandleUnlink: %v", err)
+	}
+	if reply != bot.UnlinkSuccessText(i18n.RU, "cli", 1) {
+		t.Fatalf("unexpected reply: %s", reply)
+	}
+
+	// The CLI identity now resolves to a fresh account.
+	if cliUser, _ := store.UpsertIdentity(ctx, "cli", "local", 0); cliUser == tgUser {
+		t.Fatalf("cli identity is still linked to user %d", tgUser)
+	}
+
+	if reply, _ := bot.HandleUnlink(ctx, deps, "telegram", "1", "api"); reply != bot.UnlinkAPIText(i18n.RU) {
+		t.Fatalf("api unlink: %s", reply)
+	}
+}
@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/document"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/money"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// HandlePay prepares the payment order of the single tax payment (ЕНП) for a quarter:
+//
+//	/pay [2025-q2 | q2] [amount]
+//
+// Without a quarter it is the current one, without an amount the tax due for it as
+// /total shows. Transports that can send files get the order as a 1C file to import
+// into a bank app and as a payment QR code to scan.
+func HandlePay(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandlePay"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.TaxPayments == nil {
+		return PayDisabledText(lang), nil
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	today := period.Day(now())
+
+	ref, amount, ok := parsePayArgs(args, today)
+	if !ok {
+		return PayUsageText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	order, err := deps.TaxPayments.PaymentOrder(ctx, userID, ref, amount)
+
+	switch {
+	case errors.Is(err, domain.ErrRequisitesMissing):
+		return PayNoRequisitesText(lang), nil
+	case err != nil:
+		return "", validate.Wrap(op, err)
+	}
+
+	if order.Amount == 0 {
+		return PayNothingDueText(lang, order.Year, order.Quarter), nil
+	}
+
+	if attach, ok := documentSlot(ctx); ok {
+		qr, err := document.PaymentQRPNG(order)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		name := fmt.Sprintf("enp-%d-q%d", order.Year, order.Quarter)
+
+		attach(Document{Name: name + ".txt", Data: document.PaymentOrder1C(order, now())})
+		attach(Document{Name: name + ".png", Data: qr})
+	}
+
+	return PayText(lang, order), nil
+}
+
+// parsePayArgs reads "[quarter] [amount]" and returns the last day of the quarter, which
+// must have begun by today, and the amount (0 if none is given).
+func parsePayArgs(args string, today time.Time) (ref time.Time, amount int64, ok bool) {
+	_, ref = period.QuarterBounds(today)
+
+	toks := strings.Fields(strings.ToLower(args))
+
+	if len(toks) > 0 {
+		if from, to, isQuarter := parsePayQuarter(toks[0], today.Year()); isQuarter {
+			if from.After(today) {
+				return time.Time{}, 0, false
+			}
+			ref, toks = to, toks[1:]
+		}
+	}
+
+	if len(toks) > 0 {
+		v, err := money.ParseAmount(strings.Join(toks, " "))
+		if err != nil || v <= 0 {
+			return time.Time{}, 0, false
+		}
+		amount = v
+	}
+
+	return ref, amount, true
+}
+
+// parsePayQuarter parses a quarter of a year (2025-q2) or of the current year (q2).
+func parsePayQuarter(s string, year int) (from, to time.Time, ok bool) {
+	if y, q, found := strings.Cut(s, "-"); found {
+		v, err := strconv.Atoi(y)
+		if err != nil || len(y) != 4 || v < 1970 {
+			return time.Time{}, time.Time{}, false
+		}
+		year, s = v, q
+	}
+
+	if len(s) != 2 || s[0] != 'q' || s[1] < '1' || s[1] > '4' {
+		return time.Time{}, time.Time{}, false
+	}
+
+	from, to = period.QuarterBounds(time.Date(year, time.Month(int(s[1]-'0')*3), 1, 0, 0, 0, 0, time.UTC))
+
+	return from, to, true
+}
@@ -0,0 +1,92 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+func TestHandlePay_OrderForQuarter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	deps, store := newTestDeps(withClock(&now), withInvoices())
+
+	income := service.NewIncomeService(store)
+	payment := service.NewPaymentService(store)
+	total := service.NewTotalService(store.GetUserScheme, income.SumIncomes, payment.SumPayments, tax.NewDefaultProvider())
+
+	deps.Income, deps.Payment, deps.Total = income, payment, total
+	deps.TaxPayments = service.NewTaxPaymentService(store, total, deps.Now)
+
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	run := func(ctx context.Context, text string) string {
+		t.Helper()
+
+		reply, _, err := bot.DispatchCommand(ctx, text, "", "telegram", "1", deps)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		return reply
+	}
+
+	expect := func(text string, want ...string) {
+		t.Helper()
+
+		reply := run(ctx, text)
+		for _, w := range want {
+			if !strings.Contains(reply, w) {
+				t.Errorf("%s: reply lacks %q:\n%s", text, w, reply)
+			}
+		}
+	}
+
+	expect("/pay q2", "Set your name, INN, bank, BIK and account first")
+
+	for _, cmd := range []string{
+		"/requisites name IP Ivanov",
+		"/requisites inn 500100732259",
+		"/requisites bank Sberbank",
+		"/requisites bik 044525225",
+		"/requisites account 40802810100000000001",
+	} {
+		run(ctx, cmd)
+	}
+
+	expect("/pay", "No tax is due for Q3 2025")
+	expect("/pay q4", "Usage:")
+	expect("/pay 2025-q5", "Usage:")
+	expect("/pay q2 zero", "Usage:")
+
+	run(ctx, "/add 2025-05-10 100000 Website")
+	run(ctx, "/add_advance 2025-06-20 1000")
+
+	// Tax of the quarter 6 000 less the advance paid in it.
+	docCtx, documents := bot.WithDocumentSlot(ctx)
+
+	reply := run(docCtx, "/pay 2025-q2")
+	for _, want := range []string{"ENP) for Q2 2025", "₽5,000.00", "INN 7727406020", "03100643000000018500", "KBK 18201061201010000510",
+		"Единый налоговый платеж за 2 квартал 2025 г."} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("pay: reply lacks %q:\n%s", want, reply)
+		}
+	}
+
+	docs := documents()
+	if len(docs) != 2 || docs[0].Name != "enp-2025-q2.txt" || docs[1].Name != "enp-2025-q2.png" {
+		t.Fatalf("documents = %+v", docs)
+	}
+	if !strings.Contains(string(docs[0].Data), "5000.00") || !strings.HasPrefix(string(docs[1].Data), "\x89PNG") {
+		t.Errorf("files do not hold the order of 5 000")
+	}
+
+	// An amount pays that much, even when nothing is due.
+	expect("/pay q3 1 500,50", "Q3 2025", "₽1,500.50")
+}
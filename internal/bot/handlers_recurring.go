@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/money"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleRecurring manages recurring incomes of the user:
+//
+//	/recurring add <amount> monthly|weekly on <day> [note] [ask]   — schedule an income
+//	/recurring [list]                                              — list rules
+//	/recurring pause|resume|delete <id>                            — manage a rule
+//	/recurring confirm|skip <id>                                   — resolve incomes waiting for confirmation
+//
+// The chat of the command is remembered so that the scheduler can report to it.
+func HandleRecurring(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
+	const op = "bot.HandleRecurring"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Recurring == nil {
+		return RecurringDisabledText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, domain.ChatIDFrom(ctx))
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	sub, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	sub = strings.ToLower(sub)
+	rest = strings.TrimSpace(rest)
+
+	if sub == "" || sub == "list" {
+		rules, err := deps.Recurring.ListRules(ctx, userID)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		return RecurringListText(lang, rules), nil
+	}
+
+	if sub == "add" {
+		rule, err := parseRecurringRule(rest)
+		if err != nil {
+			return RecurringUsageText(lang), nil
+		}
+
+		rule, err = deps.Recurring.AddRule(ctx, userID, rule)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		return RecurringAddedText(lang, rule), nil
+	}
+
+	ruleID, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || ruleID <= 0 {
+		return RecurringUsageText(lang), nil
+	}
+
+	var reply string
+
+	switch sub {
+	case "pause":
+		err = deps.Recurring.PauseRule(ctx, userID, ruleID)
+		reply = RecurringPausedText(lang, ruleID)
+	case "resume":
+		var rule domain.RecurringRule
+		rule, err = deps.Recurring.ResumeRule(ctx, userID, ruleID)
+		reply = RecurringResumedText(lang, rule)
+	case "delete":
+		err = deps.Recurring.DeleteRule(ctx, userID, ruleID)
+		reply = RecurringDeletedText(lang, ruleID)
+	case "confirm", "skip":
+		var runs []domain.RecurringRun
+		if sub == "confirm" {
+			runs, err = deps.Recurring.ConfirmRuns(ctx, userID, ruleID)
+		} else {
+			runs, err = deps.Recurring.SkipRuns(ctx, userID, ruleID)
+		}
+		reply = RecurringResolvedText(lang, ruleID, sub == "confirm", runs)
+	default:
+		return RecurringUsageText(lang), nil
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrEntryNotFound):
+		return RecurringNotFoundText(lang, ruleID), nil
+	case err != nil:
+		return "", validate.Wrap(op, err)
+	}
+
+	return reply, nil
+}
+
+// weekdays maps day names accepted by /recurring to ISO numbers (1 = Monday).
+var weekdays = map[string]int{
+	"mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6, "sun": 7,
+	"пн": 1, "вт": 2, "ср": 3, "чт": 4, "пт": 5, "сб": 6, "вс": 7,
+}
+
+// parseRecurringRule parses "<amount> monthly|weekly [on] <day> [note] [ask]".
+// The day is 1..31 for monthly and 1..7 or mon..sun for weekly; a leading "@" of the
+// note (a client, as in "@acme") is dropped, and a trailing "ask" asks before each income.
+func parseRecurringRule(args string) (domain.RecurringRule, error) {
+	const op = "bot.parseRecurringRule"
+
+	toks := strings.Fields(args)
+
+	at := -1
+	for i, tok := range toks {
+		if p := domain.RecurringPeriod(strings.ToLower(tok)); p == domain.RecurringMonthly || p == domain.RecurringWeekly {
+			at = i
+			break
+		}
+	}
+
+	if at < 1 || at+1 >= len(toks) {
+		return domain.RecurringRule{}, validate.Wrap(op, ErrBadInput)
+	}
+
+	amount, err := money.ParseAmount(strings.Join(toks[:at], " "))
+	if err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, ErrBadInput)
+	}
+
+	rule := domain.RecurringRule{Amount: amount, Period: domain.RecurringPeriod(strings.ToLower(toks[at]))}
+
+	rest := toks[at+1:]
+	if strings.EqualFold(rest[0], "on") {
+		rest = rest[1:]
+	}
+
+	if len(rest) == 0 {
+		return domain.RecurringRule{}, validate.Wrap(op, ErrBadInput)
+	}
+
+	maxDay := 31
+	if rule.Period == domain.RecurringWeekly {
+		maxDay = 7
+	}
+
+	day, err := strconv.Atoi(rest[0])
+	if wd, ok := weekdays[strings.ToLower(rest[0])]; ok && rule.Period == domain.RecurringWeekly {
+		day, err = wd, nil
+	}
+
+	if err != nil || day < 1 || day > maxDay {
+		return domain.RecurringRule{}, validate.Wrap(op, ErrBadInput)
+	}
+
+	rule.Day = day
+	rest = rest[1:]
+
+	if n := len(rest); n > 0 && strings.EqualFold(rest[n-1], "ask") {
+		rule.Confirm = true
+		rest = rest[:n-1]
+	}
+
+	rule.Note = strings.TrimPrefix(strings.Join(rest, " "), "@")
+
+	if err := validateEntryInput(rule.Amount, rule.Note); err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, err)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,140 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+)
+
+func TestHandleRecurring_AddRunConfirm(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	deps, store := newTestDeps(withClock(&now), withRecurring())
+	recurring := deps.Recurring.(*service.RecurringService)
+
+	ctx := domain.WithChatID(i18n.WithLang(context.Background(), i18n.EN), 777)
+
+	reply, _, err := bot.DispatchCommand(ctx, "/recurring add 50000 monthly on 5 @client", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if !strings.Contains(reply, "monthly on day 5") || !strings.Contains(reply, "client") || !strings.Contains(reply, "Sep 5, 2025") {
+		t.Fatalf("add reply: %q", reply)
+	}
+
+	if _, _, err := bot.DispatchCommand(ctx, "/recurring add 1000 weekly on fri ask", "", "telegram", "1", deps); err != nil {
+		t.Fatalf("add weekly: %v", err)
+	}
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The chat is remembered for the scheduler's messages.
+	if chatID, ok, err := store.GetTelegramChatID(ctx, uid); err != nil || !ok || chatID != 777 {
+		t.Fatalf("GetTelegramChatID = %d, %v, %v; want 777", chatID, ok, err)
+	}
+
+	// Down for two months: both dates of the monthly rule and every Friday are caught up once.
+	now = time.Date(2025, 10, 6, 9, 0, 0, 0, time.UTC)
+
+	runs, err := recurring.RunDue(ctx)
+	if err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+
+	created, pending := 0, 0
+	for _, r := range runs {
+		switch r.Status {
+		case domain.RecurringCreated:
+			created++
+		case domain.RecurringPending:
+			pending++
+		}
+	}
+	if created != 2 || pending != 8 {
+		t.Fatalf("RunDue: %d created, %d pending; want 2, 8", created, pending)
+	}
+
+	if again, err := recurring.RunDue(ctx); err != nil || len(again) != 0 {
+		t.Fatalf("RunDue again = %+v, %v; want nothing", again, err)
+	}
+
+	if got := dispatch(t, deps, i18n.EN, "/recurring confirm 2"); got != bot.RecurringResolvedText(i18n.EN, 2, true, make([]domain.RecurringRun, 8)) {
+		t.Fatalf("confirm: %q", got)
+	}
+
+	sum, err := store.SumIncomes(ctx, uid, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), now)
+	if err != nil || sum != 2*5000000+8*100000 {
+		t.Fatalf("SumIncomes = %d, %v", sum, err)
+	}
+
+	if got := dispatch(t, deps, i18n.EN, "/recurring skip 2"); got != bot.RecurringResolvedText(i18n.EN, 2, false, nil) {
+		t.Fatalf("skip with nothing pending: %q", got)
+	}
+}
+
+func TestHandleRecurring_Manage(t *testing.T) {
+	t.Parallel()
+
+	now := fixedNow()
+	deps, _ := newTestDeps(withClock(&now), withRecurring())
+
+	if got := dispatch(t, deps, i18n.EN, "/recurring"); got != bot.RecurringListText(i18n.EN, nil) {
+		t.Fatalf("empty list: %q", got)
+	}
+
+	dispatch(t, deps, i18n.EN, "/recurring add 100 weekly on 1")
+
+	if got := dispatch(t, deps, i18n.EN, "/recurring pause 1"); got != bot.RecurringPausedText(i18n.EN, 1) {
+		t.Fatalf("pause: %q", got)
+	}
+	if got := dispatch(t, deps, i18n.EN, "/recurring list"); !strings.Contains(got, "paused") {
+		t.Fatalf("list after pause: %q", got)
+	}
+	if got := dispatch(t, deps, i18n.EN, "/recurring resume 1"); !strings.Contains(got, "Aug 11, 2025") {
+		t.Fatalf("resume: %q", got)
+	}
+	if got := dispatch(t, deps, i18n.EN, "/recurring delete 1"); got != bot.RecurringDeletedText(i18n.EN, 1) {
+		t.Fatalf("delete: %q", got)
+	}
+	if got := dispatch(t, deps, i18n.EN, "/recurring delete 1"); got != bot.RecurringNotFoundText(i18n.EN, 1) {
+		t.Fatalf("delete again: %q", got)
+	}
+}
+
+func TestHandleRecurring_Usage(t *testing.T) {
+	t.Parallel()
+
+	now := fixedNow()
+	deps, _ := newTestDeps(withClock(&now), withRecurring())
+
+	for _, text := range []string{
+		"/recurring add",
+		"/recurring add 100",
+		"/recurring add 100 monthly",
+		"/recurring add 100 monthly on 32",
+		"/recurring add 100 weekly on 8",
+		"/recurring add 100 monthly on mon",
+		"/recurring add 0 monthly on 5",
+		"/recurring pause",
+		"/recurring frobnicate 1",
+	} {
+		if got := dispatch(t, deps, i18n.EN, text); got != bot.RecurringUsageText(i18n.EN) {
+			t.Errorf("%q: %q", text, got)
+		}
+	}
+
+	deps.Recurring = nil
+	if got := dispatch(t, deps, i18n.EN, "/recurring"); got != bot.RecurringDisabledText(i18n.EN) {
+		t.Errorf("disabled: %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// HandleReport replies with a table of the income of a period per month or ISO week:
+//
+//	/report [month|week] [2025 | 2025-q2 | 2025-03]
+//
+// Without a period it covers the months of the current year or the weeks of the current
+// quarter; a period that is not over yet ends today.
+func HandleReport(ctx context.Context, deps *BotDeps, transport, externalID, args string) (string, error) {
+	const op = "bot.HandleReport"
+
+	lang := i18n.FromContext(ctx)
+
+	if deps.Report == nil {
+		return ReportDisabledText(lang), nil
+	}
+
+	// Clock (UTC)
+	now := time.Now
+	if deps.Now != nil {
+		now = deps.Now
+	}
+
+	today := period.Day(now())
+
+	bucket, from, to, ok := parseReportArgs(args, today)
+	if !ok {
+		return ReportUsageText(lang), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	report, err := deps.Report.Report(ctx, userID, bucket, from, to)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return ReportText(lang, report), nil
+}
+
+// parseReportArgs reads "[month|week] [period]" in any order. The period is cut at today;
+// periods that start after today are rejected.
+func parseReportArgs(args string, today time.Time) (bucket domain.ReportBucket, from, to time.Time, ok bool) {
+	var periodSet bool
+
+	for _, tok := range strings.Fields(strings.ToLower(args)) {
+		switch {
+		case (tok == string(domain.BucketMonth) || tok == string(domain.BucketWeek)) && bucket == "":
+			bucket = domain.ReportBucket(tok)
+		case !periodSet:
+			if from, to, ok = parseReportPeriod(tok); !ok {
+				return "", time.Time{}, time.Time{}, false
+			}
+			periodSet = true
+		default:
+			return "", time.Time{}, time.Time{}, false
+		}
+	}
+
+	if bucket == "" {
+		bucket = domain.BucketMonth
+	}
+
+	if !periodSet {
+		from, to = period.YearBounds(today)
+		if bucket == domain.BucketWeek {
+			from, to = period.QuarterBounds(today)
+		}
+	}
+
+	if from.After(today) {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	return bucket, from, period.Day(minTime(to, today)), true
+}
+
+// parseReportPeriod parses a year (2025), a quarter (2025-q2) or a month (2025-03).
+func parseReportPeriod(s string) (from, to time.Time, ok bool) {
+	year, rest, _ := strings.Cut(s, "-")
+
+	y, err := strconv.Atoi(year)
+	if err != nil || len(year) != 4 || y < 1970 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	switch {
+	case rest == "":
+		from = time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, -1), true
+
+	case len(rest) == 2 && rest[0] == 'q':
+		q, err := strconv.Atoi(rest[1:])
+		if err != nil || q < 1 || q > 4 {
+			return time.Time{}, time.Time{}, false
+		}
+		from, to = period.QuarterBounds(time.Date(y, time.Month(q*3), 1, 0, 0, 0, 0, time.UTC))
+		return from, to, true
+
+	case len(rest) == 2:
+		m, err := strconv.Atoi(rest)
+		if err != nil || m < 1 || m > 12 {
+			return time.Time{}, time.Time{}, false
+		}
+		from, to = period.MonthBounds(time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.UTC))
+		return from, to, true
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
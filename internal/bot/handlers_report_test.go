@@ -0,0 +1,88 @@
+package bot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+func TestHandleReport_MonthAndWeekTables(t *testing.T) {
+	t.Parallel()
+
+	deps, store := newTestDeps(withNow(time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)), withTaxTotals())
+	deps.Report = service.NewReportService(store, tax.NewDefaultProvider())
+
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	uid, err := store.UpsertIdentity(ctx, "telegram", "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addIncomes(t, store, uid, map[time.Time]int64{
+		time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC):  10_000_00,
+		time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC): 30_000_00,
+		time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC): 5_000_50,
+	})
+
+	// Months of the current year, up to today.
+	reply, _, err := bot.DispatchCommand(ctx, "/report", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+
+	for _, want := range []string{
+		"Income by month, Jan 1, 2025 – Mar 12, 2025",
+		"January   2  40,000  20,000  30,000  2,400",
+		"February  0       –       –       –      –",
+		"March     1   5,000   5,000   5,000    300",
+		"Total     3  45,000  15,000  30,000  2,700",
+	} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("reply lacks %q:\n%s", want, reply)
+		}
+	}
+	if strings.Contains(reply, "April") {
+		t.Errorf("reply runs past today:\n%s", reply)
+	}
+
+	// Weeks of January: ISO week 1 is cut to the period and starts on January 1.
+	reply, _, err = bot.DispatchCommand(ctx, "/report week 2025-01", "", "telegram", "1", deps)
+	if err != nil {
+		t.Fatalf("report week: %v", err)
+	}
+
+	for _, want := range []string{
+		"Income by week, Jan 1, 2025 – Jan 31, 2025",
+		"W01 Jan 1   1  10,000",
+		"W04 Jan 20  1  30,000",
+		"W05 Jan 27  0",
+	} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("reply lacks %q:\n%s", want, reply)
+		}
+	}
+
+	reply, _, err = bot.DispatchCommand(ctx, "/report 2024-q4", "", "telegram", "1", deps)
+	if err != nil || !strings.Contains(reply, "No income from Oct 1, 2024 to Dec 31, 2024") {
+		t.Errorf("empty quarter: %q, %v", reply, err)
+	}
+
+	for _, args := range []string{"2025-q5", "2025-13", "day", "2025-04", "month week", "2024 2025"} {
+		reply, _, err := bot.DispatchCommand(ctx, "/report "+args, "", "telegram", "1", deps)
+		if err != nil || !strings.Contains(reply, "Usage: /report") {
+			t.Errorf("/report %s = %q, %v; want usage", args, reply, err)
+		}
+	}
+
+	deps.Report = nil
+	if reply, _, _ := bot.DispatchCommand(ctx, "/report", "", "telegram", "1", deps); !strings.Contains(reply, "not configured") {
+		t.Errorf("disabled: %q", reply)
+	}
+}
@@ -1,10 +1,13 @@
 package bot
 
-import "context"
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
 
 // HandleStart returns the response text for the /start command.
 // Transport-agnostic; the router/runner is responsible for delivery.
 func HandleStart(ctx context.Context) string {
-	_ = ctx // reserved for future use (timeouts, locale, etc.)
-	return StartText()
+	return StartText(i18n.FromContext(ctx))
 }
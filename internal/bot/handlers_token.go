@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// HandleToken manages API tokens of the user:
+//
+//	/token [name]            — issue a new token
+//	/token list              — list active tokens
+//	/token revoke <id|all>   — revoke one or all tokens
+func HandleToken(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
+	const op = "bot.HandleToken"
+
+	if deps.Tokens == nil {
+		return TokenDisabledText(i18n.FromContext(ctx)), nil
+	}
+
+	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
+
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	args = strings.TrimSpace(args)
+	sub, rest, _ := strings.Cut(args, " ")
+	rest = strings.TrimSpace(rest)
+
+	switch strings.ToLower(sub) {
+	case "list":
+		tokens, err := deps.Tokens.ListTokens(ctx, userID)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		return TokenListText(i18n.FromContext(ctx), tokens), nil
+	case "revoke":
+		if strings.EqualFold(rest, "all") {
+			n, err := deps.Tokens.RevokeAllTokens(ctx, userID)
+			if err != nil {
+				return "", validate.Wrap(op, err)
+			}
+
+			return TokenRevokedAllText(i18n.FromContext(ctx), n), nil
+		}
+
+		tokenID, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil || tokenID <= 0 {
+			return TokenUsageText(i18n.FromContext(ctx)), nil
+		}
+
+		ok, err := deps.Tokens.RevokeToken(ctx, userID, tokenID)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		if !ok {
+			return TokenNotFoundText(i18n.FromContext(ctx), tokenID), nil
+		}
+
+		return TokenRevokedText(i18n.FromContext(ctx), tokenID), nil
+	}
+
+	// Anything else is the name of a new token.
+	token, id, err := deps.Tokens.IssueToken(ctx, userID, args)
+	if err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return TokenIssuedText(i18n.FromContext(ctx), id, token), nil
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
 )
@@ -44,6 +45,7 @@ func HandleTotal(ctx context.Context, deps *BotDeps, transport, externalID, args
 	}
 
 	return TotalText(
+		i18n.FromContext(ctx),
 		QuarterTotals.IncomeSum,
 		QuarterTotals.Tax,
 		QuarterTotals.From,
@@ -53,5 +55,8 @@ func HandleTotal(ctx context.Context, deps *BotDeps, transport, externalID, args
 		YearToDateTotals.ContribSum,
 		YearToDateTotals.AdvanceSum,
 		year, quarter,
+		YearToDateTotals.VATRateBP,
+		QuarterTotals.VAT,
+		YearToDateTotals.VAT,
 	), nil
 }
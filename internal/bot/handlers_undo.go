@@ -2,16 +2,28 @@ package bot
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
+// HandleUndo voids the last income of the quarter, or with an ID (as /find shows it)
+// that income whatever its date:
+//
+//	/undo [#id]
 func HandleUndo(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
 	const op = "bot.HandleUndo"
 
-	_ = strings.TrimSpace(args) // args
+	lang := i18n.FromContext(ctx)
+
+	id, byID, ok := parseUndoArgs(args)
+	if !ok {
+		return UndoBadIDText(lang), nil
+	}
 
 	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
 
@@ -27,6 +39,19 @@ func HandleUndo(ctx context.Context, deps *BotDeps, transport, externalID string
 
 	nowUTC := now().UTC()
 
+	if byID {
+		amount, at, note, found, err := deps.Income.UndoIncome(ctx, userID, id, nowUTC)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		if !found {
+			return EntryNotFoundText(lang, domain.EntryKindIncome, id), nil
+		}
+
+		return UndoSuccessText(lang, amount, at, note), nil
+	}
+
 	amount, at, note, ok, err := deps.Income.UndoLastQuarter(ctx, userID, nowUTC)
 
 	if err != nil {
@@ -34,8 +59,31 @@ func HandleUndo(ctx context.Context, deps *BotDeps, transport, externalID string
 	}
 
 	if !ok {
-		return UndoNoIncomeText(), nil
+		return UndoNoIncomeText(lang), nil
+	}
+
+	return UndoSuccessText(lang, amount, at, note), nil
+}
+
+// parseUndoArgs reads the optional entry ID of the undo commands. byID=false without one;
+// ok=false if the arguments are not a single ID.
+func parseUndoArgs(args string) (id int64, byID, ok bool) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return 0, false, true
+	}
+
+	id, ok = parseEntryID(args)
+
+	return id, ok, ok
+}
+
+// parseEntryID reads an entry ID as /find shows it, "#42", or without the "#".
+func parseEntryID(s string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
 	}
 
-	return UndoSuccessText(amount, at, note), nil
+	return id, true
 }
@@ -2,17 +2,26 @@ package bot
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
+// HandleUndoContrib voids the last contribution of the year, or with an ID (as /find shows it)
+// that contribution whatever its date:
+//
+//	/undo_contrib [#id]
 func HandleUndoContrib(ctx context.Context, deps *BotDeps, transport, externalID string, args string) (string, error) {
 	const op = "bot.HandleUndoContrib"
 
-	_ = strings.TrimSpace(args) // args
+	lang := i18n.FromContext(ctx)
+
+	id, byID, ok := parseUndoArgs(args)
+	if !ok {
+		return UndoBadIDText(lang), nil
+	}
 
 	userID, err := deps.Identities.UpsertIdentity(ctx, transport, externalID, 0)
 
@@ -28,6 +37,19 @@ func HandleUndoContrib(ctx context.Context, deps *BotDeps, transport, externalID
 
 	nowUTC := now().UTC()
 
+	if byID {
+		amount, at, note, found, err := deps.Payment.UndoPayment(ctx, userID, id, nowUTC, domain.PaymentTypeContrib)
+		if err != nil {
+			return "", validate.Wrap(op, err)
+		}
+
+		if !found {
+			return EntryNotFoundText(lang, domain.EntryKindContrib, id), nil
+		}
+
+		return UndoContribSuccessText(lang, amount, at, note), nil
+	}
+
 	amount, at, note, _, ok, err := deps.Payment.UndoLastYear(ctx, userID, nowUTC, domain.PaymentTypeContrib)
 
 	if err != nil {
@@ -35,8 +57,8 @@ func HandleUndoContrib(ctx context.Context, deps *BotDeps, transport, externalID
 	}
 
 	if !ok {
-		return UndoNoContribText(), nil
+		return UndoNoContribText(lang), nil
 	}
 
-	return UndoContribSuccessText(amount, at, note), nil
+	return UndoContribSuccessText(lang, amount, at, note), nil
 }
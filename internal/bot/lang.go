@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+type langHintCtxKey struct{}
+
+// ResolveLang returns ctx carrying the reply language for (transport, externalID):
+// the one chosen with /lang, otherwise hint (e.g. Telegram's language_code), otherwise
+// i18n.Default. Transports call it before DispatchCommand/DispatchEdit and use
+// i18n.FromContext on the result for ReplyForError.
+// A failed lookup is not fatal: the reply just falls back to the hint.
+func ResolveLang(ctx context.Context, deps *BotDeps, transport, externalID string, hint i18n.Lang) context.Context {
+	ctx = context.WithValue(ctx, langHintCtxKey{}, hint)
+
+	lang := hint
+	if stored, ok := storedLang(ctx, deps, transport, externalID); ok {
+		lang = stored
+	}
+
+	return i18n.WithLang(ctx, lang)
+}
+
+// storedLang returns the language chosen with /lang, if any.
+func storedLang(ctx context.Context, deps *BotDeps, transport, externalID string) (i18n.Lang, bool) {
+	if deps == nil || deps.Langs == nil {
+		return "", false
+	}
+
+	stored, err := deps.Langs.GetIdentityLang(ctx, transport, externalID)
+	if err != nil {
+		return "", false
+	}
+
+	return i18n.Parse(stored)
+}
+
+// langHint returns the transport language passed to ResolveLang, or i18n.Default.
+func langHint(ctx context.Context) i18n.Lang {
+	if l, ok := ctx.Value(langHintCtxKey{}).(i18n.Lang); ok && l != "" {
+		return l
+	}
+
+	return i18n.Default
+}
@@ -3,6 +3,7 @@ package bot
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/money"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
@@ -110,6 +111,49 @@ func ParseAmountAndNote(args string) (amount int64, note string, err error) {
 	return amountValue, note, nil
 }
 
+// entryDateLayouts are the accepted forms of an explicit entry date.
+var entryDateLayouts = []string{"2006-01-02", "02.01.2006"}
+
+// ParseEntryArgs parses "[date] amount [note]" as accepted by /add, /add_contrib and /add_advance.
+// The optional leading date is YYYY-MM-DD or DD.MM.YYYY; without it, defaultAt is returned.
+// A date later than the day of now is rejected with ErrFutureDate.
+func ParseEntryArgs(args string, defaultAt, now time.Time) (amount int64, at time.Time, note string, err error) {
+	const op = "bot.ParseEntryArgs"
+
+	at = defaultAt.UTC()
+	args = strings.TrimSpace(args)
+
+	if first, rest, _ := strings.Cut(args, " "); first != "" {
+		if d, ok := parseEntryDate(first); ok {
+			at, args = d, rest
+		}
+	}
+
+	amount, note, err = ParseAmountAndNote(args)
+	if err != nil {
+		return 0, time.Time{}, "", validate.Wrap(op, err)
+	}
+
+	nowUTC := now.UTC()
+	today := time.Date(nowUTC.Year(), nowUTC.Month(), nowUTC.Day(), 0, 0, 0, 0, time.UTC)
+
+	if !at.Before(today.AddDate(0, 0, 1)) {
+		return 0, time.Time{}, "", validate.Wrap(op, ErrFutureDate)
+	}
+
+	return amount, at, note, nil
+}
+
+// parseEntryDate parses a date in one of entryDateLayouts.
+func parseEntryDate(s string) (time.Time, bool) {
+	for _, layout := range entryDateLayouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
 // isCurrencyToken checks if a token looks like a currency token
 func isCurrencyToken(token string) bool {
 	token = strings.ToLower(strings.TrimSpace(token))
@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+// ReplyForError maps a DispatchCommand error to the user-facing reply.
+// Shared by all transports so that every runner answers the same way.
+// lang is the language resolved for the request, see ResolveLang.
+func ReplyForError(lang i18n.Lang, err error) string {
+	switch {
+	case errors.Is(err, ErrBadInput):
+		return BadAmountHintText(lang)
+	case errors.Is(err, ErrAmountIsZero):
+		return AmountIsZeroText(lang)
+	case errors.Is(err, ErrFutureDate):
+		return FutureDateText(lang)
+	case errors.Is(err, domain.ErrEntryNotFound):
+		return EditNotFoundText(lang)
+	case errors.Is(err, ErrUnknownCommand):
+		return UnknownCommandText(lang)
+	default:
+		return ErrorText(lang)
+	}
+}
+
+// Photo is an image a command replies with; the text reply becomes its caption.
+type Photo struct {
+	Name string // file name, e.g. "income-2025.png"
+	PNG  []byte
+}
+
+type photoSlotKey struct{}
+
+// WithPhotoSlot marks ctx as coming from a transport that can send photos. After dispatch,
+// the returned func yields the photo a handler attached, if any; the text reply is its caption.
+// Without a slot, handlers reply with text only.
+func WithPhotoSlot(ctx context.Context) (context.Context, func() (Photo, bool)) {
+	slot := &struct {
+		photo Photo
+		set   bool
+	}{}
+
+	ctx = context.WithValue(ctx, photoSlotKey{}, func(p Photo) { slot.photo, slot.set = p, true })
+
+	return ctx, func() (Photo, bool) { return slot.photo, slot.set }
+}
+
+// photoSlot returns where a handler attaches its photo; ok=false if the transport cannot send photos.
+func photoSlot(ctx context.Context) (attach func(Photo), ok bool) {
+	attach, ok = ctx.Value(photoSlotKey{}).(func(Photo))
+	return attach, ok
+}
+
+// Document is a file a command replies with; the text reply becomes the caption of the last one.
+type Document struct {
+	Name string // file name, e.g. "invoice-2025-7.pdf"
+	Data []byte
+}
+
+type documentSlotKey struct{}
+
+// WithDocumentSlot marks ctx as coming from a transport that can send files. After dispatch,
+// the returned func yields the documents handlers attached, in order. Without a slot,
+// handlers reply with text only.
+func WithDocumentSlot(ctx context.Context) (context.Context, func() []Document) {
+	var docs []Document
+
+	ctx = context.WithValue(ctx, documentSlotKey{}, func(d Document) { docs = append(docs, d) })
+
+	return ctx, func() []Document { return docs }
+}
+
+// documentSlot returns where a handler attaches documents; ok=false if the transport cannot send files.
+func documentSlot(ctx context.Context) (attach func(Document), ok bool) {
+	attach, ok = ctx.Value(documentSlotKey{}).(func(Document))
+	return attach, ok
+}
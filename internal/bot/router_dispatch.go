@@ -2,10 +2,25 @@ package bot
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
+// Outcomes reported to CommandObserver.
+const (
+	outcomeOK        = "ok"
+	outcomeBadInput  = "bad_input"
+	outcomeDuplicate = "duplicate"
+	outcomeError     = "error"
+)
+
+// commandUnknown is the observer label of commands the bot does not know,
+// so that arbitrary user input does not create new metric series.
+const commandUnknown = "unknown"
+
 func DispatchCommand(
 	ctx context.Context,
 	text string,
@@ -22,55 +37,111 @@ func DispatchCommand(
 		return "", false, nil
 	}
 
+	start := time.Now()
+
+	reply, err = dispatchCommand(ctx, cmd, args, transport, externalID, deps)
+
+	label := cmd
+	if errors.Is(err, ErrUnknownCommand) {
+		label = commandUnknown
+	}
+	observeCommand(deps, label, start, err)
+
+	if err != nil {
+		return "", true, validate.Wrap(op, err)
+	}
+
+	return reply, true, nil
+}
+
+func dispatchCommand(ctx context.Context, cmd, args, transport, externalID string, deps *BotDeps) (string, error) {
 	switch cmd {
 	case "start":
-		return HandleStart(ctx), true, nil
+		return HandleStart(ctx), nil
 	case "help":
-		return HandleHelp(ctx), true, nil
+		return HandleHelp(ctx), nil
 	case "add":
-		reply, err := HandleAdd(ctx, deps, transport, externalID, args)
-		if err != nil {
-			return "", true, validate.Wrap(op, err)
-		}
-		return reply, true, nil
+		return HandleAdd(ctx, deps, transport, externalID, args)
 	case "undo":
-		reply, err := HandleUndo(ctx, deps, transport, externalID, args)
-		if err != nil {
-			return "", true, validate.Wrap(op, err)
-		}
-		return reply, true, nil
+		return HandleUndo(ctx, deps, transport, externalID, args)
 	case "add_contrib":
-		reply, err := HandleAddContrib(ctx, deps, transport, externalID, args)
-		if err != nil {
-			return "", true, validate.Wrap(op, err)
-		}
-		return reply, true, nil
+		return HandleAddContrib(ctx, deps, transport, externalID, args)
 	case "undo_contrib":
-		reply, err := HandleUndoContrib(ctx, deps, transport, externalID, args)
-		if err != nil {
-			return "", true, validate.Wrap(op, err)
-		}
-		return reply, true, nil
+		return HandleUndoContrib(ctx, deps, transport, externalID, args)
 	case "add_advance":
-		reply, err := HandleAddAdvance(ctx, deps, transport, externalID, args)
-		if err != nil {
-			return "", true, validate.Wrap(op, err)
-		}
-		return reply, true, nil
+		return HandleAddAdvance(ctx, deps, transport, externalID, args)
 	case "undo_advance":
-		reply, err := HandleUndoAdvance(ctx, deps, transport, externalID, args)
-		if err != nil {
-			return "", true, validate.Wrap(op, err)
-		}
-		return reply, true, nil
+		return HandleUndoAdvance(ctx, deps, transport, externalID, args)
+	case "edit":
+		return HandleEditByID(ctx, deps, transport, externalID, domain.EntryKindIncome, args)
+	case "edit_contrib":
+		return HandleEditByID(ctx, deps, transport, externalID, domain.EntryKindContrib, args)
+	case "edit_advance":
+		return HandleEditByID(ctx, deps, transport, externalID, domain.EntryKindAdvance, args)
 	case "total":
-		reply, err := HandleTotal(ctx, deps, transport, externalID, args)
-		if err != nil {
-			return "", true, validate.Wrap(op, err)
-		}
-		return reply, true, nil
+		return HandleTotal(ctx, deps, transport, externalID, args)
+	case "forecast":
+		return HandleForecast(ctx, deps, transport, externalID, args)
+	case "limits":
+		return HandleLimits(ctx, deps, transport, externalID, args)
+	case "chart":
+		return HandleChart(ctx, deps, transport, externalID, args)
+	case "report":
+		return HandleReport(ctx, deps, transport, externalID, args)
+	case "find":
+		return HandleFind(ctx, deps, transport, externalID, args)
+	case "token":
+		return HandleToken(ctx, deps, transport, externalID, args)
+	case "link":
+		return HandleLink(ctx, deps, transport, externalID, args)
+	case "lang":
+		return HandleLang(ctx, deps, transport, externalID, args)
+	case "unlink":
+		return HandleUnlink(ctx, deps, transport, externalID, args)
+	case "recurring":
+		return HandleRecurring(ctx, deps, transport, externalID, args)
+	case "client":
+		return HandleClient(ctx, deps, transport, externalID, args)
+	case "clients":
+		return HandleClients(ctx, deps, transport, externalID, args)
+	case "requisites":
+		return HandleRequisites(ctx, deps, transport, externalID, args)
+	case "invoice":
+		return HandleInvoice(ctx, deps, transport, externalID, args)
+	case "invoices":
+		return HandleInvoices(ctx, deps, transport, externalID, args)
+	case "act":
+		return HandleAct(ctx, deps, transport, externalID, args)
+	case "acts":
+		return HandleActs(ctx, deps, transport, externalID, args)
+	case "pay":
+		return HandlePay(ctx, deps, transport, externalID, args)
 	default:
 		// Unknown command: handled=true
-		return "", true, validate.Wrap(op, ErrUnknownCommand)
+		return "", ErrUnknownCommand
+	}
+}
+
+// observeCommand reports a handled command to deps.Observer, if set.
+func observeCommand(deps *BotDeps, command string, start time.Time, err error) {
+	if deps == nil || deps.Observer == nil {
+		return
+	}
+
+	deps.Observer.ObserveCommand(command, outcome(err), time.Since(start))
+}
+
+// outcome classifies a DispatchCommand / DispatchEdit error for metrics.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case errors.Is(err, domain.ErrDuplicateUpdate):
+		return outcomeDuplicate
+	case errors.Is(err, ErrBadInput), errors.Is(err, ErrAmountIsZero), errors.Is(err, ErrFutureDate),
+		errors.Is(err, ErrUnknownCommand), errors.Is(err, domain.ErrEntryNotFound):
+		return outcomeBadInput
+	default:
+		return outcomeError
 	}
 }
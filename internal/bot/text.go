@@ -4,259 +4,1221 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/tuor4eg/ip_accounting_bot/internal/money"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/render"
 )
 
+// Replies are HTML (transports send them with parse_mode=HTML). Build them with
+// render.HTML() so that user input (notes, names) is always escaped.
+// Wording comes from the i18n catalogs; money and dates follow the reply language.
+
+// writeEntry appends an entry confirmation: title, amount, date and the optional note.
+func writeEntry(b *render.Builder, p *i18n.Printer, title string, amount int64, at time.Time, note string) {
+	b.Text(title)
+	b.Text(p.Money(amount))
+	b.Text("\n")
+	b.Text(p.T("entry.date"))
+	b.Text(p.Date(at))
+	if note != "" {
+		b.Text("\n")
+		b.Text(p.T("entry.note"))
+		b.Text(note)
+	}
+}
+
+// plain renders a catalog message without markup.
+func plain(lang i18n.Lang, key string, args ...any) string {
+	return render.HTML().Text(i18n.For(lang).T(key, args...)).String()
+}
+
 // ------------------ COMMON MESSAGES ------------------
-func UnknownCommandText() string {
-	var b strings.Builder
-	b.WriteString("❓ Неизвестная команда. Напишите /help для справки.")
-	return b.String()
+func UnknownCommandText(lang i18n.Lang) string {
+	return plain(lang, "common.unknown_command")
 }
 
-func ErrorText() string {
-	var b strings.Builder
-	b.WriteString("⚠️ Ошибка при обработке команды. Попробуйте позже.")
-	return b.String()
+func ErrorText(lang i18n.Lang) string {
+	return plain(lang, "common.error")
 }
 
 // ------------------ START MESSAGE ------------------
 
 // StartText returns the greeting and quick usage guide for the bot.
 // Text is static and transport-agnostic; actual sending is done by the router/runner.
-func StartText() string {
-	var b strings.Builder
-	b.WriteString("👋 Привет! Я помогу вести учёт доходов ИП (УСН 6%).\n\n")
-	b.WriteString("📋 Основные команды:\n")
-	b.WriteString("• /add [сумма] [комментарий] — добавить поступление\n")
-	b.WriteString("  Примеры: /add 1000\n")
-	b.WriteString("           /add 1 234,56 заказ #42\n")
-	b.WriteString("           /add 10р 50к аванс\n")
-	b.WriteString("• /add_contrib [сумма] [комментарий] — добавить взнос\n")
-	b.WriteString("• /add_advance [сумма] [комментарий] — добавить авансовый платеж\n")
-	b.WriteString("• /undo — отменить последнее поступление за квартал\n")
-	b.WriteString("• /undo_contrib — отменить последний взнос\n")
-	b.WriteString("• /undo_advance — отменить последний авансовый платеж\n")
-	b.WriteString("• /total — итоги за текущий квартал (сумма и налог 6%)\n")
-	b.WriteString("• /help — подробная справка\n\n")
-	b.WriteString("💡 Формат суммы: без знака минус, поддерживаются «1 234,56», «1234.56», «10р 50к».")
+func StartText(lang i18n.Lang) string {
+	return plain(lang, "start")
+}
+
+// ------------------ EDIT MESSAGES ------------------
+
+// EditSuccessText confirms that an entry was corrected after its message was edited.
+func EditSuccessText(lang i18n.Lang, kind domain.EntryKind, amount int64, at time.Time, note string) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	switch kind {
+	case domain.EntryKindContrib:
+		writeEntry(b, p, p.T("edit.contrib"), amount, at, note)
+	case domain.EntryKindAdvance:
+		writeEntry(b, p, p.T("edit.advance"), amount, at, note)
+	default:
+		writeEntry(b, p, p.T("edit.income"), amount, at, note)
+	}
 	return b.String()
 }
 
+// EditNotFoundText is the reply when the edited message has no active entry behind it.
+func EditNotFoundText(lang i18n.Lang) string {
+	return plain(lang, "edit.not_found")
+}
+
+// EditKindMismatchText is the reply when an edit changes the command, e.g. /add to /add_contrib.
+func EditKindMismatchText(lang i18n.Lang) string {
+	return plain(lang, "edit.kind_mismatch")
+}
+
+// EditUsageText is the reply to /edit without an entry ID or new values.
+func EditUsageText(lang i18n.Lang) string {
+	return plain(lang, "edit.usage")
+}
+
+// EntryNotFoundText is the reply when /undo or /edit names an ID the user has no active entry
+// of kind with.
+func EntryNotFoundText(lang i18n.Lang, kind domain.EntryKind, id int64) string {
+	return plain(lang, "entry.not_found."+string(kind), id)
+}
+
 // ------------------ HELP MESSAGE ------------------
 
 // HelpText returns a longer help message for users.
 // Text is static and transport-agnostic.
-func HelpText() string {
-	var b strings.Builder
-	b.WriteString("📚 Справка\n\n")
-	b.WriteString("🔧 Команды:\n")
-	b.WriteString("• /add [сумма] [комментарий]\n")
-	b.WriteString("  Добавляет поступление в базу. Сумма — без минуса, в рублях и копейках.\n")
-	b.WriteString("  Примеры:\n")
-	b.WriteString("   /add 1000\n")
-	b.WriteString("   /add 1 234,56 заказ #42\n")
-	b.WriteString("   /add 10р 50к аванс\n\n")
-	b.WriteString("• /add_contrib [сумма] [комментарий]\n")
-	b.WriteString("  Добавляет взнос в базу. Сумма — аналогично /add.\n\n")
-	b.WriteString("• /add_advance [сумма] [комментарий]\n")
-	b.WriteString("  Добавляет авансовый платеж в базу. Сумма — аналогично /add.\n\n")
-	b.WriteString("• /undo\n")
-	b.WriteString("  Отменяет последнее поступление за квартал.\n\n")
-	b.WriteString("• /undo_contrib\n")
-	b.WriteString("  Отменяет последний взнос.\n\n")
-	b.WriteString("• /undo_advance\n")
-	b.WriteString("  Отменяет последний авансовый платеж.\n\n")
-	b.WriteString("• /total\n")
-	b.WriteString("  Показывает сумму доходов и налог 6% за текущий квартал.\n\n")
-	b.WriteString("• /start\n")
-	b.WriteString("  Краткая инструкция.\n\n")
-	b.WriteString("💰 Формат суммы:\n")
-	b.WriteString("  • Допускаются пробелы/точки/запятые как разделители тысяч.\n")
-	b.WriteString("  • Последняя точка или запятая — десятичный разделитель (до 2 знаков).\n")
-	b.WriteString("  • Понимает записи вида «10р 50к», «10 руб 50 коп».\n")
-	b.WriteString("  • Отрицательные значения не принимаются.\n\n")
-	b.WriteString("⚙️ Механика:\n")
-	b.WriteString("  • Налог рассчитывается как 6% от суммы квартала (округление вниз).\n")
-	b.WriteString("  • Квартал определяется по UTC датам (включительно).\n")
-	return b.String()
+func HelpText(lang i18n.Lang) string {
+	return plain(lang, "help")
 }
 
 // ------------------ ADD MESSAGE ------------------
 
 // BadAmountHintText returns a short hint for invalid /add amount input.
-func BadAmountHintText() string {
-	var b strings.Builder
-	b.WriteString("❌ Не понял сумму. Примеры: 1000 | 1 234,56 | 10р 50к")
-	return b.String()
+func BadAmountHintText(lang i18n.Lang) string {
+	return plain(lang, "input.bad_amount")
 }
 
-func AmountIsZeroText() string {
-	var b strings.Builder
-	b.WriteString("❌ Сумма не может быть 0")
-	return b.String()
+// FutureDateText is the reply for an entry dated later than today.
+func FutureDateText(lang i18n.Lang) string {
+	return plain(lang, "input.future_date")
 }
 
-func AddSuccessText(amount int64, at time.Time, note string) string {
-	// Deterministic template reply (no AI).
-	var b strings.Builder
-	b.WriteString("✅ Добавлено поступление: ")
-	b.WriteString(money.FormatAmountShort(amount))
-	b.WriteString("\n📅 Дата: ")
-	b.WriteString(at.Format("02.01.2006"))
-	if note != "" {
-		b.WriteString("\n💬 Комментарий: ")
-		b.WriteString(note)
-	}
+func AmountIsZeroText(lang i18n.Lang) string {
+	return plain(lang, "input.zero_amount")
+}
 
+func AddSuccessText(lang i18n.Lang, amount int64, at time.Time, note string) string {
+	// Deterministic template reply (no AI).
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeEntry(b, p, p.T("add.income"), amount, at, note)
 	return b.String()
 }
 
 // ------------------ ADD CONTRIB MESSAGE ------------------
 
-func AddContribSuccessText(amount int64, at time.Time, note string) string {
-	var b strings.Builder
-	b.WriteString("✅ Добавлен взнос: ")
-	b.WriteString(money.FormatAmountShort(amount))
-	b.WriteString("\n📅 Дата: ")
-	b.WriteString(at.Format("02.01.2006"))
-	if note != "" {
-		b.WriteString("\n💬 Комментарий: ")
-		b.WriteString(note)
-	}
+func AddContribSuccessText(lang i18n.Lang, amount int64, at time.Time, note string) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeEntry(b, p, p.T("add.contrib"), amount, at, note)
 	return b.String()
 }
 
 // ------------------ ADD ADVANCE MESSAGE ------------------
 
-func AddAdvanceSuccessText(amount int64, at time.Time, note string) string {
-	var b strings.Builder
-	b.WriteString("✅ Добавлен авансовый платеж: ")
-	b.WriteString(money.FormatAmountShort(amount))
-	b.WriteString("\n📅 Дата: ")
-	b.WriteString(at.Format("02.01.2006"))
-	if note != "" {
-		b.WriteString("\n💬 Комментарий: ")
-		b.WriteString(note)
-	}
+func AddAdvanceSuccessText(lang i18n.Lang, amount int64, at time.Time, note string) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeEntry(b, p, p.T("add.advance"), amount, at, note)
 	return b.String()
 }
 
 // ------------------ TOTAL MESSAGE ------------------
 
 func TotalText(
+	lang i18n.Lang,
 	sum int64, tax int64, qStart time.Time, qEnd time.Time,
 	yearSum int64, yearTax int64,
 	contribSum int64, advanceSum int64,
 	year int, quarter int,
+	vatRateBP int64, quarterVAT int64, yearVAT int64,
 ) string {
-	var b strings.Builder
+	p := i18n.For(lang)
+	b := render.HTML()
 
 	// Quarter section
-	b.WriteString("📅 <b>")
-	b.WriteString(strconv.Itoa(quarter))
-	b.WriteString(" квартал: ")
-	b.WriteString(qStart.Format("02.01.2006"))
-	b.WriteString(" - ")
-	b.WriteString(qEnd.Format("02.01.2006"))
-	b.WriteString("</b>")
-	b.WriteString("\n")
-
-	b.WriteString("💰 Поступления: ")
-	b.WriteString(money.FormatAmountShort(sum))
-	b.WriteString("\n")
-
-	b.WriteString("🧾 Налог: ")
-	b.WriteString(money.FormatAmountShort(tax))
-	b.WriteString("\n\n")
+	b.Text("📅 ")
+	b.Bold(p.T("total.quarter", quarter, p.Date(qStart), p.Date(qEnd)))
+	b.Text("\n")
+
+	b.Text(p.T("total.income"))
+	b.Text(p.Money(sum))
+	b.Text("\n")
+
+	b.Text(p.T("total.tax"))
+	b.Text(p.Money(tax))
+	b.Text("\n")
+
+	if vatRateBP > 0 || quarterVAT > 0 {
+		b.Text(p.T("total.vat", percentBP(vatRateBP)))
+		b.Text(p.Money(quarterVAT))
+		b.Text("\n")
+	}
+
+	b.Text("\n")
 
 	// Year section
-	b.WriteString("📊 <b>Итого за ")
-	b.WriteString(strconv.Itoa(year))
-	b.WriteString(" год:</b>")
-	b.WriteString("\n")
+	b.Text("📊 ")
+	b.Bold(p.T("total.year", year))
+	b.Text("\n")
+
+	b.Text(p.T("total.income"))
+	b.Text(p.Money(yearSum))
+	b.Text("\n")
 
-	b.WriteString("💰 Поступления: ")
-	b.WriteString(money.FormatAmountShort(yearSum))
-	b.WriteString("\n")
+	b.Text(p.T("total.contrib"))
+	b.Text(p.Money(contribSum))
+	b.Text("\n")
 
-	b.WriteString("💳 Взносы: ")
-	b.WriteString(money.FormatAmountShort(contribSum))
-	b.WriteString("\n")
+	b.Text(p.T("total.advance"))
+	b.Text(p.Money(advanceSum))
+	b.Text("\n")
 
-	b.WriteString("💸 Авансы: ")
-	b.WriteString(money.FormatAmountShort(advanceSum))
-	b.WriteString("\n")
+	b.Text(p.T("total.tax"))
+	b.Text(p.Money(yearTax))
 
-	b.WriteString("🧾 Налог: ")
-	b.WriteString(money.FormatAmountShort(yearTax))
+	if vatRateBP > 0 || yearVAT > 0 {
+		b.Text("\n")
+		b.Text(p.T("total.vat", percentBP(vatRateBP)))
+		b.Text(p.Money(yearVAT))
+	}
 
 	return b.String()
 }
 
-// ------------------ UNDO MESSAGE ------------------
+// percentBP formats a whole-percent rate given in basis points, e.g. 500 as "5%".
+func percentBP(bp int64) string {
+	return strconv.FormatInt(bp/100, 10) + "%"
+}
 
-func UndoSuccessText(amount int64, at time.Time, note string) string {
-	var b strings.Builder
-	b.WriteString("✅ Поступление отменено:\n")
-	b.WriteString("💰 Сумма: ")
-	b.WriteString(money.FormatAmountShort(amount))
-	b.WriteString("\n📅 Дата: ")
-	b.WriteString(at.Format("02.01.2006"))
-	if note != "" {
-		b.WriteString("\n💬 Комментарий: ")
-		b.WriteString(note)
+// ------------------ FORECAST MESSAGE ------------------
+
+// forecastRange writes the expected amount of r followed by its range, unless the estimates agree.
+func forecastRange(b *render.Builder, p *i18n.Printer, r domain.Range) {
+	b.Text(p.Money(r.Expected))
+	if r.Low != r.High {
+		b.Text(p.T("forecast.range", p.Money(r.Low), p.Money(r.High)))
 	}
+}
+
+func ForecastText(lang i18n.Lang, f domain.Forecast) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+
+	b.Text("📈 ")
+	b.Bold(p.T("forecast.title", f.Year, p.Date(f.AsOf)))
+	b.Text("\n")
+
+	b.Text(p.T("forecast.to_date"))
+	b.Text(p.Money(f.IncomeToDate))
+	b.Text("\n")
+
+	b.Text(p.T("forecast.income"))
+	forecastRange(b, p, f.Income)
+	b.Text("\n")
+
+	b.Text(p.T("forecast.tax"))
+	forecastRange(b, p, f.Tax)
+	b.Text("\n")
+
+	b.Text(p.T("forecast.extra"))
+	forecastRange(b, p, f.Extra)
+	b.Text("\n")
+
+	if f.FixedContrib > 0 {
+		b.Text(p.T("forecast.fixed", p.Money(f.FixedContrib), p.Money(f.FixedLeft)))
+		b.Text("\n")
+	}
+
+	b.Text(p.T("forecast.due"))
+	forecastRange(b, p, f.Due)
+	b.Text("\n\n")
+
+	if f.Seasonal {
+		b.Text(p.T("forecast.basis_seasonal"))
+	} else {
+		b.Text(p.T("forecast.basis_rate"))
+	}
+
+	for _, w := range f.Warnings {
+		b.Text("\n")
+		b.Text(p.T("forecast."+string(w.Limit)+"."+string(w.Level), p.Money(w.Amount)))
+	}
+
 	return b.String()
 }
 
-func UndoNoIncomeText() string {
-	var b strings.Builder
-	b.WriteString("ℹ️ Нечего отменять. Нет поступлений за текущий квартал.")
+func ForecastDisabledText(lang i18n.Lang) string {
+	return plain(lang, "forecast.disabled")
+}
+
+// ------------------ LIMITS MESSAGE ------------------
+
+// writeLimitWarnings writes a line for each limit the income is near or above.
+func writeLimitWarnings(b *render.Builder, p *i18n.Printer, usage []domain.LimitUsage) {
+	for _, u := range usage {
+		key := "limits." + string(u.Kind) + "." + string(u.Level)
+		b.Text("\n")
+		if u.Level == domain.LimitCrossed {
+			b.Text(p.T(key, p.Money(u.Amount)))
+		} else {
+			b.Text(p.T(key, u.UsedBP/100, p.Money(u.Amount)))
+		}
+	}
+}
+
+func LimitsText(lang i18n.Lang, l domain.Limits) string {
+	p := i18n.For(lang)
+
+	if len(l.Usage) == 0 {
+		return plain(lang, "limits.none", l.Year)
+	}
+
+	b := render.HTML()
+
+	b.Text("📏 ")
+	b.Bold(p.T("limits.title", l.Year, p.Date(l.AsOf)))
+	b.Text("\n")
+
+	b.Text(p.T("limits.to_date"))
+	b.Text(p.Money(l.IncomeToDate))
+
+	for _, u := range l.Usage {
+		b.Text("\n")
+		b.Text(p.T("limits."+string(u.Kind), p.Money(u.Amount), u.UsedBP/100))
+		if left := u.Amount - l.IncomeToDate; left > 0 {
+			b.Text(p.T("limits.left", p.Money(left)))
+		}
+	}
+
+	b.Text("\n")
+	if l.VATRateBP > 0 {
+		b.Text(p.T("limits.vat_rate", percentBP(l.VATRateBP), p.Date(l.VATSince)))
+	} else {
+		b.Text(p.T("limits.vat_exempt"))
+	}
+
+	if warnings := l.Warnings(); len(warnings) > 0 {
+		b.Text("\n")
+		writeLimitWarnings(b, p, warnings)
+	}
+
 	return b.String()
 }
 
-// ------------------ UNDO CONTRIB MESSAGE ------------------
+// LimitWarningsText returns the warnings appended to a reply of /add; empty if there are none.
+func LimitWarningsText(lang i18n.Lang, l domain.Limits) string {
+	warnings := l.Warnings()
+	if len(warnings) == 0 {
+		return ""
+	}
 
-func UndoContribSuccessText(amount int64, at time.Time, note string) string {
-	var b strings.Builder
-	b.WriteString("✅ Взнос отменен:\n")
-	b.WriteString("💰 Сумма: ")
-	b.WriteString(money.FormatAmountShort(amount))
-	b.WriteString("\n📅 Дата: ")
-	b.WriteString(at.Format("02.01.2006"))
-	if note != "" {
-		b.WriteString("\n💬 Комментарий: ")
-		b.WriteString(note)
+	b := render.HTML()
+	b.Text("\n")
+	writeLimitWarnings(b, i18n.For(lang), warnings)
+	return b.String()
+}
+
+func LimitsDisabledText(lang i18n.Lang) string {
+	return plain(lang, "limits.disabled")
+}
+
+// ------------------ CHART MESSAGE ------------------
+
+// writeChartHeader writes the title and the totals of the year.
+func writeChartHeader(b *render.Builder, p *i18n.Printer, m domain.MonthlyIncome) {
+	var tax int64
+	for _, v := range m.Tax {
+		tax += v
 	}
+
+	b.Text("📊 ")
+	b.Bold(p.T("chart.title", m.Year))
+	b.Text("\n")
+
+	b.Text(p.T("total.income"))
+	b.Text(p.Money(m.Total()))
+	b.Text("\n")
+
+	b.Text(p.T("total.tax"))
+	b.Text(p.Money(tax))
+}
+
+// ChartCaptionText is the caption of the chart photo.
+func ChartCaptionText(lang i18n.Lang, m domain.MonthlyIncome) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeChartHeader(b, p, m)
+	b.Text("\n")
+	b.Text(p.T("chart.legend"))
+	return b.String()
+}
+
+// ChartText lists the months with income, for transports that cannot send photos.
+func ChartText(lang i18n.Lang, m domain.MonthlyIncome) string {
+	if m.Total() == 0 {
+		return plain(lang, "chart.empty", m.Year)
+	}
+
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeChartHeader(b, p, m)
+	b.Text("\n")
+
+	for i, v := range m.Income {
+		if v == 0 {
+			continue
+		}
+		b.Text("\n")
+		b.Text(p.T("chart.month_line", p.T("chart.month."+strconv.Itoa(i+1)), p.Money(v), p.Money(m.Tax[i])))
+	}
+
+	return b.String()
+}
+
+func ChartUsageText(lang i18n.Lang) string {
+	return plain(lang, "chart.usage")
+}
+
+func ChartDisabledText(lang i18n.Lang) string {
+	return plain(lang, "chart.disabled")
+}
+
+// ------------------ REPORT MESSAGE ------------------
+
+// ReportText renders the report as a table in a preformatted block, one row per bucket
+// and a total row; amounts are whole rubles so that the columns stay narrow.
+func ReportText(lang i18n.Lang, r domain.Report) string {
+	p := i18n.For(lang)
+
+	if r.Total.Count == 0 {
+		return plain(lang, "report.empty", p.Date(r.From), p.Date(r.To))
+	}
+
+	rubles := func(kopecks int64) string { return p.Number(kopecks / 100) }
+
+	cells := func(label string, row domain.ReportRow) []string {
+		if row.Count == 0 {
+			return []string{label, "0", "–", "–", "–", "–"}
+		}
+		return []string{label, p.Number(row.Count), rubles(row.Sum), rubles(row.Avg), rubles(row.Max), rubles(row.Tax)}
+	}
+
+	table := [][]string{{
+		p.T("report.col." + string(r.Bucket)),
+		p.T("report.col.count"),
+		p.T("report.col.sum"),
+		p.T("report.col.avg"),
+		p.T("report.col.max"),
+		p.T("report.col.tax"),
+	}}
+
+	for _, row := range r.Rows {
+		label := p.T("chart.month." + strconv.Itoa(int(row.From.Month())))
+		if r.Bucket == domain.BucketWeek {
+			_, week := row.From.ISOWeek()
+			label = p.T("report.week", week, p.ShortDate(row.From))
+		}
+		table = append(table, cells(label, row))
+	}
+
+	table = append(table, nil, cells(p.T("report.total"), r.Total))
+
+	b := render.HTML()
+
+	b.Text("📋 ")
+	b.Bold(p.T("report.title."+string(r.Bucket), p.Date(r.From), p.Date(r.To)))
+	b.Text("\n")
+	b.Pre(alignTable(table))
+	b.Text("\n")
+	b.Text(p.T("report.note"))
+
 	return b.String()
 }
 
-func UndoNoContribText() string {
+// alignTable lays rows out in columns two spaces apart: the first column aligned left,
+// the others right. A nil row is drawn as a rule across the table.
+func alignTable(rows [][]string) string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+
 	var b strings.Builder
-	b.WriteString("ℹ️ Нечего отменять. Нет взносов за текущий год.")
+
+	for n, row := range rows {
+		if n > 0 {
+			b.WriteByte('\n')
+		}
+
+		if row == nil {
+			b.WriteString(strings.Repeat("─", total))
+			continue
+		}
+
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			switch {
+			case i == 0:
+				b.WriteString(cell)
+				if len(row) > 1 {
+					b.WriteString(pad)
+				}
+			default:
+				b.WriteString("  " + pad + cell)
+			}
+		}
+	}
+
 	return b.String()
 }
 
+func ReportUsageText(lang i18n.Lang) string {
+	return plain(lang, "report.usage")
+}
+
+func ReportDisabledText(lang i18n.Lang) string {
+	return plain(lang, "report.disabled")
+}
+
+// ------------------ FIND MESSAGE ------------------
+
+// FindText lists the matches of /find with their ledger and ID, newest first;
+// more says that there are older matches than those shown.
+func FindText(lang i18n.Lang, query string, matches []domain.NoteMatch, more bool) string {
+	if len(matches) == 0 {
+		return plain(lang, "find.empty", query)
+	}
+
+	p := i18n.For(lang)
+	b := render.HTML()
+
+	b.Text("🔎 ")
+	b.Bold(p.T("find.title", query))
+
+	for _, m := range matches {
+		b.Text("\n• " + p.T("find.kind."+string(m.Kind)) + " ")
+		b.Code("#" + strconv.FormatInt(m.ID, 10))
+		b.Text(" · " + p.Date(m.At) + " · " + p.Money(m.Amount) + "\n  " + m.Note)
+	}
+
+	if more {
+		b.Text("\n\n")
+		b.Text(p.T("find.more", len(matches)))
+	}
+
+	return b.String()
+}
+
+func FindUsageText(lang i18n.Lang) string {
+	return plain(lang, "find.usage")
+}
+
+func FindDisabledText(lang i18n.Lang) string {
+	return plain(lang, "find.disabled")
+}
+
+// ------------------ UNDO MESSAGE ------------------
+
+func undoText(lang i18n.Lang, key string, amount int64, at time.Time, note string) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeEntry(b, p, p.T(key)+"\n"+p.T("entry.amount"), amount, at, note)
+	return b.String()
+}
+
+func UndoSuccessText(lang i18n.Lang, amount int64, at time.Time, note string) string {
+	return undoText(lang, "undo.income", amount, at, note)
+}
+
+func UndoNoIncomeText(lang i18n.Lang) string {
+	return plain(lang, "undo.nothing_income")
+}
+
+// UndoBadIDText is the reply when the argument of an undo command is not an entry ID.
+func UndoBadIDText(lang i18n.Lang) string {
+	return plain(lang, "undo.bad_id")
+}
+
+// ------------------ UNDO CONTRIB MESSAGE ------------------
+
+func UndoContribSuccessText(lang i18n.Lang, amount int64, at time.Time, note string) string {
+	return undoText(lang, "undo.contrib", amount, at, note)
+}
+
+func UndoNoContribText(lang i18n.Lang) string {
+	return plain(lang, "undo.nothing_contrib")
+}
+
 // ------------------ UNDO ADVANCE MESSAGE ------------------
 
-func UndoAdvanceSuccessText(amount int64, at time.Time, note string) string {
-	var b strings.Builder
-	b.WriteString("✅ Авансовый платеж отменен:\n")
-	b.WriteString("💰 Сумма: ")
-	b.WriteString(money.FormatAmountShort(amount))
-	b.WriteString("\n📅 Дата: ")
-	b.WriteString(at.Format("02.01.2006"))
-	if note != "" {
-		b.WriteString("\n💬 Комментарий: ")
-		b.WriteString(note)
+func UndoAdvanceSuccessText(lang i18n.Lang, amount int64, at time.Time, note string) string {
+	return undoText(lang, "undo.advance", amount, at, note)
+}
+
+func UndoNoAdvanceText(lang i18n.Lang) string {
+	return plain(lang, "undo.nothing_advance")
+}
+
+// ------------------ TOKEN MESSAGE ------------------
+
+func TokenIssuedText(lang i18n.Lang, id int64, token string) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("token.issued", id))
+	b.Text("\n")
+	b.Code(token)
+	b.Text("\n\n")
+	b.Text(p.T("token.issued_tip"))
+	return b.String()
+}
+
+func TokenListText(lang i18n.Lang, tokens []domain.APIToken) string {
+	if len(tokens) == 0 {
+		return plain(lang, "token.list_empty")
+	}
+
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("token.list_title"))
+	for _, t := range tokens {
+		b.Newline()
+		b.Text("• #")
+		b.Text(strconv.FormatInt(t.ID, 10))
+		if t.Name != "" {
+			b.Text(" ")
+			b.Text(t.Name)
+		}
+		b.Text(p.T("token.created", p.Date(t.CreatedAt)))
+		if !t.LastUsedAt.IsZero() {
+			b.Text(p.T("token.used", p.Date(t.LastUsedAt)))
+		}
 	}
 	return b.String()
 }
 
-func UndoNoAdvanceText() string {
-	var b strings.Builder
-	b.WriteString("ℹ️ Нечего отменять. Нет авансовых платежей за текущий год.")
+func TokenRevokedText(lang i18n.Lang, id int64) string {
+	return plain(lang, "token.revoked", id)
+}
+
+func TokenRevokedAllText(lang i18n.Lang, n int64) string {
+	return render.HTML().Text(i18n.For(lang).N("token.revoked_all", n)).String()
+}
+
+func TokenNotFoundText(lang i18n.Lang, id int64) string {
+	return plain(lang, "token.not_found", id)
+}
+
+func TokenUsageText(lang i18n.Lang) string {
+	return plain(lang, "token.usage")
+}
+
+func TokenDisabledText(lang i18n.Lang) string {
+	return plain(lang, "token.disabled")
+}
+
+// ------------------ LINK MESSAGE ------------------
+
+func LinkCodeText(lang i18n.Lang, code string, expiresAt time.Time) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("link.code"))
+	b.Code(code)
+	b.Text("\n")
+	b.Text(p.T("link.send_before"))
+	b.Code("/link " + code)
+	b.Text(p.T("link.send_after"))
+	b.Text("\n")
+	b.Text(p.T("link.valid_until", expiresAt.UTC().Format("15:04")))
+	return b.String()
+}
+
+func LinkSuccessText(lang i18n.Lang) string {
+	return plain(lang, "link.success")
+}
+
+func LinkCodeInvalidText(lang i18n.Lang) string {
+	return plain(lang, "link.invalid")
+}
+
+func LinkAlreadyText(lang i18n.Lang) string {
+	return plain(lang, "link.already")
+}
+
+func LinkHasEntriesText(lang i18n.Lang) string {
+	return plain(lang, "link.has_entries")
+}
+
+func LinkDisabledText(lang i18n.Lang) string {
+	return plain(lang, "link.disabled")
+}
+
+func UnlinkSuccessText(lang i18n.Lang, transport string, n int64) string {
+	return render.HTML().Text(i18n.For(lang).N("unlink.success", n, transport)).String()
+}
+
+func UnlinkNothingText(lang i18n.Lang, transport string) string {
+	return plain(lang, "unlink.nothing", transport)
+}
+
+func UnlinkLastText(lang i18n.Lang) string {
+	return plain(lang, "unlink.last")
+}
+
+func UnlinkAPIText(lang i18n.Lang) string {
+	return plain(lang, "unlink.api")
+}
+
+// ------------------ LANG MESSAGE ------------------
+
+// LangCurrentText shows the reply language; auto means it follows the transport (Telegram settings).
+func LangCurrentText(lang i18n.Lang, auto bool) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("lang.current", p.T("lang.name."+string(p.Lang()))))
+	if auto {
+		b.Text(p.T("lang.auto_suffix"))
+	}
+	b.Text("\n")
+	b.Text(p.T("lang.usage"))
+	return b.String()
+}
+
+func LangSetText(lang i18n.Lang) string {
+	return plain(lang, "lang.set")
+}
+
+func LangAutoText(lang i18n.Lang) string {
+	return plain(lang, "lang.auto")
+}
+
+func LangUnknownText(lang i18n.Lang) string {
+	return plain(lang, "lang.unknown")
+}
+
+func LangDisabledText(lang i18n.Lang) string {
+	return plain(lang, "lang.disabled")
+}
+
+// ------------------ RECURRING MESSAGE ------------------
+
+// recurringSchedule describes when a rule runs: "monthly on day 5", "weekly on Monday".
+func recurringSchedule(p *i18n.Printer, rule domain.RecurringRule) string {
+	if rule.Period == domain.RecurringWeekly {
+		return p.T("recurring.weekly", p.T("recurring.weekday."+strconv.Itoa(rule.Day)))
+	}
+	return p.T("recurring.monthly", rule.Day)
+}
+
+func RecurringAddedText(lang i18n.Lang, rule domain.RecurringRule) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("recurring.added", rule.ID))
+	b.Text(p.Money(rule.Amount))
+	b.Text(", ")
+	b.Text(recurringSchedule(p, rule))
+	if rule.Note != "" {
+		b.Text("\n")
+		b.Text(p.T("entry.note"))
+		b.Text(rule.Note)
+	}
+	b.Text("\n")
+	b.Text(p.T("recurring.next", p.Date(rule.NextDue)))
+	if rule.Confirm {
+		b.Text("\n")
+		b.Text(p.T("recurring.asks"))
+	}
+	return b.String()
+}
+
+func RecurringListText(lang i18n.Lang, rules []domain.RecurringRule) string {
+	if len(rules) == 0 {
+		return plain(lang, "recurring.list_empty")
+	}
+
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("recurring.list_title"))
+	for _, r := range rules {
+		b.Newline()
+		b.Text("• #")
+		b.Text(strconv.FormatInt(r.ID, 10))
+		b.Text(" ")
+		b.Text(p.Money(r.Amount))
+		b.Text(", ")
+		b.Text(recurringSchedule(p, r))
+		if r.Note != "" {
+			b.Text(" — ")
+			b.Text(r.Note)
+		}
+		if r.Paused {
+			b.Text(p.T("recurring.list_paused"))
+		} else {
+			b.Text(p.T("recurring.list_next", p.Date(r.NextDue)))
+		}
+		if r.Confirm {
+			b.Text(p.T("recurring.list_asks"))
+		}
+		if r.Pending > 0 {
+			b.Text(p.T("recurring.list_pending", r.Pending, r.ID))
+		}
+	}
+	return b.String()
+}
+
+func RecurringPausedText(lang i18n.Lang, id int64) string {
+	return plain(lang, "recurring.paused", id)
+}
+
+func RecurringResumedText(lang i18n.Lang, rule domain.RecurringRule) string {
+	p := i18n.For(lang)
+	return plain(lang, "recurring.resumed", rule.ID, p.Date(rule.NextDue))
+}
+
+func RecurringDeletedText(lang i18n.Lang, id int64) string {
+	return plain(lang, "recurring.deleted", id)
+}
+
+// RecurringResolvedText reports incomes added with /recurring confirm or dropped with /recurring skip.
+func RecurringResolvedText(lang i18n.Lang, id int64, confirmed bool, runs []domain.RecurringRun) string {
+	if len(runs) == 0 {
+		return plain(lang, "recurring.nothing_pending", id)
+	}
+
+	key := "recurring.skipped"
+	if confirmed {
+		key = "recurring.confirmed"
+	}
+
+	return render.HTML().Text(i18n.For(lang).N(key, int64(len(runs)))).String()
+}
+
+func RecurringNotFoundText(lang i18n.Lang, id int64) string {
+	return plain(lang, "recurring.not_found", id)
+}
+
+func RecurringUsageText(lang i18n.Lang) string {
+	return plain(lang, "recurring.usage")
+}
+
+func RecurringDisabledText(lang i18n.Lang) string {
+	return plain(lang, "recurring.disabled")
+}
+
+// RecurringRunText tells the user what the scheduler did with an occurrence: the income
+// was added, or it waits for /recurring confirm.
+func RecurringRunText(lang i18n.Lang, run domain.RecurringRun) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	if run.Status == domain.RecurringPending {
+		writeEntry(b, p, p.T("recurring.run_pending", run.RuleID), run.Amount, run.Due, run.Note)
+		b.Text("\n")
+		b.Text(p.T("recurring.run_confirm", run.RuleID, run.RuleID))
+		return b.String()
+	}
+	writeEntry(b, p, p.T("recurring.run_created", run.RuleID), run.Amount, run.Due, run.Note)
+	return b.String()
+}
+
+// ------------------ INVOICE MESSAGE ------------------
+
+func InvoiceDisabledText(lang i18n.Lang) string {
+	return plain(lang, "invoice.disabled")
+}
+
+func ClientAddedText(lang i18n.Lang, c domain.Counterparty) string {
+	return plain(lang, "client.added", c.ID, c.Name, c.ID)
+}
+
+// ClientText shows a client with the details that are filled in.
+func ClientText(lang i18n.Lang, c domain.Counterparty) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("client.title", c.ID, c.Name))
+	for _, f := range [][2]string{{"inn", c.INN}, {"kpp", c.KPP}, {"address", c.Address}} {
+		if f[1] != "" {
+			b.Text("\n")
+			b.Text(p.T("requisites.line", p.T("requisites.field."+f[0]), f[1]))
+		}
+	}
+	return b.String()
+}
+
+func ClientListText(lang i18n.Lang, clients []domain.Counterparty) string {
+	if len(clients) == 0 {
+		return plain(lang, "client.list_empty")
+	}
+
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("client.list_title"))
+	for _, c := range clients {
+		b.Newline()
+		b.Text(p.T("client.list_line", c.ID, c.Name))
+		if c.INN != "" {
+			b.Text(p.T("client.list_inn", c.INN))
+		}
+	}
+	return b.String()
+}
+
+func ClientExistsText(lang i18n.Lang, name string) string {
+	return plain(lang, "client.exists", name)
+}
+
+func ClientNotFoundText(lang i18n.Lang, id int64) string {
+	return plain(lang, "client.not_found", id)
+}
+
+func ClientUsageText(lang i18n.Lang) string {
+	return plain(lang, "client.usage")
+}
+
+// RequisitesText lists the requisites of the user and what is missing to issue invoices.
+func RequisitesText(lang i18n.Lang, r domain.Requisites) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("requisites.title"))
+
+	for _, field := range requisiteFields {
+		value := *requisiteField(&r, field)
+		label := p.T("requisites.field." + field)
+
+		if value == "" {
+			value = p.T("requisites.unset")
+		}
+
+		b.Text("\n")
+		b.Text(p.T("requisites.line", label, value))
+	}
+
+	if !r.Complete() {
+		var missing []string
+		for _, f := range [][2]string{{"name", r.Name}, {"inn", r.INN}, {"bank", r.Bank}, {"bik", r.BIK}, {"account", r.Account}} {
+			if f[1] == "" {
+				missing = append(missing, p.T("requisites.field."+f[0]))
+			}
+		}
+
+		b.Text("\n\n")
+		b.Text(p.T("requisites.missing", strings.Join(missing, ", ")))
+	}
+
+	b.Text("\n\n")
+	b.Text(p.T("requisites.hint"))
+	return b.String()
+}
+
+// RequisiteInvalidText rejects a number that failed its check; field is as typed in
+// /requisites or /client set.
+func RequisiteInvalidText(lang i18n.Lang, field string) string {
+	p := i18n.For(lang)
+	return plain(lang, "requisites.invalid", p.T("requisites.field."+field))
+}
+
+func RequisitesUsageText(lang i18n.Lang) string {
+	return plain(lang, "requisites.usage")
+}
+
+// writeInvoice appends the header and the terms of an invoice.
+func writeInvoice(b *render.Builder, p *i18n.Printer, inv domain.Invoice) {
+	b.Text(p.T("invoice.header", inv.ID, inv.Number, p.Date(inv.IssuedOn)))
+	b.Text("\n")
+	b.Text(p.T("invoice.client", inv.Client.Name))
+	b.Text("\n")
+	b.Text(p.T("entry.amount"))
+	b.Text(p.Money(inv.Amount))
+	b.Text("\n")
+	b.Text(p.T("invoice.due", p.Date(inv.DueOn)))
+	if inv.Description != "" {
+		b.Text("\n")
+		b.Text(p.T("entry.note"))
+		b.Text(inv.Description)
+	}
+}
+
+// writeInvoiceStatus appends how much of an invoice is paid and whether it is overdue on today.
+func writeInvoiceStatus(b *render.Builder, p *i18n.Printer, inv domain.Invoice, today time.Time) {
+	switch inv.Status() {
+	case domain.InvoicePaid:
+		b.Text(p.T("invoice.status.paid"))
+		return
+	case domain.InvoicePartial:
+		b.Text(p.T("invoice.status.partial", p.Money(inv.Paid), p.Money(inv.Left())))
+	default:
+		b.Text(p.T("invoice.status.unpaid"))
+	}
+
+	if inv.Overdue(today) {
+		b.Text("\n")
+		b.Text(p.T("invoice.overdue", p.Date(inv.DueOn)))
+	}
+
+	b.Text("\n")
+	b.Text(p.T("invoice.pay_hint", inv.ID))
+}
+
+func InvoiceIssuedText(lang i18n.Lang, inv domain.Invoice) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeInvoice(b, p, inv)
+	b.Text("\n")
+	b.Text(p.T("invoice.pay_hint", inv.ID))
+	return b.String()
+}
+
+func InvoiceText(lang i18n.Lang, inv domain.Invoice, now time.Time) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeInvoice(b, p, inv)
+	b.Text("\n")
+	writeInvoiceStatus(b, p, inv, now)
+	return b.String()
+}
+
+// InvoicePaidText confirms a payment of amount recorded as an income on at.
+func InvoicePaidText(lang i18n.Lang, inv domain.Invoice, incomeID, amount int64, at time.Time) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeEntry(b, p, p.T("invoice.paid", inv.ID, incomeID), amount, at, "")
+	b.Text("\n")
+	if inv.Status() == domain.InvoicePaid {
+		b.Text(p.T("invoice.status.paid"))
+	} else {
+		b.Text(p.T("invoice.status.partial", p.Money(inv.Paid), p.Money(inv.Left())))
+	}
+	return b.String()
+}
+
+// InvoicePaymentNote is the note of the income that pays an invoice.
+func InvoicePaymentNote(lang i18n.Lang, inv domain.Invoice) string {
+	p := i18n.For(lang)
+	return p.T("invoice.payment_note", inv.Number, p.Date(inv.IssuedOn))
+}
+
+func InvoiceListText(lang i18n.Lang, invoices []domain.Invoice, unpaid bool, now time.Time) string {
+	if len(invoices) == 0 {
+		if unpaid {
+			return plain(lang, "invoice.list_empty_unpaid")
+		}
+		return plain(lang, "invoice.list_empty")
+	}
+
+	p := i18n.For(lang)
+	b := render.HTML()
+	if unpaid {
+		b.Text(p.T("invoice.list_title_unpaid"))
+	} else {
+		b.Text(p.T("invoice.list_title"))
+	}
+
+	for _, inv := range invoices {
+		b.Newline()
+		b.Text(p.T("invoice.list_line", inv.ID, inv.Number, p.Date(inv.IssuedOn), inv.Client.Name, p.Money(inv.Amount)))
+
+		switch {
+		case inv.Status() == domain.InvoicePaid:
+			b.Text(p.T("invoice.list_paid"))
+			continue
+		case inv.Status() == domain.InvoicePartial:
+			b.Text(p.T("invoice.list_left", p.Money(inv.Left())))
+		}
+
+		if inv.Overdue(now) {
+			b.Text(p.T("invoice.list_overdue", p.Date(inv.DueOn)))
+		} else {
+			b.Text(p.T("invoice.list_due", p.Date(inv.DueOn)))
+		}
+	}
+	return b.String()
+}
+
+func InvoiceNotFoundText(lang i18n.Lang, id int64) string {
+	return plain(lang, "invoice.not_found", id)
+}
+
+func InvoiceAlreadyPaidText(lang i18n.Lang, id int64) string {
+	return plain(lang, "invoice.already_paid", id)
+}
+
+func InvoiceOverpaymentText(lang i18n.Lang, inv domain.Invoice) string {
+	return plain(lang, "invoice.overpayment", i18n.For(lang).Money(inv.Left()), inv.ID)
+}
+
+func InvoiceNoRequisitesText(lang i18n.Lang) string {
+	return plain(lang, "invoice.no_requisites")
+}
+
+func InvoiceBadDueText(lang i18n.Lang) string {
+	return plain(lang, "invoice.bad_due")
+}
+
+func InvoiceUsageText(lang i18n.Lang) string {
+	return plain(lang, "invoice.usage")
+}
+
+// InvoiceReminderText reminds the user of an invoice that is past due.
+func InvoiceReminderText(lang i18n.Lang, inv domain.Invoice) string {
+	p := i18n.For(lang)
+	return plain(lang, "invoice.reminder", inv.ID, inv.Number, p.Date(inv.IssuedOn), inv.Client.Name,
+		p.Date(inv.DueOn), p.Money(inv.Left()), inv.ID)
+}
+
+// ------------------ ACT MESSAGE ------------------
+
+func ActDisabledText(lang i18n.Lang) string {
+	return plain(lang, "act.disabled")
+}
+
+// writeAct appends the header of an act, its client, amount and what it was issued for.
+func writeAct(b *render.Builder, p *i18n.Printer, act domain.Act) {
+	b.Text(p.T("act.header", act.ID, act.Number, p.Date(act.IssuedOn)))
+	b.Text("\n")
+	b.Text(p.T("act.client", act.Client.Name))
+	b.Text("\n")
+	b.Text(p.T("entry.amount"))
+	b.Text(p.Money(act.Amount))
+	b.Text("\n")
+	if act.InvoiceID != 0 {
+		b.Text(p.T("act.basis_invoice", act.InvoiceID, act.InvoiceNumber, p.Date(act.InvoiceIssuedOn)))
+	} else {
+		b.Text(p.T("act.basis_income", act.IncomeID))
+	}
+	if act.Description != "" {
+		b.Text("\n")
+		b.Text(p.T("entry.note"))
+		b.Text(act.Description)
+	}
+}
+
+func ActIssuedText(lang i18n.Lang, act domain.Act) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("act.issued"))
+	b.Text("\n")
+	writeAct(b, p, act)
+	return b.String()
+}
+
+func ActText(lang i18n.Lang, act domain.Act) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	writeAct(b, p, act)
+	return b.String()
+}
+
+// ActExistsText shows the act issued before for the same invoice or income.
+func ActExistsText(lang i18n.Lang, act domain.Act) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+	b.Text(p.T("act.exists"))
+	b.Text("\n")
+	writeAct(b, p, act)
+	return b.String()
+}
+
+// ActListText lists the acts of year, or of all years if year is 0.
+func ActListText(lang i18n.Lang, acts []domain.Act, year int) string {
+	if len(acts) == 0 {
+		return plain(lang, "act.list_empty")
+	}
+
+	p := i18n.For(lang)
+	b := render.HTML()
+	if year == 0 {
+		b.Text(p.T("act.list_title_all"))
+	} else {
+		b.Text(p.T("act.list_title", year))
+	}
+
+	for _, act := range acts {
+		b.Newline()
+		b.Text(p.T("act.list_line", act.ID, act.Number, p.Date(act.IssuedOn), act.Client.Name, p.Money(act.Amount)))
+		if act.InvoiceID != 0 {
+			b.Text(p.T("act.list_invoice", act.InvoiceID))
+		} else {
+			b.Text(p.T("act.list_income", act.IncomeID))
+		}
+	}
+	return b.String()
+}
+
+func ActNotFoundText(lang i18n.Lang, id int64) string {
+	return plain(lang, "act.not_found", id)
+}
+
+func ActIncomeNotFoundText(lang i18n.Lang, id int64) string {
+	return plain(lang, "act.income_not_found", id)
+}
+
+func ActUsageText(lang i18n.Lang) string {
+	return plain(lang, "act.usage")
+}
+
+// ------------------ PAY MESSAGE ------------------
+
+func PayDisabledText(lang i18n.Lang) string {
+	return plain(lang, "pay.disabled")
+}
+
+func PayUsageText(lang i18n.Lang) string {
+	return plain(lang, "pay.usage")
+}
+
+func PayNoRequisitesText(lang i18n.Lang) string {
+	return plain(lang, "pay.no_requisites")
+}
+
+func PayNothingDueText(lang i18n.Lang, year, quarter int) string {
+	return plain(lang, "pay.nothing_due", quarter, year)
+}
+
+// PayText shows the payment order of the single tax payment with the requisites of the
+// treasury, to check them against the bank app.
+func PayText(lang i18n.Lang, order domain.PaymentOrder) string {
+	p := i18n.For(lang)
+	b := render.HTML()
+
+	b.Bold(p.T("pay.title", order.Quarter, order.Year))
+	b.Text("\n")
+	b.Text(p.T("entry.amount"))
+	b.Text(p.Money(order.Amount))
+	b.Text("\n")
+	b.Text(p.T("pay.payee", order.Payee.Name, order.Payee.INN, order.Payee.KPP))
+	b.Text("\n")
+	b.Text(p.T("pay.account", order.Payee.Account, order.Payee.BIK, order.Payee.CorrAccount))
+	b.Text("\n")
+	b.Text(p.T("pay.kbk", order.Payee.KBK, order.Payee.OKTMO))
+	b.Text("\n")
+	b.Text(p.T("pay.purpose", order.Purpose))
+	b.Text("\n\n")
+	b.Text(p.T("pay.hint"))
+
 	return b.String()
 }
@@ -6,12 +6,51 @@ import (
 	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 )
 
+// CommandObserver is told about every handled command and edit.
+type CommandObserver interface {
+	ObserveCommand(command, outcome string, d time.Duration)
+}
+
 // BotDeps contains all dependencies for the bot.
 type BotDeps struct {
 	Identities domain.IdentityStore
 	Income     domain.IncomeUsecase
 	Payment    domain.PaymentUsecase
 	Total      domain.TotalUsecase
+	// Tokens manages API tokens; if nil, /token replies that the API is disabled.
+	Tokens domain.TokenUsecase
+	// Links binds identities of several transports to one account; if nil, /link is disabled.
+	Links domain.LinkUsecase
+	// Messages maps transport messages to the entries they created; if nil, edits are ignored.
+	Messages domain.MessageEntryStore
+	// Langs keeps the language chosen with /lang; if nil, the transport's language is used.
+	Langs domain.LanguageStore
+	// Recurring schedules recurring incomes; if nil, /recurring replies that it is disabled.
+	Recurring domain.RecurringUsecase
+	// Forecast projects the year to its end; if nil, /forecast replies that it is disabled.
+	Forecast domain.ForecastUsecase
+	// Limits watches the USN limit and the VAT threshold; if nil, /limits replies that it is
+	// disabled and /add does not warn.
+	Limits domain.LimitsUsecase
+	// Chart aggregates incomes by month; if nil, /chart replies that it is disabled.
+	Chart domain.ChartUsecase
+	// Report breaks income down by month or week; if nil, /report replies that it is disabled.
+	Report domain.ReportUsecase
+	// Search finds entries by their notes; if nil, /find replies that it is disabled.
+	Search domain.SearchUsecase
+	// Invoices keeps clients, requisites and invoices; if nil, /client, /clients, /requisites,
+	// /invoice and /invoices reply that invoicing is disabled.
+	Invoices domain.InvoiceUsecase
+	// Acts issues acts of completed work with the requisites of Invoices; if either is nil,
+	// /act and /acts reply that acts are disabled.
+	Acts domain.ActUsecase
+	// TaxPayments prepares payment orders of the single tax payment; if nil, /pay replies
+	// that they are disabled.
+	TaxPayments domain.TaxPaymentUsecase
+	// Documents renders invoices and acts as files; if nil, they are replied with as text only.
+	Documents domain.DocumentRenderer
+	// Observer records handled commands (e.g. metrics); if nil, nothing is recorded.
+	Observer CommandObserver
 	// Now returns current time; if nil, time.Now is used.
 	Now func() time.Time
 }
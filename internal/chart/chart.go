@@ -0,0 +1,157 @@
+// Package chart renders small PNG charts with the standard library only, so that the
+// bot can send them as photos without font files or native dependencies.
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// Size of the monthly chart and its plot area, in pixels.
+const (
+	Width  = 800
+	Height = 480
+
+	marginLeft   = 90
+	marginRight  = 20
+	marginTop    = 50
+	marginBottom = 50
+
+	textScale = 3
+)
+
+// Colors of the monthly chart.
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorGrid       = color.RGBA{0xe3, 0xe6, 0xea, 0xff}
+	colorAxis       = color.RGBA{0x60, 0x66, 0x70, 0xff}
+	colorText       = color.RGBA{0x30, 0x34, 0x3a, 0xff}
+	// ColorIncome and ColorTax are the bar colors, named in the caption of the chart.
+	ColorIncome = color.RGBA{0x4a, 0x90, 0xd9, 0xff} // blue
+	ColorTax    = color.RGBA{0xf0, 0x8c, 0x2e, 0xff} // orange
+)
+
+// MonthlyPNG draws the income of each month of m as a bar, with the tax of the month
+// laid over it on the same scale. The year is printed at the top, month numbers under
+// the bars and compact ruble amounts (250K, 1.5M) along the value axis.
+func MonthlyPNG(m domain.MonthlyIncome) ([]byte, error) {
+	const op = "chart.MonthlyPNG"
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	plot := image.Rect(marginLeft, marginTop, Width-marginRight, Height-marginBottom)
+
+	var peak int64
+	for _, v := range m.Income {
+		peak = max(peak, v)
+	}
+
+	top, step := axisScale(peak)
+
+	// Grid lines with their amounts, bottom to top.
+	for v := int64(0); v <= top; v += step {
+		y := plot.Max.Y - scaleTo(v, top, plot.Dy())
+		fill(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), colorGrid)
+
+		label := compactRubles(v)
+		drawText(img, plot.Min.X-10-textWidth(label, textScale), y-glyphH*textScale/2, label, textScale, colorText)
+	}
+
+	slot := plot.Dx() / 12
+	barW := slot * 2 / 3
+	taxW := barW / 2
+
+	for i := range 12 {
+		x := plot.Min.X + i*slot + (slot-barW)/2
+
+		if h := scaleTo(m.Income[i], top, plot.Dy()); h > 0 {
+			fill(img, image.Rect(x, plot.Max.Y-h, x+barW, plot.Max.Y), ColorIncome)
+		}
+
+		// A tax of a few pixels is still drawn, so that months with income show it.
+		if h := scaleTo(m.Tax[i], top, plot.Dy()); m.Tax[i] > 0 {
+			h = max(h, 2)
+			tx := x + (barW-taxW)/2
+			fill(img, image.Rect(tx, plot.Max.Y-h, tx+taxW, plot.Max.Y), ColorTax)
+		}
+
+		label := strconv.Itoa(i + 1)
+		drawText(img, x+(barW-textWidth(label, textScale))/2, plot.Max.Y+12, label, textScale, colorText)
+	}
+
+	// Axes over the bars.
+	fill(img, image.Rect(plot.Min.X, plot.Max.Y, plot.Max.X, plot.Max.Y+2), colorAxis)
+	fill(img, image.Rect(plot.Min.X-2, plot.Min.Y, plot.Min.X, plot.Max.Y+2), colorAxis)
+
+	drawText(img, plot.Min.X, 15, strconv.Itoa(m.Year), 4, colorText)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// axisScale rounds peak (kopecks) up to a top of the value axis made of 4 to 5 steps
+// of 1, 2 or 5 times a power of ten rubles. An empty year gets a 400 ₽ axis.
+func axisScale(peak int64) (top, step int64) {
+	step = 100_00 // 100 ₽
+
+	for {
+		for _, k := range []int64{1, 2, 5} {
+			if s := step * k; s*5 >= peak {
+				top = s
+				for top < peak {
+					top += s
+				}
+				return max(top, s*4), s
+			}
+		}
+		step *= 10
+	}
+}
+
+// scaleTo maps v in [0..top] to [0..size] pixels.
+func scaleTo(v, top int64, size int) int {
+	if v <= 0 || top <= 0 {
+		return 0
+	}
+	return int(min(v, top) * int64(size) / top)
+}
+
+// compactRubles formats kopecks as whole rubles with a K or M suffix: 1.5M, 250K, 900.
+func compactRubles(kopecks int64) string {
+	rub := kopecks / 100
+
+	switch {
+	case rub >= 1_000_000:
+		return decimal(rub, 1_000_000) + "M"
+	case rub >= 1_000:
+		return decimal(rub, 1_000) + "K"
+	default:
+		return strconv.FormatInt(rub, 10)
+	}
+}
+
+// decimal returns v/unit with at most one decimal digit, dropping ".0".
+func decimal(v, unit int64) string {
+	tenths := v * 10 / unit
+	s := strconv.FormatInt(tenths/10, 10)
+	if d := tenths % 10; d != 0 {
+		s += "." + strconv.FormatInt(d, 10)
+	}
+	return s
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{c}, image.Point{}, draw.Src)
+}
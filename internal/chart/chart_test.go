@@ -0,0 +1,61 @@
+package chart_test
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/chart"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+)
+
+func TestMonthlyPNG_DrawsBarsWithTaxOverlay(t *testing.T) {
+	m := domain.MonthlyIncome{Year: 2025}
+	m.Income[0], m.Tax[0] = 400_000_00, 24_000_00 // January
+	m.Income[5], m.Tax[5] = 100_000_00, 6_000_00  // June
+
+	b, err := chart.MonthlyPNG(m)
+	if err != nil {
+		t.Fatalf("MonthlyPNG: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if got := img.Bounds().Size(); got.X != chart.Width || got.Y != chart.Height {
+		t.Fatalf("size = %v", got)
+	}
+
+	// Bars stand on the axis: look just above it in the middle of each month's slot.
+	const left, right, bottom = 90, 20, 50
+	slot := (chart.Width - left - right) / 12
+	base := chart.Height - bottom - 3
+
+	at := func(month, dy int) [3]uint32 {
+		r, g, b, _ := img.At(left+month*slot+slot/2, base-dy).RGBA()
+		return [3]uint32{r >> 8, g >> 8, b >> 8}
+	}
+	rgb := func(c interface{ RGBA() (r, g, b, a uint32) }) [3]uint32 {
+		r, g, b, _ := c.RGBA()
+		return [3]uint32{r >> 8, g >> 8, b >> 8}
+	}
+
+	if got := at(0, 0); got != rgb(chart.ColorTax) {
+		t.Errorf("January base = %v, want the tax color", got)
+	}
+	if got := at(0, 100); got != rgb(chart.ColorIncome) {
+		t.Errorf("January above the tax = %v, want the income color", got)
+	}
+	if got := at(5, 0); got != rgb(chart.ColorTax) {
+		t.Errorf("June base = %v, want the tax color", got)
+	}
+	// June is a quarter of January: its bar ends well below January's height.
+	if got := at(5, 200); got == rgb(chart.ColorIncome) {
+		t.Errorf("June at 200px = income color, want the bar to be lower")
+	}
+	if got := at(2, 0); got == rgb(chart.ColorIncome) || got == rgb(chart.ColorTax) {
+		t.Errorf("March (no income) has a bar: %v", got)
+	}
+}
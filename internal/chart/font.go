@@ -0,0 +1,56 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+)
+
+// glyphs is a 3x5 bitmap font for the few characters the charts print: digits,
+// the decimal point and the K/M suffixes of compact amounts. '#' is a lit pixel.
+var glyphs = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	' ': {"...", "...", "...", "...", "..."},
+}
+
+const (
+	glyphW = 3
+	glyphH = 5
+)
+
+// textWidth returns the width of s drawn at scale, with one scaled pixel between glyphs.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphW+1) - 1) * scale
+}
+
+// drawText draws s with its top-left corner at (x, y); unknown characters are skipped.
+func drawText(img *image.RGBA, x, y int, s string, scale int, c color.Color) {
+	for _, r := range s {
+		g, ok := glyphs[r]
+		if ok {
+			for row, line := range g {
+				for col, px := range line {
+					if px == '#' {
+						fill(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+					}
+				}
+			}
+		}
+		x += (glyphW + 1) * scale
+	}
+}
@@ -1,22 +1,78 @@
 package cryptostore
 
-import "github.com/tuor4eg/ip_accounting_bot/internal/crypto"
+import (
+	"sort"
 
-// SetCryptoKeys configures HMAC and AEAD encryption keys for the store
+	"github.com/tuor4eg/ip_accounting_bot/internal/crypto"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// SetCryptoKeys configures the active HMAC and AEAD encryption keys for the store.
+// The keys are also registered in the keyring under their kids.
 func (s *BaseCryptoStore) SetCryptoKeys(hmacKey string, hmacKid int16, aeadKey string, aeadKid int16) error {
-	box, err := crypto.NewAEADBox([]byte(aeadKey))
-	if err != nil {
-		return err
+	const op = "cryptostore.SetCryptoKeys"
+
+	if err := s.AddHMACKey(hmacKid, hmacKey); err != nil {
+		return validate.Wrap(op, err)
 	}
 
-	s.hmacKey = []byte(hmacKey)
+	if err := s.AddAEADKey(aeadKid, aeadKey); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	s.hmacKey = s.keyring.hmac[hmacKid]
 	s.hmacKid = hmacKid
-	s.aeadBox = box
+	s.aeadBox = s.keyring.aead[aeadKid]
 	s.aeadKid = aeadKid
 
 	return nil
 }
 
+// AddHMACKey registers an HMAC key version without making it active.
+// Used for previous kids that identities may still be hashed with.
+func (s *BaseCryptoStore) AddHMACKey(kid int16, key string) error {
+	const op = "cryptostore.AddHMACKey"
+
+	if kid <= 0 {
+		return validate.Wrap(op, ErrInvalidKid)
+	}
+
+	if key == "" {
+		return validate.Wrap(op, ErrEmptyKey)
+	}
+
+	if s.keyring.hmac == nil {
+		s.keyring.hmac = make(map[int16][]byte)
+	}
+
+	s.keyring.hmac[kid] = []byte(key)
+
+	return nil
+}
+
+// AddAEADKey registers an AEAD key version without making it active.
+// Used for previous kids that PII may still be encrypted with.
+func (s *BaseCryptoStore) AddAEADKey(kid int16, key string) error {
+	const op = "cryptostore.AddAEADKey"
+
+	if kid <= 0 {
+		return validate.Wrap(op, ErrInvalidKid)
+	}
+
+	box, err := crypto.NewAEADBox([]byte(key))
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if s.keyring.aead == nil {
+		s.keyring.aead = make(map[int16]*crypto.AEADBox)
+	}
+
+	s.keyring.aead[kid] = box
+
+	return nil
+}
+
 // GetHMACKey returns the HMAC key for signing
 func (s *BaseCryptoStore) GetHMACKey() []byte {
 	return s.hmacKey
@@ -42,10 +98,49 @@ func (s *BaseCryptoStore) HasCryptoKeys() bool {
 	return s.hmacKey != nil && s.aeadBox != nil
 }
 
+// HMACKids returns all known HMAC kids: the active one first, then the rest newest first.
+func (s *BaseCryptoStore) HMACKids() []int16 {
+	kids := make([]int16, 0, len(s.keyring.hmac))
+
+	for kid := range s.keyring.hmac {
+		if kid != s.hmacKid {
+			kids = append(kids, kid)
+		}
+	}
+
+	sort.Slice(kids, func(i, j int) bool { return kids[i] > kids[j] })
+
+	if s.hmacKey != nil {
+		kids = append([]int16{s.hmacKid}, kids...)
+	}
+
+	return kids
+}
+
+// GetAEADBoxByKid returns the AEAD box for the given kid, or nil if the kid is unknown.
+func (s *BaseCryptoStore) GetAEADBoxByKid(kid int16) *crypto.AEADBox {
+	return s.keyring.aead[kid]
+}
+
 // ExternalHash generates a hash for external ID binding to prevent cross-transport collisions
 func (s *BaseCryptoStore) ExternalHash(transport, externalID string) []byte {
+	return externalHash(s.hmacKey, transport, externalID)
+}
+
+// ExternalHashForKid is ExternalHash computed with a specific HMAC key version.
+// Returns nil if the kid is unknown.
+func (s *BaseCryptoStore) ExternalHashForKid(kid int16, transport, externalID string) []byte {
+	key, ok := s.keyring.hmac[kid]
+	if !ok {
+		return nil
+	}
+
+	return externalHash(key, transport, externalID)
+}
+
+func externalHash(key []byte, transport, externalID string) []byte {
 	// Bind the hash to transport to prevent cross-transport collisions.
 	// Example: "telegram|123456789"
 	payload := transport + "|" + externalID
-	return crypto.HMAC256(s.hmacKey, []byte(payload))
+	return crypto.HMAC256(key, []byte(payload))
 }
@@ -0,0 +1,107 @@
+package cryptostore_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/cryptostore"
+)
+
+const (
+	aeadKeyV1 = "abcdefabcdefabcdefabcdefabcdef12"
+	aeadKeyV2 = "0123456789abcdef0123456789abcdef"
+)
+
+func TestKeyring_HMACKidsActiveFirst(t *testing.T) {
+	t.Parallel()
+
+	var s cryptostore.BaseCryptoStore
+
+	if err := s.AddHMACKey(1, "hmac-v1"); err != nil {
+		t.Fatalf("AddHMACKey: %v", err)
+	}
+	if err := s.AddHMACKey(3, "hmac-v3"); err != nil {
+		t.Fatalf("AddHMACKey: %v", err)
+	}
+	if err := s.SetCryptoKeys("hmac-v2", 2, aeadKeyV2, 2); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+
+	if got, want := s.HMACKids(), []int16{2, 3, 1}; !slices.Equal(got, want) {
+		t.Fatalf("HMACKids() = %v, want %v", got, want)
+	}
+}
+
+func TestKeyring_ExternalHashForKid(t *testing.T) {
+	t.Parallel()
+
+	var s cryptostore.BaseCryptoStore
+
+	if err := s.AddHMACKey(1, "hmac-v1"); err != nil {
+		t.Fatalf("AddHMACKey: %v", err)
+	}
+	if err := s.SetCryptoKeys("hmac-v2", 2, aeadKeyV2, 2); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+
+	active := s.ExternalHash("telegram", "42")
+
+	if !bytes.Equal(active, s.ExternalHashForKid(2, "telegram", "42")) {
+		t.Fatal("hash for the active kid must match ExternalHash")
+	}
+	if bytes.Equal(active, s.ExternalHashForKid(1, "telegram", "42")) {
+		t.Fatal("hashes for different kids must differ")
+	}
+	if s.ExternalHashForKid(9, "telegram", "42") != nil {
+		t.Fatal("unknown kid must yield nil")
+	}
+}
+
+func TestKeyring_OldAEADBoxStillOpens(t *testing.T) {
+	t.Parallel()
+
+	var s cryptostore.BaseCryptoStore
+
+	if err := s.SetCryptoKeys("hmac-v1", 1, aeadKeyV1, 1); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+
+	sealed, err := s.GetAEADBox().Seal([]byte("chat"), []byte("telegram"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	// Rotate: v2 becomes active, v1 stays in the keyring.
+	if err := s.SetCryptoKeys("hmac-v2", 2, aeadKeyV2, 2); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+
+	if s.GetAEADKid() != 2 {
+		t.Fatalf("GetAEADKid() = %d, want 2", s.GetAEADKid())
+	}
+
+	pt, err := s.GetAEADBoxByKid(1).Open(sealed, []byte("telegram"))
+	if err != nil {
+		t.Fatalf("Open with old kid: %v", err)
+	}
+	if string(pt) != "chat" {
+		t.Fatalf("Open() = %q, want %q", pt, "chat")
+	}
+}
+
+func TestKeyring_RejectsInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	var s cryptostore.BaseCryptoStore
+
+	if err := s.AddHMACKey(0, "k"); err == nil {
+		t.Fatal("kid 0 must be rejected")
+	}
+	if err := s.AddHMACKey(1, ""); err == nil {
+		t.Fatal("empty HMAC key must be rejected")
+	}
+	if err := s.AddAEADKey(1, "short"); err == nil {
+		t.Fatal("AEAD key shorter than 32 bytes must be rejected")
+	}
+}
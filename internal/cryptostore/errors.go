@@ -0,0 +1,8 @@
+package cryptostore
+
+import "errors"
+
+var (
+	ErrInvalidKid = errors.New("key id must be positive")
+	ErrEmptyKey   = errors.New("key is empty")
+)
@@ -5,10 +5,15 @@ import "github.com/tuor4eg/ip_accounting_bot/internal/crypto"
 // CryptoStore defines cryptographic key storage capabilities that any storage can implement
 type CryptoStore interface {
 	SetCryptoKeys(hmacKey string, hmacKid int16, aeadKey string, aeadKid int16) error
+	AddHMACKey(kid int16, key string) error
+	AddAEADKey(kid int16, key string) error
 	GetHMACKey() []byte
 	GetHMACKid() int16
 	GetAEADBox() *crypto.AEADBox
 	GetAEADKid() int16
 	HasCryptoKeys() bool
+	HMACKids() []int16
+	GetAEADBoxByKid(kid int16) *crypto.AEADBox
 	ExternalHash(transport, externalID string) []byte
+	ExternalHashForKid(kid int16, transport, externalID string) []byte
 }
@@ -9,4 +9,13 @@ type BaseCryptoStore struct {
 
 	aeadBox *crypto.AEADBox // AES-GCM box
 	aeadKid int16           // e.g., 1
+
+	keyring Keyring // every known key version, including the active ones
+}
+
+// Keyring holds all known key versions by kid.
+// Old kids are kept so that rows written before a rotation can still be read and upgraded.
+type Keyring struct {
+	hmac map[int16][]byte
+	aead map[int16]*crypto.AEADBox
 }
@@ -0,0 +1,187 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/money"
+	"github.com/tuor4eg/ip_accounting_bot/internal/pdf"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// actAcceptance is the clause of an act by which the client accepts the work.
+const actAcceptance = "Вышеперечисленные услуги выполнены полностью и в срок. " +
+	"Заказчик претензий по объёму, качеству и срокам оказания услуг не имеет."
+
+// actText is what an act says, shared by its PDF and DOCX layouts.
+type actText struct {
+	title       string
+	contractor  string
+	customer    string
+	basis       string // "" for an act issued for an income
+	description string
+	summary     string
+	words       string
+}
+
+func newActText(act domain.Act, seller domain.Requisites) actText {
+	t := actText{
+		title:       fmt.Sprintf("Акт № %d от %s", act.Number, date(act.IssuedOn)),
+		contractor:  join(seller.Name, labeled("ИНН", seller.INN), labeled("ОГРНИП", seller.OGRNIP), seller.Address),
+		customer:    join(act.Client.Name, labeled("ИНН", act.Client.INN), labeled("КПП", act.Client.KPP), act.Client.Address),
+		description: act.Description,
+		summary:     fmt.Sprintf("Всего оказано услуг 1, на сумму %s руб.", amount(act.Amount)),
+		words:       money.WordsRU(act.Amount),
+	}
+
+	if act.InvoiceID != 0 {
+		t.basis = fmt.Sprintf("Счёт на оплату № %d от %s", act.InvoiceNumber, date(act.InvoiceIssuedOn))
+	}
+
+	if t.description == "" {
+		t.description = DefaultService
+	}
+
+	return t
+}
+
+// ActPDF renders act as the usual "акт выполненных работ": the parties and the basis,
+// the service line and the total, the acceptance clause and the signatures of both.
+func (r *Renderer) ActPDF(act domain.Act, seller domain.Requisites) ([]byte, error) {
+	const op = "document.ActPDF"
+
+	t := newActText(act, seller)
+
+	doc := pdf.New().SetTitle(t.title)
+	s := r.sheet(doc.AddPage())
+
+	y := marginTop + 20
+	s.text(true, sizeTitle, marginLeft, y, t.title)
+	y += 8
+	s.page.Line(marginLeft, y, marginLeft+contentWidth, y, 1.5)
+
+	y += 20
+	y = s.party(y, "Исполнитель:", t.contractor)
+	y += 6
+	y = s.party(y, "Заказчик:", t.customer)
+	if t.basis != "" {
+		y += 6
+		y = s.party(y, "Основание:", t.basis)
+	}
+
+	y = s.serviceTable(y+10, t.description, act.Amount)
+
+	y += 16
+	y = s.totals(y, act.Amount, "Всего:")
+
+	y += 10
+	s.text(false, sizeText, marginLeft, y, t.summary)
+	y += leading + 2
+	y = s.paragraph(true, sizeText, marginLeft, y, contentWidth, t.words)
+
+	y += 8
+	y = s.paragraph(false, sizeText, marginLeft, y, contentWidth, actAcceptance)
+
+	y += 4
+	s.page.Line(marginLeft, y, marginLeft+contentWidth, y, 1.5)
+
+	y += 30
+	half := contentWidth / 2
+	s.signBlock(marginLeft, y, half-20, "ИСПОЛНИТЕЛЬ", seller.Name)
+	s.signBlock(marginLeft+half, y, half-20, "ЗАКАЗЧИК", act.Client.Name)
+
+	if s.err != nil {
+		return nil, validate.Wrap(op, s.err)
+	}
+
+	b, err := doc.Bytes()
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return b, nil
+}
+
+// ActDOCX renders act as ActPDF does, as a Word document the user can edit before
+// signing (e.g. to break the work down into several lines).
+func (r *Renderer) ActDOCX(act domain.Act, seller domain.Requisites) ([]byte, error) {
+	const op = "document.ActDOCX"
+
+	t := newActText(act, seller)
+
+	var d docx
+
+	d.paragraph("", run{text: t.title, bold: true, size: sizeTitle})
+	d.rule()
+
+	parties := [][]cell{
+		{{runs: []run{{text: "Исполнитель:"}}}, {runs: []run{{text: t.contractor, bold: true}}}},
+		{{runs: []run{{text: "Заказчик:"}}}, {runs: []run{{text: t.customer, bold: true}}}},
+	}
+	if t.basis != "" {
+		parties = append(parties, []cell{{runs: []run{{text: "Основание:"}}}, {runs: []run{{text: t.basis, bold: true}}}})
+	}
+	d.table([]int{1500, docxWidth - 1500}, false, parties...)
+	d.paragraph("")
+
+	// № | Наименование работ, услуг | Кол-во | Ед. | Цена | Сумма
+	widths := []int{450, 0, 900, 700, 1500, 1500}
+	widths[1] = docxWidth - widths[0] - widths[2] - widths[3] - widths[4] - widths[5]
+
+	head := make([]cell, 0, len(widths))
+	for _, title := range []string{"№", "Наименование работ, услуг", "Кол-во", "Ед.", "Цена", "Сумма"} {
+		head = append(head, cell{runs: []run{{text: title, bold: true}}, align: "center"})
+	}
+
+	d.table(widths, true, head, []cell{
+		{runs: []run{{text: "1"}}, align: "center"},
+		{runs: []run{{text: t.description}}},
+		{runs: []run{{text: "1"}}, align: "right"},
+		{runs: []run{{text: "усл."}}},
+		{runs: []run{{text: amount(act.Amount)}}, align: "right"},
+		{runs: []run{{text: amount(act.Amount)}}, align: "right"},
+	})
+
+	d.paragraph("")
+	for _, row := range [][2]string{
+		{"Итого: ", amount(act.Amount)},
+		{"Без налога (НДС) ", "-"},
+		{"Всего: ", amount(act.Amount)},
+	} {
+		d.paragraph("right", run{text: row[0] + row[1], bold: true})
+	}
+
+	d.paragraph("")
+	d.paragraph("", run{text: t.summary})
+	d.paragraph("", run{text: t.words, bold: true})
+	d.paragraph("")
+	d.paragraph("", run{text: actAcceptance})
+	d.rule()
+	d.paragraph("")
+
+	half := docxWidth / 2
+	d.table([]int{half, docxWidth - half}, false,
+		[]cell{{runs: []run{{text: "ИСПОЛНИТЕЛЬ", bold: true}}}, {runs: []run{{text: "ЗАКАЗЧИК", bold: true}}}},
+		[]cell{{runs: []run{{text: seller.Name}}}, {runs: []run{{text: act.Client.Name}}}},
+		[]cell{{}, {}},
+		[]cell{{runs: []run{{text: "____________________ (подпись)"}}}, {runs: []run{{text: "____________________ (подпись)"}}}},
+	)
+
+	b, err := d.bytes(t.title, act.IssuedOn)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return b, nil
+}
+
+// signBlock draws the signature of a party in a column of width at x: the role, the
+// name and a line to sign on.
+func (s *sheet) signBlock(x, y, width float64, role, name string) {
+	s.text(true, sizeText, x, y, role)
+	y = s.paragraph(false, sizeText, x, y+leading+2, width, name)
+
+	y += 22
+	s.page.Line(x, y, x+width*0.6, y, 0.5)
+	s.textCenter(false, sizeSmall, x+width*0.3, y+9, "подпись")
+}
@@ -0,0 +1,170 @@
+package document_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/document"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+)
+
+var (
+	testAct = domain.Act{
+		Number:          3,
+		Client:          domain.Counterparty{Name: "ООО «Ромашка»", INN: "7707083893", KPP: "770701001"},
+		IssuedOn:        time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		Amount:          50_000_00,
+		Description:     "Разработка сайта <по договору> & поддержка",
+		InvoiceID:       12,
+		InvoiceNumber:   7,
+		InvoiceIssuedOn: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+	testSeller = domain.Requisites{Name: "ИП Иванов Иван Иванович", INN: "500100732259", OGRNIP: "304500116000157"}
+)
+
+func TestRenderer_ActPDF(t *testing.T) {
+	r := newRenderer(t)
+
+	b, err := r.ActPDF(testAct, testSeller)
+	if err != nil {
+		t.Fatalf("ActPDF: %v", err)
+	}
+
+	text := strings.Join(pdfText(t, b), "\n")
+
+	for _, want := range []string{
+		"Акт № 3 от 1 апреля 2025 г.",
+		"ИП Иванов Иван Иванович, ИНН 500100732259, ОГРНИП 304500116000157",
+		"ООО «Ромашка», ИНН 7707083893, КПП 770701001",
+		"Счёт на оплату № 7 от 5 марта 2025 г.",
+		"50 000,00",
+		"Пятьдесят тысяч рублей 00 копеек",
+		"ИСПОЛНИТЕЛЬ",
+		"ЗАКАЗЧИК",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("no %q in the act:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderer_ActPDF_ForIncome(t *testing.T) {
+	r := newRenderer(t)
+
+	act := testAct
+	act.InvoiceID, act.InvoiceNumber, act.InvoiceIssuedOn, act.IncomeID = 0, 0, time.Time{}, 5
+	act.Description = ""
+
+	b, err := r.ActPDF(act, testSeller)
+	if err != nil {
+		t.Fatalf("ActPDF: %v", err)
+	}
+
+	text := strings.Join(pdfText(t, b), "\n")
+
+	if strings.Contains(text, "Основание") {
+		t.Errorf("act for an income has a basis:\n%s", text)
+	}
+	if !strings.Contains(text, document.DefaultService) {
+		t.Errorf("no default service line in the act:\n%s", text)
+	}
+}
+
+// docxText returns the text of the paragraphs of word/document.xml, one string per
+// paragraph, checking that every part of the package is well-formed XML.
+func docxText(t *testing.T, b []byte) []string {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+
+	var paragraphs []string
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+
+		var (
+			dec  = xml.NewDecoder(rc)
+			para strings.Builder
+			inT  bool
+		)
+
+		for {
+			tok, err := dec.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", f.Name, err)
+			}
+
+			if f.Name != "word/document.xml" {
+				continue
+			}
+
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				inT = tok.Name.Local == "t"
+			case xml.EndElement:
+				inT = false
+				if tok.Name.Local == "p" {
+					paragraphs = append(paragraphs, para.String())
+					para.Reset()
+				}
+			case xml.CharData:
+				if inT {
+					para.Write(tok)
+				}
+			}
+		}
+
+		rc.Close()
+	}
+
+	return paragraphs
+}
+
+func TestRenderer_ActDOCX(t *testing.T) {
+	r := newRenderer(t)
+
+	b, err := r.ActDOCX(testAct, testSeller)
+	if err != nil {
+		t.Fatalf("ActDOCX: %v", err)
+	}
+
+	again, err := r.ActDOCX(testAct, testSeller)
+	if err != nil || !bytes.Equal(b, again) {
+		t.Errorf("ActDOCX is not reproducible (err %v)", err)
+	}
+
+	paragraphs := docxText(t, b)
+	if len(paragraphs) == 0 {
+		t.Fatalf("no paragraphs in word/document.xml")
+	}
+
+	text := strings.Join(paragraphs, "\n")
+
+	for _, want := range []string{
+		"Акт № 3 от 1 апреля 2025 г.",
+		"ИП Иванов Иван Иванович, ИНН 500100732259, ОГРНИП 304500116000157",
+		"Счёт на оплату № 7 от 5 марта 2025 г.",
+		"Разработка сайта <по договору> & поддержка",
+		"Всего: 50 000,00",
+		"Пятьдесят тысяч рублей 00 копеек",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("no %q in the act:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package document renders invoices and other paperwork of the business as files
+// a client can print or sign.
+package document
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/pdf"
+)
+
+// Page layout, in points.
+const (
+	marginLeft  = 15 * pdf.MM
+	marginRight = 10 * pdf.MM
+	marginTop   = 12 * pdf.MM
+
+	contentWidth = pdf.PageWidth - marginLeft - marginRight
+
+	sizeText  = 9.0
+	sizeSmall = 7.0
+	sizeTitle = 14.0
+	leading   = 11.0 // line height of sizeText
+)
+
+// NewRenderer returns a renderer setting text in regular and headings in bold. The
+// fonts must have Cyrillic glyphs; bold may be the same font as regular.
+func NewRenderer(regular, bold *pdf.Font) *Renderer {
+	return &Renderer{regular: regular, bold: bold}
+}
+
+func (r *Renderer) sheet(page *pdf.Page) *sheet {
+	return &sheet{r: r, page: page}
+}
+
+func (s *sheet) font(bold bool) *pdf.Font {
+	if bold {
+		return s.r.bold
+	}
+	return s.r.regular
+}
+
+// printable replaces the characters f has no glyph for.
+func printable(f *pdf.Font, text string) string {
+	return strings.Map(func(r rune) rune {
+		if f.Has(r) {
+			return r
+		}
+		return '?'
+	}, text)
+}
+
+func (s *sheet) text(bold bool, size, x, y float64, text string) {
+	f := s.font(bold)
+	if err := s.page.Text(f, size, x, y, printable(f, text)); err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *sheet) textRight(bold bool, size, x, y float64, text string) {
+	f := s.font(bold)
+	s.text(bold, size, x-f.Width(printable(f, text), size), y, text)
+}
+
+func (s *sheet) textCenter(bold bool, size, x, y float64, text string) {
+	f := s.font(bold)
+	s.text(bold, size, x-f.Width(printable(f, text), size)/2, y, text)
+}
+
+// paragraph sets text wrapped to width with its first baseline at y and returns the
+// baseline of the line after it.
+func (s *sheet) paragraph(bold bool, size, x, y, width float64, text string) float64 {
+	for _, line := range s.wrap(bold, size, width, text) {
+		s.text(bold, size, x, y, line)
+		y += size * leading / sizeText
+	}
+	return y
+}
+
+func (s *sheet) wrap(bold bool, size, width float64, text string) []string {
+	f := s.font(bold)
+	return f.Wrap(printable(f, text), size, width)
+}
+
+// amount formats kopecks as printed in the tables of documents, e.g. "12 345,00".
+func amount(kopecks int64) string {
+	return i18n.For(i18n.RU).Number(kopecks/100) + fmt.Sprintf(",%02d", abs(kopecks%100))
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// date formats a date as in the headings of documents, e.g. "5 марта 2025 г.".
+func date(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%d %s %d г.", t.Day(), monthsGenitive[t.Month()-1], t.Year())
+}
+
+var monthsGenitive = [12]string{
+	"января", "февраля", "марта", "апреля", "мая", "июня",
+	"июля", "августа", "сентября", "октября", "ноября", "декабря",
+}
+
+// join joins the non-empty parts with ", ".
+func join(parts ...string) string {
+	kept := parts[:0:0]
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
+
+// labeled returns "label value", or "" if value is empty.
+func labeled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + " " + value
+}
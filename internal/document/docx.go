@@ -0,0 +1,164 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A docx document is a zip of WordprocessingML parts. Only what the documents of the
+// bot need is written: A4 pages, paragraphs of plain runs and tables with fixed widths,
+// in Arial, which Word substitutes if it is missing.
+const (
+	nsMain = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	nsRels = "http://schemas.openxmlformats.org/package/2006/relationships"
+	nsDoc  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+	// Page of the document in twips (1/20 pt), margins as in the PDF layout.
+	docxPageWidth   = 11906
+	docxPageHeight  = 16838
+	docxMarginLeft  = 850
+	docxMarginRight = 567
+	docxMarginTop   = 680
+	docxWidth       = docxPageWidth - docxMarginLeft - docxMarginRight
+)
+
+var docxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+		`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+		`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<Relationships xmlns="` + nsRels + `">` +
+		`<Relationship Id="rId1" Type="` + nsDoc + `/officeDocument" Target="word/document.xml"/>` +
+		`<Relationship Id="rId2" Type="` + nsRels + `/metadata/core-properties" Target="docProps/core.xml"/>` +
+		`</Relationships>`},
+	{"word/_rels/document.xml.rels", `<Relationships xmlns="` + nsRels + `">` +
+		`<Relationship Id="rId1" Type="` + nsDoc + `/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	{"word/styles.xml", `<w:styles xmlns:w="` + nsMain + `"><w:docDefaults>` +
+		`<w:rPrDefault><w:rPr><w:rFonts w:ascii="Arial" w:hAnsi="Arial" w:eastAsia="Arial" w:cs="Arial"/>` +
+		`<w:sz w:val="18"/><w:szCs w:val="18"/><w:lang w:val="ru-RU"/></w:rPr></w:rPrDefault>` +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="40" w:line="240" w:lineRule="auto"/></w:pPr></w:pPrDefault>` +
+		`</w:docDefaults></w:styles>`},
+}
+
+// paragraph adds a paragraph of runs; align is "", "center" or "right".
+func (d *docx) paragraph(align string, runs ...run) {
+	d.body.WriteString("<w:p>")
+	if align != "" {
+		fmt.Fprintf(&d.body, `<w:pPr><w:jc w:val="%s"/></w:pPr>`, align)
+	}
+	d.runs(runs)
+	d.body.WriteString("</w:p>")
+}
+
+// rule adds a horizontal line across the page.
+func (d *docx) rule() {
+	d.body.WriteString(`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="12" w:space="1" w:color="000000"/></w:pBdr></w:pPr></w:p>`)
+}
+
+// table adds a table with columns of widths (twips, summing up to docxWidth at most);
+// bordered draws the grid.
+func (d *docx) table(widths []int, bordered bool, rows ...[]cell) {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+
+	fmt.Fprintf(&d.body, `<w:tbl><w:tblPr><w:tblW w:w="%d" w:type="dxa"/><w:tblLayout w:type="fixed"/>`, total)
+	if bordered {
+		d.body.WriteString("<w:tblBorders>")
+		for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+			fmt.Fprintf(&d.body, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="000000"/>`, side)
+		}
+		d.body.WriteString("</w:tblBorders>")
+	}
+	d.body.WriteString(`<w:tblCellMar><w:left w:w="57" w:type="dxa"/><w:right w:w="57" w:type="dxa"/></w:tblCellMar></w:tblPr><w:tblGrid>`)
+	for _, w := range widths {
+		fmt.Fprintf(&d.body, `<w:gridCol w:w="%d"/>`, w)
+	}
+	d.body.WriteString("</w:tblGrid>")
+
+	for _, row := range rows {
+		d.body.WriteString("<w:tr>")
+		for i, c := range row {
+			fmt.Fprintf(&d.body, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/></w:tcPr>`, widths[i])
+			d.paragraph(c.align, c.runs...)
+			d.body.WriteString("</w:tc>")
+		}
+		d.body.WriteString("</w:tr>")
+	}
+
+	d.body.WriteString("</w:tbl>")
+}
+
+func (d *docx) runs(runs []run) {
+	for _, r := range runs {
+		d.body.WriteString("<w:r>")
+		if r.bold || r.size != 0 {
+			d.body.WriteString("<w:rPr>")
+			if r.bold {
+				d.body.WriteString("<w:b/><w:bCs/>")
+			}
+			if r.size != 0 {
+				fmt.Fprintf(&d.body, `<w:sz w:val="%d"/><w:szCs w:val="%[1]d"/>`, int(r.size*2))
+			}
+			d.body.WriteString("</w:rPr>")
+		}
+		d.body.WriteString(`<w:t xml:space="preserve">`)
+		_ = xml.EscapeText(&d.body, []byte(r.text)) // writes to a strings.Builder never fail
+		d.body.WriteString("</w:t></w:r>")
+	}
+}
+
+// bytes packs the body into a .docx file with title in its properties. The parts are
+// dated modified, so that the same document always gives the same file.
+func (d *docx) bytes(title string, modified time.Time) ([]byte, error) {
+	var doc strings.Builder
+
+	doc.WriteString(`<w:document xmlns:w="` + nsMain + `"><w:body>`)
+	doc.WriteString(d.body.String())
+	fmt.Fprintf(&doc, `<w:sectPr><w:pgSz w:w="%d" w:h="%d"/>`, docxPageWidth, docxPageHeight)
+	fmt.Fprintf(&doc, `<w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="0" w:footer="0" w:gutter="0"/>`,
+		docxMarginTop, docxMarginRight, docxMarginTop, docxMarginLeft)
+	doc.WriteString(`</w:sectPr></w:body></w:document>`)
+
+	var core strings.Builder
+
+	core.WriteString(`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" ` +
+		`xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>`)
+	_ = xml.EscapeText(&core, []byte(title))
+	core.WriteString(`</dc:title></cp:coreProperties>`)
+
+	parts := append(docxParts[:len(docxParts):len(docxParts)],
+		struct{ name, content string }{"docProps/core.xml", core.String()},
+		struct{ name, content string }{"word/document.xml", doc.String()},
+	)
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for _, p := range parts {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: p.name, Method: zip.Deflate, Modified: modified.UTC()})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := w.Write([]byte(xml.Header + p.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,210 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/money"
+	"github.com/tuor4eg/ip_accounting_bot/internal/pdf"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// DefaultService is the line of an invoice or an act issued without a description.
+const DefaultService = "Оказание услуг"
+
+// InvoicePDF renders inv as the usual Russian "счёт на оплату": the bank details of
+// the seller for the payment order on top, then the parties, the service line and the
+// total, also in words.
+func (r *Renderer) InvoicePDF(inv domain.Invoice, seller domain.Requisites) ([]byte, error) {
+	const op = "document.InvoicePDF"
+
+	doc := pdf.New().SetTitle(fmt.Sprintf("Счёт на оплату № %d от %s", inv.Number, date(inv.IssuedOn)))
+	s := r.sheet(doc.AddPage())
+
+	y := s.bankDetails(marginTop, seller)
+
+	y += 30
+	s.text(true, sizeTitle, marginLeft, y, fmt.Sprintf("Счёт на оплату № %d от %s", inv.Number, date(inv.IssuedOn)))
+	y += 8
+	s.page.Line(marginLeft, y, marginLeft+contentWidth, y, 1.5)
+
+	y += 20
+	y = s.party(y, "Поставщик:", join(seller.Name, labeled("ИНН", seller.INN), labeled("ОГРНИП", seller.OGRNIP), seller.Address))
+	y += 6
+	y = s.party(y, "Покупатель:", join(inv.Client.Name, labeled("ИНН", inv.Client.INN), labeled("КПП", inv.Client.KPP), inv.Client.Address))
+
+	y = s.serviceTable(y+10, inv.Description, inv.Amount)
+
+	y += 16
+	y = s.totals(y, inv.Amount, "Всего к оплате:")
+
+	y += 10
+	s.text(false, sizeText, marginLeft, y, fmt.Sprintf("Всего наименований 1, на сумму %s руб.", amount(inv.Amount)))
+	y += leading + 2
+	y = s.paragraph(true, sizeText, marginLeft, y, contentWidth, money.WordsRU(inv.Amount))
+
+	y += 8
+	s.text(false, sizeText, marginLeft, y, "Оплатить не позднее "+date(inv.DueOn))
+	y += 4 + leading
+	s.text(false, sizeSmall, marginLeft, y, fmt.Sprintf("В назначении платежа укажите: Оплата по счёту № %d от %s. Без налога (НДС).",
+		inv.Number, inv.IssuedOn.UTC().Format("02.01.2006")))
+
+	y += 12
+	s.page.Line(marginLeft, y, marginLeft+contentWidth, y, 1.5)
+
+	y += 30
+	s.signature(y, "Предприниматель", seller.Name)
+
+	if s.err != nil {
+		return nil, validate.Wrap(op, s.err)
+	}
+
+	b, err := doc.Bytes()
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return b, nil
+}
+
+// bankDetails draws the table of the seller's bank details a payment order is filled
+// in from and returns its bottom:
+//
+//	| Bank            | БИК   | 044525225            |
+//	|                 | Сч. № | 30101810400000000225 |
+//	| Банк получателя |       |                      |
+//	| ИНН 500100732259| Сч. № | 40802810000000000001 |
+//	| Name            |       |                      |
+//	| Получатель      |       |                      |
+func (s *sheet) bankDetails(top float64, r domain.Requisites) float64 {
+	const (
+		row    = 16.0
+		labelW = 45.0
+		valueW = 130.0
+	)
+
+	left, right := marginLeft, marginLeft+contentWidth
+	x1 := right - labelW - valueW // left of the labels
+	x2 := right - valueW          // left of the values
+
+	bank := s.wrap(false, sizeText, x1-left-6, r.Bank)
+	bankH := max(2, float64(len(bank))+1) * row // the last row is for the caption
+	payee := s.wrap(false, sizeText, x1-left-6, r.Name)
+	payeeH := row + max(2, float64(len(payee))+1)*row
+
+	bottom := top + bankH + payeeH
+
+	s.page.Rect(left, top, contentWidth, bottom-top, 0.75)
+	s.page.Line(x1, top, x1, bottom, 0.75)
+	s.page.Line(x2, top, x2, bottom, 0.75)
+	s.page.Line(left, top+bankH, right, top+bankH, 0.75)
+	s.page.Line(x1, top+row, right, top+row, 0.75)
+	s.page.Line(left, top+bankH+row, x1, top+bankH+row, 0.75)
+
+	base := func(y float64) float64 { return y + row - 4.5 }
+
+	for i, line := range bank {
+		s.text(false, sizeText, left+3, base(top+float64(i)*row), line)
+	}
+	s.text(false, sizeSmall, left+3, base(top+bankH-row), "Банк получателя")
+
+	s.text(false, sizeText, x1+3, base(top), "БИК")
+	s.text(false, sizeText, x2+3, base(top), r.BIK)
+	s.text(false, sizeText, x1+3, base(top+row), "Сч. №")
+	s.text(false, sizeText, x2+3, base(top+row), r.CorrAccount)
+
+	s.text(false, sizeText, left+3, base(top+bankH), "ИНН "+r.INN)
+	s.text(false, sizeText, x1+3, base(top+bankH), "Сч. №")
+	s.text(false, sizeText, x2+3, base(top+bankH), r.Account)
+
+	for i, line := range payee {
+		s.text(false, sizeText, left+3, base(top+bankH+row*float64(i+1)), line)
+	}
+	s.text(false, sizeSmall, left+3, base(bottom-row), "Получатель")
+
+	return bottom
+}
+
+// party draws a labeled party of a document and returns the baseline after it.
+func (s *sheet) party(y float64, label, details string) float64 {
+	const indent = 75.0
+
+	s.text(false, sizeText, marginLeft, y, label)
+	return s.paragraph(true, sizeText, marginLeft+indent, y, contentWidth-indent, details)
+}
+
+// serviceTable draws the table of a document with a single service line and returns
+// its bottom.
+func (s *sheet) serviceTable(top float64, description string, total int64) float64 {
+	if description == "" {
+		description = DefaultService
+	}
+
+	// № | Товары (работы, услуги) | Кол-во | Ед. | Цена | Сумма
+	widths := []float64{22, 0, 45, 35, 75, 75}
+	widths[1] = contentWidth - widths[0] - widths[2] - widths[3] - widths[4] - widths[5]
+
+	xs := make([]float64, len(widths)+1)
+	xs[0] = marginLeft
+	for i, w := range widths {
+		xs[i+1] = xs[i] + w
+	}
+
+	const head = 18.0
+	lines := s.wrap(false, sizeText, widths[1]-6, description)
+	body := float64(len(lines))*leading + 7
+
+	bottom := top + head + body
+
+	s.page.Rect(marginLeft, top, contentWidth, bottom-top, 1)
+	s.page.Line(marginLeft, top+head, marginLeft+contentWidth, top+head, 0.75)
+	for _, x := range xs[1 : len(xs)-1] {
+		s.page.Line(x, top, x, bottom, 0.75)
+	}
+
+	for i, title := range []string{"№", "Товары (работы, услуги)", "Кол-во", "Ед.", "Цена", "Сумма"} {
+		s.textCenter(true, sizeText, (xs[i]+xs[i+1])/2, top+head-6, title)
+	}
+
+	y := top + head + leading
+	s.textCenter(false, sizeText, (xs[0]+xs[1])/2, y, "1")
+	for i, line := range lines {
+		s.text(false, sizeText, xs[1]+3, y+float64(i)*leading, line)
+	}
+	s.textRight(false, sizeText, xs[3]-3, y, "1")
+	s.text(false, sizeText, xs[3]+3, y, "усл.")
+	s.textRight(false, sizeText, xs[5]-3, y, amount(total))
+	s.textRight(false, sizeText, xs[6]-3, y, amount(total))
+
+	return bottom
+}
+
+// totals draws the totals under the table of a document, aligned to its right edge,
+// with last labeling the grand total, and returns the baseline after them.
+func (s *sheet) totals(y float64, total int64, last string) float64 {
+	right := marginLeft + contentWidth
+	labels := right - 80
+
+	for _, row := range [][2]string{
+		{"Итого:", amount(total)},
+		{"Без налога (НДС)", "-"},
+		{last, amount(total)},
+	} {
+		s.textRight(true, sizeText, labels, y, row[0])
+		s.textRight(true, sizeText, right-3, y, row[1])
+		y += leading
+	}
+
+	return y
+}
+
+// signature draws a signature line with the role on the left and the name on the right.
+func (s *sheet) signature(y float64, role, name string) {
+	const lineW = 120.0
+
+	s.text(true, sizeText, marginLeft, y, role)
+	x := marginLeft + 110
+	s.page.Line(x, y+2, x+lineW, y+2, 0.5)
+	s.text(false, sizeText, x+lineW+10, y, name)
+	s.textCenter(false, sizeSmall, x+lineW/2, y+10, "подпись")
+}
@@ -0,0 +1,158 @@
+package document_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/document"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/pdf"
+)
+
+const (
+	fontRegular = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+	fontBold    = "/usr/share/fonts/truetype/dejavu/DejaVuSans-Bold.ttf"
+)
+
+func newRenderer(t *testing.T) *document.Renderer {
+	t.Helper()
+
+	fonts := make([]*pdf.Font, 2)
+	for i, path := range []string{fontRegular, fontBold} {
+		if _, err := os.Stat(path); err != nil {
+			t.Skipf("font %s not available", path)
+		}
+
+		f, err := pdf.LoadFont(path)
+		if err != nil {
+			t.Fatalf("LoadFont: %v", err)
+		}
+		fonts[i] = f
+	}
+
+	return document.NewRenderer(fonts[0], fonts[1])
+}
+
+var (
+	reStream = regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode [^>]*>>\nstream\n`)
+	reTj     = regexp.MustCompile(`/F(\d+) [\d.]+ Tf [\d.-]+ [\d.-]+ Td <([0-9A-F]*)> Tj`)
+	reChar   = regexp.MustCompile(`<([0-9A-F]{4})> <([0-9A-F]+)>`)
+)
+
+// pdfText extracts the text drawn in a PDF written by package pdf, one string per
+// Tj operator, using the ToUnicode maps of its fonts.
+func pdfText(t *testing.T, b []byte) []string {
+	t.Helper()
+
+	var streams []string
+	for _, m := range reStream.FindAllSubmatchIndex(b, -1) {
+		n, _ := strconv.Atoi(string(b[m[2]:m[3]]))
+
+		zr, err := zlib.NewReader(bytes.NewReader(b[m[1] : m[1]+n]))
+		if err != nil {
+			t.Fatalf("zlib: %v", err)
+		}
+		data, _ := io.ReadAll(zr)
+		streams = append(streams, string(data))
+	}
+
+	// ToUnicode maps in the order of the fonts /F1, /F2, ...
+	var cmaps []map[string]string
+	for _, s := range streams {
+		if !strings.Contains(s, "beginbfchar") {
+			continue
+		}
+
+		cmap := map[string]string{}
+		for _, m := range reChar.FindAllStringSubmatch(s, -1) {
+			raw, _ := hex.DecodeString(m[2])
+			units := make([]uint16, len(raw)/2)
+			for i := range units {
+				units[i] = uint16(raw[2*i])<<8 | uint16(raw[2*i+1])
+			}
+			cmap[m[1]] = string(utf16.Decode(units))
+		}
+		cmaps = append(cmaps, cmap)
+	}
+
+	var out []string
+	for _, s := range streams {
+		for _, m := range reTj.FindAllStringSubmatch(s, -1) {
+			f, _ := strconv.Atoi(m[1])
+			var text strings.Builder
+			for i := 0; i+4 <= len(m[2]); i += 4 {
+				text.WriteString(cmaps[f-1][m[2][i:i+4]])
+			}
+			out = append(out, text.String())
+		}
+	}
+
+	return out
+}
+
+func TestRenderer_InvoicePDF(t *testing.T) {
+	r := newRenderer(t)
+
+	inv := domain.Invoice{
+		Number:      7,
+		Client:      domain.Counterparty{Name: "ООО «Ромашка»", INN: "7707083893", KPP: "770701001", Address: "Москва, ул. Ленина, 1"},
+		IssuedOn:    time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+		DueOn:       time.Date(2025, 3, 19, 0, 0, 0, 0, time.UTC),
+		Amount:      123_456_78,
+		Description: "Разработка сайта 🚀",
+	}
+	seller := domain.Requisites{
+		Name: "ИП Иванов Иван Иванович", INN: "500100732259", OGRNIP: "304500116000157",
+		Bank: "ПАО Сбербанк", BIK: "044525225", Account: "40802810400000000001", CorrAccount: "30101810400000000225",
+	}
+
+	b, err := r.InvoicePDF(inv, seller)
+	if err != nil {
+		t.Fatalf("InvoicePDF: %v", err)
+	}
+
+	if !bytes.HasPrefix(b, []byte("%PDF-")) {
+		t.Fatalf("not a PDF file")
+	}
+
+	text := strings.Join(pdfText(t, b), "\n")
+
+	for _, want := range []string{
+		"Счёт на оплату № 7 от 5 марта 2025 г.",
+		"044525225",
+		"30101810400000000225",
+		"40802810400000000001",
+		"ИНН 500100732259",
+		"ООО «Ромашка», ИНН 7707083893, КПП 770701001, Москва, ул. Ленина, 1",
+		"Разработка сайта ?", // no glyph for the emoji
+		"123 456,78",
+		"Сто двадцать три тысячи четыреста пятьдесят шесть рублей 78 копеек",
+		"Оплатить не позднее 19 марта 2025 г.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("no %q in the invoice:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderer_InvoicePDF_DefaultService(t *testing.T) {
+	r := newRenderer(t)
+
+	b, err := r.InvoicePDF(domain.Invoice{Number: 1, Client: domain.Counterparty{Name: "Клиент"}, Amount: 100}, domain.Requisites{})
+	if err != nil {
+		t.Fatalf("InvoicePDF: %v", err)
+	}
+
+	if text := pdfText(t, b); !strings.Contains(strings.Join(text, "\n"), document.DefaultService) {
+		t.Errorf("no default service line in %q", text)
+	}
+}
@@ -0,0 +1,181 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/qr"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// Fields of a payment order of the single tax payment that do not vary: the payer
+// status (field 101), the basis, period, number and date of the payment (106–109) and
+// the UIN (22) are "0" for ЕНП, the priority is that of tax payments.
+const (
+	enpPayerStatus = "01"
+	enpZero        = "0"
+	enpPriority    = "5"
+	enpPaymentKind = "01" // payment order
+)
+
+// PaymentOrder1C returns order as a file in the 1C:Enterprise bank exchange format
+// (1CClientBankExchange 1.03) that bank apps import payment orders from, in Windows-1251
+// with CRLF line ends. created is written to the header.
+func PaymentOrder1C(order domain.PaymentOrder, created time.Time) []byte {
+	var b strings.Builder
+
+	day := order.Date.Format("02.01.2006")
+	line := func(key, value string) {
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(field(value))
+		b.WriteString("\r\n")
+	}
+
+	b.WriteString("1CClientBankExchange\r\n")
+	line("ВерсияФормата", "1.03")
+	line("Кодировка", "Windows")
+	line("Отправитель", "IP Accounting Bot")
+	line("Получатель", "")
+	line("ДатаСоздания", created.Format("02.01.2006"))
+	line("ВремяСоздания", created.Format("15:04:05"))
+	line("ДатаНачала", day)
+	line("ДатаКонца", day)
+	line("РасчСчет", order.Payer.Account)
+	line("Документ", "Платежное поручение")
+
+	line("СекцияДокумент", "Платежное поручение")
+	line("Номер", "1") // banks number imported orders themselves
+	line("Дата", day)
+	line("Сумма", fmt.Sprintf("%d.%02d", order.Amount/100, order.Amount%100))
+
+	line("ПлательщикСчет", order.Payer.Account)
+	line("Плательщик", "ИНН "+order.Payer.INN+" "+order.Payer.Name)
+	line("ПлательщикИНН", order.Payer.INN)
+	line("ПлательщикКПП", enpZero)
+	line("Плательщик1", order.Payer.Name)
+	line("ПлательщикРасчСчет", order.Payer.Account)
+	line("ПлательщикБанк1", order.Payer.Bank)
+	line("ПлательщикБИК", order.Payer.BIK)
+	line("ПлательщикКорсчет", order.Payer.CorrAccount)
+
+	line("ПолучательСчет", order.Payee.Account)
+	line("Получатель", "ИНН "+order.Payee.INN+" "+order.Payee.Name)
+	line("ПолучательИНН", order.Payee.INN)
+	line("ПолучательКПП", order.Payee.KPP)
+	line("Получатель1", order.Payee.Name)
+	line("ПолучательРасчСчет", order.Payee.Account)
+	line("ПолучательБанк1", order.Payee.Bank)
+	line("ПолучательБИК", order.Payee.BIK)
+	line("ПолучательКорсчет", order.Payee.CorrAccount)
+
+	line("ВидОплаты", enpPaymentKind)
+	line("Очередность", enpPriority)
+	line("СтатусСоставителя", enpPayerStatus)
+	line("ПоказательКБК", order.Payee.KBK)
+	line("ОКАТО", order.Payee.OKTMO) // the field has kept its old name, it holds the OKTMO
+	line("ПоказательОснования", enpZero)
+	line("ПоказательПериода", enpZero)
+	line("ПоказательНомера", enpZero)
+	line("ПоказательДаты", enpZero)
+	line("Код", enpZero)
+	line("НазначениеПлатежа", order.Purpose)
+	b.WriteString("КонецДокумента\r\n")
+	b.WriteString("КонецФайла\r\n")
+
+	return windows1251(b.String())
+}
+
+// PaymentQR returns order as the text of a payment QR code of ГОСТ Р 56042-2014 in
+// UTF-8, which bank apps scan to fill in a payment: the "ST00012" header and
+// Key=Value fields separated by "|". The sum is in kopecks.
+func PaymentQR(order domain.PaymentOrder) string {
+	fields := [][2]string{
+		{"Name", order.Payee.Name},
+		{"PersonalAcc", order.Payee.Account},
+		{"BankName", order.Payee.Bank},
+		{"BIC", order.Payee.BIK},
+		{"CorrespAcc", order.Payee.CorrAccount},
+		{"Sum", fmt.Sprint(order.Amount)},
+		{"Purpose", order.Purpose},
+		{"PayeeINN", order.Payee.INN},
+		{"KPP", order.Payee.KPP},
+		{"PayerINN", order.Payer.INN},
+		{"DrawerStatus", enpPayerStatus},
+		{"CBC", order.Payee.KBK},
+		{"OKTMO", order.Payee.OKTMO},
+		{"PaytReason", enpZero},
+		{"TaxPeriod", enpZero},
+		{"DocNo", enpZero},
+		{"DocDate", enpZero},
+	}
+
+	var b strings.Builder
+
+	b.WriteString("ST00012")
+	for _, f := range fields {
+		b.WriteString("|")
+		b.WriteString(f[0])
+		b.WriteString("=")
+		b.WriteString(field(strings.ReplaceAll(f[1], "|", " ")))
+	}
+
+	return b.String()
+}
+
+// PaymentQRPNG draws the PaymentQR code of order as a PNG large enough to scan from a
+// phone screen.
+func PaymentQRPNG(order domain.PaymentOrder) ([]byte, error) {
+	const op = "document.PaymentQRPNG"
+
+	code, err := qr.Encode([]byte(PaymentQR(order)), qr.LevelM)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	b, err := code.PNG(8)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return b, nil
+}
+
+// field puts value on one line: line breaks would end the field in both formats.
+func field(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// windows1251 encodes s in Windows-1251, the encoding 1C exchange files are read in.
+// Characters it has no code for become "?".
+func windows1251(s string) []byte {
+	var b bytes.Buffer
+
+	for _, r := range s {
+		switch {
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		case r >= 'А' && r <= 'я':
+			b.WriteByte(byte(r - 'А' + 0xC0))
+		default:
+			c, ok := cp1251[r]
+			if !ok {
+				c = '?'
+			}
+			b.WriteByte(c)
+		}
+	}
+
+	return b.Bytes()
+}
+
+// cp1251 maps the characters of Windows-1251 outside ASCII and А–я that names and
+// purposes may have.
+var cp1251 = map[rune]byte{
+	'Ё': 0xA8, 'ё': 0xB8, '№': 0xB9, '«': 0xAB, '»': 0xBB,
+	'–': 0x96, '—': 0x97, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'\u00a0': 0xA0, '…': 0x85, '•': 0x95,
+}
@@ -0,0 +1,107 @@
+package document_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/document"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+var testOrder = domain.PaymentOrder{
+	Year:    2025,
+	Quarter: 2,
+	Date:    time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC),
+	Amount:  12_345_67,
+	Payer: domain.Requisites{
+		Name: "ИП Иванов Иван Иванович", INN: "500100732259", Bank: "ПАО Сбербанк",
+		BIK: "044525225", Account: "40802810100000000001", CorrAccount: "30101810400000000225",
+	},
+	Payee:   tax.ENPPayee,
+	Purpose: "Единый налоговый платеж за 2 квартал 2025 г.",
+}
+
+// fromWindows1251 decodes the Cyrillic letters and the "№" sign of Windows-1251.
+func fromWindows1251(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		switch {
+		case c >= 0xC0:
+			s.WriteRune(rune(c-0xC0) + 'А')
+		case c == 0xB9:
+			s.WriteRune('№')
+		case c >= 0x80:
+			s.WriteRune('�')
+		default:
+			s.WriteByte(c)
+		}
+	}
+	return s.String()
+}
+
+func TestPaymentOrder1C(t *testing.T) {
+	b := document.PaymentOrder1C(testOrder, time.Date(2025, 7, 10, 9, 30, 0, 0, time.UTC))
+
+	text := fromWindows1251(b)
+
+	if !strings.HasPrefix(text, "1CClientBankExchange\r\n") || !strings.HasSuffix(text, "КонецДокумента\r\nКонецФайла\r\n") {
+		t.Fatalf("not a 1C exchange file:\n%s", text)
+	}
+	if strings.Contains(text, "�") {
+		t.Errorf("characters lost in Windows-1251:\n%s", text)
+	}
+
+	for _, want := range []string{
+		"ВремяСоздания=09:30:00",
+		"СекцияДокумент=Платежное поручение",
+		"Дата=10.07.2025",
+		"Сумма=12345.67",
+		"ПлательщикСчет=40802810100000000001",
+		"Плательщик=ИНН 500100732259 ИП Иванов Иван Иванович",
+		"ПлательщикБИК=044525225",
+		"ПолучательСчет=03100643000000018500",
+		"ПолучательИНН=7727406020",
+		"ПолучательКПП=770801001",
+		"ПолучательБанк1=ОКЦ № 7 ГУ Банка России по ЦФО//УФК по Тульской области, г Тула",
+		"ПолучательБИК=017003983",
+		"ПолучательКорсчет=40102810445370000059",
+		"СтатусСоставителя=01",
+		"ПоказательКБК=18201061201010000510",
+		"ОКАТО=0",
+		"ПоказательОснования=0",
+		"Код=0",
+		"Очередность=5",
+		"НазначениеПлатежа=Единый налоговый платеж за 2 квартал 2025 г.",
+	} {
+		if !strings.Contains(text, "\r\n"+want+"\r\n") {
+			t.Errorf("no line %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestPaymentQR(t *testing.T) {
+	order := testOrder
+	order.Purpose = "Единый налоговый платеж | 2 кв.\n2025"
+
+	want := "ST00012|Name=Казначейство России (ФНС России)|PersonalAcc=03100643000000018500|" +
+		"BankName=ОКЦ № 7 ГУ Банка России по ЦФО//УФК по Тульской области, г Тула|BIC=017003983|" +
+		"CorrespAcc=40102810445370000059|Sum=1234567|Purpose=Единый налоговый платеж 2 кв. 2025|" +
+		"PayeeINN=7727406020|KPP=770801001|PayerINN=500100732259|DrawerStatus=01|" +
+		"CBC=18201061201010000510|OKTMO=0|PaytReason=0|TaxPeriod=0|DocNo=0|DocDate=0"
+
+	if got := document.PaymentQR(order); got != want {
+		t.Errorf("PaymentQR =\n%s\nwant\n%s", got, want)
+	}
+
+	b, err := document.PaymentQRPNG(order)
+	if err != nil {
+		t.Fatalf("PaymentQRPNG: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(b)); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package document
+
+import (
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/pdf"
+)
+
+// Renderer lays out the printable documents of the bot in Russian, the language they
+// are legally issued in, whatever the language of the user.
+type Renderer struct {
+	regular *pdf.Font
+	bold    *pdf.Font
+}
+
+// sheet draws on a page of a document with the fonts of the renderer. Characters the
+// fonts cannot show are printed as "?"; the first drawing error is kept in err.
+type sheet struct {
+	r    *Renderer
+	page *pdf.Page
+	err  error
+}
+
+// docx collects the body of a Word document; see (*docx).bytes for the package around it.
+type docx struct {
+	body strings.Builder
+}
+
+// run is a piece of text of a paragraph or a table cell with one formatting.
+type run struct {
+	text string
+	bold bool
+	size float64 // points; 0 is the default size of the document
+}
+
+// cell is a cell of a table of a docx document.
+type cell struct {
+	runs  []run
+	align string // "", "center" or "right"
+}
@@ -16,3 +16,90 @@ const (
 const (
 	BpDen int64 = 10_000
 )
+
+// Transport names as stored in user_identities.transport.
+const (
+	TransportTelegram = "telegram"
+	TransportAPI      = "api"
+	TransportCLI      = "cli"
+)
+
+// EntryKind names the ledger an entry belongs to; payment kinds match PaymentType values.
+type EntryKind string
+
+const (
+	EntryKindIncome  EntryKind = "income"
+	EntryKindContrib EntryKind = EntryKind(PaymentTypeContrib)
+	EntryKindAdvance EntryKind = EntryKind(PaymentTypeAdvance)
+)
+
+// RecurringPeriod is how often a recurring income repeats.
+type RecurringPeriod string
+
+const (
+	RecurringMonthly RecurringPeriod = "monthly"
+	RecurringWeekly  RecurringPeriod = "weekly"
+)
+
+// RecurringStatus is the state of one occurrence of a recurring income.
+type RecurringStatus string
+
+const (
+	RecurringCreated RecurringStatus = "created" // the income was created
+	RecurringPending RecurringStatus = "pending" // waiting for the user to confirm or skip it
+	RecurringSkipped RecurringStatus = "skipped" // the user skipped it
+)
+
+// ReportBucket is the length of a row of /report.
+type ReportBucket string
+
+const (
+	BucketMonth ReportBucket = "month" // calendar month
+	BucketWeek  ReportBucket = "week"  // ISO week, Monday to Sunday
+)
+
+// LimitKind names a yearly income limit of the tax scheme.
+type LimitKind string
+
+const (
+	LimitUSN     LimitKind = "usn_limit"      // above it the simplified system is lost
+	LimitVAT     LimitKind = "vat_threshold"  // above it VAT has to be paid
+	LimitVATRate LimitKind = "vat_rate_limit" // above it the higher VAT rate applies
+)
+
+// LimitLevel says how close the income to date is to a limit.
+type LimitLevel string
+
+const (
+	LimitOK      LimitLevel = ""        // below LimitNearBP of the limit
+	LimitNear    LimitLevel = "near"    // at least LimitNearBP of the limit
+	LimitCrossed LimitLevel = "crossed" // above the limit
+)
+
+// LimitNearBP is the share of a limit (in basis points) from which the income is reported as near it.
+const LimitNearBP int64 = 8_000
+
+// ForecastLevel says how likely the income of the year crosses a limit.
+type ForecastLevel string
+
+const (
+	ForecastCrossed  ForecastLevel = "crossed"  // the income to date is already above the limit
+	ForecastExpected ForecastLevel = "expected" // the expected projection is above the limit
+	ForecastPossible ForecastLevel = "possible" // only the high projection is above the limit
+)
+
+// InvoiceStatus is derived from the incomes paid against an invoice.
+type InvoiceStatus string
+
+const (
+	InvoiceUnpaid  InvoiceStatus = "unpaid"
+	InvoicePartial InvoiceStatus = "partial" // paid in part
+	InvoicePaid    InvoiceStatus = "paid"    // paid in full
+)
+
+const (
+	// InvoiceDueDays is the payment term of an invoice issued without a due date.
+	InvoiceDueDays = 14
+	// InvoiceRemindDays is how often an overdue invoice is reminded of.
+	InvoiceRemindDays = 7
+)
@@ -0,0 +1,26 @@
+package domain
+
+import "errors"
+
+// Errors shared by usecases and stores; transports map them to user-facing replies.
+var (
+	ErrLinkCodeInvalid = errors.New("link code is invalid or expired")
+	ErrAlreadyLinked   = errors.New("identity is already linked to this account")
+	ErrLinkHasEntries  = errors.New("identity's own account has entries")
+	ErrLastIdentity    = errors.New("cannot unlink the last identity of the account")
+	// ErrDuplicateUpdate means the update carrying the idempotency key was already applied.
+	ErrDuplicateUpdate = errors.New("update was already processed")
+	// ErrEntryNotFound means the entry does not exist, belongs to another user or was undone.
+	ErrEntryNotFound = errors.New("entry not found")
+	// ErrClientExists means the user already has a client with this name.
+	ErrClientExists = errors.New("client already exists")
+	// ErrRequisitesMissing means an invoice or a payment order cannot be made before the user's
+	// requisites are set.
+	ErrRequisitesMissing = errors.New("requisites are not set")
+	// ErrInvoicePaid means a payment was recorded against an invoice that is paid in full.
+	ErrInvoicePaid = errors.New("invoice is already paid")
+	// ErrOverpayment means a payment is larger than what is left to pay on the invoice.
+	ErrOverpayment = errors.New("payment exceeds the amount left on the invoice")
+	// ErrActExists means an act was already issued for the invoice or the income.
+	ErrActExists = errors.New("act already issued")
+)
@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+type idempotencyCtxKey struct{}
+
+// WithIdempotencyKey marks ctx as processing the given transport update (e.g. "telegram", "update:42").
+// Stores record the key in the same transaction as a ledger mutation, so replaying the same update
+// cannot apply the mutation twice.
+func WithIdempotencyKey(ctx context.Context, transport, key string) context.Context {
+	return context.WithValue(ctx, idempotencyCtxKey{}, IdempotencyKey{Transport: transport, Key: key})
+}
+
+// IdempotencyKeyFrom returns the key set by WithIdempotencyKey, if any.
+func IdempotencyKeyFrom(ctx context.Context) (IdempotencyKey, bool) {
+	k, ok := ctx.Value(idempotencyCtxKey{}).(IdempotencyKey)
+	if !ok || k.Transport == "" || k.Key == "" {
+		return IdempotencyKey{}, false
+	}
+
+	return k, true
+}
@@ -6,13 +6,27 @@ import (
 )
 
 type IncomeUsecase interface {
-	AddIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) error
+	AddIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) (int64, error)
 	UndoLastQuarter(ctx context.Context, userID int64, now time.Time) (int64, time.Time, string, bool, error)
+	// UndoIncome voids an active income by id; ok=false if the user has no such income.
+	UndoIncome(ctx context.Context, userID, id int64, now time.Time) (int64, time.Time, string, bool, error)
+	ListIncomes(ctx context.Context, userID int64, from, to time.Time) ([]Income, error)
+	// GetIncome returns an active income by id; ok=false if the user has no such income.
+	GetIncome(ctx context.Context, userID, id int64) (Income, bool, error)
+	// UpdateIncome replaces date, amount and note of an active income.
+	UpdateIncome(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) error
 }
 
 type PaymentUsecase interface {
-	AddPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType PaymentType) error
+	AddPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType PaymentType) (int64, error)
 	UndoLastYear(ctx context.Context, userID int64, now time.Time, paymentType PaymentType) (int64, time.Time, string, PaymentType, bool, error)
+	// UndoPayment voids an active payment of paymentType by id; ok=false if the user has no such payment.
+	UndoPayment(ctx context.Context, userID, id int64, now time.Time, paymentType PaymentType) (int64, time.Time, string, bool, error)
+	ListPayments(ctx context.Context, userID int64, from, to time.Time) ([]Payment, error)
+	// GetPayment returns an active payment by id; ok=false if the user has no such payment.
+	GetPayment(ctx context.Context, userID, id int64) (Payment, bool, error)
+	// UpdatePayment replaces date, amount and note of an active payment; the type is kept.
+	UpdatePayment(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) error
 }
 
 type TotalUsecase interface {
@@ -20,6 +34,134 @@ type TotalUsecase interface {
 	SumYearToDate(ctx context.Context, userID int64, now time.Time) (Totals, error)
 }
 
+// ForecastUsecase projects the current year to its end from the income so far.
+type ForecastUsecase interface {
+	Forecast(ctx context.Context, userID int64, now time.Time) (Forecast, error)
+}
+
+// ChartUsecase aggregates the income of a year by month.
+type ChartUsecase interface {
+	MonthlyIncome(ctx context.Context, userID int64, year int) (MonthlyIncome, error)
+}
+
+// ReportUsecase breaks the income of a period down by month or week.
+type ReportUsecase interface {
+	Report(ctx context.Context, userID int64, bucket ReportBucket, from, to time.Time) (Report, error)
+}
+
+// SearchUsecase finds entries by their notes.
+type SearchUsecase interface {
+	// FindNotes returns up to limit active entries of [from..to] whose note contains query,
+	// newest first.
+	FindNotes(ctx context.Context, userID int64, query string, from, to time.Time, limit int) ([]NoteMatch, error)
+}
+
+// InvoiceUsecase issues invoices to clients and records their payments.
+type InvoiceUsecase interface {
+	// AddClient, UpdateClient and SetRequisites validate the INN, KPP and bank details.
+	AddClient(ctx context.Context, userID int64, c Counterparty) (Counterparty, error)
+	// UpdateClient replaces the details of a client of userID; ErrEntryNotFound if there is none.
+	UpdateClient(ctx context.Context, userID int64, c Counterparty) (Counterparty, error)
+	GetClient(ctx context.Context, userID, clientID int64) (Counterparty, error)
+	ListClients(ctx context.Context, userID int64) ([]Counterparty, error)
+	Requisites(ctx context.Context, userID int64) (Requisites, error)
+	SetRequisites(ctx context.Context, userID int64, r Requisites) (Requisites, error)
+	// IssueInvoice numbers inv (Client.ID, Amount, Description and an optional DueOn are used)
+	// within its year and returns it as stored.
+	IssueInvoice(ctx context.Context, userID int64, inv Invoice) (Invoice, error)
+	GetInvoice(ctx context.Context, userID, invoiceID int64) (Invoice, error)
+	// ListInvoices returns the invoices of userID, newest first; unpaid keeps those not paid in full.
+	ListInvoices(ctx context.Context, userID int64, unpaid bool) ([]Invoice, error)
+	// PayInvoice creates an income of amount on at linked to the invoice, in one step;
+	// amount 0 pays what is left. It returns the invoice after the payment and the income ID.
+	PayInvoice(ctx context.Context, userID, invoiceID int64, at time.Time, amount int64, note string) (Invoice, int64, error)
+}
+
+// ActUsecase issues acts of completed work and keeps the registry of them.
+type ActUsecase interface {
+	// ActForInvoice issues an act for the whole amount of an invoice to its client.
+	ActForInvoice(ctx context.Context, userID, invoiceID int64) (Act, error)
+	// ActForIncome issues an act for an active income to a client; an empty description
+	// takes the note of the income.
+	ActForIncome(ctx context.Context, userID, incomeID, clientID int64, description string) (Act, error)
+	GetAct(ctx context.Context, userID, actID int64) (Act, error)
+	// ListActs returns the acts of userID issued in year (all of them if year is 0), newest first.
+	ListActs(ctx context.Context, userID int64, year int) ([]Act, error)
+}
+
+// TaxPaymentUsecase prepares payment orders of the single tax payment.
+type TaxPaymentUsecase interface {
+	// PaymentOrder returns the order paying the tax due for the quarter that contains ref,
+	// or amount if it is not 0, from the account of the user's requisites.
+	PaymentOrder(ctx context.Context, userID int64, ref time.Time, amount int64) (PaymentOrder, error)
+}
+
+// DocumentRenderer renders invoices and acts as printable documents.
+type DocumentRenderer interface {
+	// InvoicePDF returns the invoice with the requisites of the seller as a PDF file.
+	InvoicePDF(inv Invoice, seller Requisites) ([]byte, error)
+	// ActPDF and ActDOCX return the act with the requisites of the contractor as a PDF
+	// file and as a Word document to edit before signing.
+	ActPDF(act Act, seller Requisites) ([]byte, error)
+	ActDOCX(act Act, seller Requisites) ([]byte, error)
+}
+
+// LimitsUsecase reports how close the income of the year is to the limits of the tax scheme.
+type LimitsUsecase interface {
+	Limits(ctx context.Context, userID int64, now time.Time) (Limits, error)
+}
+
+type TokenUsecase interface {
+	IssueToken(ctx context.Context, userID int64, name string) (token string, id int64, err error)
+	Authenticate(ctx context.Context, token string) (int64, error)
+	ListTokens(ctx context.Context, userID int64) ([]APIToken, error)
+	RevokeToken(ctx context.Context, userID int64, tokenID int64) (bool, error)
+	RevokeAllTokens(ctx context.Context, userID int64) (int64, error)
+}
+
+type LinkUsecase interface {
+	// IssueLinkCode creates a one-time code that binds another identity to userID.
+	IssueLinkCode(ctx context.Context, userID int64) (code string, expiresAt time.Time, err error)
+	// RedeemLinkCode binds (transport, externalID), currently resolved to userID, to the code owner.
+	RedeemLinkCode(ctx context.Context, userID int64, transport, externalID, code string) (int64, error)
+	// Unlink detaches identities of transport from userID; empty externalID means all of them.
+	Unlink(ctx context.Context, userID int64, transport, externalID string) (int64, error)
+}
+
 type IdentityStore interface {
 	UpsertIdentity(ctx context.Context, transport, externalID string, chatID int64) (int64, error)
 }
+
+type MessageEntryStore interface {
+	// FindMessageEntry returns the entry userID created with the referenced message.
+	FindMessageEntry(ctx context.Context, userID int64, ref MessageRef) (MessageEntry, bool, error)
+}
+
+type LanguageStore interface {
+	// GetIdentityLang returns the language chosen by the user behind (transport, externalID),
+	// or "" if none was chosen or the identity is unknown. It never creates a user.
+	GetIdentityLang(ctx context.Context, transport, externalID string) (string, error)
+	// SetUserLang stores the interface language of userID; "" clears the choice.
+	SetUserLang(ctx context.Context, userID int64, lang string) error
+}
+
+type RecurringUsecase interface {
+	// AddRule schedules rule (ID, NextDue and Pending are ignored) and returns it as stored.
+	AddRule(ctx context.Context, userID int64, rule RecurringRule) (RecurringRule, error)
+	ListRules(ctx context.Context, userID int64) ([]RecurringRule, error)
+	// PauseRule and ResumeRule return ErrEntryNotFound for an unknown rule.
+	PauseRule(ctx context.Context, userID, ruleID int64) error
+	ResumeRule(ctx context.Context, userID, ruleID int64) (RecurringRule, error)
+	DeleteRule(ctx context.Context, userID, ruleID int64) error
+	// ConfirmRuns creates the incomes of the pending occurrences of a rule; SkipRuns drops them.
+	ConfirmRuns(ctx context.Context, userID, ruleID int64) ([]RecurringRun, error)
+	SkipRuns(ctx context.Context, userID, ruleID int64) ([]RecurringRun, error)
+}
+
+// ChatStore returns where to message a user outside of a reply, e.g. a recurring income reminder.
+type ChatStore interface {
+	// GetTelegramChatID returns the Telegram chat last stored for userID by UpsertIdentity.
+	GetTelegramChatID(ctx context.Context, userID int64) (int64, bool, error)
+	// GetUserLang returns the language chosen with /lang, or "".
+	GetUserLang(ctx context.Context, userID int64) (string, error)
+}
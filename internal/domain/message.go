@@ -0,0 +1,36 @@
+package domain
+
+import "context"
+
+type messageRefCtxKey struct{}
+
+// WithMessageRef marks ctx as handling the given transport message. Stores link entries
+// created under ctx to the message in the same transaction.
+func WithMessageRef(ctx context.Context, ref MessageRef) context.Context {
+	return context.WithValue(ctx, messageRefCtxKey{}, ref)
+}
+
+// MessageRefFrom returns the reference set by WithMessageRef, if any.
+func MessageRefFrom(ctx context.Context) (MessageRef, bool) {
+	ref, ok := ctx.Value(messageRefCtxKey{}).(MessageRef)
+	if !ok || ref.Transport == "" || ref.Key == "" {
+		return MessageRef{}, false
+	}
+
+	return ref, true
+}
+
+type chatIDCtxKey struct{}
+
+// WithChatID records the transport chat a command came from, so that handlers which
+// set up later messages (e.g. /recurring) can store it along with the identity.
+func WithChatID(ctx context.Context, chatID int64) context.Context {
+	return context.WithValue(ctx, chatIDCtxKey{}, chatID)
+}
+
+// ChatIDFrom returns the chat set by WithChatID, or 0.
+func ChatIDFrom(ctx context.Context) int64 {
+	chatID, _ := ctx.Value(chatIDCtxKey{}).(int64)
+
+	return chatID
+}
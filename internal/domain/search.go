@@ -0,0 +1,12 @@
+package domain
+
+import "strings"
+
+// FoldNote normalizes text for note search: lower case, with ё read as е, so that
+// "Заказ", "заказ" and "ЗАКАЗ" match alike. Stores fold notes the same way
+// (postgres: note_fold() of migrations 0008 and 0011).
+func FoldNote(s string) string {
+	return noteFolder.Replace(strings.ToLower(s))
+}
+
+var noteFolder = strings.NewReplacer("ё", "е")
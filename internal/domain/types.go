@@ -12,4 +12,319 @@ type Totals struct {
 	AdvanceSum     int64     // payments type=advance in [From,To]
 	ContribApplied int64     // min(Tax, ContribSum)
 	Due            int64     // max(0, Tax - ContribApplied - AdvanceSum)
+	VATRateBP      int64     // VAT rate that applies on To (or today, if earlier); 0 if exempt
+	VAT            int64     // VAT included in IncomeSum at the rates of each month
+}
+
+// Income is an active income entry as returned by list queries.
+type Income struct {
+	ID     int64
+	At     time.Time // UTC date
+	Amount int64     // kopecks
+	Note   string
+}
+
+// Payment is an active payment entry (contribution or advance) as returned by list queries.
+type Payment struct {
+	ID     int64
+	At     time.Time // UTC date
+	Amount int64     // kopecks
+	Note   string
+	Type   PaymentType
+}
+
+// APIToken describes an issued API token; the token itself is never stored.
+type APIToken struct {
+	ID         int64
+	Name       string
+	CreatedAt  time.Time
+	LastUsedAt time.Time // zero if never used
+}
+
+// IdempotencyKey identifies a transport update (e.g. Telegram update_id) that may be delivered more than once.
+type IdempotencyKey struct {
+	Transport string
+	Key       string
+}
+
+// MessageRef identifies the transport message a command came from, so that an edit of
+// that message can be applied to the entry it created.
+type MessageRef struct {
+	Transport string
+	Key       string    // transport-specific, e.g. "<chat_id>:<message_id>" for Telegram
+	SentAt    time.Time // when the original message was sent; zero if unknown
+}
+
+// MessageEntry is the ledger entry created by a transport message.
+type MessageEntry struct {
+	Kind EntryKind
+	ID   int64
+}
+
+// RecurringRule creates the same income on a schedule, e.g. a monthly retainer.
+type RecurringRule struct {
+	ID      int64
+	UserID  int64
+	Amount  int64 // kopecks
+	Note    string
+	Period  RecurringPeriod
+	Day     int       // monthly: day of month 1..31 (the last day in shorter months); weekly: 1 (Monday)..7 (Sunday)
+	Confirm bool      // ask the user before creating each income
+	Paused  bool      // paused rules do not run; resuming skips the dates missed meanwhile
+	NextDue time.Time // UTC date of the next occurrence
+	Pending int       // occurrences waiting for confirmation (filled by list queries)
+}
+
+// RecurringRun is one occurrence of a rule; every (rule, due date) runs at most once.
+type RecurringRun struct {
+	RuleID   int64
+	UserID   int64
+	Due      time.Time // UTC date; also the date of the income
+	Amount   int64
+	Note     string
+	Status   RecurringStatus
+	IncomeID int64 // set once the income is created
+}
+
+// Range is a projected amount in kopecks: the low, the expected and the high estimate.
+type Range struct {
+	Low      int64
+	Expected int64
+	High     int64
+}
+
+// Forecast projects the income and the tax of a year to its end.
+type Forecast struct {
+	Year         int
+	AsOf         time.Time // UTC date the income to date is counted up to, inclusive
+	IncomeToDate int64     // kopecks
+	ContribPaid  int64     // payments type=contrib this year
+	AdvancePaid  int64     // payments type=advance this year
+	Seasonal     bool      // the income of the previous year shaped the projection
+	Income       Range     // income of the whole year
+	Tax          Range     // BaseRateBP% of Income
+	Extra        Range     // 1% extra contribution over the threshold, capped
+	Due          Range     // tax left to pay after contributions and advances
+	FixedContrib int64     // fixed contributions of the year; 0 if unknown
+	FixedLeft    int64     // max(0, FixedContrib - ContribPaid)
+	IncomeLimit  int64     // USN income limit; 0 if unknown
+	VATThreshold int64     // VAT threshold; 0 if not applicable
+	Warnings     []ForecastWarning
+}
+
+// ForecastWarning reports a limit the projected income may cross.
+type ForecastWarning struct {
+	Limit  LimitKind
+	Amount int64 // the limit in kopecks
+	Level  ForecastLevel
+}
+
+// LimitUsage is how much of a yearly income limit the income to date has used.
+type LimitUsage struct {
+	Kind   LimitKind
+	Amount int64      // the limit in kopecks
+	UsedBP int64      // income to date in basis points of Amount
+	Level  LimitLevel // LimitOK below LimitNearBP
+}
+
+// Limits reports the income limits of a year and the VAT rate that applies.
+type Limits struct {
+	Year         int
+	AsOf         time.Time    // UTC date the income to date is counted up to, inclusive
+	IncomeToDate int64        // kopecks
+	Usage        []LimitUsage // only the limits known for the year
+	VATRateBP    int64        // VAT rate that applies on AsOf; 0 if exempt
+	VATSince     time.Time    // first day of the month VATRateBP applies from; zero if exempt
+}
+
+// Warnings returns the usages at or above LimitNearBP.
+func (l Limits) Warnings() []LimitUsage {
+	var out []LimitUsage
+	for _, u := range l.Usage {
+		if u.Level != LimitOK {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// MonthSum is the income of one calendar month.
+type MonthSum struct {
+	Month time.Time // first day of the month, UTC
+	Sum   int64     // kopecks
+}
+
+// MonthlyIncome is the income of each month of a year and the tax on it, for /chart.
+type MonthlyIncome struct {
+	Year   int
+	Income [12]int64 // kopecks; index 0 is January
+	Tax    [12]int64 // BaseRateBP% of each month's income, integer math
+}
+
+// Total returns the income of the whole year.
+func (m MonthlyIncome) Total() int64 {
+	var sum int64
+	for _, v := range m.Income {
+		sum += v
+	}
+	return sum
+}
+
+// IncomeStats aggregates the active incomes of one bucket of a report.
+type IncomeStats struct {
+	Start time.Time // first day of the month or Monday of the ISO week, UTC
+	Count int64
+	Sum   int64 // kopecks
+	Max   int64 // the largest income, kopecks
+}
+
+// ReportRow is one bucket of a report, or the total of all of them.
+type ReportRow struct {
+	From, To time.Time // inclusive; the first and last buckets are cut to the report period
+	Count    int64
+	Sum      int64
+	Avg      int64 // Sum / Count, floored; 0 without incomes
+	Max      int64
+	Tax      int64 // BaseRateBP% of Sum, integer math
+}
+
+// Report breaks the income of a period down by month or ISO week, for /report.
+type Report struct {
+	Bucket   ReportBucket
+	From, To time.Time // inclusive days, UTC
+	Rows     []ReportRow
+	Total    ReportRow
+}
+
+// NoteMatch is an active income or payment whose note matches a /find query.
+type NoteMatch struct {
+	Kind   EntryKind
+	ID     int64     // ID within the ledger of Kind
+	At     time.Time // UTC date
+	Amount int64     // kopecks
+	Note   string
+}
+
+// Counterparty is a client of the user that invoices are issued to.
+type Counterparty struct {
+	ID      int64
+	UserID  int64
+	Name    string
+	INN     string // 10 digits for an organization, 12 for a person; "" if unknown
+	KPP     string // 9 digits, organizations only
+	Address string
+}
+
+// Requisites are the details of the user's business printed on invoices.
+type Requisites struct {
+	Name        string // e.g. "ИП Иванов Иван Иванович"
+	INN         string // 12 digits
+	OGRNIP      string // 15 digits
+	Address     string
+	Bank        string // name of the bank
+	BIK         string // 9 digits
+	Account     string // settlement account, 20 digits
+	CorrAccount string // correspondent account of the bank, 20 digits
+}
+
+// Complete reports whether the requisites a client needs to pay an invoice are filled in.
+func (r Requisites) Complete() bool {
+	return r.Name != "" && r.INN != "" && r.Bank != "" && r.BIK != "" && r.Account != ""
+}
+
+// Invoice is a bill issued to a client. It is paid by incomes linked to it, in one
+// or several parts; voiding such an income makes the invoice unpaid again.
+type Invoice struct {
+	ID          int64
+	UserID      int64
+	Number      int64 // sequential within the year of IssuedOn, from 1
+	Client      Counterparty
+	IssuedOn    time.Time // UTC date
+	DueOn       time.Time // UTC date the payment is expected by
+	Amount      int64     // kopecks
+	Description string
+	Paid        int64     // sum of the active incomes linked to the invoice
+	RemindedOn  time.Time // UTC date of the last overdue reminder; zero if none
+}
+
+// Left returns the amount still to be paid.
+func (i Invoice) Left() int64 {
+	return max(0, i.Amount-i.Paid)
+}
+
+func (i Invoice) Status() InvoiceStatus {
+	switch {
+	case i.Paid >= i.Amount:
+		return InvoicePaid
+	case i.Paid > 0:
+		return InvoicePartial
+	default:
+		return InvoiceUnpaid
+	}
+}
+
+// Payment returns the amount of a payment of the invoice, 0 meaning what is left:
+// ErrInvoicePaid if nothing is left, ErrOverpayment if amount is more than that.
+func (i Invoice) Payment(amount int64) (int64, error) {
+	left := i.Left()
+
+	switch {
+	case left == 0:
+		return 0, ErrInvoicePaid
+	case amount == 0:
+		return left, nil
+	case amount > left:
+		return 0, ErrOverpayment
+	}
+
+	return amount, nil
+}
+
+// Overdue reports whether the invoice is not paid in full after its due date.
+func (i Invoice) Overdue(today time.Time) bool {
+	return i.Status() != InvoicePaid && today.After(i.DueOn)
+}
+
+// Act is an act of completed work ("акт выполненных работ") the client signs for a job.
+// It is issued for an invoice or for an income, at most one act for each.
+type Act struct {
+	ID          int64
+	UserID      int64
+	Number      int64 // sequential within the year of IssuedOn, from 1
+	Client      Counterparty
+	IssuedOn    time.Time // UTC date
+	Amount      int64     // kopecks
+	Description string
+	// InvoiceID is the invoice the act was issued for, with its number and date printed
+	// as the basis of the act; 0 if the act was issued for an income.
+	InvoiceID       int64
+	InvoiceNumber   int64
+	InvoiceIssuedOn time.Time
+	IncomeID        int64 // income the act was issued for; 0 if it was issued for an invoice
+}
+
+// TaxPayee holds the requisites a tax is paid to: the treasury account, the budget
+// classification code (КБК) and the OKTMO of the payment order.
+type TaxPayee struct {
+	Name        string // e.g. "Казначейство России (ФНС России)"
+	INN         string
+	KPP         string
+	Bank        string
+	BIK         string
+	Account     string // treasury account
+	CorrAccount string // single treasury account (ЕКС) of the bank
+	KBK         string
+	OKTMO       string
+}
+
+// PaymentOrder is a payment order ("платёжное поручение") of the single tax payment
+// (ЕНП) for a quarter.
+type PaymentOrder struct {
+	Year    int
+	Quarter int
+	Date    time.Time // UTC date
+	Amount  int64     // kopecks
+	Payer   Requisites
+	Payee   TaxPayee
+	Purpose string
 }
@@ -0,0 +1,422 @@
+package i18n
+
+var catalogEN = map[string]string{
+	// common
+	"common.unknown_command": "❓ Unknown command. Send /help for help.",
+	"common.error":           "⚠️ Failed to process the command. Please try again later.",
+
+	// start / help
+	"start": "👋 Hi! I help sole proprietors on the simplified tax system (USN 6%) keep track of income.\n\n" +
+		"📋 Main commands:\n" +
+		"• /add [date] [amount] [note] — add income\n" +
+		"  Examples: /add 1000\n" +
+		"            /add 2025-08-01 5000 prepayment\n" +
+		"            /add 1,234.56 order #42\n" +
+		"            /add 10р 50к prepayment\n" +
+		"• /add_contrib [amount] [note] — add an insurance contribution\n" +
+		"• /add_advance [amount] [note] — add an advance tax payment\n" +
+		"• /undo [#id] — undo the last income of the quarter or the one with this ID\n" +
+		"• /undo_contrib [#id] — undo the last contribution\n" +
+		"• /undo_advance [#id] — undo the last advance payment\n" +
+		"• /edit #id [date] [amount] [note] — correct an income; /edit_contrib, /edit_advance for payments\n" +
+		"• /total — current quarter totals (income and 6% tax)\n" +
+		"• /forecast — year-end income and tax forecast\n" +
+		"• /limits — USN limit, VAT threshold and the VAT rate\n" +
+		"• /chart [year] — monthly income chart\n" +
+		"• /report [month|week] [period] — income table by month or week\n" +
+		"• /find <text> [period] — search the notes of incomes and payments\n" +
+		"• /token [name] — issue a REST API token\n" +
+		"• /link — link another account or the CLI to this ledger\n" +
+		"• /recurring — recurring incomes (retainers, subscriptions)\n" +
+		"• /invoice — invoices to clients, /clients — clients, /requisites — your requisites\n" +
+		"• /act — acts of completed work, /acts — their registry\n" +
+		"• /pay — a payment order of the tax for your bank: a 1C file and a QR code\n" +
+		"• /lang [ru|en|auto] — bot language\n" +
+		"• /help — detailed help\n\n" +
+		"💡 Amount format: no minus sign; «1,234.56», «1 234,56», «10р 50к» are accepted.",
+	"help": "📚 Help\n\n" +
+		"🔧 Commands:\n" +
+		"• /add [date] [amount] [note]\n" +
+		"  Adds an income entry. The amount is in rubles and kopecks, without a minus sign.\n" +
+		"  The date is optional: 2025-08-01 or 01.08.2025, today by default.\n" +
+		"  Examples:\n" +
+		"   /add 1000\n" +
+		"   /add 1,234.56 order #42\n" +
+		"   /add 10р 50к prepayment\n" +
+		"   /add 2025-08-01 5000 order #41\n" +
+		"  To correct an entry, edit the message with the command or use /edit.\n\n" +
+		"• /add_contrib [date] [amount] [note]\n" +
+		"  Adds an insurance contribution. Date and amount as in /add.\n\n" +
+		"• /add_advance [date] [amount] [note]\n" +
+		"  Adds an advance tax payment. Date and amount as in /add.\n\n" +
+		"• /undo [#id]\n" +
+		"  Undoes the last income of the quarter, or with an ID the income with it, of any date.\n" +
+		"  /find shows the IDs: /undo #42\n\n" +
+		"• /undo_contrib [#id]\n" +
+		"  Undoes the last contribution, or the one with the ID.\n\n" +
+		"• /undo_advance [#id]\n" +
+		"  Undoes the last advance payment, or the one with the ID.\n\n" +
+		"• /edit #id [date] [amount] [note]\n" +
+		"  Corrects the income with the ID; without a date it keeps its date.\n" +
+		"  /edit_contrib and /edit_advance correct contributions and advance payments.\n" +
+		"  Example: /edit #42 1500 order #42\n\n" +
+		"• /total\n" +
+		"  Shows income and the 6% tax for the current quarter.\n\n" +
+		"• /forecast\n" +
+		"  Projects the year-end income from the run-rate so far and last year's seasonality,\n" +
+		"  with the tax, the 1% extra contribution and the fixed contributions left to pay.\n" +
+		"  Warns when the income may exceed the USN limit or the VAT threshold.\n\n" +
+		"• /limits\n" +
+		"  Shows how much of the USN limit, the VAT threshold and the reduced VAT rate limit\n" +
+		"  the income of the year has used, and the VAT rate that applies.\n" +
+		"  From 80% of a limit, /add warns after every income.\n\n" +
+		"• /chart [year]\n" +
+		"  Sends a bar chart of the income of each month with the tax on it; the current year by default.\n\n" +
+		"• /report [month|week] [period]\n" +
+		"  A table of the number of incomes, their sum, average, largest and the tax per month or week.\n" +
+		"  The period is a year (2025), a quarter (2025-q2) or a month (2025-03); by default\n" +
+		"  the months of the current year or the weeks of the current quarter.\n\n" +
+		"• /find <text> [period]\n" +
+		"  Finds incomes and payments whose note contains the text, in any case and with ё as е,\n" +
+		"  newest first, with their IDs for /undo and /edit. A period (2025, 2025-q2, 2025-03) at the end narrows the search.\n\n" +
+		"• /token [name]\n" +
+		"  Issues a REST API token. The token is shown only once.\n" +
+		"  /token list — active tokens\n" +
+		"  /token revoke [id|all] — revoke one or all tokens\n\n" +
+		"• /link [code]\n" +
+		"  Without an argument, gives a one-time code valid for 10 minutes.\n" +
+		"  Send /link [code] from another Telegram account or the CLI to share one ledger.\n\n" +
+		"• /unlink [transport]\n" +
+		"  Detaches this account or all accounts of a transport (telegram, cli).\n\n" +
+		"• /recurring add [amount] monthly|weekly on [day] [note] [ask]\n" +
+		"  Adds the income on every due date: day 1–31 of the month (the last day in shorter months)\n" +
+		"  or a weekday (1–7, mon–sun). With «ask» the bot asks before adding each income.\n" +
+		"  Dates missed while the bot was down are added once it is back.\n" +
+		"  Example: /recurring add 50000 monthly on 5 @client\n" +
+		"  /recurring list — rules\n" +
+		"  /recurring pause|resume|delete [id] — manage a rule\n" +
+		"  /recurring confirm|skip [id] — add or drop the incomes waiting for confirmation\n\n" +
+		"• /requisites [field value]\n" +
+		"  Your requisites printed on invoices: name, inn, ogrnip, address, bank, bik, account, corr.\n" +
+		"  Set the BIK before the accounts: their control keys are checked against it. «-» clears a field.\n" +
+		"  Example: /requisites name IP Ivanov Ivan Ivanovich\n\n" +
+		"• /client add [name] | /client set [id] inn|kpp|address [value] | /clients\n" +
+		"  Clients to issue invoices to, with their INN, KPP and address.\n\n" +
+		"• /invoice new [client id] [amount] [due date] [description]\n" +
+		"  Issues an invoice numbered within the year and sends it as a PDF; due in 14 days by default.\n" +
+		"  Example: /invoice new 1 50000 due 2025-08-15 Website development\n" +
+		"  /invoice [id] — show an invoice and send the PDF again\n" +
+		"  /invoice paid [id] [date] [amount] — add the income that paid it; without an amount, what is left.\n" +
+		"  Undoing that income with /undo makes the invoice unpaid again.\n" +
+		"  /invoices [unpaid] — invoices; the bot reminds of overdue ones weekly\n\n" +
+		"• /act invoice [invoice id] | /act income [income id] [client id] [description]\n" +
+		"  Issues an act of completed work numbered within the year, as a PDF and a DOCX to edit.\n" +
+		"  An invoice or an income has one act; asking again sends it again.\n" +
+		"  /act [id] — show an act and send its files again\n" +
+		"  /acts [year|all] — registry of issued acts, the current year by default\n\n" +
+		"• /pay [2025-q2|q2] [amount]\n" +
+		"  Payment order of the single tax payment (ENP) for a quarter, the current one by default,\n" +
+		"  for the tax due as in /total: a 1C file to import into your bank and a QR code to scan.\n\n" +
+		"• /lang [ru|en|auto]\n" +
+		"  Chooses the reply language; auto follows your Telegram or system settings.\n\n" +
+		"• /start\n" +
+		"  Short guide.\n\n" +
+		"💰 Amount format:\n" +
+		"  • Spaces, dots and commas are accepted as thousands separators.\n" +
+		"  • The last dot or comma is the decimal separator (up to 2 digits).\n" +
+		"  • «10р 50к» and «10 руб 50 коп» are understood.\n" +
+		"  • Negative values are rejected.\n\n" +
+		"⚙️ How it works:\n" +
+		"  • The tax is 6% of the quarter's income (rounded down).\n" +
+		"  • Quarters use UTC dates, bounds inclusive.",
+
+	// input errors
+	"input.bad_amount":  "❌ Could not read the amount. Examples: 1000 | 1,234.56 | 10р 50к",
+	"input.future_date": "❌ The date cannot be in the future. Format: 2025-08-01 or 01.08.2025",
+	"input.zero_amount": "❌ The amount cannot be 0",
+
+	// entry fields
+	"entry.amount": "💰 Amount: ",
+	"entry.date":   "📅 Date: ",
+	"entry.note":   "💬 Note: ",
+
+	// add / edit / undo
+	"add.income":              "✅ Income added: ",
+	"add.contrib":             "✅ Contribution added: ",
+	"add.advance":             "✅ Advance payment added: ",
+	"edit.income":             "✏️ Income corrected: ",
+	"edit.contrib":            "✏️ Contribution corrected: ",
+	"edit.advance":            "✏️ Advance payment corrected: ",
+	"edit.not_found":          "⚠️ No active entry was created by this message. Send the command again.",
+	"edit.kind_mismatch":      "⚠️ An edit cannot change the entry type. Undo it with /undo and add it again.",
+	"edit.usage":              "❌ Usage: /edit #id [date] [amount] [note], e.g. /edit #42 1500 order #42\nWithout a date the entry keeps its date. /edit_contrib and /edit_advance correct payments; /find shows the IDs.",
+	"undo.income":             "✅ Income undone:",
+	"undo.contrib":            "✅ Contribution undone:",
+	"undo.advance":            "✅ Advance payment undone:",
+	"undo.nothing_income":     "ℹ️ Nothing to undo: no income this quarter.",
+	"undo.nothing_contrib":    "ℹ️ Nothing to undo: no contributions this year.",
+	"undo.nothing_advance":    "ℹ️ Nothing to undo: no advance payments this year.",
+	"undo.bad_id":             "❌ The ID must be a number, e.g. /undo #42. /find shows the IDs of entries.",
+	"entry.not_found.income":  "ℹ️ Income #%d not found or already undone.",
+	"entry.not_found.contrib": "ℹ️ Contribution #%d not found or already undone.",
+	"entry.not_found.advance": "ℹ️ Advance payment #%d not found or already undone.",
+
+	// total
+	"total.quarter": "Q%d: %s – %s",
+	"total.year":    "Total for %d:",
+	"total.income":  "💰 Income: ",
+	"total.contrib": "💳 Contributions: ",
+	"total.advance": "💸 Advance payments: ",
+	"total.tax":     "🧾 Tax: ",
+	"total.vat":     "🧾 VAT %s: ",
+
+	// token
+	"token.issued":     "🔑 Token #%d created:",
+	"token.issued_tip": "⚠️ Save it now — the token is not shown again.\nSend it in the header: Authorization: Bearer <token>",
+	"token.list_empty": "ℹ️ No active tokens. Create one: /token [name]",
+	"token.list_title": "🔑 Active tokens:",
+	"token.created":    " — created %s",
+	"token.used":       ", last used %s",
+	"token.revoked":    "✅ Token #%d revoked.",
+	"token.not_found":  "ℹ️ Active token #%d not found.",
+	"token.usage": "❌ Usage:\n" +
+		"/token [name] — issue a token\n" +
+		"/token list — list tokens\n" +
+		"/token revoke [id|all] — revoke",
+	"token.disabled": "ℹ️ The REST API is not configured on this server.",
+
+	// link
+	"link.code":        "🔗 Link code: ",
+	"link.send_before": "Send ",
+	"link.send_after":  " from another account or the CLI.",
+	"link.valid_until": "⏳ Valid until %s UTC, once.",
+	"link.success":     "✅ Account linked. The shared ledger is now visible here.",
+	"link.invalid":     "❌ The code is wrong, expired or already used. Get a new one: /link",
+	"link.already":     "ℹ️ This account is already linked to this ledger.",
+	"link.has_entries": "❌ This account already has entries — they would become inaccessible after linking.\n" +
+		"Undo them, or link the other way round: get a code here and enter it in the other account.",
+	"link.disabled":  "ℹ️ Account linking is not configured on this server.",
+	"unlink.nothing": "ℹ️ No linked %s accounts.",
+	"unlink.last":    "❌ The last account cannot be unlinked — the ledger would become inaccessible.",
+	"unlink.api":     "ℹ️ API access is managed with tokens: /token list, /token revoke [id|all]",
+
+	// lang
+	"lang.current":     "🌐 Language: %s",
+	"lang.auto_suffix": " (automatic)",
+	"lang.usage":       "Change: /lang ru | /lang en | /lang auto",
+	"lang.set":         "✅ I will reply in English now.",
+	"lang.auto":        "✅ The language will follow your Telegram or system settings.",
+	"lang.unknown":     "❌ This language is not supported. Available: ru, en",
+	"lang.disabled":    "ℹ️ Language selection is not configured on this server.",
+	"lang.name.ru":     "Russian",
+	"lang.name.en":     "English",
+
+	// recurring
+	"recurring.added":           "🔁 Recurring income #%d: ",
+	"recurring.monthly":         "monthly on day %d",
+	"recurring.weekly":          "weekly on %s",
+	"recurring.weekday.1":       "Monday",
+	"recurring.weekday.2":       "Tuesday",
+	"recurring.weekday.3":       "Wednesday",
+	"recurring.weekday.4":       "Thursday",
+	"recurring.weekday.5":       "Friday",
+	"recurring.weekday.6":       "Saturday",
+	"recurring.weekday.7":       "Sunday",
+	"recurring.next":            "📅 Next: %s",
+	"recurring.asks":            "❓ I will ask before adding each income.",
+	"recurring.list_empty":      "ℹ️ No recurring incomes. Add one: /recurring add 50000 monthly on 5 @client",
+	"recurring.list_title":      "🔁 Recurring incomes:",
+	"recurring.list_next":       ", next %s",
+	"recurring.list_paused":     ", paused",
+	"recurring.list_asks":       ", with confirmation",
+	"recurring.list_pending":    "\n  ⏳ %d waiting: /recurring confirm %d",
+	"recurring.paused":          "⏸ Recurring income #%d paused.",
+	"recurring.resumed":         "▶️ Recurring income #%d resumed, next %s.",
+	"recurring.deleted":         "✅ Recurring income #%d deleted. Incomes already added stay.",
+	"recurring.nothing_pending": "ℹ️ Recurring income #%d has nothing waiting for confirmation.",
+	"recurring.not_found":       "ℹ️ Recurring income #%d not found.",
+	"recurring.usage": "❌ Usage:\n" +
+		"/recurring add [amount] monthly|weekly on [day] [note] [ask]\n" +
+		"/recurring list\n" +
+		"/recurring pause|resume|delete [id]\n" +
+		"/recurring confirm|skip [id]",
+	"recurring.disabled":    "ℹ️ Recurring incomes are not configured on this server.",
+	"recurring.run_created": "🔁 Recurring income #%d added: ",
+	"recurring.run_pending": "❓ Recurring income #%d is due: ",
+	"recurring.run_confirm": "Add it: /recurring confirm %d, skip it: /recurring skip %d",
+
+	// forecast
+	"forecast.title":                  "Forecast for %d (as of %s)",
+	"forecast.to_date":                "💰 Income to date: ",
+	"forecast.income":                 "🎯 Year-end income: ",
+	"forecast.tax":                    "🧾 Tax: ",
+	"forecast.extra":                  "➕ 1% extra contribution: ",
+	"forecast.fixed":                  "💳 Fixed contributions: %s, left to pay %s",
+	"forecast.due":                    "💸 Tax to pay after contributions and advances: ",
+	"forecast.range":                  " (%s – %s)",
+	"forecast.basis_seasonal":         "ℹ️ Based on the run-rate so far and on last year's income by month.",
+	"forecast.basis_rate":             "ℹ️ Based on the run-rate so far; with last year's income the forecast would follow its seasonality.",
+	"forecast.usn_limit.crossed":      "⛔ Income already exceeds the USN limit of %s.",
+	"forecast.usn_limit.expected":     "⚠️ Income is expected to exceed the USN limit of %s.",
+	"forecast.usn_limit.possible":     "⚠️ Income may exceed the USN limit of %s.",
+	"forecast.vat_threshold.crossed":  "⛔ Income already exceeds the VAT threshold of %s.",
+	"forecast.vat_threshold.expected": "⚠️ Income is expected to exceed the VAT threshold of %s.",
+	"forecast.vat_threshold.possible": "⚠️ Income may exceed the VAT threshold of %s.",
+	"forecast.disabled":               "ℹ️ The forecast is not configured on this server.",
+
+	// limits
+	"limits.title":                  "Limits for %d (as of %s)",
+	"limits.to_date":                "💰 Income to date: ",
+	"limits.usn_limit":              "• USN limit %s: %d%% used",
+	"limits.vat_threshold":          "• VAT threshold %s: %d%% used",
+	"limits.vat_rate_limit":         "• Reduced VAT rate up to %s: %d%% used",
+	"limits.left":                   ", %s left",
+	"limits.vat_exempt":             "🧾 VAT: exempt",
+	"limits.vat_rate":               "🧾 VAT: %s since %s",
+	"limits.none":                   "ℹ️ No income limits are known for %d.",
+	"limits.usn_limit.near":         "⚠️ %d%% of the USN limit of %s is used.",
+	"limits.usn_limit.crossed":      "⛔ The income of the year exceeds the USN limit of %s: the simplified system is lost.",
+	"limits.vat_threshold.near":     "⚠️ %d%% of the VAT threshold of %s is used.",
+	"limits.vat_threshold.crossed":  "⛔ The income of the year exceeds the VAT threshold of %s: VAT is due from the next month.",
+	"limits.vat_rate_limit.near":    "⚠️ %d%% of the reduced VAT rate limit of %s is used.",
+	"limits.vat_rate_limit.crossed": "⛔ The income of the year exceeds %s: the higher VAT rate applies from the next month.",
+	"limits.disabled":               "ℹ️ Limit monitoring is not configured on this server.",
+
+	// chart
+	"chart.title":      "Income by month, %d",
+	"chart.legend":     "🟦 income  🟧 tax",
+	"chart.month_line": "• %s: %s, tax %s",
+	"chart.empty":      "ℹ️ No income in %d.",
+	"chart.usage":      "❌ Usage: /chart [year], e.g. /chart 2024",
+	"chart.disabled":   "ℹ️ Charts are not configured on this server.",
+	"chart.month.1":    "January",
+	"chart.month.2":    "February",
+	"chart.month.3":    "March",
+	"chart.month.4":    "April",
+	"chart.month.5":    "May",
+	"chart.month.6":    "June",
+	"chart.month.7":    "July",
+	"chart.month.8":    "August",
+	"chart.month.9":    "September",
+	"chart.month.10":   "October",
+	"chart.month.11":   "November",
+	"chart.month.12":   "December",
+
+	// report
+	"report.title.month": "Income by month, %s – %s",
+	"report.title.week":  "Income by week, %s – %s",
+	"report.col.month":   "Month",
+	"report.col.week":    "Week",
+	"report.col.count":   "N",
+	"report.col.sum":     "Sum",
+	"report.col.avg":     "Avg",
+	"report.col.max":     "Max",
+	"report.col.tax":     "Tax",
+	"report.week":        "W%02d %s",
+	"report.total":       "Total",
+	"report.note":        "Amounts in whole rubles, kopecks dropped.",
+	"report.empty":       "ℹ️ No income from %s to %s.",
+	"report.usage":       "❌ Usage: /report [month|week] [period]\nThe period is a year (2025), a quarter (2025-q2) or a month (2025-03), not in the future.",
+	"report.disabled":    "ℹ️ Reports are not configured on this server.",
+
+	// find
+	"find.title":        "Notes with “%s”",
+	"find.kind.income":  "income",
+	"find.kind.contrib": "contribution",
+	"find.kind.advance": "advance",
+	"find.more":         "Showing the newest %d. Add a period to narrow the search, e.g. /find order 2025-q2",
+	"find.empty":        "ℹ️ Nothing found for “%s”.",
+	"find.usage":        "❌ Usage: /find <text> [period], e.g. /find order #42 2025\nThe period is a year (2025), a quarter (2025-q2) or a month (2025-03), not in the future.",
+	"find.disabled":     "ℹ️ Search is not configured on this server.",
+
+	// invoices
+	"client.added":              "✅ Client #%d added: %s\nDetails: /client set %d inn|kpp|address [value]",
+	"client.title":              "👤 Client #%d: %s",
+	"client.list_title":         "👥 Clients:",
+	"client.list_line":          "• #%d %s",
+	"client.list_inn":           ", INN %s",
+	"client.list_empty":         "ℹ️ No clients yet. Add one: /client add Acme LLC",
+	"client.exists":             "ℹ️ A client named “%s” already exists. See /clients",
+	"client.not_found":          "ℹ️ Client #%d not found. See /clients",
+	"client.usage":              "❌ Usage:\n/client add [name]\n/client set [id] inn|kpp|address [value]\n/client [id]",
+	"requisites.title":          "🏦 Requisites for invoices:",
+	"requisites.line":           "%s: %s",
+	"requisites.unset":          "—",
+	"requisites.field.name":     "Name",
+	"requisites.field.inn":      "INN",
+	"requisites.field.kpp":      "KPP",
+	"requisites.field.ogrnip":   "OGRNIP",
+	"requisites.field.address":  "Address",
+	"requisites.field.bank":     "Bank",
+	"requisites.field.bik":      "BIK",
+	"requisites.field.account":  "Account",
+	"requisites.field.corr":     "Corr. account",
+	"requisites.missing":        "⚠️ To issue invoices, fill in: %s.",
+	"requisites.hint":           "✏️ /requisites name|inn|ogrnip|address|bank|bik|account|corr [value]",
+	"requisites.invalid":        "❌ %s is invalid. Check the digits; accounts are checked against the BIK, so set it first.",
+	"requisites.usage":          "❌ Usage: /requisites name|inn|ogrnip|address|bank|bik|account|corr [value]\n«-» clears a field.",
+	"invoice.header":            "🧾 Invoice #%d (No. %d of %s)",
+	"invoice.client":            "👤 Client: %s",
+	"invoice.due":               "📅 Due: %s",
+	"invoice.status.unpaid":     "⏳ Not paid",
+	"invoice.status.partial":    "🟡 Paid %s, left %s",
+	"invoice.status.paid":       "✅ Paid in full",
+	"invoice.overdue":           "⚠️ Overdue: it was due %s",
+	"invoice.pay_hint":          "When it is paid: /invoice paid %d",
+	"invoice.paid":              "✅ Payment of invoice #%d added as income #%d: ",
+	"invoice.payment_note":      "Payment of invoice No. %d of %s",
+	"invoice.list_title":        "🧾 Invoices:",
+	"invoice.list_title_unpaid": "🧾 Unpaid invoices:",
+	"invoice.list_line":         "• #%d No. %d of %s, %s: %s",
+	"invoice.list_paid":         " ✅",
+	"invoice.list_left":         ", %s left",
+	"invoice.list_due":          ", due %s",
+	"invoice.list_overdue":      ", ⚠️ overdue since %s",
+	"invoice.list_empty":        "ℹ️ No invoices. Issue one: /invoice new [client id] [amount]",
+	"invoice.list_empty_unpaid": "✅ All invoices are paid.",
+	"invoice.not_found":         "ℹ️ Invoice #%d not found. See /invoices",
+	"invoice.already_paid":      "ℹ️ Invoice #%d is already paid in full.",
+	"invoice.overpayment":       "❌ Only %s is left to pay on invoice #%d.",
+	"invoice.no_requisites":     "⚠️ Set your requisites first: name, INN, bank, BIK and account. See /requisites",
+	"invoice.bad_due":           "❌ The due date cannot be before today.",
+	"invoice.usage":             "❌ Usage:\n/invoice new [client id] [amount] [due date] [description]\n/invoice [id]\n/invoice paid [id] [date] [amount]\n/invoices [unpaid]",
+	"invoice.disabled":          "ℹ️ Invoices are not configured on this server.",
+	"invoice.reminder":          "⏰ Invoice #%d (No. %d of %s) to %s is overdue: it was due %s, %s is left to pay.\nWhen it is paid: /invoice paid %d",
+
+	// acts
+	"act.header":           "📄 Act #%d (No. %d of %s)",
+	"act.client":           "👤 Customer: %s",
+	"act.basis_invoice":    "🧾 For invoice #%d (No. %d of %s)",
+	"act.basis_income":     "💰 For income #%d",
+	"act.issued":           "✅ Act issued. The PDF is for signing, the DOCX for editing.",
+	"act.exists":           "ℹ️ An act was already issued for it:",
+	"act.list_title":       "📄 Acts of %d:",
+	"act.list_title_all":   "📄 Acts:",
+	"act.list_line":        "• #%d No. %d of %s, %s: %s",
+	"act.list_invoice":     " (invoice #%d)",
+	"act.list_income":      " (income #%d)",
+	"act.list_empty":       "ℹ️ No acts. Issue one: /act invoice [invoice id]",
+	"act.not_found":        "ℹ️ Act #%d not found. See /acts",
+	"act.income_not_found": "ℹ️ Income #%d not found or undone.",
+	"act.usage":            "❌ Usage:\n/act invoice [invoice id]\n/act income [income id] [client id] [description]\n/act [id]\n/acts [year|all]",
+	// tax payment orders
+	"pay.title":         "🧾 Single tax payment (ENP) for Q%d %d",
+	"pay.payee":         "🏛 Payee: %s, INN %s, KPP %s",
+	"pay.account":       "🏦 Account %s, BIK %s, corr. account %s",
+	"pay.kbk":           "🔢 KBK %s, OKTMO %s",
+	"pay.purpose":       "💬 Purpose: %s",
+	"pay.hint":          "Import the .txt file into your bank app or scan the QR code with it.",
+	"pay.nothing_due":   "✅ No tax is due for Q%d %d. To pay anyway: /pay [quarter] [amount]",
+	"pay.no_requisites": "ℹ️ Set your name, INN, bank, BIK and account first: /requisites",
+	"pay.usage":         "❌ Usage: /pay [2025-q2|q2] [amount]\nWithout a quarter — the current one, without an amount — the tax due as in /total.",
+	"pay.disabled":      "ℹ️ Tax payment orders are not configured on this server.",
+
+	"act.disabled": "ℹ️ Acts are not configured on this server.",
+}
+
+var pluralsEN = map[string][]string{
+	"token.revoked_all":   {"✅ Revoked %d token.", "✅ Revoked %d tokens."},
+	"unlink.success":      {"✅ Unlinked %d %s account.", "✅ Unlinked %d %s accounts."},
+	"recurring.confirmed": {"✅ Added %d income.", "✅ Added %d incomes."},
+	"recurring.skipped":   {"✅ Skipped %d income.", "✅ Skipped %d incomes."},
+}
@@ -0,0 +1,422 @@
+package i18n
+
+var catalogRU = map[string]string{
+	// common
+	"common.unknown_command": "❓ Неизвестная команда. Напишите /help для справки.",
+	"common.error":           "⚠️ Ошибка при обработке команды. Попробуйте позже.",
+
+	// start / help
+	"start": "👋 Привет! Я помогу вести учёт доходов ИП (УСН 6%).\n\n" +
+		"📋 Основные команды:\n" +
+		"• /add [дата] [сумма] [комментарий] — добавить поступление\n" +
+		"  Примеры: /add 1000\n" +
+		"           /add 01.08.2025 5000 аванс\n" +
+		"           /add 1 234,56 заказ #42\n" +
+		"           /add 10р 50к аванс\n" +
+		"• /add_contrib [сумма] [комментарий] — добавить взнос\n" +
+		"• /add_advance [сумма] [комментарий] — добавить авансовый платеж\n" +
+		"• /undo [#номер] — отменить последнее поступление за квартал или поступление с номером\n" +
+		"• /undo_contrib [#номер] — отменить последний взнос\n" +
+		"• /undo_advance [#номер] — отменить последний авансовый платеж\n" +
+		"• /edit #номер [дата] [сумма] [комментарий] — исправить поступление; /edit_contrib, /edit_advance — платежи\n" +
+		"• /total — итоги за текущий квартал (сумма и налог 6%)\n" +
+		"• /forecast — прогноз поступлений и налога на конец года\n" +
+		"• /limits — лимит УСН, порог НДС и ставка НДС\n" +
+		"• /chart [год] — график поступлений по месяцам\n" +
+		"• /report [month|week] [период] — таблица поступлений по месяцам или неделям\n" +
+		"• /find <текст> [период] — поиск по комментариям поступлений и платежей\n" +
+		"• /token [название] — выпустить токен для REST API\n" +
+		"• /link — привязать другой аккаунт или CLI к этому учёту\n" +
+		"• /recurring — регулярные поступления (абонентка, подписки)\n" +
+		"• /invoice — счета клиентам, /clients — контрагенты, /requisites — ваши реквизиты\n" +
+		"• /act — акты выполненных работ, /acts — реестр актов\n" +
+		"• /pay — платёжка по налогу для банка: файл 1С и QR-код\n" +
+		"• /lang [ru|en|auto] — язык бота\n" +
+		"• /help — подробная справка\n\n" +
+		"💡 Формат суммы: без знака минус, поддерживаются «1 234,56», «1234.56», «10р 50к».",
+	"help": "📚 Справка\n\n" +
+		"🔧 Команды:\n" +
+		"• /add [дата] [сумма] [комментарий]\n" +
+		"  Добавляет поступление в базу. Сумма — без минуса, в рублях и копейках.\n" +
+		"  Дата необязательна: 2025-08-01 или 01.08.2025, по умолчанию — сегодня.\n" +
+		"  Примеры:\n" +
+		"   /add 1000\n" +
+		"   /add 1 234,56 заказ #42\n" +
+		"   /add 10р 50к аванс\n" +
+		"   /add 01.08.2025 5000 заказ #41\n" +
+		"  Чтобы исправить запись, отредактируйте сообщение с командой или используйте /edit.\n\n" +
+		"• /add_contrib [дата] [сумма] [комментарий]\n" +
+		"  Добавляет взнос в базу. Дата и сумма — аналогично /add.\n\n" +
+		"• /add_advance [дата] [сумма] [комментарий]\n" +
+		"  Добавляет авансовый платеж в базу. Дата и сумма — аналогично /add.\n\n" +
+		"• /undo [#номер]\n" +
+		"  Отменяет последнее поступление за квартал, а с номером — поступление с этим номером за любую дату.\n" +
+		"  Номера показывает /find: /undo #42\n\n" +
+		"• /undo_contrib [#номер]\n" +
+		"  Отменяет последний взнос или взнос с номером.\n\n" +
+		"• /undo_advance [#номер]\n" +
+		"  Отменяет последний авансовый платеж или платеж с номером.\n\n" +
+		"• /edit #номер [дата] [сумма] [комментарий]\n" +
+		"  Исправляет поступление с номером; без даты у него остаётся прежняя дата.\n" +
+		"  /edit_contrib и /edit_advance исправляют взносы и авансовые платежи.\n" +
+		"  Пример: /edit #42 1500 заказ #42\n\n" +
+		"• /total\n" +
+		"  Показывает сумму доходов и налог 6% за текущий квартал.\n\n" +
+		"• /forecast\n" +
+		"  Прогнозирует поступления за год по темпу с начала года и сезонности прошлого года,\n" +
+		"  а также налог, взнос 1% и остаток фиксированных взносов.\n" +
+		"  Предупреждает, если поступления могут превысить лимит УСН или порог НДС.\n\n" +
+		"• /limits\n" +
+		"  Показывает, какая доля лимита УСН, порога НДС и лимита пониженной ставки НДС\n" +
+		"  использована, и какая ставка НДС действует.\n" +
+		"  С 80% лимита /add предупреждает после каждого поступления.\n\n" +
+		"• /chart [год]\n" +
+		"  Присылает столбчатую диаграмму поступлений по месяцам с налогом; по умолчанию за текущий год.\n\n" +
+		"• /report [month|week] [период]\n" +
+		"  Таблица по месяцам или неделям: число поступлений, сумма, среднее, максимум и налог.\n" +
+		"  Период — год (2025), квартал (2025-q2) или месяц (2025-03); по умолчанию\n" +
+		"  месяцы текущего года или недели текущего квартала.\n\n" +
+		"• /find <текст> [период]\n" +
+		"  Находит поступления и платежи, в комментарии которых есть текст, без учёта регистра\n" +
+		"  и разницы между ё и е; новые сверху, с номерами для /undo и /edit. Период (2025, 2025-q2, 2025-03) в конце сужает поиск.\n\n" +
+		"• /token [название]\n" +
+		"  Выпускает токен для REST API. Токен показывается один раз.\n" +
+		"  /token list — список активных токенов\n" +
+		"  /token revoke [id|all] — отозвать токен или все токены\n\n" +
+		"• /link [код]\n" +
+		"  Без аргумента выдаёт одноразовый код на 10 минут.\n" +
+		"  Отправьте /link [код] из другого аккаунта Telegram или CLI, чтобы вести общий учёт.\n\n" +
+		"• /unlink [transport]\n" +
+		"  Отвязывает текущий аккаунт или все аккаунты транспорта (telegram, cli).\n\n" +
+		"• /recurring add [сумма] monthly|weekly on [день] [комментарий] [ask]\n" +
+		"  Добавляет поступление в каждую дату: день месяца 1–31 (в коротких месяцах — последний день)\n" +
+		"  или день недели (1–7, пн–вс). С «ask» бот спрашивает перед каждым поступлением.\n" +
+		"  Даты, пропущенные пока бот не работал, добавляются после запуска.\n" +
+		"  Пример: /recurring add 50000 monthly on 5 @клиент\n" +
+		"  /recurring list — список правил\n" +
+		"  /recurring pause|resume|delete [id] — управление правилом\n" +
+		"  /recurring confirm|skip [id] — добавить или пропустить ожидающие поступления\n\n" +
+		"• /requisites [поле значение]\n" +
+		"  Ваши реквизиты для счетов: name, inn, ogrnip, address, bank, bik, account, corr.\n" +
+		"  Сначала укажите БИК: контрольные ключи счетов проверяются по нему. «-» очищает поле.\n" +
+		"  Пример: /requisites name ИП Иванов Иван Иванович\n\n" +
+		"• /client add [название] | /client set [id] inn|kpp|address [значение] | /clients\n" +
+		"  Контрагенты, которым выставляются счета, с ИНН, КПП и адресом.\n\n" +
+		"• /invoice new [id клиента] [сумма] [до дата] [описание]\n" +
+		"  Выставляет счёт с номером в пределах года и присылает его в PDF; срок оплаты по умолчанию 14 дней.\n" +
+		"  Пример: /invoice new 1 50000 до 15.08.2025 Разработка сайта\n" +
+		"  /invoice [id] — показать счёт и прислать PDF ещё раз\n" +
+		"  /invoice paid [id] [дата] [сумма] — добавить поступление по счёту; без суммы — весь остаток.\n" +
+		"  Если отменить это поступление через /undo, счёт снова станет неоплаченным.\n" +
+		"  /invoices [unpaid] — счета; о просроченных бот напоминает раз в неделю\n\n" +
+		"• /act invoice [id счёта] | /act income [id поступления] [id клиента] [описание]\n" +
+		"  Выставляет акт выполненных работ с номером в пределах года в PDF и в DOCX для правки.\n" +
+		"  На счёт или поступление выставляется один акт; повторный запрос пришлёт его ещё раз.\n" +
+		"  /act [id] — показать акт и прислать файлы ещё раз\n" +
+		"  /acts [год|all] — реестр выставленных актов, по умолчанию за текущий год\n\n" +
+		"• /pay [2025-q2|q2] [сумма]\n" +
+		"  Платёжка единого налогового платежа (ЕНП) за квартал, по умолчанию текущий,\n" +
+		"  на налог к уплате, как в /total: файл 1С для загрузки в банк и QR-код для оплаты.\n\n" +
+		"• /lang [ru|en|auto]\n" +
+		"  Выбирает язык ответов; auto — по настройкам Telegram или системы.\n\n" +
+		"• /start\n" +
+		"  Краткая инструкция.\n\n" +
+		"💰 Формат суммы:\n" +
+		"  • Допускаются пробелы/точки/запятые как разделители тысяч.\n" +
+		"  • Последняя точка или запятая — десятичный разделитель (до 2 знаков).\n" +
+		"  • Понимает записи вида «10р 50к», «10 руб 50 коп».\n" +
+		"  • Отрицательные значения не принимаются.\n\n" +
+		"⚙️ Механика:\n" +
+		"  • Налог рассчитывается как 6% от суммы квартала (округление вниз).\n" +
+		"  • Квартал определяется по UTC датам (включительно).",
+
+	// input errors
+	"input.bad_amount":  "❌ Не понял сумму. Примеры: 1000 | 1 234,56 | 10р 50к",
+	"input.future_date": "❌ Дата не может быть в будущем. Формат: 2025-08-01 или 01.08.2025",
+	"input.zero_amount": "❌ Сумма не может быть 0",
+
+	// entry fields
+	"entry.amount": "💰 Сумма: ",
+	"entry.date":   "📅 Дата: ",
+	"entry.note":   "💬 Комментарий: ",
+
+	// add / edit / undo
+	"add.income":              "✅ Добавлено поступление: ",
+	"add.contrib":             "✅ Добавлен взнос: ",
+	"add.advance":             "✅ Добавлен авансовый платеж: ",
+	"edit.income":             "✏️ Поступление исправлено: ",
+	"edit.contrib":            "✏️ Взнос исправлен: ",
+	"edit.advance":            "✏️ Авансовый платеж исправлен: ",
+	"edit.not_found":          "⚠️ Запись для этого сообщения не найдена или уже отменена. Отправьте команду заново.",
+	"edit.kind_mismatch":      "⚠️ Нельзя сменить тип записи правкой. Отмените её через /undo и добавьте заново.",
+	"edit.usage":              "❌ Формат: /edit #номер [дата] [сумма] [комментарий], например /edit #42 1500 заказ #42\nБез даты у записи остаётся прежняя дата. Платежи исправляют /edit_contrib и /edit_advance; номера показывает /find.",
+	"undo.income":             "✅ Поступление отменено:",
+	"undo.contrib":            "✅ Взнос отменен:",
+	"undo.advance":            "✅ Авансовый платеж отменен:",
+	"undo.nothing_income":     "ℹ️ Нечего отменять. Нет поступлений за текущий квартал.",
+	"undo.nothing_contrib":    "ℹ️ Нечего отменять. Нет взносов за текущий год.",
+	"undo.nothing_advance":    "ℹ️ Нечего отменять. Нет авансовых платежей за текущий год.",
+	"undo.bad_id":             "❌ Номер должен быть числом, например /undo #42. Номера записей показывает /find.",
+	"entry.not_found.income":  "ℹ️ Поступление #%d не найдено или уже отменено.",
+	"entry.not_found.contrib": "ℹ️ Взнос #%d не найден или уже отменен.",
+	"entry.not_found.advance": "ℹ️ Авансовый платеж #%d не найден или уже отменен.",
+
+	// total
+	"total.quarter": "%d квартал: %s - %s",
+	"total.year":    "Итого за %d год:",
+	"total.income":  "💰 Поступления: ",
+	"total.contrib": "💳 Взносы: ",
+	"total.advance": "💸 Авансы: ",
+	"total.tax":     "🧾 Налог: ",
+	"total.vat":     "🧾 НДС %s: ",
+
+	// token
+	"token.issued":     "🔑 Токен #%d создан:",
+	"token.issued_tip": "⚠️ Сохраните его сейчас — повторно токен не показывается.\nПередавайте в заголовке: Authorization: Bearer <токен>",
+	"token.list_empty": "ℹ️ Активных токенов нет. Создайте: /token [название]",
+	"token.list_title": "🔑 Активные токены:",
+	"token.created":    " — создан %s",
+	"token.used":       ", использован %s",
+	"token.revoked":    "✅ Токен #%d отозван.",
+	"token.not_found":  "ℹ️ Активный токен #%d не найден.",
+	"token.usage": "❌ Использование:\n" +
+		"/token [название] — выпустить токен\n" +
+		"/token list — список токенов\n" +
+		"/token revoke [id|all] — отозвать",
+	"token.disabled": "ℹ️ REST API не настроен на этом сервере.",
+
+	// link
+	"link.code":        "🔗 Код привязки: ",
+	"link.send_before": "Отправьте ",
+	"link.send_after":  " из другого аккаунта или CLI.",
+	"link.valid_until": "⏳ Действует до %s UTC, один раз.",
+	"link.success":     "✅ Аккаунт привязан. Теперь здесь виден общий учёт.",
+	"link.invalid":     "❌ Код неверный, истёк или уже использован. Получите новый: /link",
+	"link.already":     "ℹ️ Этот аккаунт уже привязан к этому учёту.",
+	"link.has_entries": "❌ В этом аккаунте уже есть записи — после привязки они станут недоступны.\n" +
+		"Отмените их или привяжите в обратную сторону: получите код здесь и введите его в другом аккаунте.",
+	"link.disabled":  "ℹ️ Привязка аккаунтов не настроена на этом сервере.",
+	"unlink.nothing": "ℹ️ Нет привязанных аккаунтов %s.",
+	"unlink.last":    "❌ Нельзя отвязать последний аккаунт — учёт станет недоступен.",
+	"unlink.api":     "ℹ️ Доступ к API управляется токенами: /token list, /token revoke [id|all]",
+
+	// lang
+	"lang.current":     "🌐 Язык: %s",
+	"lang.auto_suffix": " (автоматически)",
+	"lang.usage":       "Сменить: /lang ru | /lang en | /lang auto",
+	"lang.set":         "✅ Теперь я отвечаю по-русски.",
+	"lang.auto":        "✅ Язык будет выбираться автоматически — по настройкам Telegram или системы.",
+	"lang.unknown":     "❌ Такой язык не поддерживается. Доступны: ru, en",
+	"lang.disabled":    "ℹ️ Выбор языка не настроен на этом сервере.",
+	"lang.name.ru":     "русский",
+	"lang.name.en":     "английский",
+
+	// recurring
+	"recurring.added":           "🔁 Регулярное поступление #%d: ",
+	"recurring.monthly":         "ежемесячно %d числа",
+	"recurring.weekly":          "еженедельно, %s",
+	"recurring.weekday.1":       "понедельник",
+	"recurring.weekday.2":       "вторник",
+	"recurring.weekday.3":       "среда",
+	"recurring.weekday.4":       "четверг",
+	"recurring.weekday.5":       "пятница",
+	"recurring.weekday.6":       "суббота",
+	"recurring.weekday.7":       "воскресенье",
+	"recurring.next":            "📅 Следующее: %s",
+	"recurring.asks":            "❓ Перед каждым поступлением я спрошу подтверждение.",
+	"recurring.list_empty":      "ℹ️ Регулярных поступлений нет. Добавьте: /recurring add 50000 monthly on 5 @клиент",
+	"recurring.list_title":      "🔁 Регулярные поступления:",
+	"recurring.list_next":       ", следующее %s",
+	"recurring.list_paused":     ", на паузе",
+	"recurring.list_asks":       ", с подтверждением",
+	"recurring.list_pending":    "\n  ⏳ ждут подтверждения: %d — /recurring confirm %d",
+	"recurring.paused":          "⏸ Регулярное поступление #%d на паузе.",
+	"recurring.resumed":         "▶️ Регулярное поступление #%d возобновлено, следующее %s.",
+	"recurring.deleted":         "✅ Регулярное поступление #%d удалено. Добавленные поступления остаются.",
+	"recurring.nothing_pending": "ℹ️ У регулярного поступления #%d ничего не ждёт подтверждения.",
+	"recurring.not_found":       "ℹ️ Регулярное поступление #%d не найдено.",
+	"recurring.usage": "❌ Использование:\n" +
+		"/recurring add [сумма] monthly|weekly on [день] [комментарий] [ask]\n" +
+		"/recurring list\n" +
+		"/recurring pause|resume|delete [id]\n" +
+		"/recurring confirm|skip [id]",
+	"recurring.disabled":    "ℹ️ Регулярные поступления не настроены на этом сервере.",
+	"recurring.run_created": "🔁 Добавлено регулярное поступление #%d: ",
+	"recurring.run_pending": "❓ Подошёл срок регулярного поступления #%d: ",
+	"recurring.run_confirm": "Добавить: /recurring confirm %d, пропустить: /recurring skip %d",
+
+	// forecast
+	"forecast.title":                  "Прогноз на %d год (на %s)",
+	"forecast.to_date":                "💰 Поступления с начала года: ",
+	"forecast.income":                 "🎯 Поступления за год: ",
+	"forecast.tax":                    "🧾 Налог: ",
+	"forecast.extra":                  "➕ Взнос 1%: ",
+	"forecast.fixed":                  "💳 Фиксированные взносы: %s, осталось заплатить %s",
+	"forecast.due":                    "💸 Налог к уплате после взносов и авансов: ",
+	"forecast.range":                  " (%s – %s)",
+	"forecast.basis_seasonal":         "ℹ️ По темпу поступлений с начала года и по прошлогодним поступлениям по месяцам.",
+	"forecast.basis_rate":             "ℹ️ По темпу поступлений с начала года; с поступлениями прошлого года прогноз учтёт сезонность.",
+	"forecast.usn_limit.crossed":      "⛔ Поступления уже превышают лимит УСН %s.",
+	"forecast.usn_limit.expected":     "⚠️ Поступления, вероятно, превысят лимит УСН %s.",
+	"forecast.usn_limit.possible":     "⚠️ Поступления могут превысить лимит УСН %s.",
+	"forecast.vat_threshold.crossed":  "⛔ Поступления уже превышают порог НДС %s.",
+	"forecast.vat_threshold.expected": "⚠️ Поступления, вероятно, превысят порог НДС %s.",
+	"forecast.vat_threshold.possible": "⚠️ Поступления могут превысить порог НДС %s.",
+	"forecast.disabled":               "ℹ️ Прогноз не настроен на этом сервере.",
+
+	// limits
+	"limits.title":                  "Лимиты на %d год (на %s)",
+	"limits.to_date":                "💰 Поступления с начала года: ",
+	"limits.usn_limit":              "• Лимит УСН %s: использовано %d%%",
+	"limits.vat_threshold":          "• Порог НДС %s: использовано %d%%",
+	"limits.vat_rate_limit":         "• Пониженная ставка НДС до %s: использовано %d%%",
+	"limits.left":                   ", осталось %s",
+	"limits.vat_exempt":             "🧾 НДС: освобождение",
+	"limits.vat_rate":               "🧾 НДС: %s с %s",
+	"limits.none":                   "ℹ️ Лимиты на %d год неизвестны.",
+	"limits.usn_limit.near":         "⚠️ Использовано %d%% лимита УСН %s.",
+	"limits.usn_limit.crossed":      "⛔ Поступления за год превысили лимит УСН %s: право на упрощённую систему утрачено.",
+	"limits.vat_threshold.near":     "⚠️ Использовано %d%% порога НДС %s.",
+	"limits.vat_threshold.crossed":  "⛔ Поступления за год превысили порог НДС %s: НДС нужно платить со следующего месяца.",
+	"limits.vat_rate_limit.near":    "⚠️ Использовано %d%% лимита пониженной ставки НДС %s.",
+	"limits.vat_rate_limit.crossed": "⛔ Поступления за год превысили %s: со следующего месяца действует повышенная ставка НДС.",
+	"limits.disabled":               "ℹ️ Контроль лимитов не настроен на этом сервере.",
+
+	// chart
+	"chart.title":      "Поступления по месяцам, %d",
+	"chart.legend":     "🟦 поступления  🟧 налог",
+	"chart.month_line": "• %s: %s, налог %s",
+	"chart.empty":      "ℹ️ Поступлений за %d год нет.",
+	"chart.usage":      "❌ Формат: /chart [год], например /chart 2024",
+	"chart.disabled":   "ℹ️ Графики не настроены на этом сервере.",
+	"chart.month.1":    "Январь",
+	"chart.month.2":    "Февраль",
+	"chart.month.3":    "Март",
+	"chart.month.4":    "Апрель",
+	"chart.month.5":    "Май",
+	"chart.month.6":    "Июнь",
+	"chart.month.7":    "Июль",
+	"chart.month.8":    "Август",
+	"chart.month.9":    "Сентябрь",
+	"chart.month.10":   "Октябрь",
+	"chart.month.11":   "Ноябрь",
+	"chart.month.12":   "Декабрь",
+
+	// report
+	"report.title.month": "Поступления по месяцам, %s – %s",
+	"report.title.week":  "Поступления по неделям, %s – %s",
+	"report.col.month":   "Месяц",
+	"report.col.week":    "Неделя",
+	"report.col.count":   "Шт",
+	"report.col.sum":     "Сумма",
+	"report.col.avg":     "Средн",
+	"report.col.max":     "Макс",
+	"report.col.tax":     "Налог",
+	"report.week":        "№%02d %s",
+	"report.total":       "Итого",
+	"report.note":        "Суммы в целых рублях, без копеек.",
+	"report.empty":       "ℹ️ Поступлений с %s по %s нет.",
+	"report.usage":       "❌ Формат: /report [month|week] [период]\nПериод — год (2025), квартал (2025-q2) или месяц (2025-03), не в будущем.",
+	"report.disabled":    "ℹ️ Отчёты не настроены на этом сервере.",
+
+	// find
+	"find.title":        "Комментарии с «%s»",
+	"find.kind.income":  "поступление",
+	"find.kind.contrib": "взнос",
+	"find.kind.advance": "аванс",
+	"find.more":         "Показаны %d последних. Добавьте период, чтобы сузить поиск, например /find заказ 2025-q2",
+	"find.empty":        "ℹ️ По запросу «%s» ничего не найдено.",
+	"find.usage":        "❌ Формат: /find <текст> [период], например /find заказ #42 2025\nПериод — год (2025), квартал (2025-q2) или месяц (2025-03), не в будущем.",
+	"find.disabled":     "ℹ️ Поиск не настроен на этом сервере.",
+
+	// invoices
+	"client.added":              "✅ Контрагент #%d добавлен: %s\nРеквизиты: /client set %d inn|kpp|address [значение]",
+	"client.title":              "👤 Контрагент #%d: %s",
+	"client.list_title":         "👥 Контрагенты:",
+	"client.list_line":          "• #%d %s",
+	"client.list_inn":           ", ИНН %s",
+	"client.list_empty":         "ℹ️ Контрагентов пока нет. Добавьте: /client add ООО Ромашка",
+	"client.exists":             "ℹ️ Контрагент «%s» уже есть. Список: /clients",
+	"client.not_found":          "ℹ️ Контрагент #%d не найден. Список: /clients",
+	"client.usage":              "❌ Формат:\n/client add [название]\n/client set [id] inn|kpp|address [значение]\n/client [id]",
+	"requisites.title":          "🏦 Реквизиты для счетов:",
+	"requisites.line":           "%s: %s",
+	"requisites.unset":          "—",
+	"requisites.field.name":     "Наименование",
+	"requisites.field.inn":      "ИНН",
+	"requisites.field.kpp":      "КПП",
+	"requisites.field.ogrnip":   "ОГРНИП",
+	"requisites.field.address":  "Адрес",
+	"requisites.field.bank":     "Банк",
+	"requisites.field.bik":      "БИК",
+	"requisites.field.account":  "Расчётный счёт",
+	"requisites.field.corr":     "Корр. счёт",
+	"requisites.missing":        "⚠️ Чтобы выставлять счета, заполните: %s.",
+	"requisites.hint":           "✏️ /requisites name|inn|ogrnip|address|bank|bik|account|corr [значение]",
+	"requisites.invalid":        "❌ %s указан неверно. Проверьте цифры; счета проверяются по БИК, поэтому сначала укажите его.",
+	"requisites.usage":          "❌ Формат: /requisites name|inn|ogrnip|address|bank|bik|account|corr [значение]\n«-» очищает поле.",
+	"invoice.header":            "🧾 Счёт #%d (№ %d от %s)",
+	"invoice.client":            "👤 Покупатель: %s",
+	"invoice.due":               "📅 Оплатить до: %s",
+	"invoice.status.unpaid":     "⏳ Не оплачен",
+	"invoice.status.partial":    "🟡 Оплачено %s, осталось %s",
+	"invoice.status.paid":       "✅ Оплачен полностью",
+	"invoice.overdue":           "⚠️ Просрочен: срок оплаты был %s",
+	"invoice.pay_hint":          "Когда оплатят: /invoice paid %d",
+	"invoice.paid":              "✅ Оплата счёта #%d добавлена как поступление #%d: ",
+	"invoice.payment_note":      "Оплата по счёту № %d от %s",
+	"invoice.list_title":        "🧾 Счета:",
+	"invoice.list_title_unpaid": "🧾 Неоплаченные счета:",
+	"invoice.list_line":         "• #%d № %d от %s, %s: %s",
+	"invoice.list_paid":         " ✅",
+	"invoice.list_left":         ", осталось %s",
+	"invoice.list_due":          ", до %s",
+	"invoice.list_overdue":      ", ⚠️ просрочен с %s",
+	"invoice.list_empty":        "ℹ️ Счетов нет. Выставить: /invoice new [id клиента] [сумма]",
+	"invoice.list_empty_unpaid": "✅ Все счета оплачены.",
+	"invoice.not_found":         "ℹ️ Счёт #%d не найден. Список: /invoices",
+	"invoice.already_paid":      "ℹ️ Счёт #%d уже оплачен полностью.",
+	"invoice.overpayment":       "❌ Осталось оплатить только %s по счёту #%d.",
+	"invoice.no_requisites":     "⚠️ Сначала заполните реквизиты: наименование, ИНН, банк, БИК и расчётный счёт. См. /requisites",
+	"invoice.bad_due":           "❌ Срок оплаты не может быть раньше сегодняшнего дня.",
+	"invoice.usage":             "❌ Формат:\n/invoice new [id клиента] [сумма] [до дата] [описание]\n/invoice [id]\n/invoice paid [id] [дата] [сумма]\n/invoices [unpaid]",
+	"invoice.disabled":          "ℹ️ Счета не настроены на этом сервере.",
+	"invoice.reminder":          "⏰ Счёт #%d (№ %d от %s) для %s просрочен: срок оплаты был %s, осталось оплатить %s.\nКогда оплатят: /invoice paid %d",
+
+	// acts
+	"act.header":           "📄 Акт #%d (№ %d от %s)",
+	"act.client":           "👤 Заказчик: %s",
+	"act.basis_invoice":    "🧾 По счёту #%d (№ %d от %s)",
+	"act.basis_income":     "💰 По поступлению #%d",
+	"act.issued":           "✅ Акт выставлен. PDF — для подписания, DOCX — для правки.",
+	"act.exists":           "ℹ️ Акт на это уже выставлен:",
+	"act.list_title":       "📄 Акты за %d год:",
+	"act.list_title_all":   "📄 Акты:",
+	"act.list_line":        "• #%d № %d от %s, %s: %s",
+	"act.list_invoice":     " (счёт #%d)",
+	"act.list_income":      " (поступление #%d)",
+	"act.list_empty":       "ℹ️ Актов нет. Выставить: /act invoice [id счёта]",
+	"act.not_found":        "ℹ️ Акт #%d не найден. Список: /acts",
+	"act.income_not_found": "ℹ️ Поступление #%d не найдено или отменено.",
+	"act.usage":            "❌ Формат:\n/act invoice [id счёта]\n/act income [id поступления] [id клиента] [описание]\n/act [id]\n/acts [год|all]",
+	// tax payment orders
+	"pay.title":         "🧾 Единый налоговый платёж (ЕНП) за %d квартал %d г.",
+	"pay.payee":         "🏛 Получатель: %s, ИНН %s, КПП %s",
+	"pay.account":       "🏦 Счёт %s, БИК %s, ЕКС %s",
+	"pay.kbk":           "🔢 КБК %s, ОКТМО %s",
+	"pay.purpose":       "💬 Назначение: %s",
+	"pay.hint":          "Загрузите файл .txt в банк-клиент или отсканируйте QR-код в приложении банка.",
+	"pay.nothing_due":   "✅ За %d квартал %d г. налог к уплате не начислен. Заплатить всё равно: /pay [квартал] [сумма]",
+	"pay.no_requisites": "ℹ️ Сначала укажите ФИО, ИНН, банк, БИК и счёт: /requisites",
+	"pay.usage":         "❌ Формат: /pay [2025-q2|q2] [сумма]\nБез квартала — текущий, без суммы — налог к уплате, как в /total.",
+	"pay.disabled":      "ℹ️ Платёжки по налогам не настроены на этом сервере.",
+
+	"act.disabled": "ℹ️ Акты не настроены на этом сервере.",
+}
+
+var pluralsRU = map[string][]string{
+	"token.revoked_all":   {"✅ Отозван %d токен.", "✅ Отозвано %d токена.", "✅ Отозвано %d токенов."},
+	"unlink.success":      {"✅ Отвязан %d аккаунт %s.", "✅ Отвязано %d аккаунта %s.", "✅ Отвязано %d аккаунтов %s."},
+	"recurring.confirmed": {"✅ Добавлено %d поступление.", "✅ Добавлено %d поступления.", "✅ Добавлено %d поступлений."},
+	"recurring.skipped":   {"✅ Пропущено %d поступление.", "✅ Пропущено %d поступления.", "✅ Пропущено %d поступлений."},
+}
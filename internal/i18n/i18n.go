@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+type langCtxKey struct{}
+
+// Supported returns the languages that have a catalog, default first.
+func Supported() []Lang {
+	return []Lang{RU, EN}
+}
+
+// Parse maps a language tag such as Telegram's language_code ("en", "en-US")
+// or a POSIX locale ("en_US.UTF-8") to a supported language.
+func Parse(tag string) (Lang, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	if i := strings.IndexAny(tag, "-_."); i >= 0 {
+		tag = tag[:i]
+	}
+
+	for _, l := range Supported() {
+		if tag == string(l) {
+			return l, true
+		}
+	}
+
+	return "", false
+}
+
+// WithLang stores the language of the current request in ctx.
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, langCtxKey{}, lang)
+}
+
+// FromContext returns the language set by WithLang, or Default.
+func FromContext(ctx context.Context) Lang {
+	if l, ok := ctx.Value(langCtxKey{}).(Lang); ok && l != "" {
+		return l
+	}
+
+	return Default
+}
+
+// For returns the printer of lang; unknown languages fall back to Default.
+func For(lang Lang) *Printer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = Default
+	}
+
+	return &Printer{
+		lang:     lang,
+		messages: catalogs[lang],
+		plurals:  pluralCatalogs[lang],
+	}
+}
+
+// Keys lists the message and plural keys of lang's catalog, sorted.
+func Keys(lang Lang) []string {
+	keys := make([]string, 0, len(catalogs[lang])+len(pluralCatalogs[lang]))
+
+	for k := range catalogs[lang] {
+		keys = append(keys, k)
+	}
+	for k := range pluralCatalogs[lang] {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
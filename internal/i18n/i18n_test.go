@@ -0,0 +1,129 @@
+package i18n_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag  string
+		want i18n.Lang
+		ok   bool
+	}{
+		{"ru", i18n.RU, true},
+		{"en", i18n.EN, true},
+		{"en-US", i18n.EN, true},
+		{"en_GB.UTF-8", i18n.EN, true},
+		{" RU ", i18n.RU, true},
+		{"de", "", false},
+		{"", "", false},
+		{"C", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := i18n.Parse(tt.tag)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v; want %q, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPrinter_N_RussianPlurals(t *testing.T) {
+	t.Parallel()
+
+	p := i18n.For(i18n.RU)
+
+	tests := map[int64]string{
+		1:   "✅ Отозван 1 токен.",
+		2:   "✅ Отозвано 2 токена.",
+		5:   "✅ Отозвано 5 токенов.",
+		11:  "✅ Отозвано 11 токенов.",
+		21:  "✅ Отозван 21 токен.",
+		22:  "✅ Отозвано 22 токена.",
+		112: "✅ Отозвано 112 токенов.",
+		0:   "✅ Отозвано 0 токенов.",
+	}
+
+	for n, want := range tests {
+		if got := p.N("token.revoked_all", n); got != want {
+			t.Errorf("N(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestPrinter_N_EnglishPlurals(t *testing.T) {
+	t.Parallel()
+
+	p := i18n.For(i18n.EN)
+
+	if got, want := p.N("unlink.success", 1, "cli"), "✅ Unlinked 1 cli account."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := p.N("unlink.success", 3, "cli"), "✅ Unlinked 3 cli accounts."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrinter_MoneyAndDate(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	ru, en := i18n.For(i18n.RU), i18n.For(i18n.EN)
+
+	if got, want := ru.Money(123456), "1 234,56 ₽"; got != want {
+		t.Errorf("ru Money = %q, want %q", got, want)
+	}
+	if got, want := en.Money(123456789), "₽1,234,567.89"; got != want {
+		t.Errorf("en Money = %q, want %q", got, want)
+	}
+	if got, want := en.Money(5), "₽0.05"; got != want {
+		t.Errorf("en Money = %q, want %q", got, want)
+	}
+	if got, want := ru.Date(day), "01.08.2025"; got != want {
+		t.Errorf("ru Date = %q, want %q", got, want)
+	}
+	if got, want := en.Date(day), "Aug 1, 2025"; got != want {
+		t.Errorf("en Date = %q, want %q", got, want)
+	}
+}
+
+func TestPrinter_T_FallsBack(t *testing.T) {
+	t.Parallel()
+
+	if got := i18n.For("de").Lang(); got != i18n.Default {
+		t.Errorf("For(de).Lang() = %q, want default", got)
+	}
+	if got := i18n.For(i18n.EN).T("no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key = %q", got)
+	}
+}
+
+// Every language must translate every key of the default catalog.
+func TestCatalogs_Complete(t *testing.T) {
+	t.Parallel()
+
+	keys := i18n.Keys(i18n.Default)
+
+	for _, lang := range i18n.Supported() {
+		got := map[string]bool{}
+		for _, k := range i18n.Keys(lang) {
+			got[k] = true
+		}
+
+		for _, k := range keys {
+			if !got[k] {
+				t.Errorf("%s: missing key %q", lang, k)
+			}
+		}
+
+		if len(got) != len(keys) {
+			t.Errorf("%s: %d keys, default has %d", lang, len(got), len(keys))
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package i18n
+
+// locales holds number and date formatting; Russian uses no-break spaces so amounts are never wrapped.
+var locales = map[Lang]locale{
+	RU: {thousandsSep: " ", decimalSep: ",", moneySuffix: " ₽", dateLayout: "02.01.2006", shortDateLayout: "02.01"},
+	EN: {thousandsSep: ",", decimalSep: ".", moneyPrefix: "₽", dateLayout: "Jan 2, 2006", shortDateLayout: "Jan 2"},
+}
+
+var catalogs = map[Lang]map[string]string{
+	RU: catalogRU,
+	EN: catalogEN,
+}
+
+var pluralCatalogs = map[Lang]map[string][]string{
+	RU: pluralsRU,
+	EN: pluralsEN,
+}
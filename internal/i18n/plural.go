@@ -0,0 +1,29 @@
+package i18n
+
+// pluralIndex returns the index of the plural form for n:
+//   - ru: 0 = one (1, 21, 101), 1 = few (2-4, 22-24), 2 = many (0, 5-20, 25-30)
+//   - en: 0 = one (1), 1 = other
+func pluralIndex(lang Lang, n int64) int {
+	if n < 0 {
+		n = -n
+	}
+
+	switch lang {
+	case RU:
+		mod10, mod100 := n%10, n%100
+
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return 0
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
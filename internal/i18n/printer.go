@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lang returns the printer's language.
+func (p *Printer) Lang() Lang {
+	return p.lang
+}
+
+// T returns the message for key, formatted with args (fmt verbs) if any.
+// A key missing in the language falls back to Default, then to the key itself.
+func (p *Printer) T(key string, args ...any) string {
+	msg, ok := p.messages[key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			msg = key
+		}
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, args...)
+}
+
+// N returns the plural form of key that agrees with n; the form gets n as its first
+// argument, followed by args.
+func (p *Printer) N(key string, n int64, args ...any) string {
+	forms, ok := p.plurals[key]
+	lang := p.lang
+	if !ok {
+		forms, lang = pluralCatalogs[Default][key], Default
+	}
+
+	if len(forms) == 0 {
+		return key
+	}
+
+	i := min(pluralIndex(lang, n), len(forms)-1)
+
+	return fmt.Sprintf(forms[i], append([]any{n}, args...)...)
+}
+
+// Money formats an amount in kopecks, e.g. "1 234,56 ₽" (ru) or "₽1,234.56" (en).
+func (p *Printer) Money(amount int64) string {
+	loc := locales[p.lang]
+
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	rub, kop := amount/100, amount%100
+
+	return sign + loc.moneyPrefix + groupThousands(rub, loc.thousandsSep) + loc.decimalSep + fmt.Sprintf("%02d", kop) + loc.moneySuffix
+}
+
+// Date formats the calendar day of t (in UTC).
+func (p *Printer) Date(t time.Time) string {
+	return t.UTC().Format(locales[p.lang].dateLayout)
+}
+
+// ShortDate formats the day and month of t (in UTC), e.g. "06.01" (ru) or "Jan 6" (en).
+func (p *Printer) ShortDate(t time.Time) string {
+	return t.UTC().Format(locales[p.lang].shortDateLayout)
+}
+
+// Number formats an integer with the thousands separator of the language, e.g. "1 234" (ru).
+func (p *Printer) Number(n int64) string {
+	if n < 0 {
+		return "-" + groupThousands(-n, locales[p.lang].thousandsSep)
+	}
+	return groupThousands(n, locales[p.lang].thousandsSep)
+}
+
+func groupThousands(n int64, sep string) string {
+	s := strconv.FormatInt(n, 10)
+	if len(s) <= 3 {
+		return s
+	}
+
+	var b strings.Builder
+
+	head := len(s) % 3
+	if head > 0 {
+		b.WriteString(s[:head])
+	}
+
+	for i := head; i < len(s); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(s[i : i+3])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package i18n
+
+// Lang is a supported interface language (ISO 639-1 code).
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+)
+
+// Default is used when neither the user nor the transport tells the language.
+const Default = RU
+
+// Printer renders catalog messages, money and dates for one language.
+type Printer struct {
+	lang     Lang
+	messages map[string]string
+	plurals  map[string][]string
+}
+
+// locale holds the formatting conventions of a language.
+type locale struct {
+	thousandsSep string
+	decimalSep   string
+	moneyPrefix  string // e.g. "₽" in English
+	moneySuffix  string // e.g. " ₽" in Russian
+	dateLayout   string
+	// shortDateLayout formats a day of the month without the year, e.g. in table rows.
+	shortDateLayout string
+}
@@ -0,0 +1,8 @@
+package metrics
+
+import "errors"
+
+var (
+	ErrNoPollYet = errors.New("no successful getUpdates yet")
+	ErrPollStale = errors.New("last successful getUpdates is too old")
+)
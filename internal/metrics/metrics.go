@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
+)
+
+// DefaultPollStaleAfter is how long after the last successful getUpdates the bot
+// stops being ready: a long poll holds for 30s, so this allows a couple of failed polls.
+const DefaultPollStaleAfter = 90 * time.Second
+
+// New registers the bot metrics in a fresh registry.
+func New() *Metrics {
+	reg := NewRegistry()
+
+	m := &Metrics{
+		reg: reg,
+		now: time.Now,
+		commands: reg.NewCounterVec("ipbot_commands_total",
+			"Bot commands handled, by command and outcome (ok, bad_input, duplicate, error).",
+			"command", "outcome"),
+		commandDuration: reg.NewHistogramVec("ipbot_command_duration_seconds",
+			"Time spent in command handlers.", nil, "command"),
+		tgErrors: reg.NewCounterVec("ipbot_telegram_request_errors_total",
+			"Failed Telegram Bot API requests, by method and error code (HTTP/API code, or network).",
+			"method", "code"),
+		tgDuration: reg.NewHistogramVec("ipbot_telegram_request_duration_seconds",
+			"Telegram Bot API request latency per attempt; getUpdates includes the long-poll hold time.",
+			[]float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}, "method"),
+		pollErrors: reg.NewCounterVec("ipbot_polling_errors_total",
+			"Failed getUpdates polls.", "transport"),
+		pollLag: reg.NewGauge("ipbot_polling_lag_seconds",
+			"Age of the oldest new message in the last getUpdates batch when it was received."),
+		lastPoll: reg.NewGauge("ipbot_polling_last_success_timestamp_seconds",
+			"Unix time of the last successful getUpdates."),
+	}
+
+	return m
+}
+
+// Registry returns the registry for additional metrics.
+func (m *Metrics) Registry() *Registry {
+	return m.reg
+}
+
+// ObserveCommand implements bot.CommandObserver.
+func (m *Metrics) ObserveCommand(command, outcome string, d time.Duration) {
+	m.commands.Inc(command, outcome)
+	m.commandDuration.ObserveDuration(d, command)
+}
+
+// ObserveRequest implements telegram.RequestObserver.
+func (m *Metrics) ObserveRequest(method string, d time.Duration, err error) {
+	m.tgDuration.ObserveDuration(d, method)
+
+	if err != nil {
+		m.tgErrors.Inc(method, errorCode(err))
+	}
+}
+
+// ObservePoll implements telegram_runner.PollObserver: err is the getUpdates error,
+// lag the age of the oldest new message in the batch (0 if there were none).
+func (m *Metrics) ObservePoll(err error, lag time.Duration) {
+	if err != nil {
+		m.pollErrors.Inc("telegram")
+		return
+	}
+
+	now := m.now()
+	m.lastPollOK.Store(now.UnixNano())
+	m.lastPoll.Set(float64(now.UnixNano()) / 1e9)
+
+	if lag > 0 {
+		m.pollLag.Set(lag.Seconds())
+	} else {
+		m.pollLag.Set(0)
+	}
+}
+
+// PollReady returns a readiness check that fails until getUpdates has succeeded
+// and again when the last success is older than staleAfter.
+func (m *Metrics) PollReady(staleAfter time.Duration) func(ctx context.Context) error {
+	return func(context.Context) error {
+		last := m.lastPollOK.Load()
+		if last == 0 {
+			return ErrNoPollYet
+		}
+
+		if age := m.now().Sub(time.Unix(0, last)); age > staleAfter {
+			return ErrPollStale
+		}
+
+		return nil
+	}
+}
+
+// ObservePool exports connection pool statistics of pool, read on every scrape.
+func (m *Metrics) ObservePool(pool *pgxpool.Pool) {
+	stat := func(f func(s *pgxpool.Stat) float64) func() float64 {
+		return func() float64 { return f(pool.Stat()) }
+	}
+
+	m.reg.NewGaugeFunc("ipbot_db_pool_total_conns", "Connections in the DB pool.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }))
+	m.reg.NewGaugeFunc("ipbot_db_pool_acquired_conns", "DB connections currently in use.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }))
+	m.reg.NewGaugeFunc("ipbot_db_pool_idle_conns", "Idle DB connections.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }))
+	m.reg.NewGaugeFunc("ipbot_db_pool_max_conns", "Maximum size of the DB pool.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }))
+	m.reg.NewCounterFunc("ipbot_db_pool_acquires_total", "Successful DB connection acquires.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }))
+	m.reg.NewCounterFunc("ipbot_db_pool_empty_acquires_total", "Acquires that had to wait for a connection.",
+		stat(func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }))
+	m.reg.NewCounterFunc("ipbot_db_pool_acquire_wait_seconds_total", "Total time spent waiting for DB connections.",
+		stat(func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }))
+}
+
+// errorCode labels a failed Bot API request.
+func errorCode(err error) string {
+	var apiErr *telegram.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code != 0:
+			return strconv.Itoa(apiErr.Code)
+		case apiErr.Status != 0:
+			return strconv.Itoa(apiErr.Status)
+		}
+		return "api"
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	return "network"
+}
@@ -0,0 +1,136 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/metrics"
+	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
+)
+
+func render(t *testing.T, reg *metrics.Registry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := reg.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestRegistry_TextFormat(t *testing.T) {
+	t.Parallel()
+
+	reg := metrics.NewRegistry()
+
+	c := reg.NewCounterVec("test_total", "A counter.\nSecond line.", "kind")
+	c.Inc("b")
+	c.Add(2, `a"\`)
+	c.Inc("b")
+
+	h := reg.NewHistogramVec("test_seconds", "A histogram.", []float64{0.1, 1}, "op")
+	h.Observe(0.05, "x")
+	h.Observe(0.1, "x")
+	h.Observe(3, "x")
+
+	reg.NewGaugeFunc("test_gauge", "A gauge.", func() float64 { return 1.5 })
+
+	want := strings.Join([]string{
+		`# HELP test_total A counter.\nSecond line.`,
+		`# TYPE test_total counter`,
+		`test_total{kind="a\"\\"} 2`,
+		`test_total{kind="b"} 2`,
+		`# HELP test_seconds A histogram.`,
+		`# TYPE test_seconds histogram`,
+		`test_seconds_bucket{op="x",le="0.1"} 2`,
+		`test_seconds_bucket{op="x",le="1"} 2`,
+		`test_seconds_bucket{op="x",le="+Inf"} 3`,
+		`test_seconds_sum{op="x"} 3.15`,
+		`test_seconds_count{op="x"} 3`,
+		`# HELP test_gauge A gauge.`,
+		`# TYPE test_gauge gauge`,
+		`test_gauge 1.5`,
+	}, "\n") + "\n"
+
+	if got := render(t, reg); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_DuplicatePanics(t *testing.T) {
+	t.Parallel()
+
+	reg := metrics.NewRegistry()
+	reg.NewGauge("dup", "")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate metric")
+		}
+	}()
+
+	reg.NewGauge("dup", "")
+}
+
+func TestMetrics_ObserveRequestErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+
+	m.ObserveRequest("sendMessage", 10*time.Millisecond, nil)
+	m.ObserveRequest("sendMessage", 10*time.Millisecond, &telegram.APIError{Status: 429, Code: 429})
+	m.ObserveRequest("getUpdates", time.Second, errors.New("connection reset"))
+
+	got := render(t, m.Registry())
+
+	for _, want := range []string{
+		`ipbot_telegram_request_errors_total{method="getUpdates",code="network"} 1`,
+		`ipbot_telegram_request_errors_total{method="sendMessage",code="429"} 1`,
+		`ipbot_telegram_request_duration_seconds_count{method="sendMessage"} 2`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestMetrics_PollReady(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+	ready := m.PollReady(time.Hour)
+	ctx := context.Background()
+
+	if err := ready(ctx); !errors.Is(err, metrics.ErrNoPollYet) {
+		t.Fatalf("before first poll: %v", err)
+	}
+
+	m.ObservePoll(errors.New("boom"), 0)
+	if err := ready(ctx); !errors.Is(err, metrics.ErrNoPollYet) {
+		t.Fatalf("after failed poll: %v", err)
+	}
+
+	m.ObservePoll(nil, 2*time.Second)
+	if err := ready(ctx); err != nil {
+		t.Fatalf("after successful poll: %v", err)
+	}
+
+	if err := m.PollReady(-time.Second)(ctx); !errors.Is(err, metrics.ErrPollStale) {
+		t.Fatalf("stale: %v", err)
+	}
+
+	got := render(t, m.Registry())
+	for _, want := range []string{
+		`ipbot_polling_errors_total{transport="telegram"} 1`,
+		"ipbot_polling_lag_seconds 2\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefBuckets are latency buckets in seconds, as in the Prometheus client libraries.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// contentType is the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// register adds f; a name registered twice is a programming error and panics.
+func (r *Registry) register(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, g := range r.families {
+		if g.name() == f.name() {
+			panic("metrics: duplicate metric " + f.name())
+		}
+	}
+
+	r.families = append(r.families, f)
+}
+
+// NewCounterVec registers a counter. Label values are given to Add in the same order.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{fname: name, help: help, labels: labels, series: make(map[string]*counterSeries)}
+	r.register(c)
+
+	return c
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds (nil means DefBuckets).
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+
+	h := &HistogramVec{fname: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogramSeries)}
+	r.register(h)
+
+	return h
+}
+
+// NewGauge registers a gauge set with Set.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{fname: name, help: help}
+	r.register(g)
+
+	return g
+}
+
+// NewGaugeFunc registers a gauge whose value is read from fn on every scrape.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(&GaugeFunc{fname: name, help: help, kind: "gauge", fn: fn})
+}
+
+// NewCounterFunc registers a counter whose value is read from fn on every scrape;
+// fn must never decrease (e.g. a cumulative count kept elsewhere).
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	r.register(&GaugeFunc{fname: name, help: help, kind: "counter", fn: fn})
+}
+
+// WriteText renders all families in registration order.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]family(nil), r.families...)
+	r.mu.Unlock()
+
+	tw := &textWriter{w: w}
+	for _, f := range families {
+		f.write(tw)
+	}
+
+	return tw.err
+}
+
+// Handler serves the registry for Prometheus scrapes.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_ = r.WriteText(w)
+	})
+}
+
+// ------------------ COUNTER ------------------
+
+// Add increases the series identified by values (one per label) by v (v >= 0).
+func (c *CounterVec) Add(v float64, values ...string) {
+	if v < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := lookup(c.series, values, func() *counterSeries { return &counterSeries{values: clone(values)} })
+	s.value += v
+}
+
+// Inc increases the series identified by values by one.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) name() string { return c.fname }
+
+func (c *CounterVec) write(w *textWriter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w.header(c.fname, c.help, "counter")
+	for _, s := range sorted(c.series) {
+		w.sample(c.fname, c.labels, s.values, "", "", s.value)
+	}
+}
+
+// ------------------ HISTOGRAM ------------------
+
+// Observe records v in the series identified by values.
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := lookup(h.series, values, func() *histogramSeries {
+		return &histogramSeries{values: clone(values), counts: make([]uint64, len(h.buckets)+1)}
+	})
+
+	i := sort.SearchFloat64s(h.buckets, v) // first bound >= v
+	s.counts[i]++
+	s.sum += v
+	s.count++
+}
+
+// ObserveDuration records d in seconds.
+func (h *HistogramVec) ObserveDuration(d time.Duration, values ...string) {
+	h.Observe(d.Seconds(), values...)
+}
+
+func (h *HistogramVec) name() string { return h.fname }
+
+func (h *HistogramVec) write(w *textWriter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w.header(h.fname, h.help, "histogram")
+	for _, s := range sorted(h.series) {
+		var cum uint64
+		for i, n := range s.counts {
+			cum += n
+			le := "+Inf"
+			if i < len(h.buckets) {
+				le = formatFloat(h.buckets[i])
+			}
+			w.sample(h.fname+"_bucket", h.labels, s.values, "le", le, float64(cum))
+		}
+		w.sample(h.fname+"_sum", h.labels, s.values, "", "", s.sum)
+		w.sample(h.fname+"_count", h.labels, s.values, "", "", float64(s.count))
+	}
+}
+
+// ------------------ GAUGE ------------------
+
+func (g *Gauge) Set(v float64) {
+	g.bits.Store(math.Float64bits(v))
+}
+
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
+
+func (g *Gauge) name() string { return g.fname }
+
+func (g *Gauge) write(w *textWriter) {
+	w.header(g.fname, g.help, "gauge")
+	w.sample(g.fname, nil, nil, "", "", g.Value())
+}
+
+func (g *GaugeFunc) name() string { return g.fname }
+
+func (g *GaugeFunc) write(w *textWriter) {
+	w.header(g.fname, g.help, g.kind)
+	w.sample(g.fname, nil, nil, "", "", g.fn())
+}
+
+// ------------------ TEXT FORMAT ------------------
+
+// textWriter writes the Prometheus text format and keeps the first write error.
+type textWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (t *textWriter) writeString(s string) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = io.WriteString(t.w, s)
+}
+
+func (t *textWriter) header(name, help, kind string) {
+	t.writeString("# HELP " + name + " " + escapeHelp(help) + "\n")
+	t.writeString("# TYPE " + name + " " + kind + "\n")
+}
+
+// sample writes one line; extraName/extraValue add a label such as le.
+func (t *textWriter) sample(name string, labels, values []string, extraName, extraValue string, v float64) {
+	var b strings.Builder
+
+	b.WriteString(name)
+
+	if len(labels) > 0 || extraName != "" {
+		b.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			val := ""
+			if i < len(values) {
+				val = values[i]
+			}
+			b.WriteString(l + `="` + escapeLabel(val) + `"`)
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(extraName + `="` + extraValue + `"`)
+		}
+		b.WriteByte('}')
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(v))
+	b.WriteByte('\n')
+
+	t.writeString(b.String())
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// ------------------ SERIES ------------------
+
+// seriesKey joins label values with a byte that cannot appear in valid UTF-8.
+func seriesKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func lookup[S any](m map[string]*S, values []string, create func() *S) *S {
+	key := seriesKey(values)
+
+	s, ok := m[key]
+	if !ok {
+		s = create()
+		m[key] = s
+	}
+
+	return s
+}
+
+func clone(values []string) []string {
+	return append([]string(nil), values...)
+}
+
+// sorted returns the series ordered by label values so that output is stable.
+func sorted[S any](m map[string]*S) []*S {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]*S, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, m[k])
+	}
+
+	return out
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry holds metric families and renders them in the Prometheus text format.
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+// family is one metric name with its HELP/TYPE header and samples.
+type family interface {
+	name() string
+	write(w *textWriter)
+}
+
+// CounterVec is a monotonically increasing counter partitioned by labels.
+type CounterVec struct {
+	fname  string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries // key = joined label values
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// HistogramVec counts observations into cumulative buckets, partitioned by labels.
+type HistogramVec struct {
+	fname   string
+	help    string
+	labels  []string
+	buckets []float64 // upper bounds, ascending; +Inf is implicit
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // per bucket, not cumulative; the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+// GaugeFunc reports the value returned by fn at scrape time.
+type GaugeFunc struct {
+	fname string
+	help  string
+	kind  string // "gauge" or "counter"
+	fn    func() float64
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	fname string
+	help  string
+	bits  atomic.Uint64 // math.Float64bits of the value
+}
+
+// Metrics are the bot's metrics: commands, Telegram API calls, polling and the DB pool.
+// The zero value is not usable; create it with New.
+type Metrics struct {
+	reg *Registry
+
+	commands        *CounterVec
+	commandDuration *HistogramVec
+	tgErrors        *CounterVec
+	tgDuration      *HistogramVec
+	pollErrors      *CounterVec
+	pollLag         *Gauge
+	lastPoll        *Gauge
+
+	// lastPollOK is the Unix nanosecond time of the last successful getUpdates, 0 if none yet.
+	lastPollOK atomic.Int64
+	now        func() time.Time
+}
@@ -0,0 +1,127 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	onesMale   = [...]string{"", "один", "два", "три", "четыре", "пять", "шесть", "семь", "восемь", "девять"}
+	onesFemale = [...]string{"", "одна", "две", "три", "четыре", "пять", "шесть", "семь", "восемь", "девять"}
+	teens      = [...]string{"десять", "одиннадцать", "двенадцать", "тринадцать", "четырнадцать", "пятнадцать",
+		"шестнадцать", "семнадцать", "восемнадцать", "девятнадцать"}
+	tens = [...]string{"", "", "двадцать", "тридцать", "сорок", "пятьдесят", "шестьдесят", "семьдесят",
+		"восемьдесят", "девяносто"}
+	hundreds = [...]string{"", "сто", "двести", "триста", "четыреста", "пятьсот", "шестьсот", "семьсот",
+		"восемьсот", "девятьсот"}
+)
+
+// scales are the groups of three digits from thousands up: the forms for 1, 2-4 and 5+
+// and whether the word is feminine.
+var scales = [...]struct {
+	forms  [3]string
+	female bool
+}{
+	{[3]string{"тысяча", "тысячи", "тысяч"}, true},
+	{[3]string{"миллион", "миллиона", "миллионов"}, false},
+	{[3]string{"миллиард", "миллиарда", "миллиардов"}, false},
+	{[3]string{"триллион", "триллиона", "триллионов"}, false},
+	{[3]string{"квадриллион", "квадриллиона", "квадриллионов"}, false},
+}
+
+// WordsRU spells amount (kopecks) as Russian invoices write the total: the rubles in
+// words, capitalized, and the kopecks in digits, e.g. "Сто двадцать три рубля 45 копеек".
+func WordsRU(amount int64) string {
+	neg := amount < 0
+	rubles, kopecks := amount/100, amount%100
+	if neg {
+		// -(MinInt64/100) still fits, unlike -MinInt64.
+		rubles, kopecks = -rubles, -kopecks
+	}
+
+	var words []string
+
+	if rubles == 0 {
+		words = append(words, "ноль")
+	}
+
+	// Groups of three digits, the lowest first.
+	var groups []int
+	for n := rubles; n > 0; n /= 1000 {
+		groups = append(groups, int(n%1000))
+	}
+
+	for i := len(groups) - 1; i >= 0; i-- {
+		g := groups[i]
+		if g == 0 {
+			continue
+		}
+
+		if i == 0 {
+			words = append(words, triple(g, false)...)
+			continue
+		}
+
+		scale := scales[i-1]
+		words = append(words, triple(g, scale.female)...)
+		words = append(words, scale.forms[plural(g)])
+	}
+
+	words = append(words, [3]string{"рубль", "рубля", "рублей"}[plural(int(rubles%1000))])
+
+	if neg {
+		words = append([]string{"минус"}, words...)
+	}
+
+	s := strings.Join(words, " ")
+	r, size := utf8.DecodeRuneInString(s)
+
+	return fmt.Sprintf("%c%s %02d %s", unicode.ToUpper(r), s[size:], kopecks,
+		[3]string{"копейка", "копейки", "копеек"}[plural(int(kopecks))])
+}
+
+// triple spells 1..999; female picks "одна"/"две" for feminine nouns.
+func triple(n int, female bool) []string {
+	var out []string
+
+	if h := n / 100; h > 0 {
+		out = append(out, hundreds[h])
+	}
+
+	switch t := n % 100; {
+	case t >= 10 && t < 20:
+		out = append(out, teens[t-10])
+	default:
+		if t/10 > 0 {
+			out = append(out, tens[t/10])
+		}
+		if t%10 > 0 {
+			if female {
+				out = append(out, onesFemale[t%10])
+			} else {
+				out = append(out, onesMale[t%10])
+			}
+		}
+	}
+
+	return out
+}
+
+// plural returns the index of the noun form after n: 0 for 1 (21, ...), 1 for 2-4 (22-24, ...), 2 otherwise.
+func plural(n int) int {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return 2
+	}
+
+	switch n % 10 {
+	case 1:
+		return 0
+	case 2, 3, 4:
+		return 1
+	}
+
+	return 2
+}
@@ -0,0 +1,36 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/money"
+)
+
+func TestWordsRU(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		amount int64
+		want   string
+	}{
+		{0, "Ноль рублей 00 копеек"},
+		{1, "Ноль рублей 01 копейка"},
+		{100, "Один рубль 00 копеек"},
+		{222, "Два рубля 22 копейки"},
+		{1_100, "Одиннадцать рублей 00 копеек"},
+		{2_111, "Двадцать один рубль 11 копеек"},
+		{12_345, "Сто двадцать три рубля 45 копеек"},
+		{100_000, "Одна тысяча рублей 00 копеек"},
+		{214_200, "Две тысячи сто сорок два рубля 00 копеек"},
+		{1_200_000_00, "Один миллион двести тысяч рублей 00 копеек"},
+		{2_000_005_00, "Два миллиона пять рублей 00 копеек"},
+		{11_000_000_000_00, "Одиннадцать миллиардов рублей 00 копеек"},
+		{-150, "Минус один рубль 50 копеек"},
+	}
+
+	for _, tc := range cases {
+		if got := money.WordsRU(tc.amount); got != tc.want {
+			t.Errorf("WordsRU(%d) = %q, want %q", tc.amount, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package pdf
+
+import "errors"
+
+var (
+	// ErrBadFont means the font file is not a TrueType font this package can embed.
+	ErrBadFont = errors.New("unsupported or malformed TrueType font")
+	// ErrNoGlyph means a font has no glyph for a character of the text.
+	ErrNoGlyph = errors.New("font has no glyph for the character")
+)
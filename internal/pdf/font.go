@@ -0,0 +1,323 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+// LoadFont reads a TrueType font file, see ParseFont.
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFont(data)
+}
+
+// ParseFont parses a TrueType font (glyf outlines; CFF-based OpenType is not supported).
+// It reads the tables needed for metrics, the Unicode cmap and subsetting.
+func ParseFont(data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, ErrBadFont
+	}
+
+	if v := binary.BigEndian.Uint32(data); v != 0x00010000 && v != 0x74727565 { // 1.0 or 'true'
+		return nil, fmt.Errorf("%w: version %#x", ErrBadFont, v)
+	}
+
+	f := &Font{data: data, tables: make(map[string][]byte)}
+
+	n := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*n {
+		return nil, ErrBadFont
+	}
+
+	for i := range n {
+		rec := data[12+16*i:]
+		tag := string(rec[:4])
+		off, length := binary.BigEndian.Uint32(rec[8:]), binary.BigEndian.Uint32(rec[12:])
+
+		if uint64(off)+uint64(length) > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: table %s out of bounds", ErrBadFont, tag)
+		}
+
+		f.tables[tag] = data[off : off+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "cmap", "loca", "glyf"} {
+		if f.tables[tag] == nil {
+			return nil, fmt.Errorf("%w: no %s table", ErrBadFont, tag)
+		}
+	}
+
+	if err := f.parseMetrics(); err != nil {
+		return nil, err
+	}
+
+	if err := f.parseCmap(); err != nil {
+		return nil, err
+	}
+
+	f.name = f.postScriptName()
+
+	return f, nil
+}
+
+// parseMetrics reads head, hhea, maxp and hmtx.
+func (f *Font) parseMetrics() error {
+	head, hhea, maxp, hmtx := f.tables["head"], f.tables["hhea"], f.tables["maxp"], f.tables["hmtx"]
+
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return fmt.Errorf("%w: short head, hhea or maxp", ErrBadFont)
+	}
+
+	f.unitsPerEm = float64(binary.BigEndian.Uint16(head[18:]))
+	if f.unitsPerEm == 0 {
+		return fmt.Errorf("%w: unitsPerEm is 0", ErrBadFont)
+	}
+
+	for i := range f.bbox {
+		f.bbox[i] = int16(binary.BigEndian.Uint16(head[36+2*i:]))
+	}
+
+	f.longLoca = binary.BigEndian.Uint16(head[50:]) == 1
+	f.ascent = int16(binary.BigEndian.Uint16(hhea[4:]))
+	f.descent = int16(binary.BigEndian.Uint16(hhea[6:]))
+	f.numGlyphs = int(binary.BigEndian.Uint16(maxp[4:]))
+
+	metrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if metrics == 0 || metrics > f.numGlyphs || len(hmtx) < 4*metrics {
+		return fmt.Errorf("%w: bad hmtx", ErrBadFont)
+	}
+
+	// Glyphs past numberOfHMetrics share the last advance.
+	f.advances = make([]uint16, f.numGlyphs)
+	for g := range f.advances {
+		f.advances[g] = binary.BigEndian.Uint16(hmtx[4*min(g, metrics-1):])
+	}
+
+	return nil
+}
+
+// parseCmap reads the Unicode mapping: a full-repertoire format 12 subtable if there is
+// one, else a BMP format 4 one.
+func (f *Font) parseCmap() error {
+	cmap := f.tables["cmap"]
+	if len(cmap) < 4 {
+		return fmt.Errorf("%w: short cmap", ErrBadFont)
+	}
+
+	var bmp, full []byte
+
+	n := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := range n {
+		rec := cmap[4+8*i:]
+		if len(rec) < 8 {
+			return fmt.Errorf("%w: short cmap", ErrBadFont)
+		}
+
+		platform, encoding := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[2:])
+		off := binary.BigEndian.Uint32(rec[4:])
+		if int(off)+4 > len(cmap) {
+			return fmt.Errorf("%w: cmap subtable out of bounds", ErrBadFont)
+		}
+
+		sub := cmap[off:]
+		format := binary.BigEndian.Uint16(sub)
+
+		switch {
+		case format == 12 && (platform == 3 && encoding == 10 || platform == 0):
+			full = sub
+		case format == 4 && (platform == 3 && encoding == 1 || platform == 0):
+			bmp = sub
+		}
+	}
+
+	f.cmap = make(map[rune]uint16)
+
+	switch {
+	case full != nil:
+		return f.parseCmap12(full)
+	case bmp != nil:
+		return f.parseCmap4(bmp)
+	}
+
+	return fmt.Errorf("%w: no Unicode cmap", ErrBadFont)
+}
+
+func (f *Font) parseCmap4(sub []byte) error {
+	if len(sub) < 14 {
+		return fmt.Errorf("%w: short cmap format 4", ErrBadFont)
+	}
+
+	segs := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	if len(sub) < 16+8*segs {
+		return fmt.Errorf("%w: short cmap format 4", ErrBadFont)
+	}
+
+	ends := sub[14:]
+	starts := sub[16+2*segs:]
+	deltas := sub[16+4*segs:]
+	offsets := sub[16+6*segs:]
+
+	for i := range segs {
+		end := binary.BigEndian.Uint16(ends[2*i:])
+		start := binary.BigEndian.Uint16(starts[2*i:])
+		delta := binary.BigEndian.Uint16(deltas[2*i:])
+		rangeOff := int(binary.BigEndian.Uint16(offsets[2*i:]))
+
+		for c := int(start); c <= int(end) && c != 0xFFFF; c++ {
+			var g uint16
+
+			if rangeOff == 0 {
+				g = uint16(c) + delta
+			} else {
+				// idRangeOffset is relative to its own slot in the offsets array.
+				at := 16 + 6*segs + 2*i + rangeOff + 2*(c-int(start))
+				if at+2 > len(sub) {
+					return fmt.Errorf("%w: cmap format 4 glyph out of bounds", ErrBadFont)
+				}
+				if g = binary.BigEndian.Uint16(sub[at:]); g != 0 {
+					g += delta
+				}
+			}
+
+			if g != 0 && int(g) < f.numGlyphs {
+				f.cmap[rune(c)] = g
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *Font) parseCmap12(sub []byte) error {
+	if len(sub) < 16 {
+		return fmt.Errorf("%w: short cmap format 12", ErrBadFont)
+	}
+
+	groups := int(binary.BigEndian.Uint32(sub[12:]))
+	if len(sub) < 16+12*groups {
+		return fmt.Errorf("%w: short cmap format 12", ErrBadFont)
+	}
+
+	for i := range groups {
+		rec := sub[16+12*i:]
+		start, end, g := binary.BigEndian.Uint32(rec), binary.BigEndian.Uint32(rec[4:]), binary.BigEndian.Uint32(rec[8:])
+
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			if gid := g + (c - start); gid != 0 && int(gid) < f.numGlyphs {
+				f.cmap[rune(c)] = uint16(gid)
+			}
+		}
+	}
+
+	return nil
+}
+
+// postScriptName returns name ID 6 of the name table, or "Font".
+func (f *Font) postScriptName() string {
+	name := f.tables["name"]
+	if len(name) < 6 {
+		return "Font"
+	}
+
+	count := int(binary.BigEndian.Uint16(name[2:]))
+	strs := int(binary.BigEndian.Uint16(name[4:]))
+
+	for i := range count {
+		rec := name[6+12*i:]
+		if len(rec) < 12 {
+			break
+		}
+
+		platform, id := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[6:])
+		length, off := int(binary.BigEndian.Uint16(rec[8:])), int(binary.BigEndian.Uint16(rec[10:]))
+
+		if id != 6 || strs+off+length > len(name) {
+			continue
+		}
+
+		raw := name[strs+off : strs+off+length]
+
+		var s string
+		switch platform {
+		case 1: // Macintosh, Roman: PostScript names are ASCII
+			s = string(raw)
+		case 0, 3: // Unicode, Windows: UTF-16BE
+			u := make([]uint16, len(raw)/2)
+			for j := range u {
+				u[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			s = string(utf16.Decode(u))
+		default:
+			continue
+		}
+
+		// PostScript names are printable ASCII without delimiters.
+		s = strings.Map(func(r rune) rune {
+			if r <= ' ' || r > '~' || strings.ContainsRune("[](){}<>/%", r) {
+				return -1
+			}
+			return r
+		}, s)
+
+		if s != "" {
+			return s
+		}
+	}
+
+	return "Font"
+}
+
+// Name returns the PostScript name of the font.
+func (f *Font) Name() string {
+	return f.name
+}
+
+// Has reports whether the font has a glyph for r.
+func (f *Font) Has(r rune) bool {
+	_, ok := f.cmap[r]
+	return ok
+}
+
+// Width returns the width of s set in the font at size, in points. Characters without
+// a glyph count as the .notdef glyph.
+func (f *Font) Width(s string, size float64) float64 {
+	var units float64
+	for _, r := range s {
+		units += float64(f.advances[f.cmap[r]])
+	}
+
+	return units * size / f.unitsPerEm
+}
+
+// Wrap breaks s into lines no wider than width at size, at spaces; a word wider than
+// width gets a line of its own. Newlines in s always break.
+func (f *Font) Wrap(s string, size, width float64) []string {
+	var lines []string
+
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = word
+			case f.Width(line+" "+word, size) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
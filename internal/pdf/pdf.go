@@ -0,0 +1,302 @@
+// Package pdf writes simple PDF documents: A4 pages with text, lines and rectangles.
+// Text is set in TrueType fonts embedded as subsets, so any script the font covers
+// (Cyrillic included) prints and can be copied back as text.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// MM is one millimetre in points.
+const MM = 72 / 25.4
+
+func New() *Document {
+	return &Document{}
+}
+
+// SetTitle sets the title shown by PDF viewers.
+func (d *Document) SetTitle(title string) *Document {
+	d.title = title
+	return d
+}
+
+// AddPage appends an empty page and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// use returns the resource index of f in the document, registering it on first use.
+func (d *Document) use(f *Font) (int, *fontUse) {
+	for i, u := range d.fonts {
+		if u.font == f {
+			return i, u
+		}
+	}
+
+	u := &fontUse{font: f, glyphs: make(map[uint16]rune)}
+	d.fonts = append(d.fonts, u)
+
+	return len(d.fonts) - 1, u
+}
+
+// Text draws s with its baseline starting at (x, y). It fails with ErrNoGlyph if the
+// font cannot show a character of s.
+func (p *Page) Text(f *Font, size, x, y float64, s string) error {
+	idx, u := p.doc.use(f)
+
+	var hex strings.Builder
+
+	for _, r := range s {
+		g, ok := f.cmap[r]
+		if !ok {
+			return fmt.Errorf("%w: %q in %s", ErrNoGlyph, r, f.name)
+		}
+
+		if _, seen := u.glyphs[g]; !seen {
+			u.glyphs[g] = r
+		}
+
+		fmt.Fprintf(&hex, "%04X", g)
+	}
+
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td <%s> Tj ET\n", idx+1, num(size), num(x), num(PageHeight-y), hex.String())
+
+	return nil
+}
+
+// TextRight draws s so that it ends at x.
+func (p *Page) TextRight(f *Font, size, x, y float64, s string) error {
+	return p.Text(f, size, x-f.Width(s, size), y, s)
+}
+
+// TextCenter draws s centred on x.
+func (p *Page) TextCenter(f *Font, size, x, y float64, s string) error {
+	return p.Text(f, size, x-f.Width(s, size)/2, y, s)
+}
+
+// Line draws a line of width from (x1, y1) to (x2, y2).
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Rect draws the outline of a rectangle with its top-left corner at (x, y).
+func (p *Page) Rect(x, y, w, h, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n", num(width), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Bytes serializes the document.
+func (d *Document) Bytes() ([]byte, error) {
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+
+	// Objects 1 and 2 are the catalog and the page tree; the rest follow in order.
+	catalog, pages := w.reserve(), w.reserve()
+
+	fontRefs := make([]int, len(d.fonts))
+	for i, u := range d.fonts {
+		ref, err := w.font(u)
+		if err != nil {
+			return nil, err
+		}
+		fontRefs[i] = ref
+	}
+
+	var fonts strings.Builder
+	for i, ref := range fontRefs {
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, ref)
+	}
+
+	kids := make([]string, len(d.pages))
+	for i, p := range d.pages {
+		content := w.stream("", p.content.Bytes())
+		page := w.object(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			pages, num(PageWidth), num(PageHeight), fonts.String(), content))
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+
+	w.define(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	w.define(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+
+	info := 0
+	if d.title != "" {
+		info = w.object(fmt.Sprintf("<< /Title %s /Producer (ip_accounting_bot) >>", textString(d.title)))
+	}
+
+	return w.finish(catalog, info), nil
+}
+
+// font writes the objects of an embedded font and returns its Type0 font object.
+func (w *writer) font(u *fontUse) (int, error) {
+	f := u.font
+
+	data, err := f.subset(u.glyphs)
+	if err != nil {
+		return 0, err
+	}
+
+	glyphs := make([]uint16, 0, len(u.glyphs))
+	for g := range u.glyphs {
+		glyphs = append(glyphs, g)
+	}
+	slices.Sort(glyphs)
+
+	// A subset is named with a tag of six capitals derived from its glyphs.
+	sum := sha256.Sum256(fmt.Append(nil, glyphs))
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + sum[i]%26
+	}
+	name := string(tag) + "+" + f.name
+
+	scale := func(v int16) string { return num(float64(v) * 1000 / f.unitsPerEm) }
+
+	file := w.stream(fmt.Sprintf("/Length1 %d", len(data)), data)
+
+	descriptor := w.object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s %s %s %s] "+
+		"/ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>",
+		name, scale(f.bbox[0]), scale(f.bbox[1]), scale(f.bbox[2]), scale(f.bbox[3]),
+		scale(f.ascent), scale(f.descent), scale(f.ascent), file))
+
+	var widths strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(&widths, "%d [%s] ", g, num(float64(f.advances[g])*1000/f.unitsPerEm))
+	}
+
+	cid := w.object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>", name, descriptor, widths.String()))
+
+	toUnicode := w.stream("", toUnicodeCMap(glyphs, u.glyphs))
+
+	return w.object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", name, cid, toUnicode)), nil
+}
+
+// toUnicodeCMap maps the glyphs back to characters, so that text can be copied and searched.
+func toUnicodeCMap(glyphs []uint16, chars map[uint16]rune) []byte {
+	var b bytes.Buffer
+
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// At most 100 entries per block.
+	for len(glyphs) > 0 {
+		n := min(len(glyphs), 100)
+
+		fmt.Fprintf(&b, "%d beginbfchar\n", n)
+		for _, g := range glyphs[:n] {
+			fmt.Fprintf(&b, "<%04X> <", g)
+			for _, u := range utf16Units(chars[g]) {
+				fmt.Fprintf(&b, "%04X", u)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+
+		glyphs = glyphs[n:]
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	return b.Bytes()
+}
+
+func utf16Units(r rune) []uint16 {
+	if r < 0x10000 {
+		return []uint16{uint16(r)}
+	}
+
+	r -= 0x10000
+	return []uint16{0xD800 + uint16(r>>10), 0xDC00 + uint16(r&0x3FF)}
+}
+
+// textString encodes s as a PDF text string: UTF-16BE with a byte order mark.
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, r := range s {
+		for _, u := range utf16Units(r) {
+			fmt.Fprintf(&b, "%04X", u)
+		}
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// num formats a coordinate with at most two decimals.
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// reserve allocates an object number to be written later with define.
+func (w *writer) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+func (w *writer) define(n int, body string) {
+	w.offsets[n-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+func (w *writer) object(body string) int {
+	n := w.reserve()
+	w.define(n, body)
+	return n
+}
+
+// stream writes data compressed with extra entries in its dictionary.
+func (w *writer) stream(extra string, data []byte) int {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	_, _ = zw.Write(data) // writes to a bytes.Buffer do not fail
+	_ = zw.Close()
+
+	n := w.reserve()
+	w.offsets[n-1] = w.buf.Len()
+
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode %s>>\nstream\n", n, z.Len(), extra+" ")
+	w.buf.Write(z.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+
+	return n
+}
+
+// finish writes the xref table and the trailer; info 0 means no document information.
+func (w *writer) finish(root, info int) []byte {
+	xref := w.buf.Len()
+
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, off := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", off)
+	}
+
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R ", len(w.offsets)+1, root)
+	if info != 0 {
+		fmt.Fprintf(&w.buf, "/Info %d 0 R ", info)
+	}
+	fmt.Fprintf(&w.buf, ">>\nstartxref\n%d\n%%%%EOF\n", xref)
+
+	return w.buf.Bytes()
+}
@@ -0,0 +1,142 @@
+package pdf_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/pdf"
+)
+
+const fontPath = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+
+func loadFont(t *testing.T) *pdf.Font {
+	t.Helper()
+
+	if _, err := os.Stat(fontPath); err != nil {
+		t.Skipf("font %s not available", fontPath)
+	}
+
+	f, err := pdf.LoadFont(fontPath)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+
+	return f
+}
+
+func TestDocument_Bytes(t *testing.T) {
+	f := loadFont(t)
+
+	doc := pdf.New().SetTitle("Счёт")
+	page := doc.AddPage()
+	if err := page.Text(f, 12, 20*pdf.MM, 20*pdf.MM, "Счёт на оплату № 1"); err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	page.Line(10, 30, 100, 30, 0.5)
+	page.Rect(10, 40, 100, 20, 1)
+	doc.AddPage()
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.7\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF file: %q...", out[:20])
+	}
+
+	if got := bytes.Count(out, []byte("/Type /Page ")); got != 2 {
+		t.Errorf("pages = %d, want 2", got)
+	}
+
+	// Every xref entry must point at its object.
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, out[off:off+10], want)
+		}
+	}
+}
+
+func TestDocument_FontSubset(t *testing.T) {
+	f := loadFont(t)
+
+	doc := pdf.New()
+	if err := doc.AddPage().Text(f, 10, 0, 10, "Итого: 1 000,00"); err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	// The embedded font is the stream with /Length1; it must parse and keep the drawn glyphs.
+	m := regexp.MustCompile(`(?s)<< /Length (\d+) /Filter /FlateDecode /Length1 (\d+) >>\nstream\n`).FindSubmatchIndex(out)
+	if m == nil {
+		t.Fatal("no embedded font file")
+	}
+	n, _ := strconv.Atoi(string(out[m[2]:m[3]]))
+
+	zr, err := zlib.NewReader(bytes.NewReader(out[m[1] : m[1]+n]))
+	if err != nil {
+		t.Fatalf("zlib: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("zlib: %v", err)
+	}
+
+	// A subset keeps the outline tables and drops cmap: text is drawn by glyph index.
+	if len(data) < 12 || binary.BigEndian.Uint32(data) != 0x00010000 {
+		t.Fatalf("subset is not a TrueType font")
+	}
+	tables := map[string]bool{}
+	for i := range int(binary.BigEndian.Uint16(data[4:])) {
+		tables[string(data[12+16*i:][:4])] = true
+	}
+	for _, tag := range []string{"glyf", "loca", "head", "hhea", "hmtx", "maxp"} {
+		if !tables[tag] {
+			t.Errorf("subset has no %s table", tag)
+		}
+	}
+	if tables["cmap"] {
+		t.Error("subset keeps the cmap table")
+	}
+
+	if len(data) >= 100_000 {
+		t.Errorf("subset is %d bytes, expected it much smaller than the font", len(data))
+	}
+
+	if !bytes.Contains(out, []byte("/ToUnicode")) {
+		t.Error("no ToUnicode map")
+	}
+}
+
+func TestPage_TextNoGlyph(t *testing.T) {
+	f := loadFont(t)
+
+	err := pdf.New().AddPage().Text(f, 10, 0, 0, "\U0001F600\U000F0000")
+	if !errors.Is(err, pdf.ErrNoGlyph) {
+		t.Fatalf("err = %v, want ErrNoGlyph", err)
+	}
+}
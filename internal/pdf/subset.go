@@ -0,0 +1,226 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// subsetTables are copied into an embedded font; a PDF reader needs no cmap, name or
+// layout tables, as text is drawn by glyph index.
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// subset returns the font with the outlines of glyphs (and the components of composite
+// ones) kept and all the other glyphs emptied. Glyph indexes do not change, so the
+// metrics tables are kept whole; loca is rewritten in the long format.
+func (f *Font) subset(glyphs map[uint16]rune) ([]byte, error) {
+	keep := make(map[uint16]bool, len(glyphs)+1)
+	queue := []uint16{0} // .notdef
+	for g := range glyphs {
+		queue = append(queue, g)
+	}
+
+	for len(queue) > 0 {
+		g := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		if keep[g] {
+			continue
+		}
+		keep[g] = true
+
+		outline, err := f.outline(g)
+		if err != nil {
+			return nil, err
+		}
+
+		components, err := components(outline)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range components {
+			if !keep[c] {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	glyf := make([]byte, 0, 64*len(keep))
+	loca := make([]byte, 4*(f.numGlyphs+1))
+
+	for g := range f.numGlyphs {
+		if keep[uint16(g)] {
+			outline, err := f.outline(uint16(g))
+			if err != nil {
+				return nil, err
+			}
+
+			glyf = append(glyf, outline...)
+			for len(glyf)%4 != 0 {
+				glyf = append(glyf, 0)
+			}
+		}
+
+		binary.BigEndian.PutUint32(loca[4*(g+1):], uint32(len(glyf)))
+	}
+
+	head := slices.Clone(f.tables["head"])
+	binary.BigEndian.PutUint32(head[8:], 0)  // checksumAdjustment, set below
+	binary.BigEndian.PutUint16(head[50:], 1) // indexToLocFormat: long
+
+	tables := map[string][]byte{"glyf": glyf, "loca": loca, "head": head}
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok && f.tables[tag] != nil {
+			tables[tag] = f.tables[tag]
+		}
+	}
+
+	out := assemble(tables)
+
+	// The whole font sums to 0xB1B0AFBA.
+	binary.BigEndian.PutUint32(out[tableOffset(out, "head")+8:], 0xB1B0AFBA-checksum(out))
+
+	return out, nil
+}
+
+// outline returns the glyf data of glyph g; empty for glyphs without contours.
+func (f *Font) outline(g uint16) ([]byte, error) {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+
+	var start, end uint32
+	if f.longLoca {
+		if 4*int(g)+8 > len(loca) {
+			return nil, fmt.Errorf("%w: glyph %d not in loca", ErrBadFont, g)
+		}
+		start, end = binary.BigEndian.Uint32(loca[4*int(g):]), binary.BigEndian.Uint32(loca[4*int(g)+4:])
+	} else {
+		if 2*int(g)+4 > len(loca) {
+			return nil, fmt.Errorf("%w: glyph %d not in loca", ErrBadFont, g)
+		}
+		start, end = 2*uint32(binary.BigEndian.Uint16(loca[2*int(g):])), 2*uint32(binary.BigEndian.Uint16(loca[2*int(g)+2:]))
+	}
+
+	if start > end || int(end) > len(glyf) {
+		return nil, fmt.Errorf("%w: glyph %d out of bounds", ErrBadFont, g)
+	}
+
+	return glyf[start:end], nil
+}
+
+// Flags of the components of a composite glyph.
+const (
+	argsAreWords    = 0x0001
+	haveScale       = 0x0008
+	moreComponents  = 0x0020
+	haveXYScale     = 0x0040
+	haveTwoByTwo    = 0x0080
+	compositeHeader = 10
+)
+
+// components returns the glyphs a composite outline is made of; none for a simple one.
+func components(outline []byte) ([]uint16, error) {
+	if len(outline) < compositeHeader || int16(binary.BigEndian.Uint16(outline)) >= 0 {
+		return nil, nil
+	}
+
+	var out []uint16
+
+	for p := compositeHeader; ; {
+		if p+4 > len(outline) {
+			return nil, fmt.Errorf("%w: truncated composite glyph", ErrBadFont)
+		}
+
+		flags := binary.BigEndian.Uint16(outline[p:])
+		out = append(out, binary.BigEndian.Uint16(outline[p+2:]))
+		p += 4
+
+		if flags&argsAreWords != 0 {
+			p += 4
+		} else {
+			p += 2
+		}
+
+		switch {
+		case flags&haveScale != 0:
+			p += 2
+		case flags&haveXYScale != 0:
+			p += 4
+		case flags&haveTwoByTwo != 0:
+			p += 8
+		}
+
+		if flags&moreComponents == 0 {
+			return out, nil
+		}
+	}
+}
+
+// assemble writes tables as a TrueType file, sorted by tag and aligned to 4 bytes.
+func assemble(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	n := len(tags)
+
+	// searchRange and friends let readers binary-search the directory.
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= n {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+
+	out := make([]byte, 12+16*n)
+	binary.BigEndian.PutUint32(out, 0x00010000)
+	binary.BigEndian.PutUint16(out[4:], uint16(n))
+	binary.BigEndian.PutUint16(out[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(out[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(out[10:], uint16(16*n-searchRange))
+
+	for i, tag := range tags {
+		data := tables[tag]
+		rec := out[12+16*i:]
+
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[4:], checksum(data))
+		binary.BigEndian.PutUint32(rec[8:], uint32(len(out)))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(data)))
+
+		out = append(out, data...)
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+
+	return out
+}
+
+// tableOffset returns where table tag starts in an assembled font.
+func tableOffset(font []byte, tag string) int {
+	n := int(binary.BigEndian.Uint16(font[4:]))
+	for i := range n {
+		rec := font[12+16*i:]
+		if string(rec[:4]) == tag {
+			return int(binary.BigEndian.Uint32(rec[8:]))
+		}
+	}
+
+	return -1
+}
+
+// checksum sums b as big-endian uint32 words, the last one padded with zeros.
+func checksum(b []byte) uint32 {
+	var sum uint32
+
+	for i := 0; i < len(b); i += 4 {
+		var word [4]byte
+		copy(word[:], b[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+
+	return sum
+}
@@ -0,0 +1,43 @@
+package pdf
+
+import "bytes"
+
+// Font is a TrueType font to draw text with. Only the glyphs a document uses are embedded.
+type Font struct {
+	name       string // PostScript name
+	data       []byte
+	tables     map[string][]byte
+	cmap       map[rune]uint16
+	advances   []uint16 // per glyph, font units
+	unitsPerEm float64
+	ascent     int16
+	descent    int16
+	bbox       [4]int16
+	longLoca   bool
+	numGlyphs  int
+}
+
+// Document is a PDF being built page by page; Bytes serializes it.
+type Document struct {
+	pages []*Page
+	fonts []*fontUse
+	title string
+}
+
+// Page is an A4 page. Coordinates are points from the top-left corner, y growing down.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// fontUse is a font of a document with the glyphs drawn with it.
+type fontUse struct {
+	font   *Font
+	glyphs map[uint16]rune // glyph -> the character it was drawn for, for ToUnicode
+}
+
+// writer numbers the objects of a PDF file and records their offsets for the xref table.
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int // by object number - 1; 0 until written
+}
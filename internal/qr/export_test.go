@@ -0,0 +1,23 @@
+package qr
+
+var (
+	RSDivisor          = rsDivisor
+	RSRemainder        = rsRemainder
+	FormatBits         = formatBits
+	VersionBits        = versionBits
+	AlignmentPositions = alignmentPositions
+	ByteCapacity       = byteCapacity
+)
+
+// FunctionModules returns which modules of version are not data modules.
+func FunctionModules(version int) []bool {
+	m := newMatrix(version)
+	m.drawFunctionPatterns()
+	return m.function
+}
+
+// BlockLayout returns the number of error correction blocks of version and level, the
+// error correction codewords of each and the number of codewords of the code.
+func BlockLayout(version int, level Level) (blocks, eccLen, codewords int) {
+	return eccBlocks[level][version], eccPerBlock[level][version], rawModules(version) / 8
+}
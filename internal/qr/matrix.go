@@ -0,0 +1,320 @@
+package qr
+
+// matrix is a code being drawn. Coordinates are a column x and a row y from the top
+// left corner.
+type matrix struct {
+	version  int
+	size     int
+	dark     []bool
+	function []bool // finder, timing, alignment, format and version modules
+}
+
+func newMatrix(version int) *matrix {
+	size := version*4 + 17
+
+	return &matrix{
+		version:  version,
+		size:     size,
+		dark:     make([]bool, size*size),
+		function: make([]bool, size*size),
+	}
+}
+
+func (m *matrix) get(x, y int) bool {
+	return m.dark[y*m.size+x]
+}
+
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.dark[y*m.size+x] = dark
+	m.function[y*m.size+x] = true
+}
+
+// drawFunctionPatterns draws everything but the data: the finders with their
+// separators, the timing and alignment patterns and the version information, and
+// reserves the format information.
+func (m *matrix) drawFunctionPatterns() {
+	for i := range m.size {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+
+	pos := alignmentPositions(m.version)
+	last := len(pos) - 1
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the corners taken by the finders.
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			m.drawAlignment(x, y)
+		}
+	}
+
+	m.drawFormat(LevelL, 0) // reserved, overwritten once the mask is chosen
+	m.drawVersion()
+}
+
+// drawFinder draws a finder pattern centered at x, y with its light separator.
+func (m *matrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= m.size || yy < 0 || yy >= m.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			m.setFunction(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern centered at x, y.
+func (m *matrix) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPositions returns the rows (and columns) of the alignment patterns of version.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+
+	out := make([]int, n)
+	out[0] = 6
+	for i, pos := n-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		out[i] = pos
+	}
+
+	return out
+}
+
+// formatBits returns the 15 bits of format information of level and mask: 5 data bits,
+// a BCH(15,5) remainder and the fixed mask of the standard.
+func formatBits(level Level, mask int) int {
+	// Level indicators are L=01, M=00, Q=11, H=10.
+	data := [4]int{LevelL: 1, LevelM: 0, LevelQ: 3, LevelH: 2}[level]<<3 | mask
+
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormat draws both copies of the format information and the dark module.
+func (m *matrix) drawFormat(level Level, mask int) {
+	bits := formatBits(level, mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	// Around the top left finder.
+	for i := range 6 {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+
+	// Under the top right finder and beside the bottom left one.
+	for i := range 8 {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true)
+}
+
+// versionBits returns the 18 bits of version information: 6 data bits and a
+// BCH(18,6) remainder.
+func versionBits(version int) int {
+	rem := version
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// drawVersion draws both copies of the version information of versions 7 and up.
+func (m *matrix) drawVersion() {
+	if m.version < 7 {
+		return
+	}
+
+	bits := versionBits(m.version)
+
+	for i := range 18 {
+		dark := bits>>i&1 != 0
+		a, b := m.size-11+i%3, i/3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places data in the zigzag order of the standard: two columns at a
+// time from the right, alternately upwards and downwards, skipping function modules
+// and the vertical timing pattern. Remainder modules stay light.
+func (m *matrix) drawCodewords(data []byte) {
+	i := 0
+
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+
+		for vert := range m.size {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := range 2 {
+				x := right - j
+				if m.function[y*m.size+x] || i >= len(data)*8 {
+					continue
+				}
+				m.dark[y*m.size+x] = data[i/8]>>(7-i%8)&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask; applying it again undoes it.
+func (m *matrix) applyMask(mask int) {
+	for y := range m.size {
+		for x := range m.size {
+			var invert bool
+
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+
+			if invert && !m.function[y*m.size+x] {
+				m.dark[y*m.size+x] = !m.dark[y*m.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard m is to read, by the rules of the standard: long runs of one
+// color, 2×2 blocks, patterns that look like finders and the balance of dark modules.
+// The mask with the lowest score is used.
+func (m *matrix) penalty() int {
+	score := 0
+
+	line := make([]bool, m.size)
+	for _, vertical := range []bool{false, true} {
+		for i := range m.size {
+			for j := range m.size {
+				if vertical {
+					line[j] = m.get(i, j)
+				} else {
+					line[j] = m.get(j, i)
+				}
+			}
+			score += linePenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := range m.size {
+		for x := range m.size {
+			if m.get(x, y) {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				c := m.get(x, y)
+				if c == m.get(x-1, y) && c == m.get(x, y-1) && c == m.get(x-1, y-1) {
+					score += 3
+				}
+			}
+		}
+	}
+
+	total := m.size * m.size
+	score += (abs(dark*20-total*10)+total-1)/total*10 - 10
+
+	return score
+}
+
+// finderLike is a 1:1:3:1:1 run of dark and light modules, as in a finder pattern.
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// linePenalty scores the runs and finder-like patterns of a row or a column.
+func linePenalty(line []bool) int {
+	score := 0
+
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += run - 2
+		}
+		run = 1
+	}
+
+	// Modules beyond the edge are light, as the quiet zone is.
+	light := func(from, to int) bool {
+		for i := from; i < to; i++ {
+			if i >= 0 && i < len(line) && line[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		match := true
+		for j, d := range finderLike {
+			if line[i+j] != d {
+				match = false
+				break
+			}
+		}
+		if match && (light(i-4, i) || light(i+7, i+11)) {
+			score += 40
+		}
+	}
+
+	return score
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
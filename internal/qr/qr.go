@@ -0,0 +1,173 @@
+// Package qr encodes QR codes (ISO/IEC 18004) with the standard library only, so that
+// the bot can send payment codes as PNG photos without native dependencies. Only what
+// payment codes need is supported: byte mode, all versions and error correction levels.
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// Level is the error correction level of a code.
+type Level int
+
+const (
+	LevelL Level = iota // ~7% of the codewords can be restored
+	LevelM              // ~15%
+	LevelQ              // ~25%
+	LevelH              // ~30%
+)
+
+// QuietZone is the light border around a code, in modules, that readers need.
+const QuietZone = 4
+
+// ErrTooLong is returned when the data does not fit into a version 40 code.
+var ErrTooLong = errors.New("qr: data too long")
+
+// Code is an encoded QR code: a square of size×size dark and light modules.
+type Code struct {
+	size    int
+	modules []bool // dark modules, row by row
+}
+
+// Encode encodes data in byte mode at level into the smallest version it fits.
+func Encode(data []byte, level Level) (*Code, error) {
+	const op = "qr.Encode"
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if len(data) <= byteCapacity(v, level) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, validate.Wrap(op, ErrTooLong)
+	}
+
+	codewords := addECC(dataCodewords(data, version, level), version, level)
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns()
+	m.drawCodewords(codewords)
+
+	best, bestPenalty := 0, -1
+	for mask := range 8 {
+		m.applyMask(mask)
+		m.drawFormat(level, mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask) // masks are their own inverse
+	}
+
+	m.applyMask(best)
+	m.drawFormat(level, best)
+
+	return &Code{size: m.size, modules: m.dark}, nil
+}
+
+// Size returns the number of modules on a side of c, without the quiet zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark reports whether the module in column x and row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y*c.size+x]
+}
+
+// PNG draws c in black on white with scale pixels per module, in a quiet zone.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	const op = "qr.PNG"
+
+	if scale < 1 {
+		scale = 1
+	}
+
+	side := (c.size + 2*QuietZone) * scale
+
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+
+	for y := range c.size {
+		for x := range c.size {
+			if !c.Dark(x, y) {
+				continue
+			}
+			for dy := range scale {
+				row := img.Pix[((y+QuietZone)*scale+dy)*img.Stride:]
+				for dx := range scale {
+					row[(x+QuietZone)*scale+dx] = 1
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// byteCapacity returns how many bytes a code of version and level holds in byte mode.
+func byteCapacity(version int, level Level) int {
+	bits := dataCapacity(version, level)*8 - 4 - countBits(version)
+	return bits / 8
+}
+
+// countBits returns the length of the character count of byte mode in version.
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// dataCodewords encodes data as the mode indicator, the count and the bytes, then pads
+// it up to the data capacity of version and level.
+func dataCodewords(data []byte, version int, level Level) []byte {
+	var w bitWriter
+
+	w.write(0b0100, 4) // byte mode
+	w.write(len(data), countBits(version))
+	for _, b := range data {
+		w.write(int(b), 8)
+	}
+
+	capacity := dataCapacity(version, level) * 8
+
+	w.write(0, min(4, capacity-w.n)) // terminator
+	w.write(0, (8-w.n%8)%8)
+
+	for pad := 0xEC; w.n < capacity; pad ^= 0xEC ^ 0x11 {
+		w.write(pad, 8)
+	}
+
+	return w.bytes
+}
+
+// bitWriter appends bits most significant first.
+type bitWriter struct {
+	bytes []byte
+	n     int // bits written
+}
+
+func (w *bitWriter) write(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.bytes = append(w.bytes, 0)
+		}
+		if v>>i&1 != 0 {
+			w.bytes[w.n/8] |= 0x80 >> (w.n % 8)
+		}
+		w.n++
+	}
+}
@@ -0,0 +1,290 @@
+package qr_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/qr"
+)
+
+func TestRSRemainder_HelloWorld(t *testing.T) {
+	// "HELLO WORLD" as 1-M in alphanumeric mode, the worked example of the standard.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := qr.RSRemainder(data, qr.RSDivisor(10)); !bytes.Equal(got, want) {
+		t.Fatalf("ecc = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	for _, tc := range []struct {
+		level qr.Level
+		mask  int
+		want  string
+	}{
+		{qr.LevelL, 0, "111011111000100"},
+		{qr.LevelM, 0, "101010000010010"},
+		{qr.LevelQ, 0, "011010101011111"},
+		{qr.LevelH, 0, "001011010001001"},
+		{qr.LevelM, 5, "100000011001110"},
+		{qr.LevelL, 7, "110100101110110"},
+	} {
+		if got := fmt.Sprintf("%015b", qr.FormatBits(tc.level, tc.mask)); got != tc.want {
+			t.Errorf("format(%d, %d) = %s, want %s", tc.level, tc.mask, got, tc.want)
+		}
+	}
+
+	for version, want := range map[int]string{7: "000111110010010100", 21: "010101011010000011", 40: "101000110001101001"} {
+		if got := fmt.Sprintf("%018b", qr.VersionBits(version)); got != want {
+			t.Errorf("version(%d) = %s, want %s", version, got, want)
+		}
+	}
+}
+
+func TestAlignmentPositionsAndCapacity(t *testing.T) {
+	for version, want := range map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	} {
+		if got := qr.AlignmentPositions(version); !slices.Equal(got, want) {
+			t.Errorf("alignment(%d) = %v, want %v", version, got, want)
+		}
+	}
+
+	for _, tc := range []struct {
+		version int
+		level   qr.Level
+		want    int
+	}{
+		{1, qr.LevelL, 17}, {1, qr.LevelM, 14}, {1, qr.LevelQ, 11}, {1, qr.LevelH, 7},
+		{10, qr.LevelM, 213}, {17, qr.LevelM, 504}, {40, qr.LevelL, 2953}, {40, qr.LevelH, 1273},
+	} {
+		if got := qr.ByteCapacity(tc.version, tc.level); got != tc.want {
+			t.Errorf("capacity(%d, %d) = %d, want %d", tc.version, tc.level, got, tc.want)
+		}
+	}
+}
+
+// decode reads c back as a reader would once it has located the modules: the format,
+// the codewords in zigzag order and the blocks, checking the error correction of each,
+// and returns the byte mode data.
+func decode(t *testing.T, c *qr.Code) []byte {
+	t.Helper()
+
+	size := c.Size()
+	version := (size - 17) / 4
+
+	// Format information next to the top left finder, against the copy at the others.
+	var format, copy2 int
+	for i, p := range [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}} {
+		if c.Dark(p[0], p[1]) {
+			format |= 1 << i
+		}
+	}
+	for i := range 15 {
+		x, y := size-1-i, 8
+		if i >= 8 {
+			x, y = 8, size-15+i
+		}
+		if c.Dark(x, y) {
+			copy2 |= 1 << i
+		}
+	}
+	if format != copy2 {
+		t.Fatalf("format copies differ: %015b and %015b", format, copy2)
+	}
+
+	var level qr.Level
+	mask := -1
+	for l := range 4 {
+		for m := range 8 {
+			if qr.FormatBits(qr.Level(l), m) == format {
+				level, mask = qr.Level(l), m
+			}
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("unknown format %015b", format)
+	}
+
+	masked := []func(x, y int) bool{
+		func(x, y int) bool { return (x+y)%2 == 0 },
+		func(x, y int) bool { return y%2 == 0 },
+		func(x, y int) bool { return x%3 == 0 },
+		func(x, y int) bool { return (x+y)%3 == 0 },
+		func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+		func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+		func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+		func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+	}[mask]
+
+	function := qr.FunctionModules(version)
+
+	bits := zigzag(size, func(x, y int) (bool, bool) {
+		if function[y*size+x] {
+			return false, false
+		}
+		return c.Dark(x, y) != masked(x, y), true
+	})
+
+	blocks, eccLen, codewords := qr.BlockLayout(version, level)
+	if len(bits)/8 != codewords {
+		t.Fatalf("%d data modules, want %d codewords", len(bits), codewords)
+	}
+
+	stream := make([]byte, codewords)
+	for i := range codewords * 8 {
+		if bits[i] {
+			stream[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+
+	// De-interleave: the last blocks hold one more data codeword.
+	short := blocks - codewords%blocks
+	shortLen := codewords/blocks - eccLen
+
+	blockData := make([][]byte, blocks)
+	k := 0
+	for i := range shortLen + 1 {
+		for b := range blocks {
+			if i < shortLen || b >= short {
+				blockData[b] = append(blockData[b], stream[k])
+				k++
+			}
+		}
+	}
+
+	var data []byte
+	for b := range blocks {
+		ecc := make([]byte, eccLen)
+		for i := range eccLen {
+			ecc[i] = stream[k+i*blocks+b]
+		}
+		if want := qr.RSRemainder(blockData[b], qr.RSDivisor(eccLen)); !bytes.Equal(ecc, want) {
+			t.Fatalf("block %d: ecc = %v, want %v", b, ecc, want)
+		}
+		data = append(data, blockData[b]...)
+	}
+
+	read := func(from, n int) int {
+		v := 0
+		for i := from; i < from+n; i++ {
+			v = v<<1 | int(data[i/8]>>(7-i%8)&1)
+		}
+		return v
+	}
+
+	if mode := read(0, 4); mode != 0b0100 {
+		t.Fatalf("mode = %04b, want byte mode", mode)
+	}
+
+	countLen := 8
+	if version >= 10 {
+		countLen = 16
+	}
+
+	n := read(4, countLen)
+	out := make([]byte, n)
+	for i := range n {
+		out[i] = byte(read(4+countLen+i*8, 8))
+	}
+
+	return out
+}
+
+// zigzag returns the modules that module accepts in the placement order of the
+// standard: pairs of columns from the right, upwards first, skipping column 6.
+func zigzag(size int, module func(x, y int) (dark, ok bool)) []bool {
+	var out []bool
+
+	upward := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for k := range size {
+			y := k
+			if upward {
+				y = size - 1 - k
+			}
+			for _, x := range []int{right, right - 1} {
+				if dark, ok := module(x, y); ok {
+					out = append(out, dark)
+				}
+			}
+		}
+		upward = !upward
+	}
+
+	return out
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	payment := "ST00012|Name=Казначейство России (ФНС России)|PersonalAcc=03100643000000018500|" +
+		"BankName=ОКЦ № 7 ГУ Банка России по ЦФО//УФК по Тульской области, г Тула|BIC=017003983|" +
+		"CorrespAcc=40102810445370000059|PayeeINN=7727406020|KPP=770801001|Sum=1234500"
+
+	for _, tc := range []struct {
+		data  string
+		level qr.Level
+		size  int
+	}{
+		{"HELLO", qr.LevelH, 21},
+		{strings.Repeat("a", 14), qr.LevelM, 21},
+		{strings.Repeat("a", 15), qr.LevelM, 25},
+		{payment, qr.LevelM, 0},
+		{strings.Repeat("0123456789", 100), qr.LevelQ, 0},
+		{strings.Repeat("x", 2953), qr.LevelL, 177},
+	} {
+		c, err := qr.Encode([]byte(tc.data), tc.level)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(tc.data), err)
+		}
+		if tc.size != 0 && c.Size() != tc.size {
+			t.Errorf("Encode(%d bytes): size = %d, want %d", len(tc.data), c.Size(), tc.size)
+		}
+		if got := decode(t, c); string(got) != tc.data {
+			t.Errorf("decoded %q, want %q", got, tc.data)
+		}
+	}
+
+	if _, err := qr.Encode(make([]byte, 2954), qr.LevelL); err == nil {
+		t.Errorf("Encode(2954 bytes at L): no error")
+	}
+}
+
+func TestCode_PNG(t *testing.T) {
+	c, err := qr.Encode([]byte("HELLO"), qr.LevelM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := c.PNG(4)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	side := (21 + 2*qr.QuietZone) * 4
+	if got := img.Bounds().Size(); got.X != side || got.Y != side {
+		t.Fatalf("size = %v, want %d", got, side)
+	}
+
+	// The quiet zone is white, the top left corner of the finder black.
+	dark := func(x, y int) bool { r, _, _, _ := img.At(x, y).RGBA(); return r < 0x8000 }
+	if dark(0, 0) || !dark(qr.QuietZone*4, qr.QuietZone*4) || !dark(qr.QuietZone*4+3, qr.QuietZone*4+3) {
+		t.Errorf("finder is not drawn at the quiet zone")
+	}
+}
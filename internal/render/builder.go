@@ -0,0 +1,83 @@
+package render
+
+// HTML returns a Builder for parse_mode=HTML.
+func HTML() *Builder {
+	return &Builder{mode: ModeHTML}
+}
+
+// MarkdownV2 returns a Builder for parse_mode=MarkdownV2.
+func MarkdownV2() *Builder {
+	return &Builder{mode: ModeMarkdownV2}
+}
+
+// Mode returns the parse_mode the message must be sent with.
+func (b *Builder) Mode() Mode {
+	return b.mode
+}
+
+// Text appends escaped text.
+func (b *Builder) Text(s string) *Builder {
+	b.b.WriteString(Escape(b.mode, s))
+	return b
+}
+
+// Line appends escaped text followed by a line break.
+func (b *Builder) Line(s string) *Builder {
+	return b.Text(s).Newline()
+}
+
+// Newline appends a line break.
+func (b *Builder) Newline() *Builder {
+	b.b.WriteByte('\n')
+	return b
+}
+
+// Bold appends s in bold.
+func (b *Builder) Bold(s string) *Builder {
+	return b.wrap("<b>", "</b>", "*", "*", s)
+}
+
+// Italic appends s in italics.
+func (b *Builder) Italic(s string) *Builder {
+	return b.wrap("<i>", "</i>", "_", "_", s)
+}
+
+// Code appends s as inline monospace.
+func (b *Builder) Code(s string) *Builder {
+	if b.mode == ModeMarkdownV2 {
+		b.b.WriteString("`" + escapeMarkdownV2Code(s) + "`")
+		return b
+	}
+
+	return b.wrap("<code>", "</code>", "", "", s)
+}
+
+// Pre appends s as a preformatted block, e.g. a table aligned with spaces.
+func (b *Builder) Pre(s string) *Builder {
+	if b.mode == ModeMarkdownV2 {
+		b.b.WriteString("```\n" + escapeMarkdownV2Code(s) + "\n```")
+		return b
+	}
+
+	return b.wrap("<pre>", "</pre>", "", "", s)
+}
+
+// Len returns the length of the markup built so far in bytes.
+func (b *Builder) Len() int {
+	return b.b.Len()
+}
+
+// String returns the markup.
+func (b *Builder) String() string {
+	return b.b.String()
+}
+
+func (b *Builder) wrap(htmlOpen, htmlClose, mdOpen, mdClose, s string) *Builder {
+	if b.mode == ModeMarkdownV2 {
+		b.b.WriteString(mdOpen + EscapeMarkdownV2(s) + mdClose)
+		return b
+	}
+
+	b.b.WriteString(htmlOpen + EscapeHTML(s) + htmlClose)
+	return b
+}
@@ -0,0 +1,40 @@
+package render
+
+import "strings"
+
+var (
+	htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+	// markdownV2Escaper escapes every character MarkdownV2 reserves outside entities.
+	markdownV2Escaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+
+	// markdownV2CodeEscaper escapes what MarkdownV2 reserves inside code entities.
+	markdownV2CodeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+)
+
+// Escape escapes s as plain text for mode.
+func Escape(mode Mode, s string) string {
+	if mode == ModeMarkdownV2 {
+		return EscapeMarkdownV2(s)
+	}
+
+	return EscapeHTML(s)
+}
+
+// EscapeHTML escapes the characters Telegram's HTML parser treats as markup.
+func EscapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// EscapeMarkdownV2 escapes the characters reserved by MarkdownV2.
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+func escapeMarkdownV2Code(s string) string {
+	return markdownV2CodeEscaper.Replace(s)
+}
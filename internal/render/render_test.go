@@ -0,0 +1,191 @@
+package render_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/render"
+)
+
+func TestBuilder_HTMLEscapesText(t *testing.T) {
+	t.Parallel()
+
+	got := render.HTML().Text("💬 ").Text("<b").Text(" & </code>").Newline().Bold("1 < 2").Code("a&b").String()
+	want := "💬 &lt;b &amp; &lt;/code&gt;\n<b>1 &lt; 2</b><code>a&amp;b</code>"
+
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestBuilder_MarkdownV2EscapesText(t *testing.T) {
+	t.Parallel()
+
+	got := render.MarkdownV2().Text("1 234,56 (заказ #42)!").Bold("a_b").Code("x`y\\").String()
+	want := "1 234,56 \\(заказ \\#42\\)\\!*a\\_b*`x\\`y\\\\`"
+
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestBuilder_Pre(t *testing.T) {
+	t.Parallel()
+
+	if got, want := render.HTML().Pre("a  <1>\nb").String(), "<pre>a  &lt;1&gt;\nb</pre>"; got != want {
+		t.Errorf("HTML: got %q, want %q", got, want)
+	}
+
+	if got, want := render.MarkdownV2().Pre("a_1 `x`").String(), "```\na_1 \\`x\\`\n```"; got != want {
+		t.Errorf("MarkdownV2: got %q, want %q", got, want)
+	}
+}
+
+func TestSplit_ShortMessageUntouched(t *testing.T) {
+	t.Parallel()
+
+	parts := render.Split(render.ModeHTML, "hello\nworld", render.MaxMessageLength)
+	if len(parts) != 1 || parts[0] != "hello\nworld" {
+		t.Fatalf("parts = %q", parts)
+	}
+}
+
+func TestSplit_PrefersLineBreaks(t *testing.T) {
+	t.Parallel()
+
+	text := "aaaa\nbbbb\ncccc"
+
+	parts := render.Split(render.ModeHTML, text, 10)
+	if len(parts) != 2 || parts[0] != "aaaa\nbbbb" || parts[1] != "cccc" {
+		t.Fatalf("parts = %q", parts)
+	}
+}
+
+func TestSplit_LongLineKeepsEntitiesAndTags(t *testing.T) {
+	t.Parallel()
+
+	b := render.HTML()
+	for range 3000 {
+		b.Text("<&>")
+	}
+	b.Bold("конец")
+	text := b.String()
+
+	parts := render.Split(render.ModeHTML, text, render.MaxMessageLength)
+	if len(parts) < 2 {
+		t.Fatalf("expected several parts, got %d", len(parts))
+	}
+
+	if strings.Join(parts, "") != text {
+		t.Fatalf("parts do not add up to the original text")
+	}
+
+	for i, p := range parts {
+		if n := len(utf16.Encode([]rune(p))); n > render.MaxMessageLength {
+			t.Fatalf("part %d has %d units", i, n)
+		}
+
+		if strings.Count(p, "&") != strings.Count(p, ";") {
+			t.Fatalf("part %d cuts an entity: ...%q", i, p[len(p)-10:])
+		}
+
+		if strings.Count(p, "<b>") != strings.Count(p, "</b>") {
+			t.Fatalf("part %d cuts a tag", i)
+		}
+	}
+}
+
+func TestSplit_HTMLBackslashIsText(t *testing.T) {
+	t.Parallel()
+
+	// In HTML a backslash escapes nothing, so "\&lt;" must not make the entity look escaped.
+	text := strings.Repeat(render.EscapeHTML(`\<`), 7)
+
+	for limit := 5; limit <= 12; limit++ {
+		parts := render.Split(render.ModeHTML, text, limit)
+		if strings.Join(parts, "") != text {
+			t.Fatalf("limit %d: parts do not add up to the original text", limit)
+		}
+
+		for i, p := range parts {
+			if strings.Count(p, "&") != strings.Count(p, ";") {
+				t.Fatalf("limit %d: part %d cuts an entity: %q", limit, i, p)
+			}
+		}
+	}
+}
+
+func TestSplit_MarkdownV2KeepsEscapes(t *testing.T) {
+	t.Parallel()
+
+	text := strings.Repeat(render.EscapeMarkdownV2("a.b"), 5)
+
+	for limit := 3; limit <= 8; limit++ {
+		parts := render.Split(render.ModeMarkdownV2, text, limit)
+		if strings.Join(parts, "") != text {
+			t.Fatalf("limit %d: parts do not add up to the original text", limit)
+		}
+
+		for i, p := range parts {
+			if strings.HasSuffix(p, `\`) {
+				t.Fatalf("limit %d: part %d cuts an escape: %q", limit, i, p)
+			}
+		}
+	}
+}
+
+func TestSplit_CountsUTF16(t *testing.T) {
+	t.Parallel()
+
+	// Each emoji is two UTF-16 units, so 3 of them do not fit into 5.
+	parts := render.Split(render.ModeHTML, "💰💰💰", 5)
+	if len(parts) != 2 || parts[0] != "💰💰" || parts[1] != "💰" {
+		t.Fatalf("parts = %q", parts)
+	}
+}
+
+func TestSplit_ReportTableReopensPre(t *testing.T) {
+	t.Parallel()
+
+	// A /report table: a title, then a <pre> block far longer than one message.
+	var table []string
+	for i := range 300 {
+		table = append(table, fmt.Sprintf("2025-W%03d │ %3d │ 1 234 567,89 ₽ │ <&>", i, i))
+	}
+
+	text := render.HTML().Bold("Report").Newline().Pre(strings.Join(table, "\n")).Newline().Text("Total").String()
+
+	parts := render.Split(render.ModeHTML, text, render.MaxMessageLength)
+	if len(parts) < 3 {
+		t.Fatalf("expected several parts, got %d", len(parts))
+	}
+
+	var rows []string
+
+	for i, p := range parts {
+		if n := len(utf16.Encode([]rune(p))); n > render.MaxMessageLength {
+			t.Fatalf("part %d has %d units", i, n)
+		}
+
+		if strings.Count(p, "<pre>") != 1 || strings.Count(p, "</pre>") != 1 || strings.Index(p, "<pre>") > strings.Index(p, "</pre>") {
+			t.Fatalf("part %d does not hold one whole <pre> block:\n%s", i, p)
+		}
+
+		body := p[strings.Index(p, "<pre>")+len("<pre>") : strings.Index(p, "</pre>")]
+		rows = append(rows, strings.Split(body, "\n")...)
+	}
+
+	if !strings.HasPrefix(parts[0], "<b>Report</b>\n<pre>") || !strings.HasSuffix(parts[len(parts)-1], "</pre>\nTotal") {
+		t.Errorf("title or footer lost: %q ... %q", parts[0][:20], parts[len(parts)-1])
+	}
+
+	want := strings.Split(render.HTML().Pre(strings.Join(table, "\n")).String(), "\n")
+	want[0] = strings.TrimPrefix(want[0], "<pre>")
+	want[len(want)-1] = strings.TrimSuffix(want[len(want)-1], "</pre>")
+
+	if strings.Join(rows, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("rows of the table do not add up to the original")
+	}
+}
@@ -0,0 +1,176 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is the Bot API limit for one text message.
+const MaxMessageLength = 4096
+
+// MaxCaptionLength is the Bot API limit for the caption of a photo.
+const MaxCaptionLength = 1024
+
+// Tags of a preformatted block. Builder.Pre emits it over several lines (a report table),
+// so a part may end inside one.
+const (
+	preOpen  = "<pre>"
+	preClose = "</pre>"
+)
+
+// Split cuts a message in mode into parts of at most limit UTF-16 code units (how Telegram
+// counts). Parts end at line breaks where possible; a longer line is cut between characters,
+// never inside an HTML tag or entity (HTML) or an escape (MarkdownV2). Markup built with
+// Builder keeps formatting within a line, except <pre> blocks: a part cut inside one closes
+// it and the next part opens it again, so every part stays well-formed.
+func Split(mode Mode, text string, limit int) []string {
+	if limit <= 0 || utf16Len(text) <= limit {
+		return []string{text}
+	}
+
+	var (
+		parts []string
+		cur   strings.Builder
+		n     int
+		start int  // length of the <pre> reopened at the start of cur
+		inPre bool // cur ends inside a <pre> block
+	)
+
+	write := func(s string) {
+		cur.WriteString(s)
+		n += utf16Len(s)
+		inPre = preAfter(s, inPre)
+	}
+
+	flush := func() {
+		if cur.Len() <= start {
+			return
+		}
+
+		part := strings.TrimRight(cur.String(), "\n")
+		if inPre {
+			part += preClose
+		}
+		parts = append(parts, part)
+
+		cur.Reset()
+		n, start = 0, 0
+
+		if inPre {
+			cur.WriteString(preOpen)
+			n, start = len(preOpen), len(preOpen)
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		// Room for closing a <pre> block the part may end in.
+		reserve := 0
+		if inPre || strings.Contains(line, preOpen) {
+			reserve = len(preClose)
+		}
+
+		if n+utf16Len(line)+reserve <= limit {
+			write(line)
+			continue
+		}
+
+		flush()
+
+		for n+utf16Len(line)+reserve > limit {
+			head, tail := cutLine(mode, line, limit-n-reserve)
+			write(head)
+			flush()
+			line = tail
+		}
+
+		write(line)
+	}
+
+	inPre = false
+	flush()
+
+	return parts
+}
+
+// preAfter reports whether a <pre> block is open after s, given whether it was before.
+func preAfter(s string, inPre bool) bool {
+	open, closed := strings.LastIndex(s, preOpen), strings.LastIndex(s, preClose)
+	if open < 0 && closed < 0 {
+		return inPre
+	}
+	return open > closed
+}
+
+// cutLine splits line so that head fits limit and does not end inside markup of mode:
+// a tag or an entity in HTML, an escape in MarkdownV2. A backslash is plain text in HTML.
+func cutLine(mode Mode, line string, limit int) (head, tail string) {
+	markdown := mode == ModeMarkdownV2
+
+	var (
+		n                       int
+		safe                    int // last byte offset where a cut is safe
+		inTag, inEntity, escape bool
+	)
+
+	for i, r := range line {
+		outside := !inTag && !inEntity && !escape
+		if outside {
+			safe = i
+		}
+
+		if n+utf16.RuneLen(r) > limit {
+			break
+		}
+		n += utf16.RuneLen(r)
+
+		switch {
+		case escape:
+			escape = false
+		case markdown:
+			escape = r == '\\'
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case r == '&':
+			inEntity = true
+		case inEntity && r == ';', inEntity && !isEntityRune(r):
+			inEntity = false
+		}
+	}
+
+	if safe == 0 {
+		// No safe point (e.g. one huge tag): cut at the limit rather than loop forever.
+		safe = byteOffset(line, limit)
+	}
+	if safe == 0 {
+		_, safe = utf8.DecodeRuneInString(line)
+	}
+
+	return line[:safe], line[safe:]
+}
+
+// byteOffset returns the byte offset after the first units UTF-16 code units of s.
+func byteOffset(s string, units int) int {
+	n := 0
+	for i, r := range s {
+		if n+utf16.RuneLen(r) > units {
+			return i
+		}
+		n += utf16.RuneLen(r)
+	}
+	return len(s)
+}
+
+func isEntityRune(r rune) bool {
+	return r == '#' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
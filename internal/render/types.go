@@ -0,0 +1,18 @@
+package render
+
+import "strings"
+
+// Mode is a Telegram parse_mode the markup is written for.
+type Mode string
+
+const (
+	ModeHTML       Mode = "HTML"
+	ModeMarkdownV2 Mode = "MarkdownV2"
+)
+
+// Builder assembles a message in one Mode. Every string passed to it is treated as
+// text and escaped; markup is produced only by the Builder's own methods.
+type Builder struct {
+	mode Mode
+	b    strings.Builder
+}
@@ -0,0 +1,208 @@
+package api_runner_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	apirunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/api_runner"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+func fixedNow() time.Time {
+	return time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+}
+
+// newTestServer wires memstore-backed usecases and issues a token for a fresh user.
+func newTestServer(t *testing.T) (*httptest.Server, *memstore.Store, *service.TokenService, int64, string) {
+	t.Helper()
+
+	ctx := context.Background()
+	store := memstore.NewStore()
+
+	income := service.NewIncomeService(store)
+	payment := service.NewPaymentService(store)
+	total := service.NewTotalService(store.GetUserScheme, income.SumIncomes, payment.SumPayments, tax.NewDefaultProvider())
+	tokens := service.NewTokenService(store, fixedNow)
+
+	deps := bot.NewBotDeps(store, income, payment, total, tokens, nil, nil, nil, fixedNow)
+
+	userID, err := store.UpsertIdentity(ctx, "telegram", "42", 0)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	token, _, err := tokens.IssueToken(ctx, userID, "ci")
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	h, err := apirunner.NewRunner(":0").SetBotDeps(deps).Handler()
+	if err != nil {
+		t.Fatalf("Handler: %v", err)
+	}
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	return srv, store, tokens, userID, token
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, token, body string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	b, _ := io.ReadAll(resp.Body)
+
+	return resp.StatusCode, string(b)
+}
+
+func TestAPI_IncomesListAndExport(t *testing.T) {
+	t.Parallel()
+
+	srv, _, _, _, token := newTestServer(t)
+
+	code, body := do(t, srv, http.MethodPost, "/v1/incomes", token, `{"date":"2025-08-01","amount":150000,"note":"заказ #42"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("add income: status %d, body %s", code, body)
+	}
+
+	code, body = do(t, srv, http.MethodPost, "/v1/payments", token, `{"date":"2025-08-02","amount":5000,"type":"advance"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("add payment: status %d, body %s", code, body)
+	}
+
+	code, body = do(t, srv, http.MethodGet, "/v1/incomes?from=2025-01-01&to=2025-12-31", token, "")
+	if code != http.StatusOK {
+		t.Fatalf("list incomes: status %d, body %s", code, body)
+	}
+
+	var items []struct {
+		ID     int64  `json:"id"`
+		Date   string `json:"date"`
+		Amount int64  `json:"amount"`
+		Note   string `json:"note"`
+	}
+	if err := json.Unmarshal([]byte(body), &items); err != nil {
+		t.Fatalf("decode list: %v (%s)", err, body)
+	}
+	if len(items) != 1 || items[0].Amount != 150000 || items[0].Date != "2025-08-01" || items[0].Note != "заказ #42" {
+		t.Fatalf("unexpected incomes: %+v", items)
+	}
+
+	code, body = do(t, srv, http.MethodGet, "/v1/export?from=2025-01-01&to=2025-12-31", token, "")
+	if code != http.StatusOK {
+		t.Fatalf("export: status %d, body %s", code, body)
+	}
+
+	want := "kind,id,date,amount_kopecks,payment_type,note\n" +
+		"income,1,2025-08-01,150000,,заказ #42\n" +
+		"payment,1,2025-08-02,5000,advance,\n"
+	if body != want {
+		t.Fatalf("unexpected export:\n--- got ---\n%s\n--- want ---\n%s", body, want)
+	}
+}
+
+func TestAPI_BadInput(t *testing.T) {
+	t.Parallel()
+
+	srv, _, _, _, token := newTestServer(t)
+
+	cases := []struct {
+		desc, method, path, body string
+	}{
+		{"zero amount", http.MethodPost, "/v1/incomes", `{"amount":0}`},
+		{"bad date", http.MethodPost, "/v1/incomes", `{"amount":100,"date":"01.08.2025"}`},
+		{"unknown field", http.MethodPost, "/v1/incomes", `{"amount":100,"sum":1}`},
+		{"bad payment type", http.MethodPost, "/v1/payments", `{"amount":100,"type":"tax"}`},
+		{"inverted range", http.MethodGet, "/v1/incomes?from=2025-02-01&to=2025-01-01", ""},
+	}
+
+	for _, tc := range cases {
+		code, body := do(t, srv, tc.method, tc.path, token, tc.body)
+		if code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, body %s", tc.desc, code, body)
+		}
+	}
+}
+
+func TestAPI_Auth(t *testing.T) {
+	t.Parallel()
+
+	srv, _, tokens, userID, token := newTestServer(t)
+
+	if code, _ := do(t, srv, http.MethodGet, "/v1/incomes", "", ""); code != http.StatusUnauthorized {
+		t.Fatalf("no token: status %d", code)
+	}
+
+	if code, _ := do(t, srv, http.MethodGet, "/v1/incomes", "ipb_unknown", ""); code != http.StatusUnauthorized {
+		t.Fatalf("unknown token: status %d", code)
+	}
+
+	if code, _ := do(t, srv, http.MethodGet, "/openapi.yaml", "", ""); code != http.StatusOK {
+		t.Fatalf("openapi: status %d", code)
+	}
+
+	if _, err := tokens.RevokeAllTokens(context.Background(), userID); err != nil {
+		t.Fatalf("RevokeAllTokens: %v", err)
+	}
+
+	if code, _ := do(t, srv, http.MethodGet, "/v1/incomes", token, ""); code != http.StatusUnauthorized {
+		t.Fatalf("revoked token: status %d", code)
+	}
+}
+
+func TestAPI_RevokeDropsIdentity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv, store, tokens, userID, token := newTestServer(t)
+
+	list, err := tokens.ListTokens(ctx, userID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListTokens: %v, %+v", err, list)
+	}
+
+	apiID := strconv.FormatInt(list[0].ID, 10)
+
+	if got, err := store.UpsertIdentity(ctx, domain.TransportAPI, apiID, 0); err != nil || got != userID {
+		t.Fatalf("api identity before revoke: user %d, err %v; want %d", got, err, userID)
+	}
+
+	if ok, err := tokens.RevokeToken(ctx, userID, list[0].ID); err != nil || !ok {
+		t.Fatalf("RevokeToken: ok=%v, err %v", ok, err)
+	}
+
+	if code, _ := do(t, srv, http.MethodGet, "/v1/incomes", token, ""); code != http.StatusUnauthorized {
+		t.Fatalf("revoked token: status %d", code)
+	}
+
+	// The identity is gone, so resolving it creates a new account instead of the owner's.
+	if got, err := store.UpsertIdentity(ctx, domain.TransportAPI, apiID, 0); err != nil || got == userID {
+		t.Fatalf("api identity after revoke: user %d, err %v; want a new user", got, err)
+	}
+}
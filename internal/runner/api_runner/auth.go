@@ -0,0 +1,44 @@
+package api_runner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+)
+
+const ctxKeyUserID ctxKey = iota
+
+// auth resolves "Authorization: Bearer <token>" to a user and stores the user ID in the request context.
+func (r *Runner) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		userID, err := r.botDeps.Tokens.Authenticate(req.Context(), token)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				writeError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+
+			r.internalError(w, req, err)
+			return
+		}
+
+		next(w, req.WithContext(context.WithValue(req.Context(), ctxKeyUserID, userID)))
+	})
+}
+
+// userIDFrom returns the authenticated user ID set by auth.
+func userIDFrom(ctx context.Context) int64 {
+	id, _ := ctx.Value(ctxKeyUserID).(int64)
+	return id
+}
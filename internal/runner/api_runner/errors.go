@@ -0,0 +1,9 @@
+package api_runner
+
+import "errors"
+
+var (
+	ErrBotDepsNotSet = errors.New("bot deps are not set")
+	ErrTokensNotSet  = errors.New("token usecase is not set")
+	ErrAddrNotSet    = errors.New("listen address is not set")
+)
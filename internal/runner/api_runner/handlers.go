@@ -0,0 +1,344 @@
+package api_runner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// dateLayout is the only date format accepted and returned by the API.
+const dateLayout = "2006-01-02"
+
+// ------------------ INCOMES ------------------
+
+func (r *Runner) handleAddIncome(w http.ResponseWriter, req *http.Request) {
+	var in entryRequest
+
+	if !r.decodeJSON(w, req, &in) {
+		return
+	}
+
+	at, ok := r.parseDateOrToday(w, in.Date)
+	if !ok {
+		return
+	}
+
+	id, err := r.botDeps.Income.AddIncome(req.Context(), userIDFrom(req.Context()), at, in.Amount, in.Note)
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdResponse{ID: id})
+}
+
+func (r *Runner) handleListIncomes(w http.ResponseWriter, req *http.Request) {
+	from, to, ok := r.parseRange(w, req)
+	if !ok {
+		return
+	}
+
+	items, err := r.botDeps.Income.ListIncomes(req.Context(), userIDFrom(req.Context()), from, to)
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	out := make([]entryResponse, 0, len(items))
+	for _, it := range items {
+		out = append(out, entryResponse{ID: it.ID, Date: it.At.Format(dateLayout), Amount: it.Amount, Note: it.Note})
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (r *Runner) handleUndoIncome(w http.ResponseWriter, req *http.Request) {
+	amount, at, note, ok, err := r.botDeps.Income.UndoLastQuarter(req.Context(), userIDFrom(req.Context()), r.now())
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	if !ok {
+		writeJSON(w, http.StatusOK, undoResponse{Undone: false})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, undoResponse{
+		Undone: true,
+		Entry:  &entryResponse{Date: at.Format(dateLayout), Amount: amount, Note: note},
+	})
+}
+
+// ------------------ PAYMENTS ------------------
+
+func (r *Runner) handleAddPayment(w http.ResponseWriter, req *http.Request) {
+	var in entryRequest
+
+	if !r.decodeJSON(w, req, &in) {
+		return
+	}
+
+	at, ok := r.parseDateOrToday(w, in.Date)
+	if !ok {
+		return
+	}
+
+	id, err := r.botDeps.Payment.AddPayment(req.Context(), userIDFrom(req.Context()), at, in.Amount, in.Note, domain.PaymentType(in.Type))
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdResponse{ID: id})
+}
+
+func (r *Runner) handleListPayments(w http.ResponseWriter, req *http.Request) {
+	from, to, ok := r.parseRange(w, req)
+	if !ok {
+		return
+	}
+
+	items, err := r.botDeps.Payment.ListPayments(req.Context(), userIDFrom(req.Context()), from, to)
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	paymentType := req.URL.Query().Get("type")
+
+	out := make([]entryResponse, 0, len(items))
+	for _, it := range items {
+		if paymentType != "" && string(it.Type) != paymentType {
+			continue
+		}
+		out = append(out, entryResponse{ID: it.ID, Date: it.At.Format(dateLayout), Amount: it.Amount, Note: it.Note, Type: string(it.Type)})
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (r *Runner) handleUndoPayment(w http.ResponseWriter, req *http.Request) {
+	var in undoRequest
+
+	if !r.decodeJSON(w, req, &in) {
+		return
+	}
+
+	amount, at, note, paymentType, ok, err := r.botDeps.Payment.UndoLastYear(req.Context(), userIDFrom(req.Context()), r.now(), domain.PaymentType(in.Type))
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	if !ok {
+		writeJSON(w, http.StatusOK, undoResponse{Undone: false})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, undoResponse{
+		Undone: true,
+		Entry:  &entryResponse{Date: at.Format(dateLayout), Amount: amount, Note: note, Type: string(paymentType)},
+	})
+}
+
+// ------------------ TOTALS ------------------
+
+func (r *Runner) handleTotals(w http.ResponseWriter, req *http.Request) {
+	date, ok := r.parseDateOrToday(w, req.URL.Query().Get("date"))
+	if !ok {
+		return
+	}
+
+	userID := userIDFrom(req.Context())
+
+	quarter, err := r.botDeps.Total.SumQuarter(req.Context(), userID, date)
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	year, err := r.botDeps.Total.SumYearToDate(req.Context(), userID, date)
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, totalsPairResponse{
+		Quarter: toTotalsResponse(quarter),
+		Year:    toTotalsResponse(year),
+	})
+}
+
+func toTotalsResponse(t domain.Totals) totalsResponse {
+	return totalsResponse{
+		From:           t.From.Format(dateLayout),
+		To:             t.To.Format(dateLayout),
+		IncomeSum:      t.IncomeSum,
+		Tax:            t.Tax,
+		ContribSum:     t.ContribSum,
+		AdvanceSum:     t.AdvanceSum,
+		ContribApplied: t.ContribApplied,
+		Due:            t.Due,
+		VATRateBP:      t.VATRateBP,
+		VAT:            t.VAT,
+	}
+}
+
+// ------------------ EXPORT ------------------
+
+// handleExport streams incomes and payments in [from..to] as CSV ordered by date.
+func (r *Runner) handleExport(w http.ResponseWriter, req *http.Request) {
+	from, to, ok := r.parseRange(w, req)
+	if !ok {
+		return
+	}
+
+	userID := userIDFrom(req.Context())
+
+	incomes, err := r.botDeps.Income.ListIncomes(req.Context(), userID, from, to)
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	payments, err := r.botDeps.Payment.ListPayments(req.Context(), userID, from, to)
+	if err != nil {
+		r.writeUsecaseError(w, req, err)
+		return
+	}
+
+	rows := make([][]string, 0, len(incomes)+len(payments))
+	for _, it := range incomes {
+		rows = append(rows, []string{"income", strconv.FormatInt(it.ID, 10), it.At.Format(dateLayout), strconv.FormatInt(it.Amount, 10), "", it.Note})
+	}
+	for _, it := range payments {
+		rows = append(rows, []string{"payment", strconv.FormatInt(it.ID, 10), it.At.Format(dateLayout), strconv.FormatInt(it.Amount, 10), string(it.Type), it.Note})
+	}
+
+	// Dates are YYYY-MM-DD, so string order is chronological.
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i][2] < rows[j][2] })
+
+	filename := fmt.Sprintf("export_%s_%s.csv", from.Format(dateLayout), to.Format(dateLayout))
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"kind", "id", "date", "amount_kopecks", "payment_type", "note"})
+	_ = cw.WriteAll(rows)
+}
+
+// ------------------ HELPERS ------------------
+
+// decodeJSON reads a bounded JSON body into dst; on failure it writes 400 and returns false.
+func (r *Runner) decodeJSON(w http.ResponseWriter, req *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, req.Body, apiMaxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		if errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "empty request body")
+			return false
+		}
+
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+// parseDateOrToday parses YYYY-MM-DD as a UTC date; empty means today.
+func (r *Runner) parseDateOrToday(w http.ResponseWriter, s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		return r.now(), true
+	}
+
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date, want YYYY-MM-DD")
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// parseRange reads ?from=&to= (inclusive). Defaults: from = start of the current year, to = today.
+func (r *Runner) parseRange(w http.ResponseWriter, req *http.Request) (time.Time, time.Time, bool) {
+	q := req.URL.Query()
+	now := r.now()
+
+	from, _ := period.YearBounds(now)
+	to := now
+
+	if s := q.Get("from"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid from, want YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+		from = t
+	}
+
+	if s := q.Get("to"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid to, want YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+		to = t
+	}
+
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "to is before from")
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+// writeUsecaseError maps validation errors to 400 and everything else to 500.
+func (r *Runner) writeUsecaseError(w http.ResponseWriter, req *http.Request, err error) {
+	switch {
+	case errors.Is(err, validate.ErrInvalidAmount):
+		writeError(w, http.StatusBadRequest, "amount must be a positive number of kopecks")
+	case errors.Is(err, validate.ErrInvalidPaymentType):
+		writeError(w, http.StatusBadRequest, "type must be one of: contrib, advance")
+	case errors.Is(err, validate.ErrInvalidDate),
+		errors.Is(err, validate.ErrInvalidDateRange),
+		errors.Is(err, validate.ErrInvalidDateUTC):
+		writeError(w, http.StatusBadRequest, "invalid date")
+	default:
+		r.internalError(w, req, err)
+	}
+}
+
+func (r *Runner) internalError(w http.ResponseWriter, req *http.Request, err error) {
+	r.log.Error("api handler error", "code", codeAPIHandlerFailed, "method", req.Method, "path", req.URL.Path, "error", err)
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
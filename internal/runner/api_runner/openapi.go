@@ -0,0 +1,15 @@
+package api_runner
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// handleOpenAPI serves the API description; it does not require a token.
+func (r *Runner) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	_, _ = w.Write(openAPISpec)
+}
@@ -0,0 +1,140 @@
+package api_runner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
+)
+
+const (
+	codeAPIStarted       = "api_started"
+	codeAPIServeFailed   = "api_serve_failed"
+	codeAPIHandlerFailed = "api_handler_failed"
+)
+
+const (
+	apiReadHeaderTimeout = 5 * time.Second
+	apiReadTimeout       = 15 * time.Second
+	apiWriteTimeout      = 30 * time.Second
+	apiShutdownTimeout   = 10 * time.Second
+	// apiMaxBodyBytes bounds JSON request bodies.
+	apiMaxBodyBytes = 64 << 10
+)
+
+// NewRunner creates the REST API runner listening on addr (e.g. ":8080").
+func NewRunner(addr string) *Runner {
+	return &Runner{
+		addr: addr,
+		log:  logging.WithPackage(),
+	}
+}
+
+func (r *Runner) Name() string {
+	return "api"
+}
+
+// SetBotDeps injects bot dependencies into the Runner and returns the runner for chaining.
+func (r *Runner) SetBotDeps(deps *bot.BotDeps) *Runner {
+	r.botDeps = deps
+
+	return r
+}
+
+// Run serves HTTP until ctx is cancelled, then shuts the server down gracefully.
+func (r *Runner) Run(ctx context.Context) error {
+	const op = "api_runner.Run"
+
+	if r.addr == "" {
+		return validate.Wrap(op, ErrAddrNotSet)
+	}
+
+	h, err := r.Handler()
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	ln, err := net.Listen("tcp", r.addr)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	r.srv = &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: apiReadHeaderTimeout,
+		ReadTimeout:       apiReadTimeout,
+		WriteTimeout:      apiWriteTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	r.log.Info("api started", "code", codeAPIStarted, "addr", ln.Addr().String())
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- r.srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		r.log.Error("api serve error", "code", codeAPIServeFailed, "error", err)
+
+		return validate.Wrap(op, err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), apiShutdownTimeout)
+	defer cancel()
+
+	if err := r.srv.Shutdown(shutdownCtx); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Handler builds the HTTP routes. Exposed for tests and for embedding into another server.
+func (r *Runner) Handler() (http.Handler, error) {
+	const op = "api_runner.Handler"
+
+	if r.botDeps == nil {
+		return nil, validate.Wrap(op, ErrBotDepsNotSet)
+	}
+
+	if r.botDeps.Tokens == nil {
+		return nil, validate.Wrap(op, ErrTokensNotSet)
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /openapi.yaml", r.handleOpenAPI)
+
+	mux.Handle("POST /v1/incomes", r.auth(r.handleAddIncome))
+	mux.Handle("GET /v1/incomes", r.auth(r.handleListIncomes))
+	mux.Handle("POST /v1/incomes/undo", r.auth(r.handleUndoIncome))
+	mux.Handle("POST /v1/payments", r.auth(r.handleAddPayment))
+	mux.Handle("GET /v1/payments", r.auth(r.handleListPayments))
+	mux.Handle("POST /v1/payments/undo", r.auth(r.handleUndoPayment))
+	mux.Handle("GET /v1/totals", r.auth(r.handleTotals))
+	mux.Handle("GET /v1/export", r.auth(r.handleExport))
+
+	return mux, nil
+}
+
+// now returns the current UTC time from bot deps.
+func (r *Runner) now() time.Time {
+	if r.botDeps.Now != nil {
+		return r.botDeps.Now().UTC()
+	}
+
+	return time.Now().UTC()
+}
@@ -0,0 +1,71 @@
+package api_runner
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+)
+
+// Runner serves the REST API over the same usecases the bot uses.
+type Runner struct {
+	addr    string
+	log     *slog.Logger
+	botDeps *bot.BotDeps
+	srv     *http.Server
+}
+
+// ctxKey is the private type for request context values.
+type ctxKey int
+
+// entryRequest is the body of POST /v1/incomes and POST /v1/payments.
+type entryRequest struct {
+	Date   string `json:"date,omitempty"` // YYYY-MM-DD, defaults to today (UTC)
+	Amount int64  `json:"amount"`         // kopecks
+	Note   string `json:"note,omitempty"`
+	Type   string `json:"type,omitempty"` // payments only: contrib | advance
+}
+
+// undoRequest is the body of POST /v1/payments/undo.
+type undoRequest struct {
+	Type string `json:"type"`
+}
+
+type createdResponse struct {
+	ID int64 `json:"id"`
+}
+
+type entryResponse struct {
+	ID     int64  `json:"id,omitempty"`
+	Date   string `json:"date"`
+	Amount int64  `json:"amount"`
+	Note   string `json:"note,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+type undoResponse struct {
+	Undone bool           `json:"undone"`
+	Entry  *entryResponse `json:"entry,omitempty"`
+}
+
+type totalsResponse struct {
+	From           string `json:"from"`
+	To             string `json:"to"`
+	IncomeSum      int64  `json:"income_sum"`
+	Tax            int64  `json:"tax"`
+	ContribSum     int64  `json:"contrib_sum"`
+	AdvanceSum     int64  `json:"advance_sum"`
+	ContribApplied int64  `json:"contrib_applied"`
+	Due            int64  `json:"due"`
+	VATRateBP      int64  `json:"vat_rate_bp"`
+	VAT            int64  `json:"vat"`
+}
+
+type totalsPairResponse struct {
+	Quarter totalsResponse `json:"quarter"`
+	Year    totalsResponse `json:"year"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
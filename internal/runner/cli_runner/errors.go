@@ -0,0 +1,7 @@
+package cli_runner
+
+import "errors"
+
+var (
+	ErrBotDepsNotSet = errors.New("bot deps are not set")
+)
@@ -0,0 +1,168 @@
+package cli_runner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
+)
+
+const (
+	codeCLIDispatchFailed = "cli_dispatch_failed"
+)
+
+const (
+	// Transport is the user_identities.transport of CLI users.
+	Transport = domain.TransportCLI
+	// DefaultExternalID is the CLI user when none is given.
+	DefaultExternalID = "local"
+
+	prompt = "> "
+	// maxLineBytes bounds a single input line.
+	maxLineBytes = 64 << 10
+)
+
+// NewRunner creates a CLI runner reading commands from in and writing replies to out.
+// By default it runs in script mode: no prompt, each command is echoed before its reply.
+func NewRunner(in io.Reader, out io.Writer) *Runner {
+	return &Runner{
+		in:         in,
+		out:        out,
+		log:        logging.WithPackage(),
+		externalID: DefaultExternalID,
+	}
+}
+
+func (r *Runner) Name() string {
+	return "cli"
+}
+
+// SetBotDeps injects bot dependencies into the Runner and returns the runner for chaining.
+func (r *Runner) SetBotDeps(deps *bot.BotDeps) *Runner {
+	r.botDeps = deps
+
+	return r
+}
+
+// SetExternalID selects the CLI user (maps to user_identities as transport "cli").
+func (r *Runner) SetExternalID(externalID string) *Runner {
+	if externalID != "" {
+		r.externalID = externalID
+	}
+
+	return r
+}
+
+// SetLanguage sets the reply language used until the user picks one with /lang,
+// e.g. from $LANG. Unsupported values are ignored.
+func (r *Runner) SetLanguage(tag string) *Runner {
+	if lang, ok := i18n.Parse(tag); ok {
+		r.lang = lang
+	}
+
+	return r
+}
+
+// SetInteractive switches between REPL mode (prompt, no echo) and script mode.
+func (r *Runner) SetInteractive(interactive bool) *Runner {
+	r.interactive = interactive
+
+	return r
+}
+
+// Run processes input line by line until EOF, "exit"/"quit" or ctx cancellation.
+// Empty lines and lines starting with '#' are skipped; the leading '/' is optional.
+func (r *Runner) Run(ctx context.Context) error {
+	const op = "cli_runner.Run"
+
+	if r.botDeps == nil {
+		return validate.Wrap(op, ErrBotDepsNotSet)
+	}
+
+	if r.interactive {
+		fmt.Fprintln(r.out, "ip_accounting_bot CLI. Type /help for commands, exit to quit.")
+	}
+
+	sc := bufio.NewScanner(r.in)
+	sc.Buffer(make([]byte, 0, 4096), maxLineBytes)
+
+	for {
+		if r.interactive {
+			fmt.Fprint(r.out, prompt)
+		}
+
+		if !sc.Scan() {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		line := strings.TrimSpace(sc.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if line == "exit" || line == "quit" {
+			return nil
+		}
+
+		if !strings.HasPrefix(line, "/") {
+			line = "/" + line
+		}
+
+		if !r.interactive {
+			fmt.Fprintln(r.out, prompt+line)
+		}
+
+		fmt.Fprintln(r.out, plainText(r.dispatch(ctx, line)))
+
+		if !r.interactive {
+			fmt.Fprintln(r.out)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if r.interactive {
+		fmt.Fprintln(r.out)
+	}
+
+	return nil
+}
+
+// dispatch runs a single command and returns the reply the user would see in Telegram.
+func (r *Runner) dispatch(ctx context.Context, line string) string {
+	ctx = bot.ResolveLang(ctx, r.botDeps, Transport, r.externalID, r.lang)
+	lang := i18n.FromContext(ctx)
+
+	reply, handled, err := bot.DispatchCommand(ctx, line, "", Transport, r.externalID, r.botDeps)
+
+	if !handled {
+		return bot.UnknownCommandText(lang)
+	}
+
+	if err != nil {
+		msg := bot.ReplyForError(lang, err)
+
+		// Input errors are answered with a hint; anything else is worth a warning on stderr.
+		if msg == bot.ErrorText(lang) {
+			r.log.Warn("command failed", "code", codeCLIDispatchFailed, "line", line, "error", err)
+		}
+
+		return msg
+	}
+
+	return reply
+}
@@ -0,0 +1,63 @@
+package cli_runner_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	clirunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/cli_runner"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+func fixedNow() time.Time {
+	return time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+}
+
+func TestRun_ScriptMode(t *testing.T) {
+	t.Parallel()
+
+	store := memstore.NewStore()
+	income := service.NewIncomeService(store)
+	payment := service.NewPaymentService(store)
+	total := service.NewTotalService(store.GetUserScheme, income.SumIncomes, payment.SumPayments, tax.NewDefaultProvider())
+	deps := bot.NewBotDeps(store, income, payment, total, nil, nil, nil, nil, fixedNow)
+
+	script := strings.Join([]string{
+		"# comment lines and blanks are skipped",
+		"",
+		"/add 1000 заказ",
+		"add_contrib 100",
+		"/add abc",
+		"/total",
+	}, "\n")
+
+	var out bytes.Buffer
+
+	err := clirunner.NewRunner(strings.NewReader(script), &out).SetBotDeps(deps).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	got := out.String()
+
+	for _, want := range []string{
+		"> /add 1000 заказ\n" + bot.AddSuccessText(i18n.RU, 100000, fixedNow(), "заказ") + "\n",
+		"> /add_contrib 100\n",
+		"> /add abc\n" + bot.BadAmountHintText(i18n.RU) + "\n",
+		"💰 Поступления: 1\u00a0000,00\u00a0₽",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "<b>") || strings.Contains(got, "# comment") {
+		t.Errorf("unexpected markup or comment in output:\n%s", got)
+	}
+}
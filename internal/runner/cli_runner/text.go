@@ -0,0 +1,26 @@
+package cli_runner
+
+import (
+	"html"
+	"strings"
+)
+
+// plainText strips the HTML markup used by bot replies (parse_mode=HTML) for terminal output.
+func plainText(s string) string {
+	var b strings.Builder
+
+	inTag := false
+
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+
+	return html.UnescapeString(b.String())
+}
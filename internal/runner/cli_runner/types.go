@@ -0,0 +1,20 @@
+package cli_runner
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+)
+
+// Runner feeds lines from an input stream into bot.DispatchCommand and prints replies.
+type Runner struct {
+	in          io.Reader
+	out         io.Writer
+	log         *slog.Logger
+	botDeps     *bot.BotDeps
+	externalID  string
+	lang        i18n.Lang // language until /lang; "" means i18n.Default
+	interactive bool
+}
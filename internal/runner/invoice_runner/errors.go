@@ -0,0 +1,5 @@
+package invoice_runner
+
+import "errors"
+
+var ErrRemindersNotSet = errors.New("reminders are not set")
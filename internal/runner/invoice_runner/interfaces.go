@@ -0,0 +1,18 @@
+package invoice_runner
+
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+)
+
+// Reminders finds the overdue invoices to remind of (service.InvoiceService).
+type Reminders interface {
+	OverdueInvoices(ctx context.Context) ([]domain.Invoice, error)
+	MarkReminded(ctx context.Context, invoiceID int64) error
+}
+
+// Notifier reminds the user of an overdue invoice (e.g. a Telegram message).
+type Notifier interface {
+	NotifyOverdue(ctx context.Context, inv domain.Invoice) error
+}
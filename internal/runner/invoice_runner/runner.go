@@ -0,0 +1,107 @@
+package invoice_runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
+)
+
+const (
+	codeInvoiceRemindersStarted = "invoice_reminders_started"
+	codeInvoiceOverdue          = "invoice_overdue"
+	codeInvoiceOverdueFailed    = "invoice_overdue_failed"
+	codeInvoiceNotifyFailed     = "invoice_notify_failed"
+	codeInvoiceMarkFailed       = "invoice_mark_failed"
+)
+
+// DefaultInterval is how often overdue invoices are looked for. Reminders are at most
+// daily (domain.InvoiceRemindDays apart), so an hour only bounds how late one comes.
+const DefaultInterval = time.Hour
+
+// NewRunner creates the runner of reminders.
+func NewRunner(reminders Reminders) *Runner {
+	return &Runner{
+		reminders: reminders,
+		interval:  DefaultInterval,
+		log:       logging.WithPackage(),
+	}
+}
+
+func (r *Runner) Name() string {
+	return "invoices"
+}
+
+// SetInterval sets how often overdue invoices are looked for (DefaultInterval if not positive)
+// and returns the runner for chaining.
+func (r *Runner) SetInterval(d time.Duration) *Runner {
+	if d <= 0 {
+		d = DefaultInterval
+	}
+
+	r.interval = d
+
+	return r
+}
+
+// SetNotifier sets who reminds the user and returns the runner for chaining.
+// Without a notifier overdue invoices are only logged.
+func (r *Runner) SetNotifier(n Notifier) *Runner {
+	r.notifier = n
+
+	return r
+}
+
+// Run reminds of overdue invoices, then checks every interval until ctx is cancelled.
+// A failed pass is logged and retried at the next tick.
+func (r *Runner) Run(ctx context.Context) error {
+	const op = "invoice_runner.Run"
+
+	if r.reminders == nil {
+		return validate.Wrap(op, ErrRemindersNotSet)
+	}
+
+	r.log.Info("invoice reminders started", "code", codeInvoiceRemindersStarted, "interval", r.interval.String())
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce reminds of every overdue invoice due a reminder. An invoice is marked as
+// reminded only once the notifier succeeds, so a failed reminder is tried again.
+func (r *Runner) RunOnce(ctx context.Context) {
+	invoices, err := r.reminders.OverdueInvoices(ctx)
+	if err != nil {
+		r.log.Error("overdue invoices failed", "code", codeInvoiceOverdueFailed, "error", err)
+		return
+	}
+
+	for _, inv := range invoices {
+		r.log.Info("overdue invoice", "code", codeInvoiceOverdue,
+			"invoice_id", inv.ID, "user_id", inv.UserID, "due", inv.DueOn.Format(time.DateOnly))
+
+		if r.notifier == nil {
+			continue
+		}
+
+		if err := r.notifier.NotifyOverdue(ctx, inv); err != nil {
+			r.log.Warn("invoice notify failed", "code", codeInvoiceNotifyFailed, "invoice_id", inv.ID, "error", err)
+			continue
+		}
+
+		if err := r.reminders.MarkReminded(ctx, inv.ID); err != nil {
+			r.log.Warn("invoice mark reminded failed", "code", codeInvoiceMarkFailed, "invoice_id", inv.ID, "error", err)
+		}
+	}
+}
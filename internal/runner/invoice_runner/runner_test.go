@@ -0,0 +1,103 @@
+package invoice_runner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	invoicerunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/invoice_runner"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+)
+
+type notifier struct {
+	invoices []domain.Invoice
+	fail     bool
+}
+
+func (n *notifier) NotifyOverdue(ctx context.Context, inv domain.Invoice) error {
+	if n.fail {
+		return errors.New("telegram is down")
+	}
+
+	n.invoices = append(n.invoices, inv)
+	return nil
+}
+
+func TestRunner_RemindsWeekly(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memstore.NewStore()
+
+	uid, err := store.UpsertIdentity(ctx, domain.TransportTelegram, "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	invoices := service.NewInvoiceService(store, func() time.Time { return now })
+
+	if _, err := invoices.SetRequisites(ctx, uid, domain.Requisites{
+		Name: "IP Ivanov", INN: "500100732259", Bank: "Sberbank", BIK: "044525225", Account: "40802810100000000001",
+	}); err != nil {
+		t.Fatalf("SetRequisites: %v", err)
+	}
+
+	client, err := invoices.AddClient(ctx, uid, domain.Counterparty{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("AddClient: %v", err)
+	}
+
+	inv, err := invoices.IssueInvoice(ctx, uid, domain.Invoice{Client: client, Amount: 1000_00})
+	if err != nil {
+		t.Fatalf("IssueInvoice: %v", err)
+	}
+
+	n := &notifier{}
+	r := invoicerunner.NewRunner(invoices).SetNotifier(n)
+
+	// Due on Mar 15: not overdue until the 16th.
+	now = time.Date(2025, 3, 15, 9, 0, 0, 0, time.UTC)
+	r.RunOnce(ctx)
+
+	if len(n.invoices) != 0 {
+		t.Fatalf("reminded of %d invoices before the due date", len(n.invoices))
+	}
+
+	// A failed reminder is tried again at the next pass.
+	now = time.Date(2025, 3, 16, 9, 0, 0, 0, time.UTC)
+	n.fail = true
+	r.RunOnce(ctx)
+	n.fail = false
+	r.RunOnce(ctx)
+	r.RunOnce(ctx)
+
+	if len(n.invoices) != 1 || n.invoices[0].ID != inv.ID || n.invoices[0].Client.Name != "Acme" {
+		t.Fatalf("reminders = %+v, want one of invoice %d", n.invoices, inv.ID)
+	}
+
+	// The next reminder comes a week later.
+	now = now.AddDate(0, 0, domain.InvoiceRemindDays-1)
+	r.RunOnce(ctx)
+	now = now.AddDate(0, 0, 1)
+	r.RunOnce(ctx)
+
+	if len(n.invoices) != 2 {
+		t.Fatalf("reminded %d times, want 2 after a week", len(n.invoices))
+	}
+
+	// Paid invoices are not reminded of.
+	if _, _, err := invoices.PayInvoice(ctx, uid, inv.ID, now, 0, "paid"); err != nil {
+		t.Fatalf("PayInvoice: %v", err)
+	}
+
+	now = now.AddDate(0, 0, domain.InvoiceRemindDays)
+	r.RunOnce(ctx)
+
+	if len(n.invoices) != 2 {
+		t.Fatalf("reminded %d times, want no reminder after the payment", len(n.invoices))
+	}
+}
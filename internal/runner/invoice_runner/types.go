@@ -0,0 +1,14 @@
+package invoice_runner
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Runner reminds of overdue invoices on start and then every interval.
+type Runner struct {
+	reminders Reminders
+	notifier  Notifier
+	interval  time.Duration
+	log       *slog.Logger
+}
@@ -0,0 +1,8 @@
+package metrics_runner
+
+import "errors"
+
+var (
+	ErrMetricsNotSet = errors.New("metrics are not set")
+	ErrAddrNotSet    = errors.New("listen address is not set")
+)
@@ -0,0 +1,158 @@
+package metrics_runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/metrics"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
+)
+
+const (
+	codeMetricsStarted     = "metrics_started"
+	codeMetricsServeFailed = "metrics_serve_failed"
+	codeMetricsNotReady    = "metrics_not_ready"
+)
+
+const (
+	metricsReadHeaderTimeout = 5 * time.Second
+	metricsWriteTimeout      = 10 * time.Second
+	metricsShutdownTimeout   = 5 * time.Second
+	// readyCheckTimeout bounds all readiness checks of one /readyz request.
+	readyCheckTimeout = 3 * time.Second
+)
+
+// NewRunner creates the metrics runner listening on addr (e.g. ":9090").
+func NewRunner(addr string) *Runner {
+	return &Runner{
+		addr: addr,
+		log:  logging.WithPackage(),
+	}
+}
+
+func (r *Runner) Name() string {
+	return "metrics"
+}
+
+// SetMetrics sets the metrics served on /metrics and returns the runner for chaining.
+func (r *Runner) SetMetrics(m *metrics.Metrics) *Runner {
+	r.metrics = m
+
+	return r
+}
+
+// AddReadyCheck adds a dependency probed by /readyz (e.g. a DB ping) and returns the runner for chaining.
+func (r *Runner) AddReadyCheck(name string, check func(ctx context.Context) error) *Runner {
+	r.checks = append(r.checks, readyCheck{name: name, check: check})
+
+	return r
+}
+
+// Run serves HTTP until ctx is cancelled, then shuts the server down gracefully.
+func (r *Runner) Run(ctx context.Context) error {
+	const op = "metrics_runner.Run"
+
+	if r.addr == "" {
+		return validate.Wrap(op, ErrAddrNotSet)
+	}
+
+	h, err := r.Handler()
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	ln, err := net.Listen("tcp", r.addr)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	r.srv = &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: metricsReadHeaderTimeout,
+		WriteTimeout:      metricsWriteTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	r.log.Info("metrics started", "code", codeMetricsStarted, "addr", ln.Addr().String())
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- r.srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		r.log.Error("metrics serve error", "code", codeMetricsServeFailed, "error", err)
+
+		return validate.Wrap(op, err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metricsShutdownTimeout)
+	defer cancel()
+
+	if err := r.srv.Shutdown(shutdownCtx); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Handler builds the HTTP routes. Exposed for tests and for embedding into another server.
+func (r *Runner) Handler() (http.Handler, error) {
+	const op = "metrics_runner.Handler"
+
+	if r.metrics == nil {
+		return nil, validate.Wrap(op, ErrMetricsNotSet)
+	}
+
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /metrics", r.metrics.Registry().Handler())
+	mux.HandleFunc("GET /healthz", r.handleHealthz)
+	mux.HandleFunc("GET /readyz", r.handleReadyz)
+
+	return mux, nil
+}
+
+// handleHealthz reports that the process is alive; it checks no dependencies.
+func (r *Runner) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz runs every readiness check and answers 503 listing the failed ones.
+func (r *Runner) handleReadyz(w http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithTimeout(req.Context(), readyCheckTimeout)
+	defer cancel()
+
+	var failed []string
+
+	for _, c := range r.checks {
+		if err := c.check(ctx); err != nil {
+			r.log.Warn("not ready", "code", codeMetricsNotReady, "check", c.name, "error", err)
+			failed = append(failed, c.name+": "+err.Error())
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if len(failed) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, strings.Join(failed, "\n"))
+		return
+	}
+
+	fmt.Fprintln(w, "ok")
+}
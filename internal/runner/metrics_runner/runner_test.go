@@ -0,0 +1,107 @@
+package metrics_runner_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/metrics"
+	metricsrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/metrics_runner"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+)
+
+func get(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	body, _ := io.ReadAll(rec.Body)
+
+	return rec.Code, string(body)
+}
+
+func TestHandler_Probes(t *testing.T) {
+	t.Parallel()
+
+	dbErr := errors.New("connection refused")
+	dbDown := true
+
+	m := metrics.New()
+	h, err := metricsrunner.NewRunner(":0").
+		SetMetrics(m).
+		AddReadyCheck("db", func(context.Context) error {
+			if dbDown {
+				return dbErr
+			}
+			return nil
+		}).
+		AddReadyCheck("telegram", m.PollReady(time.Minute)).
+		Handler()
+	if err != nil {
+		t.Fatalf("Handler: %v", err)
+	}
+
+	if code, _ := get(t, h, "/healthz"); code != http.StatusOK {
+		t.Fatalf("/healthz = %d", code)
+	}
+
+	code, body := get(t, h, "/readyz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "db: connection refused") || !strings.Contains(body, "telegram:") {
+		t.Fatalf("/readyz = %d %q", code, body)
+	}
+
+	dbDown = false
+	m.ObservePoll(nil, 0)
+
+	if code, body := get(t, h, "/readyz"); code != http.StatusOK {
+		t.Fatalf("/readyz when ready = %d %q", code, body)
+	}
+}
+
+func TestHandler_MetricsCountCommands(t *testing.T) {
+	t.Parallel()
+
+	store := memstore.NewStore()
+	income := service.NewIncomeService(store)
+	payment := service.NewPaymentService(store)
+	total := service.NewTotalService(store.GetUserScheme, income.SumIncomes, payment.SumPayments, tax.NewDefaultProvider())
+
+	m := metrics.New()
+	deps := bot.NewBotDeps(store, income, payment, total, nil, nil, nil, nil, nil)
+	deps.Observer = m
+
+	ctx := context.Background()
+	for _, text := range []string{"/add 100", "/add 200", "/add abc", "/frobnicate"} {
+		_, _, _ = bot.DispatchCommand(ctx, text, "", "cli", "local", deps)
+	}
+
+	h, err := metricsrunner.NewRunner(":0").SetMetrics(m).Handler()
+	if err != nil {
+		t.Fatalf("Handler: %v", err)
+	}
+
+	code, body := get(t, h, "/metrics")
+	if code != http.StatusOK {
+		t.Fatalf("/metrics = %d", code)
+	}
+
+	for _, want := range []string{
+		`ipbot_commands_total{command="add",outcome="ok"} 2`,
+		`ipbot_commands_total{command="add",outcome="bad_input"} 1`,
+		`ipbot_commands_total{command="unknown",outcome="bad_input"} 1`,
+		`ipbot_command_duration_seconds_count{command="add"} 3`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package metrics_runner
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/metrics"
+)
+
+// Runner serves Prometheus metrics and liveness/readiness probes.
+type Runner struct {
+	addr    string
+	log     *slog.Logger
+	metrics *metrics.Metrics
+	checks  []readyCheck
+	srv     *http.Server
+}
+
+// readyCheck is one named dependency probed by /readyz.
+type readyCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
@@ -0,0 +1,5 @@
+package recurring_runner
+
+import "errors"
+
+var ErrSchedulerNotSet = errors.New("scheduler is not set")
@@ -0,0 +1,17 @@
+package recurring_runner
+
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+)
+
+// Scheduler runs the occurrences due up to today (service.RecurringService).
+type Scheduler interface {
+	RunDue(ctx context.Context) ([]domain.RecurringRun, error)
+}
+
+// Notifier tells the user about an occurrence the scheduler ran (e.g. a Telegram message).
+type Notifier interface {
+	NotifyRecurring(ctx context.Context, run domain.RecurringRun) error
+}
@@ -0,0 +1,99 @@
+package recurring_runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
+)
+
+const (
+	codeRecurringStarted      = "recurring_started"
+	codeRecurringRun          = "recurring_run"
+	codeRecurringRunFailed    = "recurring_run_failed"
+	codeRecurringNotifyFailed = "recurring_notify_failed"
+)
+
+// DefaultInterval is how often due rules are checked. Occurrences are per day, so an
+// hour only bounds how late after midnight (UTC) an income is added.
+const DefaultInterval = time.Hour
+
+// NewRunner creates the runner of scheduler.
+func NewRunner(scheduler Scheduler) *Runner {
+	return &Runner{
+		scheduler: scheduler,
+		interval:  DefaultInterval,
+		log:       logging.WithPackage(),
+	}
+}
+
+func (r *Runner) Name() string {
+	return "recurring"
+}
+
+// SetInterval sets how often due rules are checked (DefaultInterval if not positive) and returns the runner for chaining.
+func (r *Runner) SetInterval(d time.Duration) *Runner {
+	if d <= 0 {
+		d = DefaultInterval
+	}
+
+	r.interval = d
+
+	return r
+}
+
+// SetNotifier sets who is told about each occurrence and returns the runner for chaining.
+// Without a notifier incomes are still added, only logged.
+func (r *Runner) SetNotifier(n Notifier) *Runner {
+	r.notifier = n
+
+	return r
+}
+
+// Run catches up the occurrences missed while the bot was down, then checks every interval
+// until ctx is cancelled. A failed pass is logged and retried at the next tick.
+func (r *Runner) Run(ctx context.Context) error {
+	const op = "recurring_runner.Run"
+
+	if r.scheduler == nil {
+		return validate.Wrap(op, ErrSchedulerNotSet)
+	}
+
+	r.log.Info("recurring started", "code", codeRecurringStarted, "interval", r.interval.String())
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs the due occurrences and notifies about each of them.
+func (r *Runner) RunOnce(ctx context.Context) {
+	runs, err := r.scheduler.RunDue(ctx)
+	if err != nil {
+		// Runs of the other rules went through; report them anyway.
+		r.log.Error("recurring run failed", "code", codeRecurringRunFailed, "error", err)
+	}
+
+	for _, run := range runs {
+		r.log.Info("recurring income", "code", codeRecurringRun,
+			"rule_id", run.RuleID, "user_id", run.UserID, "due", run.Due.Format(time.DateOnly), "status", string(run.Status))
+
+		if r.notifier == nil {
+			continue
+		}
+
+		if err := r.notifier.NotifyRecurring(ctx, run); err != nil {
+			r.log.Warn("recurring notify failed", "code", codeRecurringNotifyFailed, "rule_id", run.RuleID, "error", err)
+		}
+	}
+}
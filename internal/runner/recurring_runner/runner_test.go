@@ -0,0 +1,85 @@
+package recurring_runner_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	recurringrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/recurring_runner"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+)
+
+type notifier struct {
+	mu   sync.Mutex
+	runs []domain.RecurringRun
+}
+
+func (n *notifier) NotifyRecurring(ctx context.Context, run domain.RecurringRun) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.runs = append(n.runs, run)
+
+	return nil
+}
+
+func (n *notifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return len(n.runs)
+}
+
+func TestRunner_CatchesUpOnStart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memstore.NewStore()
+
+	uid, err := store.UpsertIdentity(ctx, domain.TransportCLI, "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recurring := service.NewRecurringService(store, func() time.Time { return now })
+
+	if _, err := recurring.AddRule(ctx, uid, domain.RecurringRule{Amount: 100, Period: domain.RecurringMonthly, Day: 31}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+
+	// Jan 31, Feb 28 and Mar 31 were missed.
+	now = time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	n := &notifier{}
+	r := recurringrunner.NewRunner(recurring).SetNotifier(n).SetInterval(time.Millisecond)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+
+	go func() { done <- r.Run(runCtx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for n.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Later ticks find nothing new.
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if got := n.count(); got != 3 {
+		t.Fatalf("notified %d runs, want 3", got)
+	}
+
+	if !n.runs[1].Due.Equal(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)) || n.runs[1].IncomeID == 0 {
+		t.Errorf("second run = %+v, want an income on Feb 28", n.runs[1])
+	}
+}
@@ -0,0 +1,14 @@
+package recurring_runner
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Runner runs due recurring incomes on start and then every interval.
+type Runner struct {
+	scheduler Scheduler
+	notifier  Notifier
+	interval  time.Duration
+	log       *slog.Logger
+}
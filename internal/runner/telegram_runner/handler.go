@@ -5,8 +5,11 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
 	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
@@ -25,56 +28,99 @@ func HandleTelegramUpdate(
 ) error {
 	op := "telegram.HandleTelegramUpdate"
 
-	if upd.Message == nil {
+	msg, edited := upd.Message, false
+	if msg == nil && upd.EditedMessage != nil {
+		msg, edited = upd.EditedMessage, true
+	}
+
+	if msg == nil {
 		return nil
 	}
 
-	text := strings.TrimSpace(upd.Message.Text)
+	text := strings.TrimSpace(msg.Text)
 
 	if text == "" {
 		return nil
 	}
 
-	chatID := upd.Message.Chat.ID
-	externalID := strconv.FormatInt(upd.Message.From.ID, 10)
+	chatID := msg.Chat.ID
+	externalID := strconv.FormatInt(msg.From.ID, 10)
 
 	self = NormalizeSelf(self)
 
-	reply, handled, err := bot.DispatchCommand(ctx, text, self, "telegram", externalID, botDeps)
+	// Entries created by this message are linked to it, so that editing the message edits them.
+	ref := domain.MessageRef{Transport: domain.TransportTelegram, Key: messageKey(chatID, msg.MessageID)}
+	if msg.Date > 0 {
+		ref.SentAt = time.Unix(msg.Date, 0).UTC()
+	}
+	ctx = domain.WithMessageRef(ctx, ref)
+	// Commands that notify the user later (/recurring) store the chat along with the identity.
+	ctx = domain.WithChatID(ctx, chatID)
+
+	// Reply in the language chosen with /lang, else in the user's Telegram language.
+	hint, _ := i18n.Parse(msg.From.LanguageCode)
+	ctx = bot.ResolveLang(ctx, botDeps, domain.TransportTelegram, externalID, hint)
+
+	if edited {
+		reply, handled, err := bot.DispatchEdit(ctx, text, self, domain.TransportTelegram, externalID, botDeps)
+		if !handled {
+			// Edits of anything but entry commands are not worth a reply.
+			return nil
+		}
+
+		return sendResult(ctx, op, sender, chatID, reply, err)
+	}
+
+	// Commands that draw (/chart) reply with a photo captioned with their text.
+	ctx, photo := bot.WithPhotoSlot(ctx)
+	// Commands that issue paperwork (/invoice) reply with files, the last captioned with their text.
+	ctx, documents := bot.WithDocumentSlot(ctx)
+
+	reply, handled, err := bot.DispatchCommand(ctx, text, self, domain.TransportTelegram, externalID, botDeps)
 
 	if !handled {
-		if sendErr := sender.SendMessage(ctx, chatID, bot.UnknownCommandText()); sendErr != nil {
+		if sendErr := sender.SendMessage(ctx, chatID, bot.UnknownCommandText(i18n.FromContext(ctx))); sendErr != nil {
 			return validate.Wrap(op, sendErr)
 		}
 
 		return nil
 	}
 
-	if err != nil {
-		if errors.Is(err, bot.ErrBadInput) {
-			if sendErr := sender.SendMessage(ctx, chatID, bot.BadAmountHintText()); sendErr != nil {
-				return validate.Wrap(op, sendErr)
-			}
-
-			return nil
+	if p, ok := photo(); ok && err == nil {
+		if err := sender.SendPhoto(ctx, chatID, p.Name, p.PNG, reply); err != nil {
+			return validate.Wrap(op, err)
 		}
 
-		if errors.Is(err, bot.ErrAmountIsZero) {
-			if sendErr := sender.SendMessage(ctx, chatID, bot.AmountIsZeroText()); sendErr != nil {
-				return validate.Wrap(op, sendErr)
+		return nil
+	}
+
+	if docs := documents(); len(docs) > 0 && err == nil {
+		for i, d := range docs {
+			caption := ""
+			if i == len(docs)-1 {
+				caption = reply
 			}
 
-			return nil
+			if err := sender.SendDocument(ctx, chatID, d.Name, d.Data, caption); err != nil {
+				return validate.Wrap(op, err)
+			}
 		}
 
-		if strings.Contains(err.Error(), "unknown command") {
-			if sendErr := sender.SendMessage(ctx, chatID, bot.UnknownCommandText()); sendErr != nil {
-				return validate.Wrap(op, sendErr)
-			}
+		return nil
+	}
 
+	return sendResult(ctx, op, sender, chatID, reply, err)
+}
+
+// sendResult replies with the dispatch result or the user-facing text for its error.
+func sendResult(ctx context.Context, op string, sender TelegramSender, chatID int64, reply string, err error) error {
+	if err != nil {
+		// Already applied by an earlier delivery of the same update: nothing to do or say.
+		if errors.Is(err, domain.ErrDuplicateUpdate) {
 			return nil
 		}
-		if sendErr := sender.SendMessage(ctx, chatID, bot.ErrorText()); sendErr != nil {
+
+		if sendErr := sender.SendMessage(ctx, chatID, bot.ReplyForError(i18n.FromContext(ctx), err)); sendErr != nil {
 			return validate.Wrap(op, sendErr)
 		}
 
@@ -87,3 +133,8 @@ func HandleTelegramUpdate(
 
 	return nil
 }
+
+// messageKey identifies a message for domain.MessageRef; message IDs are unique only within a chat.
+func messageKey(chatID, messageID int64) string {
+	return strconv.FormatInt(chatID, 10) + ":" + strconv.FormatInt(messageID, 10)
+}
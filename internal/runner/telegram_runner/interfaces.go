@@ -2,16 +2,40 @@ package telegram_runner
 
 import (
 	"context"
+	"time"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
 )
 
-// TelegramUpdateGetter defines the interface for getting Telegram updates
-type TelegramUpdateGetter interface {
-	GetUpdates(ctx context.Context, offset int64, timeoutSec int) ([]telegram.Update, error)
-}
-
 // TelegramSender defines the interface for sending Telegram messages
 type TelegramSender interface {
 	SendMessage(ctx context.Context, chatID int64, text string) error
+	// SendPhoto sends a PNG with an HTML caption.
+	SendPhoto(ctx context.Context, chatID int64, name string, png []byte, caption string) error
+	// SendDocument sends a file with an HTML caption.
+	SendDocument(ctx context.Context, chatID int64, name string, data []byte, caption string) error
+}
+
+// TelegramClient is the subset of the Bot API client used by Runner.
+type TelegramClient interface {
+	GetMe(ctx context.Context) (*telegram.User, error)
+	GetUpdates(ctx context.Context, p telegram.GetUpdatesParams) ([]telegram.Update, error)
+	SendMessage(ctx context.Context, p telegram.SendMessageParams) (*telegram.Message, error)
+	SendPhoto(ctx context.Context, p telegram.SendPhotoParams) (*telegram.Message, error)
+	SendDocument(ctx context.Context, p telegram.SendDocumentParams) (*telegram.Message, error)
+}
+
+// PollObserver is told about every getUpdates poll: its error, and the age of the
+// oldest new message in the batch (0 if there were none).
+type PollObserver interface {
+	ObservePoll(err error, lag time.Duration)
+}
+
+// UpdateStore persists the polling offset and processed update keys across restarts.
+type UpdateStore interface {
+	GetPollOffset(ctx context.Context, transport string) (int64, error)
+	SetPollOffset(ctx context.Context, transport string, offset int64) error
+	IsProcessed(ctx context.Context, transport, key string) (bool, error)
+	MarkProcessed(ctx context.Context, transport, key string) error
+	PruneProcessed(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,80 @@
+package telegram_runner
+
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// SetChatStore sets where chats and languages of users are looked up for messages the bot
+// sends on its own (recurring incomes, invoice reminders) and returns the runner for chaining.
+func (r *Runner) SetChatStore(store domain.ChatStore) *Runner {
+	r.chats = store
+
+	return r
+}
+
+// NotifyRecurring tells the user about an income added by the scheduler, or asks to confirm it.
+// Users without a known Telegram chat (e.g. who set the rule up from the CLI) are skipped.
+func (r *Runner) NotifyRecurring(ctx context.Context, run domain.RecurringRun) error {
+	const op = "telegram_runner.NotifyRecurring"
+
+	if r.chats == nil {
+		return nil
+	}
+
+	chatID, ok, err := r.chats.GetTelegramChatID(ctx, run.UserID)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if err := r.SendMessage(ctx, chatID, bot.RecurringRunText(r.userLang(ctx, run.UserID), run)); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// NotifyOverdue reminds the user of an invoice that is past due. Users without a known
+// Telegram chat are skipped.
+func (r *Runner) NotifyOverdue(ctx context.Context, inv domain.Invoice) error {
+	const op = "telegram_runner.NotifyOverdue"
+
+	if r.chats == nil {
+		return nil
+	}
+
+	chatID, ok, err := r.chats.GetTelegramChatID(ctx, inv.UserID)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if err := r.SendMessage(ctx, chatID, bot.InvoiceReminderText(r.userLang(ctx, inv.UserID), inv)); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// userLang returns the language of messages the bot sends on its own. There is no Telegram
+// language hint here: the language chosen with /lang, else the default.
+func (r *Runner) userLang(ctx context.Context, userID int64) i18n.Lang {
+	if stored, err := r.chats.GetUserLang(ctx, userID); err == nil {
+		if l, ok := i18n.Parse(stored); ok {
+			return l
+		}
+	}
+
+	return i18n.Default
+}
@@ -0,0 +1,89 @@
+package telegram_runner
+
+// NewOffsetTracker creates a tracker for getUpdates offsets with parallel processing,
+// starting from a previously confirmed offset (0 if none).
+func NewOffsetTracker(start int64) *OffsetTracker {
+	return &OffsetTracker{
+		inflight: make(map[int64]struct{}),
+		done:     make(map[int64]struct{}),
+		next:     start,
+	}
+}
+
+// Begin registers an update for processing. It returns false for updates that were
+// already seen: Telegram re-delivers everything at or after the confirmed offset,
+// including updates that are still in flight or finished out of order.
+func (t *OffsetTracker) Begin(updateID int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if updateID < t.offsetLocked() {
+		return false
+	}
+
+	if _, ok := t.inflight[updateID]; ok {
+		return false
+	}
+
+	if _, ok := t.done[updateID]; ok {
+		return false
+	}
+
+	t.inflight[updateID] = struct{}{}
+
+	if updateID >= t.next {
+		t.next = updateID + 1
+	}
+
+	return true
+}
+
+// Done marks an update as processed.
+func (t *OffsetTracker) Done(updateID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.inflight[updateID]; !ok {
+		return
+	}
+
+	delete(t.inflight, updateID)
+	t.done[updateID] = struct{}{}
+
+	// Forget finished updates that are now below the watermark.
+	low := t.offsetLocked()
+	for id := range t.done {
+		if id < low {
+			delete(t.done, id)
+		}
+	}
+}
+
+// Offset returns the offset to confirm with getUpdates: the oldest in-flight update,
+// or the one after the newest seen update when nothing is in flight.
+func (t *OffsetTracker) Offset() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.offsetLocked()
+}
+
+// InFlight returns the number of updates being processed.
+func (t *OffsetTracker) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.inflight)
+}
+
+func (t *OffsetTracker) offsetLocked() int64 {
+	low := t.next
+
+	for id := range t.inflight {
+		if id < low {
+			low = id
+		}
+	}
+
+	return low
+}
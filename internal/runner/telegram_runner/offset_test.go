@@ -0,0 +1,64 @@
+package telegram_runner_test
+
+import (
+	"testing"
+
+	tgrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/telegram_runner"
+)
+
+func TestOffsetTracker_WatermarkAndDedup(t *testing.T) {
+	t.Parallel()
+
+	tr := tgrunner.NewOffsetTracker(0)
+
+	if got := tr.Offset(); got != 0 {
+		t.Fatalf("initial offset = %d, want 0", got)
+	}
+
+	for _, id := range []int64{10, 11, 12} {
+		if !tr.Begin(id) {
+			t.Fatalf("Begin(%d) = false, want true", id)
+		}
+	}
+
+	// Out-of-order completion must not move the offset past the oldest in-flight update.
+	tr.Done(12)
+	tr.Done(11)
+
+	if got := tr.Offset(); got != 10 {
+		t.Fatalf("offset with 10 in flight = %d, want 10", got)
+	}
+
+	// Re-delivered updates are ignored, both in flight and finished.
+	for _, id := range []int64{10, 11, 12} {
+		if tr.Begin(id) {
+			t.Fatalf("Begin(%d) on re-delivery = true, want false", id)
+		}
+	}
+
+	tr.Done(10)
+
+	if got := tr.Offset(); got != 13 {
+		t.Fatalf("offset after all done = %d, want 13", got)
+	}
+
+	if got := tr.InFlight(); got != 0 {
+		t.Fatalf("InFlight = %d, want 0", got)
+	}
+
+	// Anything below the confirmed offset is old.
+	if tr.Begin(9) {
+		t.Fatal("Begin(9) = true, want false")
+	}
+
+	if !tr.Begin(13) {
+		t.Fatal("Begin(13) = false, want true")
+	}
+
+	// Done for an unknown update is a no-op.
+	tr.Done(100)
+
+	if got := tr.Offset(); got != 13 {
+		t.Fatalf("offset = %d, want 13", got)
+	}
+}
@@ -0,0 +1,63 @@
+package telegram_runner
+
+import (
+	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
+)
+
+// workerQueueSize is the per-worker backlog; a full queue blocks polling (backpressure).
+const workerQueueSize = 64
+
+// newWorkerPool starts n workers calling handle for every update of their shard.
+func newWorkerPool(n int, handle func(telegram.Update)) *workerPool {
+	if n < 1 {
+		n = 1
+	}
+
+	p := &workerPool{queues: make([]chan telegram.Update, n)}
+
+	p.wg.Add(n)
+
+	for i := range p.queues {
+		q := make(chan telegram.Update, workerQueueSize)
+		p.queues[i] = q
+
+		go func() {
+			defer p.wg.Done()
+
+			for u := range q {
+				handle(u)
+			}
+		}()
+	}
+
+	return p
+}
+
+// Submit enqueues the update on the worker that owns its chat.
+func (p *workerPool) Submit(u telegram.Update) {
+	p.queues[shardOf(u, len(p.queues))] <- u
+}
+
+// Close stops accepting updates and waits until every queued update is handled.
+func (p *workerPool) Close() {
+	for _, q := range p.queues {
+		close(q)
+	}
+
+	p.wg.Wait()
+}
+
+// shardOf maps an update to a worker by chat ID (falling back to the sender),
+// so that updates of one conversation are processed sequentially.
+func shardOf(u telegram.Update, n int) int {
+	var key int64
+
+	switch {
+	case u.Message != nil:
+		key = u.Message.Chat.ID
+	case u.EditedMessage != nil:
+		key = u.EditedMessage.Chat.ID
+	}
+
+	return int(uint64(key) % uint64(n))
+}
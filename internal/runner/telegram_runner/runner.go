@@ -3,9 +3,13 @@ package telegram_runner
 import (
 	"context"
 	"errors"
+	"strconv"
 	"time"
+	"unicode/utf16"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/render"
 	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
 	"github.com/tuor4eg/ip_accounting_bot/pkg/logging"
 )
@@ -16,12 +20,33 @@ const (
 	codeTGGetUpdatesFailed   = "tg_getupdates_failed"
 	codeTGSendFailed         = "tg_send_failed"
 	codeTGHandleUpdateFailed = "tg_handle_update_failed"
+	codeTGDrainTimeout       = "tg_drain_timeout"
+	codeTGUpdateStoreFailed  = "tg_update_store_failed"
+	codeTGUpdateDuplicate    = "tg_update_duplicate"
 )
 
-func NewRunner(tg *telegram.Client) *Runner {
+const (
+	tgGetUpdatesTimeoutSec = 30
+	tgPollReqTimeout       = 35 * time.Second
+	// tgSendTimeout leaves room for rate limiting and a 429 retry_after inside the client.
+	tgSendTimeout = 30 * time.Second
+	tgPingTimeout = 8 * time.Second
+	// tgInflightRepoll is how often getUpdates is re-polled while updates are in flight.
+	tgInflightRepoll = 200 * time.Millisecond
+	// tgDrainTimeout bounds how long shutdown waits for in-flight updates.
+	tgDrainTimeout = 15 * time.Second
+	// processedRetention is how long processed update keys are kept (Telegram keeps updates for 24h).
+	processedRetention = 72 * time.Hour
+)
+
+// DefaultWorkers is the number of parallel update workers when not configured.
+const DefaultWorkers = 4
+
+func NewRunner(tg TelegramClient) *Runner {
 	tgRunner := &Runner{
-		tg:  tg,
-		log: logging.WithPackage(),
+		tg:      tg,
+		log:     logging.WithPackage(),
+		workers: DefaultWorkers,
 	}
 
 	return tgRunner
@@ -38,17 +63,107 @@ func (r *Runner) SetBotDeps(deps *bot.BotDeps) *Runner {
 	return r
 }
 
+// SetWorkers sets how many updates are processed in parallel (at least 1) and returns the runner for chaining.
+// Updates of the same chat are always processed in order by a single worker.
+func (r *Runner) SetWorkers(n int) *Runner {
+	if n < 1 {
+		n = 1
+	}
+
+	r.workers = n
+
+	return r
+}
+
+// SetUpdateStore enables the persisted offset and processed-update tracking and returns the runner for chaining.
+// With a store, a redelivered update (e.g. after a crash) is skipped instead of being applied again.
+func (r *Runner) SetUpdateStore(store UpdateStore) *Runner {
+	r.updates = store
+
+	return r
+}
+
+// SetObserver sets where getUpdates polls are reported (e.g. metrics) and returns the runner for chaining.
+func (r *Runner) SetObserver(o PollObserver) *Runner {
+	r.obs = o
+
+	return r
+}
+
+// SendMessage sends a bot reply (HTML built with render), split into several messages
+// if it exceeds the Bot API length limit.
 func (r *Runner) SendMessage(ctx context.Context, chatID int64, text string) error {
 	sentCtx, cancel := context.WithTimeout(ctx, tgSendTimeout)
 	defer cancel()
 
-	_, err := r.tg.SendMessage(sentCtx, telegram.SendMessageParams{
+	for _, part := range render.Split(render.ModeHTML, text, render.MaxMessageLength) {
+		if _, err := r.tg.SendMessage(sentCtx, telegram.SendMessageParams{
+			ChatID:    chatID,
+			Text:      part,
+			ParseMode: string(render.ModeHTML),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendPhoto uploads a PNG with an HTML caption; a caption too long for a photo follows
+// as a separate message.
+func (r *Runner) SendPhoto(ctx context.Context, chatID int64, name string, png []byte, caption string) error {
+	sentCtx, cancel := context.WithTimeout(ctx, tgSendTimeout)
+	defer cancel()
+
+	photoCaption := caption
+	// Counted in UTF-16 code units, as Telegram does; markup makes this a safe overestimate.
+	if len(utf16.Encode([]rune(caption))) > render.MaxCaptionLength {
+		photoCaption = ""
+	}
+
+	if _, err := r.tg.SendPhoto(sentCtx, telegram.SendPhotoParams{
 		ChatID:    chatID,
-		Text:      text,
-		ParseMode: "HTML",
-	})
+		Photo:     png,
+		FileName:  name,
+		Caption:   photoCaption,
+		ParseMode: string(render.ModeHTML),
+	}); err != nil {
+		return err
+	}
 
-	return err
+	if photoCaption == "" && caption != "" {
+		return r.SendMessage(ctx, chatID, caption)
+	}
+
+	return nil
+}
+
+// SendDocument uploads a file with an HTML caption; a caption too long for a file follows
+// as a separate message.
+func (r *Runner) SendDocument(ctx context.Context, chatID int64, name string, data []byte, caption string) error {
+	sentCtx, cancel := context.WithTimeout(ctx, tgSendTimeout)
+	defer cancel()
+
+	fileCaption := caption
+	if len(utf16.Encode([]rune(caption))) > render.MaxCaptionLength {
+		fileCaption = ""
+	}
+
+	if _, err := r.tg.SendDocument(sentCtx, telegram.SendDocumentParams{
+		ChatID:    chatID,
+		Document:  data,
+		FileName:  name,
+		Caption:   fileCaption,
+		ParseMode: string(render.ModeHTML),
+	}); err != nil {
+		return err
+	}
+
+	if fileCaption == "" && caption != "" {
+		return r.SendMessage(ctx, chatID, caption)
+	}
+
+	return nil
 }
 
 func (r *Runner) Run(ctx context.Context) error {
@@ -66,26 +181,60 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	r.log.Info("bot started", "username", self, "id", me.ID)
 
-	var offset int64
+	start, err := r.loadOffset(ctx)
+	if err != nil {
+		return err
+	}
+
+	tracker := NewOffsetTracker(start)
+	saved := start
+
+	// Handlers are detached from ctx so that in-flight updates can finish on shutdown;
+	// cancelWork aborts them if draining takes longer than tgDrainTimeout.
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
 
-	for {
-		if ctx.Err() != nil {
-			return nil
+	// progress wakes the poll loop when an update is done and the offset may move.
+	progress := make(chan struct{}, 1)
+
+	pool := newWorkerPool(r.workers, func(u telegram.Update) {
+		r.processUpdate(workCtx, self, u)
+
+		tracker.Done(u.UpdateID)
+
+		select {
+		case progress <- struct{}{}:
+		default:
+		}
+	})
+
+	r.log.Info("workers started", "code", codeTGStarted, "workers", len(pool.queues))
+
+	for ctx.Err() == nil {
+		// Long-poll only when idle: while updates are in flight the confirmed offset stays
+		// behind them, so Telegram would return them again right away.
+		timeout := tgGetUpdatesTimeoutSec
+		if tracker.InFlight() > 0 {
+			timeout = 0
 		}
 
 		callCtx, cancel := context.WithTimeout(ctx, tgPollReqTimeout)
 
 		updates, err := r.tg.GetUpdates(callCtx, telegram.GetUpdatesParams{
-			Offset:         offset,
-			Timeout:        30,
-			AllowedUpdates: []string{"message"},
+			Offset:         tracker.Offset(),
+			Timeout:        timeout,
+			AllowedUpdates: []string{"message", "edited_message"},
 		})
 		cancel()
 
+		r.observePoll(updates, err)
+
 		if err != nil {
 			if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 				r.log.Error("getUpdates error", "code", codeTGGetUpdatesFailed, "error", err)
 
+				r.drain(pool, tracker, cancelWork)
+
 				return err
 			}
 
@@ -93,22 +242,156 @@ func (r *Runner) Run(ctx context.Context) error {
 			case <-time.After(75 * time.Millisecond):
 				continue
 			case <-ctx.Done():
-				return nil
+				continue
 			}
 		}
 
-		if len(updates) == 0 {
-			continue
-		}
-
 		for _, u := range updates {
-			if err := HandleTelegramUpdate(ctx, self, u, r, r.botDeps); err != nil {
-				r.log.Error("handle telegram update", "code", codeTGHandleUpdateFailed, "error", err)
+			if tracker.Begin(u.UpdateID) {
+				pool.Submit(u)
 			}
+		}
 
-			if u.UpdateID >= offset {
-				offset = u.UpdateID + 1
+		if tracker.InFlight() > 0 {
+			select {
+			case <-progress:
+			case <-time.After(tgInflightRepoll):
+			case <-ctx.Done():
 			}
 		}
+
+		saved = r.saveOffset(ctx, tracker.Offset(), saved)
+	}
+
+	r.drain(pool, tracker, cancelWork)
+
+	return nil
+}
+
+// drain waits for queued and in-flight updates (up to tgDrainTimeout), then confirms
+// the final offset so processed updates are not delivered again after restart.
+func (r *Runner) drain(pool *workerPool, tracker *OffsetTracker, cancelWork context.CancelFunc) {
+	r.log.Info("draining updates", "in_flight", tracker.InFlight())
+
+	done := make(chan struct{})
+
+	go func() {
+		pool.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(tgDrainTimeout):
+		r.log.Warn("drain timeout, cancelling handlers", "code", codeTGDrainTimeout, "in_flight", tracker.InFlight())
+		cancelWork()
+		<-done
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tgSendTimeout)
+	defer cancel()
+
+	r.saveOffset(ctx, tracker.Offset(), -1)
+
+	if _, err := r.tg.GetUpdates(ctx, telegram.GetUpdatesParams{
+		Offset: tracker.Offset(),
+		Limit:  1,
+	}); err != nil {
+		r.log.Warn("failed to confirm offset", "code", codeTGGetUpdatesFailed, "offset", tracker.Offset(), "error", err)
+	}
+}
+
+// observePoll reports a poll; a poll cut short by shutdown is not an error.
+func (r *Runner) observePoll(updates []telegram.Update, err error) {
+	if r.obs == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	var lag time.Duration
+
+	now := time.Now()
+	for _, u := range updates {
+		// Edited messages keep the original date, so only new messages tell the delay.
+		if u.Message == nil || u.Message.Date <= 0 {
+			continue
+		}
+
+		lag = max(lag, now.Sub(time.Unix(u.Message.Date, 0)))
+	}
+
+	r.obs.ObservePoll(err, lag)
+}
+
+// processUpdate handles one update at most once when an UpdateStore is set:
+// already processed updates are skipped, and ledger mutations record the update key
+// in the same transaction via the idempotency key in ctx.
+func (r *Runner) processUpdate(ctx context.Context, self string, u telegram.Update) {
+	key := updateKey(u.UpdateID)
+
+	if r.updates != nil {
+		done, err := r.updates.IsProcessed(ctx, domain.TransportTelegram, key)
+		if err != nil {
+			r.log.Error("check processed update", "code", codeTGUpdateStoreFailed, "update_id", u.UpdateID, "error", err)
+		}
+
+		if done {
+			r.log.Info("skip processed update", "code", codeTGUpdateDuplicate, "update_id", u.UpdateID)
+			return
+		}
+
+		ctx = domain.WithIdempotencyKey(ctx, domain.TransportTelegram, key)
+	}
+
+	if err := HandleTelegramUpdate(ctx, self, u, r, r.botDeps); err != nil {
+		r.log.Error("handle telegram update", "code", codeTGHandleUpdateFailed, "update_id", u.UpdateID, "error", err)
+	}
+
+	if r.updates != nil {
+		if err := r.updates.MarkProcessed(ctx, domain.TransportTelegram, key); err != nil {
+			r.log.Error("mark processed update", "code", codeTGUpdateStoreFailed, "update_id", u.UpdateID, "error", err)
+		}
+	}
+}
+
+// loadOffset returns the persisted offset and prunes old processed keys.
+func (r *Runner) loadOffset(ctx context.Context) (int64, error) {
+	if r.updates == nil {
+		return 0, nil
+	}
+
+	offset, err := r.updates.GetPollOffset(ctx, domain.TransportTelegram)
+	if err != nil {
+		r.log.Error("load poll offset", "code", codeTGUpdateStoreFailed, "error", err)
+		return 0, err
+	}
+
+	// Telegram keeps undelivered updates for 24 hours; older keys cannot be redelivered.
+	if n, err := r.updates.PruneProcessed(ctx, time.Now().Add(-processedRetention)); err != nil {
+		r.log.Warn("prune processed updates", "code", codeTGUpdateStoreFailed, "error", err)
+	} else if n > 0 {
+		r.log.Info("pruned processed updates", "count", n)
+	}
+
+	r.log.Info("poll offset loaded", "offset", offset)
+
+	return offset, nil
+}
+
+// saveOffset persists offset if it differs from saved and returns the new saved value.
+func (r *Runner) saveOffset(ctx context.Context, offset, saved int64) int64 {
+	if r.updates == nil || offset == saved {
+		return saved
+	}
+
+	if err := r.updates.SetPollOffset(ctx, domain.TransportTelegram, offset); err != nil {
+		r.log.Warn("save poll offset", "code", codeTGUpdateStoreFailed, "offset", offset, "error", err)
+		return saved
+	}
+
+	return offset
+}
+
+// updateKey is the processed_updates key of a Telegram update.
+func updateKey(updateID int64) string {
+	return "update:" + strconv.FormatInt(updateID, 10)
 }
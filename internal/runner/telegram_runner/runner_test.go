@@ -0,0 +1,450 @@
+package telegram_runner_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/i18n"
+	tgrunner "github.com/tuor4eg/ip_accounting_bot/internal/runner/telegram_runner"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
+)
+
+const testUserTG = 7
+
+// fakeTelegram mimics getUpdates: it returns every pending update with id >= offset
+// and forgets nothing, so a lower offset means redelivery.
+type fakeTelegram struct {
+	mu        sync.Mutex
+	updates   []telegram.Update
+	confirmed int64
+	sent      []telegram.SendMessageParams
+	photos    []telegram.SendPhotoParams
+	documents []telegram.SendDocumentParams
+}
+
+func (f *fakeTelegram) GetMe(ctx context.Context) (*telegram.User, error) {
+	return &telegram.User{ID: 1, IsBot: true, Username: "testbot"}, nil
+}
+
+func (f *fakeTelegram) GetUpdates(ctx context.Context, p telegram.GetUpdatesParams) ([]telegram.Update, error) {
+	f.mu.Lock()
+
+	if p.Offset > f.confirmed {
+		f.confirmed = p.Offset
+	}
+
+	var out []telegram.Update
+	for _, u := range f.updates {
+		if u.UpdateID >= p.Offset {
+			out = append(out, u)
+		}
+	}
+
+	f.mu.Unlock()
+
+	if len(out) == 0 && p.Timeout > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	return out, nil
+}
+
+func (f *fakeTelegram) SendMessage(ctx context.Context, p telegram.SendMessageParams) (*telegram.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent = append(f.sent, p)
+
+	return &telegram.Message{MessageID: int64(len(f.sent)), Chat: telegram.Chat{ID: p.ChatID}}, nil
+}
+
+func (f *fakeTelegram) SendPhoto(ctx context.Context, p telegram.SendPhotoParams) (*telegram.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.photos = append(f.photos, p)
+
+	return &telegram.Message{MessageID: int64(len(f.sent) + len(f.photos)), Chat: telegram.Chat{ID: p.ChatID}}, nil
+}
+
+func (f *fakeTelegram) SendDocument(ctx context.Context, p telegram.SendDocumentParams) (*telegram.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.documents = append(f.documents, p)
+
+	return &telegram.Message{MessageID: int64(len(f.sent) + len(f.photos) + len(f.documents)), Chat: telegram.Chat{ID: p.ChatID}}, nil
+}
+
+func (f *fakeTelegram) Confirmed() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.confirmed
+}
+
+func (f *fakeTelegram) Sent() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.sent)
+}
+
+// forgetfulOffsets loses the persisted offset, as if the process crashed before saving it.
+type forgetfulOffsets struct {
+	*memstore.Store
+}
+
+func (forgetfulOffsets) GetPollOffset(ctx context.Context, transport string) (int64, error) {
+	return 0, nil
+}
+
+func textUpdate(id int64, text string) telegram.Update {
+	return telegram.Update{
+		UpdateID: id,
+		Message: &telegram.Message{
+			MessageID: id,
+			Text:      text,
+			Chat:      telegram.Chat{ID: testUserTG, Type: "private"},
+			From:      &telegram.User{ID: testUserTG},
+		},
+	}
+}
+
+func fixedNow() time.Time {
+	return time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+}
+
+func newDeps(store *memstore.Store) *bot.BotDeps {
+	income := service.NewIncomeService(store)
+	payment := service.NewPaymentService(store)
+	total := service.NewTotalService(store.GetUserScheme, income.SumIncomes, payment.SumPayments, tax.NewDefaultProvider())
+
+	return bot.NewBotDeps(store, income, payment, total, nil, nil, store, store, fixedNow)
+}
+
+// runUntilConfirmed runs the runner until Telegram has seen the wanted offset, then shuts it down.
+func runUntilConfirmed(t *testing.T, r *tgrunner.Runner, tg *fakeTelegram, want int64) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx) }()
+
+	deadline := time.After(5 * time.Second)
+
+	for tg.Confirmed() < want {
+		select {
+		case err := <-errCh:
+			t.Fatalf("runner stopped early: %v", err)
+		case <-deadline:
+			t.Fatalf("offset %d was not confirmed, got %d", want, tg.Confirmed())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func incomeSum(t *testing.T, store *memstore.Store) int64 {
+	t.Helper()
+
+	ctx := context.Background()
+
+	userID, err := store.UpsertIdentity(ctx, domain.TransportTelegram, "7", 0)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	sum, err := store.SumIncomes(ctx, userID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("SumIncomes: %v", err)
+	}
+
+	return sum
+}
+
+func TestRunner_PersistsOffset(t *testing.T) {
+	t.Parallel()
+
+	store := memstore.NewStore()
+	tg := &fakeTelegram{updates: []telegram.Update{textUpdate(100, "/add 100"), textUpdate(101, "/add 200")}}
+
+	runUntilConfirmed(t, tgrunner.NewRunner(tg).SetBotDeps(newDeps(store)).SetUpdateStore(store), tg, 102)
+
+	if got := incomeSum(t, store); got != 30000 {
+		t.Fatalf("income sum = %d, want 30000", got)
+	}
+
+	offset, _ := store.GetPollOffset(context.Background(), domain.TransportTelegram)
+	if offset != 102 {
+		t.Fatalf("persisted offset = %d, want 102", offset)
+	}
+
+	// Restart: polling resumes from the persisted offset even if Telegram would redeliver.
+	tg2 := &fakeTelegram{updates: tg.updates}
+
+	runUntilConfirmed(t, tgrunner.NewRunner(tg2).SetBotDeps(newDeps(store)).SetUpdateStore(store), tg2, 102)
+
+	if got := incomeSum(t, store); got != 30000 {
+		t.Fatalf("income sum after restart = %d, want 30000", got)
+	}
+}
+
+func TestRunner_ReplayAfterLostOffset(t *testing.T) {
+	t.Parallel()
+
+	store := memstore.NewStore()
+	tg := &fakeTelegram{updates: []telegram.Update{textUpdate(100, "/add 100"), textUpdate(101, "/add 200")}}
+
+	runUntilConfirmed(t, tgrunner.NewRunner(tg).SetBotDeps(newDeps(store)).SetUpdateStore(store), tg, 102)
+
+	// Crash before the offset reached Telegram or the store: everything is redelivered,
+	// together with one new update.
+	tg2 := &fakeTelegram{updates: append(tg.updates, textUpdate(102, "/add 50"))}
+
+	runUntilConfirmed(t, tgrunner.NewRunner(tg2).SetBotDeps(newDeps(store)).SetUpdateStore(forgetfulOffsets{store}), tg2, 103)
+
+	if got := incomeSum(t, store); got != 35000 {
+		t.Fatalf("income sum = %d, want 35000", got)
+	}
+
+	if got := tg2.Sent(); got != 1 {
+		t.Fatalf("replies after replay = %d, want 1 (only the new update)", got)
+	}
+}
+
+func TestRunner_ReplayAfterCrashMidUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memstore.NewStore()
+	deps := newDeps(store)
+
+	// Crash after InsertIncome committed but before the runner marked the update
+	// and replied: the mutation carried the idempotency key in its own transaction.
+	keyed := domain.WithIdempotencyKey(ctx, domain.TransportTelegram, "update:100")
+	if _, _, err := bot.DispatchCommand(keyed, "/add 100", "testbot", domain.TransportTelegram, "7", deps); err != nil {
+		t.Fatalf("DispatchCommand: %v", err)
+	}
+
+	// The same key is refused by the store even without the runner's check.
+	_, _, err := bot.DispatchCommand(keyed, "/add 100", "testbot", domain.TransportTelegram, "7", deps)
+	if !errors.Is(err, domain.ErrDuplicateUpdate) {
+		t.Fatalf("second dispatch err = %v, want ErrDuplicateUpdate", err)
+	}
+
+	tg := &fakeTelegram{updates: []telegram.Update{textUpdate(100, "/add 100")}}
+
+	runUntilConfirmed(t, tgrunner.NewRunner(tg).SetBotDeps(deps).SetUpdateStore(store), tg, 101)
+
+	if got := incomeSum(t, store); got != 10000 {
+		t.Fatalf("income sum = %d, want 10000", got)
+	}
+
+	if got := tg.Sent(); got != 0 {
+		t.Fatalf("replies = %d, want 0", got)
+	}
+}
+
+func TestRunner_EditedMessageUpdatesEntry(t *testing.T) {
+	t.Parallel()
+
+	store := memstore.NewStore()
+
+	added := textUpdate(100, "/add 100")
+	edited := telegram.Update{UpdateID: 101, EditedMessage: &telegram.Message{
+		MessageID: added.Message.MessageID,
+		Text:      "/add 150 исправлено",
+		Chat:      added.Message.Chat,
+		From:      added.Message.From,
+	}}
+	unrelated := telegram.Update{UpdateID: 102, EditedMessage: &telegram.Message{
+		MessageID: 55,
+		Text:      "просто текст",
+		Chat:      added.Message.Chat,
+		From:      added.Message.From,
+	}}
+
+	tg := &fakeTelegram{updates: []telegram.Update{added, edited, unrelated}}
+
+	runUntilConfirmed(t, tgrunner.NewRunner(tg).SetBotDeps(newDeps(store)).SetUpdateStore(store), tg, 103)
+
+	if got := incomeSum(t, store); got != 15000 {
+		t.Fatalf("income sum = %d, want 15000", got)
+	}
+
+	if got := tg.Sent(); got != 2 {
+		t.Fatalf("replies = %d, want 2 (add and correction)", got)
+	}
+}
+
+func TestNotifyRecurring_SendsToStoredChat(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memstore.NewStore()
+	tg := &fakeTelegram{}
+
+	now := fixedNow()
+	recurring := service.NewRecurringService(store, func() time.Time { return now })
+
+	deps := newDeps(store)
+	deps.Recurring = recurring
+
+	r := tgrunner.NewRunner(tg).SetBotDeps(deps).SetChatStore(store)
+
+	if err := tgrunner.HandleTelegramUpdate(ctx, "testbot", textUpdate(1, "/recurring add 500 monthly on 12 @acme ask"), r, deps); err != nil {
+		t.Fatalf("HandleTelegramUpdate: %v", err)
+	}
+
+	now = now.AddDate(0, 0, 2)
+
+	runs, err := recurring.RunDue(ctx)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("RunDue = %+v, %v; want one run", runs, err)
+	}
+
+	if err := r.NotifyRecurring(ctx, runs[0]); err != nil {
+		t.Fatalf("NotifyRecurring: %v", err)
+	}
+
+	// A user without a Telegram chat is skipped.
+	if err := r.NotifyRecurring(ctx, domain.RecurringRun{UserID: 999}); err != nil {
+		t.Fatalf("NotifyRecurring(no chat): %v", err)
+	}
+
+	if tg.Sent() != 2 {
+		t.Fatalf("sent %d messages, want the /recurring reply and one notice", tg.Sent())
+	}
+
+	last := tg.sent[1]
+	if last.ChatID != testUserTG || last.Text != bot.RecurringRunText(i18n.Default, runs[0]) {
+		t.Fatalf("notice = %+v", last)
+	}
+}
+
+func TestHandleTelegramUpdate_ChartSendsPhoto(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memstore.NewStore()
+	tg := &fakeTelegram{}
+
+	deps := newDeps(store)
+	deps.Chart = service.NewChartService(store, tax.NewDefaultProvider())
+
+	r := tgrunner.NewRunner(tg).SetBotDeps(deps)
+
+	for i, text := range []string{"/add 2025-03-05 1000", "/chart"} {
+		if err := tgrunner.HandleTelegramUpdate(ctx, "testbot", textUpdate(int64(i+1), text), r, deps); err != nil {
+			t.Fatalf("HandleTelegramUpdate(%q): %v", text, err)
+		}
+	}
+
+	if tg.Sent() != 1 || len(tg.photos) != 1 {
+		t.Fatalf("sent %d messages and %d photos, want the /add reply and one photo", tg.Sent(), len(tg.photos))
+	}
+
+	p := tg.photos[0]
+	if p.ChatID != testUserTG || p.FileName != "income-2025.png" || !bytes.HasPrefix(p.Photo, []byte("\x89PNG")) {
+		t.Fatalf("photo = chat %d, %q, %d bytes", p.ChatID, p.FileName, len(p.Photo))
+	}
+	if !strings.Contains(p.Caption, "Налог: 60,00") || p.ParseMode != "HTML" {
+		t.Fatalf("caption = %q (%s)", p.Caption, p.ParseMode)
+	}
+}
+
+// pdfStub renders every document as the same bytes.
+type pdfStub struct{}
+
+func (pdfStub) InvoicePDF(inv domain.Invoice, seller domain.Requisites) ([]byte, error) {
+	return []byte("%PDF-stub"), nil
+}
+
+func (pdfStub) ActPDF(act domain.Act, seller domain.Requisites) ([]byte, error) {
+	return []byte("%PDF-stub"), nil
+}
+
+func (pdfStub) ActDOCX(act domain.Act, seller domain.Requisites) ([]byte, error) {
+	return []byte("PK-stub"), nil
+}
+
+func TestHandleTelegramUpdate_InvoiceSendsDocumentAndReminder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memstore.NewStore()
+	tg := &fakeTelegram{}
+
+	now := fixedNow()
+	invoices := service.NewInvoiceService(store, func() time.Time { return now })
+
+	deps := newDeps(store)
+	deps.Invoices = invoices
+	deps.Documents = pdfStub{}
+
+	r := tgrunner.NewRunner(tg).SetBotDeps(deps).SetChatStore(store)
+
+	for i, text := range []string{
+		"/requisites name ИП Иванов",
+		"/requisites inn 500100732259",
+		"/requisites bank Сбербанк",
+		"/requisites bik 044525225",
+		"/requisites account 40802810100000000001",
+		"/client add Acme",
+		"/invoice new 1 5000",
+	} {
+		if err := tgrunner.HandleTelegramUpdate(ctx, "testbot", textUpdate(int64(i+1), text), r, deps); err != nil {
+			t.Fatalf("HandleTelegramUpdate(%q): %v", text, err)
+		}
+	}
+
+	if tg.Sent() != 6 || len(tg.documents) != 1 {
+		t.Fatalf("sent %d messages and %d documents, want 6 replies and the invoice", tg.Sent(), len(tg.documents))
+	}
+
+	d := tg.documents[0]
+	if d.ChatID != testUserTG || d.FileName != "invoice-2025-1.pdf" || string(d.Document) != "%PDF-stub" {
+		t.Fatalf("document = chat %d, %q, %q", d.ChatID, d.FileName, d.Document)
+	}
+	if !strings.Contains(d.Caption, "Счёт #1 (№ 1 от 10.08.2025)") || d.ParseMode != "HTML" {
+		t.Fatalf("caption = %q (%s)", d.Caption, d.ParseMode)
+	}
+
+	now = now.AddDate(0, 1, 0)
+
+	overdue, err := invoices.OverdueInvoices(ctx)
+	if err != nil || len(overdue) != 1 {
+		t.Fatalf("OverdueInvoices = %+v, %v; want the invoice", overdue, err)
+	}
+
+	if err := r.NotifyOverdue(ctx, overdue[0]); err != nil {
+		t.Fatalf("NotifyOverdue: %v", err)
+	}
+
+	last := tg.sent[len(tg.sent)-1]
+	if last.ChatID != testUserTG || last.Text != bot.InvoiceReminderText(i18n.Default, overdue[0]) {
+		t.Fatalf("reminder = %+v", last)
+	}
+}
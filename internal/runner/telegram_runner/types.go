@@ -2,14 +2,34 @@ package telegram_runner
 
 import (
 	"log/slog"
+	"sync"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/bot"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
 )
 
 // Runner handles Telegram bot operations
 type Runner struct {
-	tg      *telegram.Client
+	tg      TelegramClient
 	log     *slog.Logger
 	botDeps *bot.BotDeps
+	workers int
+	updates UpdateStore // optional; without it the offset lives only in memory
+	obs     PollObserver
+	chats   domain.ChatStore // optional; without it recurring incomes are not reported
+}
+
+// OffsetTracker keeps the getUpdates offset from passing updates that are still in flight.
+type OffsetTracker struct {
+	mu       sync.Mutex
+	inflight map[int64]struct{}
+	done     map[int64]struct{} // finished at or above the watermark
+	next     int64              // newest seen update_id + 1
+}
+
+// workerPool runs updates in parallel; updates of the same chat go to the same worker, in order.
+type workerPool struct {
+	queues []chan telegram.Update
+	wg     sync.WaitGroup
 }
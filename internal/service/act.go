@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// NewActService wires the act store. If now is nil, time.Now will be used.
+func NewActService(store ActStore, now func() time.Time) *ActService {
+	if now == nil {
+		now = time.Now
+	}
+	return &ActService{store: store, now: now}
+}
+
+// ActForInvoice issues an act dated today for an invoice of userID, to its client and
+// for its whole amount, whether it is paid yet or not.
+func (s *ActService) ActForInvoice(ctx context.Context, userID, invoiceID int64) (domain.Act, error) {
+	const op = "service.ActService.ActForInvoice"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	if invoiceID <= 0 {
+		return domain.Act{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	invoices, err := s.store.ListInvoices(ctx, userID, invoiceID)
+	if err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	if len(invoices) == 0 {
+		return domain.Act{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	inv := invoices[0]
+
+	act, err := s.issue(ctx, domain.Act{
+		UserID:          userID,
+		Client:          inv.Client,
+		Amount:          inv.Amount,
+		Description:     inv.Description,
+		InvoiceID:       inv.ID,
+		InvoiceNumber:   inv.Number,
+		InvoiceIssuedOn: inv.IssuedOn,
+	})
+	if err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	return act, nil
+}
+
+// ActForIncome issues an act dated today for an active income of userID to one of its
+// clients. Without a description the note of the income is printed.
+func (s *ActService) ActForIncome(ctx context.Context, userID, incomeID, clientID int64, description string) (domain.Act, error) {
+	const op = "service.ActService.ActForIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	income, ok, err := s.store.GetIncome(ctx, userID, incomeID)
+	if err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return domain.Act{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	client, err := s.client(ctx, userID, clientID)
+	if err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	description = strings.TrimSpace(description)
+	if description == "" {
+		description = income.Note
+	}
+
+	act, err := s.issue(ctx, domain.Act{
+		UserID:      userID,
+		Client:      client,
+		Amount:      income.Amount,
+		Description: description,
+		IncomeID:    income.ID,
+	})
+	if err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	return act, nil
+}
+
+// GetAct returns the act of userID with actID, or domain.ErrEntryNotFound.
+func (s *ActService) GetAct(ctx context.Context, userID, actID int64) (domain.Act, error) {
+	const op = "service.ActService.GetAct"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	if actID <= 0 {
+		return domain.Act{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	acts, err := s.store.ListActs(ctx, userID, actID)
+	if err != nil {
+		return domain.Act{}, validate.Wrap(op, err)
+	}
+
+	if len(acts) == 0 {
+		return domain.Act{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	return acts[0], nil
+}
+
+// ListActs returns the registry of acts of userID issued in year, or of all years if
+// year is 0, newest first.
+func (s *ActService) ListActs(ctx context.Context, userID int64, year int) ([]domain.Act, error) {
+	const op = "service.ActService.ListActs"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	acts, err := s.store.ListActs(ctx, userID, 0)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if year == 0 {
+		return acts, nil
+	}
+
+	out := acts[:0]
+	for _, act := range acts {
+		if act.IssuedOn.Year() == year {
+			out = append(out, act)
+		}
+	}
+
+	return out, nil
+}
+
+// issue dates act today and stores it with the next number of the year; the
+// requisites of the user must be complete to print it.
+func (s *ActService) issue(ctx context.Context, act domain.Act) (domain.Act, error) {
+	req, err := s.store.GetRequisites(ctx, act.UserID)
+	if err != nil {
+		return domain.Act{}, err
+	}
+
+	if !req.Complete() {
+		return domain.Act{}, domain.ErrRequisitesMissing
+	}
+
+	act.IssuedOn = period.Day(s.now())
+	act.Description = strings.TrimSpace(act.Description)
+
+	act.ID, act.Number, err = s.store.CreateAct(ctx, act)
+	if err != nil {
+		return domain.Act{}, err
+	}
+
+	return act, nil
+}
+
+// client returns the client of userID with clientID, or domain.ErrEntryNotFound.
+func (s *ActService) client(ctx context.Context, userID, clientID int64) (domain.Counterparty, error) {
+	clients, err := s.store.ListCounterparties(ctx, userID)
+	if err != nil {
+		return domain.Counterparty{}, err
+	}
+
+	for _, c := range clients {
+		if c.ID == clientID {
+			return c, nil
+		}
+	}
+
+	return domain.Counterparty{}, domain.ErrEntryNotFound
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func NewChartService(store ChartStore, provider tax.Provider) *ChartService {
+	return &ChartService{store: store, provider: provider}
+}
+
+// MonthlyIncome returns the income of every month of year and the tax on it at the rate
+// of the user's scheme for that year.
+func (s *ChartService) MonthlyIncome(ctx context.Context, userID int64, year int) (domain.MonthlyIncome, error) {
+	const op = "service.ChartService.MonthlyIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.MonthlyIncome{}, validate.Wrap(op, err)
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	scheme, err := s.store.GetUserScheme(ctx, userID)
+	if err != nil {
+		return domain.MonthlyIncome{}, validate.Wrap(op, err)
+	}
+
+	policy, err := s.provider.ForDate(scheme, to)
+	if err != nil {
+		return domain.MonthlyIncome{}, validate.Wrap(op, err)
+	}
+
+	sums, err := s.store.SumIncomesByMonth(ctx, userID, from, to)
+	if err != nil {
+		return domain.MonthlyIncome{}, validate.Wrap(op, err)
+	}
+
+	out := domain.MonthlyIncome{Year: year}
+
+	for _, m := range sums {
+		if m.Month.Year() != year {
+			continue
+		}
+
+		i := m.Month.Month() - 1
+		out.Income[i] = m.Sum
+		out.Tax[i] = m.Sum * policy.BaseRateBP / domain.BpDen
+	}
+
+	return out, nil
+}
@@ -0,0 +1,8 @@
+package service
+
+import "errors"
+
+var (
+	ErrInvalidToken    = errors.New("invalid api token")
+	ErrInvalidSchedule = errors.New("invalid recurring schedule")
+)
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/bits"
+	"slices"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// recentDays is the window of the recent run-rate estimate.
+const recentDays = 90
+
+func (s *TotalService) Forecast(ctx context.Context, userID int64, now time.Time) (domain.Forecast, error) {
+	return Forecast(
+		ctx,
+		s.getUserScheme,
+		s.sumIncomes,
+		s.sumPayments,
+		s.provider,
+		userID,
+		now,
+	)
+}
+
+// Forecast projects the income of the year that contains ref to its end and derives
+// the tax, the 1% extra contribution and the fixed contributions left from it.
+//
+// The income is estimated in up to three ways:
+//   - the year-to-date run-rate;
+//   - the run-rate of the last 90 days (once the year is older than that);
+//   - the previous year's shape: income to date scaled by how much of the previous
+//     year's income had come by the same date.
+//
+// The range runs from the lowest to the highest estimate; the expected value is their median.
+func Forecast(
+	ctx context.Context,
+	getUserScheme func(ctx context.Context, userID int64) (domain.TaxScheme, error),
+	sumIncomes func(ctx context.Context, userID int64, from, to time.Time) (int64, error),
+	sumPayments func(ctx context.Context, userID int64, from, to time.Time) (int64, int64, error), // (contrib, advance)
+	provider tax.Provider,
+	userID int64,
+	ref time.Time,
+) (domain.Forecast, error) {
+	const op = "service.total.Forecast"
+
+	from, to := period.YearBounds(ref)
+	asOf := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, time.UTC)
+
+	scheme, err := getUserScheme(ctx, userID)
+	if err != nil {
+		return domain.Forecast{}, validate.Wrap(op, err)
+	}
+
+	policy, err := provider.ForDate(scheme, to)
+	if err != nil {
+		return domain.Forecast{}, validate.Wrap(op, err)
+	}
+
+	ytd, err := sumIncomes(ctx, userID, from, asOf)
+	if err != nil {
+		return domain.Forecast{}, validate.Wrap(op, err)
+	}
+
+	contrib, advance, err := sumPayments(ctx, userID, from, to)
+	if err != nil {
+		return domain.Forecast{}, validate.Wrap(op, err)
+	}
+
+	// Whole days, counted inclusively: elapsed >= 1, elapsed + remaining = days in the year.
+	elapsed := int64(asOf.YearDay())
+	remaining := int64(time.Date(ref.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()) - elapsed
+
+	estimates := []int64{ytd + mulDiv(ytd, remaining, elapsed)}
+
+	if elapsed > recentDays {
+		recent, err := sumIncomes(ctx, userID, asOf.AddDate(0, 0, 1-recentDays), asOf)
+		if err != nil {
+			return domain.Forecast{}, validate.Wrap(op, err)
+		}
+
+		estimates = append(estimates, ytd+mulDiv(recent, remaining, recentDays))
+	}
+
+	prevFrom, prevTo := period.YearBounds(from.AddDate(-1, 0, 0))
+
+	prevTotal, err := sumIncomes(ctx, userID, prevFrom, prevTo)
+	if err != nil {
+		return domain.Forecast{}, validate.Wrap(op, err)
+	}
+
+	seasonal := prevTotal > 0
+	if seasonal {
+		prevToDate, err := sumIncomes(ctx, userID, prevFrom, sameDayLastYear(asOf))
+		if err != nil {
+			return domain.Forecast{}, validate.Wrap(op, err)
+		}
+
+		if prevToDate > 0 {
+			estimates = append(estimates, mulDiv(ytd, prevTotal, prevToDate))
+		} else {
+			// Last year's income all came later in the year: expect it on top.
+			estimates = append(estimates, ytd+prevTotal)
+		}
+	}
+
+	income := spread(estimates)
+
+	// Deterministic integer math (kopecks only); each figure grows with the income,
+	// so it keeps the order of the range.
+	project := func(f func(income int64) int64) domain.Range {
+		return domain.Range{Low: f(income.Low), Expected: f(income.Expected), High: f(income.High)}
+	}
+
+	taxOf := func(income int64) int64 { return income * policy.BaseRateBP / domain.BpDen }
+
+	extraOf := func(income int64) int64 {
+		extra := tax.ExtraOverThreshold(income, policy.ExcessThreshold, policy.ExcessRateBP)
+		if policy.ExcessCap > 0 {
+			extra = min(extra, policy.ExcessCap)
+		}
+		return extra
+	}
+
+	// Contributions of the year reduce the tax: those already paid, or the fixed ones
+	// plus the 1% extra if they are yet to be paid.
+	dueOf := func(income int64) int64 {
+		t := taxOf(income)
+		applied := min(max(contrib, policy.FixedContrib+extraOf(income)), t)
+		return max(t-applied-advance, 0)
+	}
+
+	f := domain.Forecast{
+		Year:         ref.Year(),
+		AsOf:         asOf,
+		IncomeToDate: ytd,
+		ContribPaid:  contrib,
+		AdvancePaid:  advance,
+		Seasonal:     seasonal,
+		Income:       income,
+		Tax:          project(taxOf),
+		Extra:        project(extraOf),
+		Due:          project(dueOf),
+		FixedContrib: policy.FixedContrib,
+		FixedLeft:    max(policy.FixedContrib-contrib, 0),
+		IncomeLimit:  policy.IncomeLimit,
+		VATThreshold: policy.VATThreshold,
+	}
+
+	for _, l := range []struct {
+		kind   domain.LimitKind
+		amount int64
+	}{
+		{domain.LimitUSN, policy.IncomeLimit},
+		{domain.LimitVAT, policy.VATThreshold},
+	} {
+		if w, ok := limitWarning(l.kind, l.amount, ytd, income); ok {
+			f.Warnings = append(f.Warnings, w)
+		}
+	}
+
+	return f, nil
+}
+
+// limitWarning reports whether the income crosses limit (0 = no limit) and how likely that is.
+func limitWarning(kind domain.LimitKind, limit, ytd int64, income domain.Range) (domain.ForecastWarning, bool) {
+	w := domain.ForecastWarning{Limit: kind, Amount: limit}
+
+	switch {
+	case limit <= 0:
+		return domain.ForecastWarning{}, false
+	case ytd > limit:
+		w.Level = domain.ForecastCrossed
+	case income.Expected > limit:
+		w.Level = domain.ForecastExpected
+	case income.High > limit:
+		w.Level = domain.ForecastPossible
+	default:
+		return domain.ForecastWarning{}, false
+	}
+
+	return w, true
+}
+
+// spread returns the lowest, the median and the highest of the estimates (at least one).
+func spread(estimates []int64) domain.Range {
+	slices.Sort(estimates)
+
+	n := len(estimates)
+	mid := estimates[n/2]
+	if n%2 == 0 {
+		mid = estimates[n/2-1] + (estimates[n/2]-estimates[n/2-1])/2
+	}
+
+	return domain.Range{Low: estimates[0], Expected: mid, High: estimates[n-1]}
+}
+
+// sameDayLastYear returns the date a year before d; 29 February becomes 28 February.
+func sameDayLastYear(d time.Time) time.Time {
+	prev := time.Date(d.Year()-1, d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	if prev.Month() != d.Month() {
+		prev = prev.AddDate(0, 0, -prev.Day())
+	}
+	return prev
+}
+
+// mulDiv returns a*b/c for non-negative a, b and positive c without overflowing
+// the intermediate product; the result saturates at math.MaxInt64.
+func mulDiv(a, b, c int64) int64 {
+	if a <= 0 || b <= 0 || c <= 0 {
+		return 0
+	}
+
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi >= uint64(c) {
+		return math.MaxInt64
+	}
+
+	q, _ := bits.Div64(hi, lo, uint64(c))
+	if q > math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return int64(q)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
 )
@@ -18,9 +19,37 @@ func NewIncomeService(store IncomeStore) *IncomeService {
 // - amount is in minor units (e.g., kopecks) and must be >= 0
 // - at must be a non-zero time; the date part is persisted (storage casts to DATE)
 // - note is trimmed; empty string is stored as NULL (handled by storage)
-func (s *IncomeService) AddIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) error {
+//
+// Returns the ID of the new record.
+func (s *IncomeService) AddIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) (int64, error) {
 	const op = "service.IncomeService.AddIncome"
 
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateAmount(amount); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDate(at); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	note = strings.TrimSpace(note)
+
+	// Delegate to storage; it applies DATE cast and NULLIF on note.
+	id, err := s.store.InsertIncome(ctx, userID, at, amount, note)
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	return id, nil
+}
+
+// UpdateIncome replaces date, amount and note of an active income owned by userID.
+// Returns domain.ErrEntryNotFound if there is no such income or it was undone.
+func (s *IncomeService) UpdateIncome(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) error {
+	const op = "service.IncomeService.UpdateIncome"
+
 	if err := validate.ValidateUserID(userID); err != nil {
 		return validate.Wrap(op, err)
 	}
@@ -34,10 +63,15 @@ func (s *IncomeService) AddIncome(ctx context.Context, userID int64, at time.Tim
 	}
 	note = strings.TrimSpace(note)
 
-	// Delegate to storage; it applies DATE cast and NULLIF on note.
-	if err := s.store.InsertIncome(ctx, userID, at, amount, note); err != nil {
+	ok, err := s.store.UpdateIncome(ctx, userID, id, at, amount, note)
+	if err != nil {
 		return validate.Wrap(op, err)
 	}
+
+	if !ok {
+		return validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
 	return nil
 }
 
@@ -58,6 +92,40 @@ func (s *IncomeService) UndoLastQuarter(ctx context.Context, userID int64, now t
 	return amount, at, note, ok, nil
 }
 
+// UndoIncome voids the active income id of userID, whatever its date.
+// ok=false if the user has no such income.
+func (s *IncomeService) UndoIncome(ctx context.Context, userID, id int64, now time.Time) (int64, time.Time, string, bool, error) {
+	const op = "service.IncomeService.UndoIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	amount, at, note, ok, err := s.store.VoidIncome(ctx, userID, id, now.UTC())
+
+	if err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	return amount, at, note, ok, nil
+}
+
+// GetIncome returns the active income id of userID; ok=false if there is none.
+func (s *IncomeService) GetIncome(ctx context.Context, userID, id int64) (domain.Income, bool, error) {
+	const op = "service.IncomeService.GetIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Income{}, false, validate.Wrap(op, err)
+	}
+
+	income, ok, err := s.store.GetIncome(ctx, userID, id)
+	if err != nil {
+		return domain.Income{}, false, validate.Wrap(op, err)
+	}
+
+	return income, ok, nil
+}
+
 func (s *IncomeService) SumIncomes(ctx context.Context, userID int64, from, to time.Time) (int64, error) {
 	const op = "service.IncomeService.SumIncomes"
 
@@ -78,6 +146,26 @@ func (s *IncomeService) SumIncomes(ctx context.Context, userID int64, from, to t
 	return sum, nil
 }
 
+// ListIncomes returns active incomes in [from..to] inclusive, oldest first.
+func (s *IncomeService) ListIncomes(ctx context.Context, userID int64, from, to time.Time) ([]domain.Income, error) {
+	const op = "service.IncomeService.ListIncomes"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	items, err := s.store.ListIncomes(ctx, userID, from, to)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return items, nil
+}
+
 // SumQuarter returns total income and 6% tax for the current quarter.
 // All amounts are int64 minor units (kopecks). No floats.
 // Tax is computed with floor division for determinism.
@@ -8,17 +8,148 @@ import (
 )
 
 type IncomeStore interface {
-	InsertIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) error
+	InsertIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) (int64, error)
 	VoidLastIncomeInRange(ctx context.Context, userID int64, from, to, now time.Time) (
 		amount int64, at time.Time, note string, ok bool, err error,
 	)
+	// VoidIncome voids an active income of userID by id; ok=false if there is none.
+	VoidIncome(ctx context.Context, userID, id int64, now time.Time) (amount int64, at time.Time, note string, ok bool, err error)
 	SumIncomes(ctx context.Context, userID int64, from, to time.Time) (int64, error)
+	ListIncomes(ctx context.Context, userID int64, from, to time.Time) ([]domain.Income, error)
+	// GetIncome returns an active income of userID; ok=false if there is none with this id.
+	GetIncome(ctx context.Context, userID, id int64) (domain.Income, bool, error)
+	// UpdateIncome changes an active income of userID; ok=false if there is none with this id.
+	UpdateIncome(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (bool, error)
 }
 
 type PaymentStore interface {
-	InsertPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType domain.PaymentType) error
+	InsertPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType domain.PaymentType) (int64, error)
 	VoidLastPaymentInRange(ctx context.Context, userID int64, from, to, now time.Time, payoutType domain.PaymentType) (
 		amount int64, at time.Time, note string, pType domain.PaymentType, ok bool, err error,
 	)
+	// VoidPayment voids an active payment of userID by id if it is of paymentType; ok=false if there is none.
+	VoidPayment(ctx context.Context, userID, id int64, now time.Time, paymentType domain.PaymentType) (
+		amount int64, at time.Time, note string, ok bool, err error,
+	)
 	SumPayments(ctx context.Context, userID int64, from, to time.Time) (int64, int64, error)
+	ListPayments(ctx context.Context, userID int64, from, to time.Time) ([]domain.Payment, error)
+	// GetPayment returns an active payment of userID; ok=false if there is none with this id.
+	GetPayment(ctx context.Context, userID, id int64) (domain.Payment, bool, error)
+	// UpdatePayment changes an active payment of userID; ok=false if there is none with this id.
+	UpdatePayment(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (bool, error)
+}
+
+type TokenStore interface {
+	// CreateAPIToken stores the token hash and binds an "api" identity to userID in one step.
+	CreateAPIToken(ctx context.Context, userID int64, tokenHash []byte, name string) (int64, error)
+	// ResolveAPIToken returns the owner of an active token and records its use.
+	ResolveAPIToken(ctx context.Context, tokenHash []byte, now time.Time) (userID int64, ok bool, err error)
+	ListAPITokens(ctx context.Context, userID int64) ([]domain.APIToken, error)
+	// RevokeAPIToken and RevokeAllAPITokens also drop the "api" identities of the revoked tokens.
+	RevokeAPIToken(ctx context.Context, userID, tokenID int64, now time.Time) (bool, error)
+	RevokeAllAPITokens(ctx context.Context, userID int64, now time.Time) (int64, error)
+}
+
+type LinkStore interface {
+	// CreateLinkCode stores a code hash for userID, replacing the user's unused codes.
+	CreateLinkCode(ctx context.Context, userID int64, codeHash []byte, now, expiresAt time.Time) error
+	// RedeemLinkCode atomically consumes the code and binds (transport, externalID) to its owner.
+	// currentUserID is the account the identity resolves to now; it must have no entries.
+	RedeemLinkCode(ctx context.Context, codeHash []byte, now time.Time, currentUserID int64, transport, externalID string) (int64, error)
+	// UnbindIdentities removes identities of transport from userID (all of them if externalID is empty).
+	// Returns domain.ErrLastIdentity if the account would be left without a non-API identity.
+	UnbindIdentities(ctx context.Context, userID int64, transport, externalID string) (int64, error)
+}
+
+type RecurringStore interface {
+	// CreateRecurringRule stores a rule of rule.UserID and returns its ID.
+	CreateRecurringRule(ctx context.Context, rule domain.RecurringRule) (int64, error)
+	// ListRecurringRules returns the rules of userID, oldest first, with their pending counts.
+	ListRecurringRules(ctx context.Context, userID int64) ([]domain.RecurringRule, error)
+	// SetRecurringPaused pauses or resumes a rule; a non-zero nextDue replaces its next date.
+	SetRecurringPaused(ctx context.Context, userID, ruleID int64, paused bool, nextDue time.Time) (bool, error)
+	// DeleteRecurringRule deletes a rule with its pending occurrences; created incomes stay.
+	DeleteRecurringRule(ctx context.Context, userID, ruleID int64) (bool, error)
+	// DueRecurringRules returns active rules of all users due on or before today.
+	DueRecurringRules(ctx context.Context, today time.Time) ([]domain.RecurringRule, error)
+	// RunRecurring atomically records the occurrence of ruleID on due, creates its income unless
+	// the rule asks for confirmation, and moves the rule to next. It does nothing (ok=false) if
+	// the rule is no longer active and due on that date; if the date already ran, it only moves on.
+	RunRecurring(ctx context.Context, ruleID int64, due, next time.Time) (run domain.RecurringRun, ok bool, err error)
+	// ResolveRecurringRuns creates the incomes of the pending occurrences of a rule (confirm)
+	// or marks them skipped, and returns them.
+	ResolveRecurringRuns(ctx context.Context, userID, ruleID int64, confirm bool) ([]domain.RecurringRun, error)
+}
+
+// ChartStore aggregates incomes by month in the store instead of reading every entry.
+type ChartStore interface {
+	GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error)
+	// SumIncomesByMonth returns the active incomes in [from..to] summed per calendar month,
+	// ordered by month; months without income are omitted.
+	SumIncomesByMonth(ctx context.Context, userID int64, from, to time.Time) ([]domain.MonthSum, error)
+}
+
+// SearchStore looks up notes of incomes and payments.
+type SearchStore interface {
+	// SearchNotes returns up to limit active incomes and payments in [from..to] whose note
+	// contains query after domain.FoldNote on both, ordered by date and ID, newest first.
+	SearchNotes(ctx context.Context, userID int64, query string, from, to time.Time, limit int) ([]domain.NoteMatch, error)
+}
+
+// ReportStore aggregates incomes by month or ISO week in the store.
+type ReportStore interface {
+	GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error)
+	// IncomeStatsByBucket returns the count, sum and largest of the active incomes in [from..to]
+	// per bucket, ordered by bucket start; buckets without income are omitted.
+	IncomeStatsByBucket(ctx context.Context, userID int64, bucket domain.ReportBucket, from, to time.Time) ([]domain.IncomeStats, error)
+}
+
+// InvoiceStore keeps the clients and the requisites of users and their invoices.
+type InvoiceStore interface {
+	// CreateCounterparty stores a client of c.UserID and returns its ID; names are unique
+	// per user regardless of case (domain.ErrClientExists).
+	CreateCounterparty(ctx context.Context, c domain.Counterparty) (int64, error)
+	// UpdateCounterparty replaces the INN, KPP and address of a client of c.UserID;
+	// ok=false if there is none with c.ID.
+	UpdateCounterparty(ctx context.Context, c domain.Counterparty) (bool, error)
+	// ListCounterparties returns the clients of userID ordered by ID.
+	ListCounterparties(ctx context.Context, userID int64) ([]domain.Counterparty, error)
+	// GetRequisites returns the requisites of userID, all empty if they were never set.
+	GetRequisites(ctx context.Context, userID int64) (domain.Requisites, error)
+	SetRequisites(ctx context.Context, userID int64, r domain.Requisites) error
+	// CreateInvoice stores inv with the next number of inv.UserID in the year of inv.IssuedOn
+	// and returns its ID and number. inv.Client.ID must be a client of the same user.
+	CreateInvoice(ctx context.Context, inv domain.Invoice) (id, number int64, err error)
+	// ListInvoices returns the invoices of userID with their clients and paid sums, newest
+	// first; a non-zero invoiceID keeps only that one.
+	ListInvoices(ctx context.Context, userID, invoiceID int64) ([]domain.Invoice, error)
+	// PayInvoice creates an income of userID and links it to the invoice in one step. amount 0
+	// pays what is left; domain.ErrInvoicePaid if nothing is, domain.ErrOverpayment if amount is
+	// more. Like InsertIncome it honours the idempotency key and records the message entry.
+	PayInvoice(ctx context.Context, userID, invoiceID int64, at time.Time, amount int64, note string) (incomeID int64, err error)
+	// OverdueInvoices returns the invoices of all users not paid in full, due before today and
+	// not reminded of since remindedBefore, ordered by due date.
+	OverdueInvoices(ctx context.Context, today, remindedBefore time.Time) ([]domain.Invoice, error)
+	MarkInvoiceReminded(ctx context.Context, invoiceID int64, day time.Time) error
+}
+
+// TaxPaymentStore provides the requisites payment orders are paid from.
+type TaxPaymentStore interface {
+	GetRequisites(ctx context.Context, userID int64) (domain.Requisites, error)
+}
+
+// ActStore keeps the registry of acts issued for invoices and incomes.
+type ActStore interface {
+	GetRequisites(ctx context.Context, userID int64) (domain.Requisites, error)
+	ListCounterparties(ctx context.Context, userID int64) ([]domain.Counterparty, error)
+	ListInvoices(ctx context.Context, userID, invoiceID int64) ([]domain.Invoice, error)
+	// GetIncome returns an active income of userID; ok=false if there is none with this id.
+	GetIncome(ctx context.Context, userID, id int64) (domain.Income, bool, error)
+	// CreateAct stores act with the next number of act.UserID in the year of act.IssuedOn and
+	// returns its ID and number. act.Client.ID must be a client of the same user and exactly one
+	// of act.InvoiceID and act.IncomeID is set; domain.ErrActExists if that one has an act already.
+	CreateAct(ctx context.Context, act domain.Act) (id, number int64, err error)
+	// ListActs returns the acts of userID with their clients and invoices, newest first;
+	// a non-zero actID keeps only that one.
+	ListActs(ctx context.Context, userID, actID int64) ([]domain.Act, error)
 }
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// NewInvoiceService wires the invoice store. If now is nil, time.Now will be used.
+func NewInvoiceService(store InvoiceStore, now func() time.Time) *InvoiceService {
+	if now == nil {
+		now = time.Now
+	}
+	return &InvoiceService{store: store, now: now}
+}
+
+// AddClient stores a client of userID; the INN and the KPP are optional but checked if given.
+func (s *InvoiceService) AddClient(ctx context.Context, userID int64, c domain.Counterparty) (domain.Counterparty, error) {
+	const op = "service.InvoiceService.AddClient"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Counterparty{}, validate.Wrap(op, err)
+	}
+
+	c.UserID = userID
+	c = trimCounterparty(c)
+
+	if c.Name == "" {
+		return domain.Counterparty{}, validate.Wrap(op, validate.ErrEmptyString)
+	}
+
+	if err := validateCounterparty(c); err != nil {
+		return domain.Counterparty{}, validate.Wrap(op, err)
+	}
+
+	id, err := s.store.CreateCounterparty(ctx, c)
+	if err != nil {
+		return domain.Counterparty{}, validate.Wrap(op, err)
+	}
+
+	c.ID = id
+
+	return c, nil
+}
+
+// UpdateClient replaces the INN, the KPP and the address of a client; its name stays.
+func (s *InvoiceService) UpdateClient(ctx context.Context, userID int64, c domain.Counterparty) (domain.Counterparty, error) {
+	const op = "service.InvoiceService.UpdateClient"
+
+	old, err := s.GetClient(ctx, userID, c.ID)
+	if err != nil {
+		return domain.Counterparty{}, validate.Wrap(op, err)
+	}
+
+	c.UserID = userID
+	c.Name = old.Name
+	c = trimCounterparty(c)
+
+	if err := validateCounterparty(c); err != nil {
+		return domain.Counterparty{}, validate.Wrap(op, err)
+	}
+
+	ok, err := s.store.UpdateCounterparty(ctx, c)
+	if err != nil {
+		return domain.Counterparty{}, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return domain.Counterparty{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	return c, nil
+}
+
+// GetClient returns the client of userID with clientID, or domain.ErrEntryNotFound.
+func (s *InvoiceService) GetClient(ctx context.Context, userID, clientID int64) (domain.Counterparty, error) {
+	const op = "service.InvoiceService.GetClient"
+
+	clients, err := s.ListClients(ctx, userID)
+	if err != nil {
+		return domain.Counterparty{}, validate.Wrap(op, err)
+	}
+
+	for _, c := range clients {
+		if c.ID == clientID {
+			return c, nil
+		}
+	}
+
+	return domain.Counterparty{}, validate.Wrap(op, domain.ErrEntryNotFound)
+}
+
+func (s *InvoiceService) ListClients(ctx context.Context, userID int64) ([]domain.Counterparty, error) {
+	const op = "service.InvoiceService.ListClients"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	clients, err := s.store.ListCounterparties(ctx, userID)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return clients, nil
+}
+
+func (s *InvoiceService) Requisites(ctx context.Context, userID int64) (domain.Requisites, error) {
+	const op = "service.InvoiceService.Requisites"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	r, err := s.store.GetRequisites(ctx, userID)
+	if err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	return r, nil
+}
+
+// SetRequisites replaces the requisites of userID. Every filled-in number is checked;
+// the accounts need the BIK to check their control keys.
+func (s *InvoiceService) SetRequisites(ctx context.Context, userID int64, r domain.Requisites) (domain.Requisites, error) {
+	const op = "service.InvoiceService.SetRequisites"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	r = domain.Requisites{
+		Name:        strings.TrimSpace(r.Name),
+		INN:         strings.TrimSpace(r.INN),
+		OGRNIP:      strings.TrimSpace(r.OGRNIP),
+		Address:     strings.TrimSpace(r.Address),
+		Bank:        strings.TrimSpace(r.Bank),
+		BIK:         strings.TrimSpace(r.BIK),
+		Account:     strings.TrimSpace(r.Account),
+		CorrAccount: strings.TrimSpace(r.CorrAccount),
+	}
+
+	if err := validateRequisites(r); err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	if err := s.store.SetRequisites(ctx, userID, r); err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	return r, nil
+}
+
+// IssueInvoice issues an invoice dated today to a client of userID. Without a due date
+// it is due in domain.InvoiceDueDays; the requisites of the user must be complete.
+func (s *InvoiceService) IssueInvoice(ctx context.Context, userID int64, inv domain.Invoice) (domain.Invoice, error) {
+	const op = "service.InvoiceService.IssueInvoice"
+
+	if err := validate.ValidateAmount(inv.Amount); err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	client, err := s.GetClient(ctx, userID, inv.Client.ID)
+	if err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	req, err := s.store.GetRequisites(ctx, userID)
+	if err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	if !req.Complete() {
+		return domain.Invoice{}, validate.Wrap(op, domain.ErrRequisitesMissing)
+	}
+
+	today := period.Day(s.now())
+	due := inv.DueOn
+
+	inv = domain.Invoice{
+		UserID:      userID,
+		Client:      client,
+		IssuedOn:    today,
+		DueOn:       today.AddDate(0, 0, domain.InvoiceDueDays),
+		Amount:      inv.Amount,
+		Description: strings.TrimSpace(inv.Description),
+	}
+
+	if !due.IsZero() {
+		inv.DueOn = period.Day(due)
+	}
+
+	if inv.DueOn.Before(today) {
+		return domain.Invoice{}, validate.Wrap(op, validate.ErrInvalidDate)
+	}
+
+	inv.ID, inv.Number, err = s.store.CreateInvoice(ctx, inv)
+	if err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	return inv, nil
+}
+
+// GetInvoice returns the invoice of userID with invoiceID, or domain.ErrEntryNotFound.
+func (s *InvoiceService) GetInvoice(ctx context.Context, userID, invoiceID int64) (domain.Invoice, error) {
+	const op = "service.InvoiceService.GetInvoice"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	if invoiceID <= 0 {
+		return domain.Invoice{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	invoices, err := s.store.ListInvoices(ctx, userID, invoiceID)
+	if err != nil {
+		return domain.Invoice{}, validate.Wrap(op, err)
+	}
+
+	if len(invoices) == 0 {
+		return domain.Invoice{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	return invoices[0], nil
+}
+
+func (s *InvoiceService) ListInvoices(ctx context.Context, userID int64, unpaid bool) ([]domain.Invoice, error) {
+	const op = "service.InvoiceService.ListInvoices"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	invoices, err := s.store.ListInvoices(ctx, userID, 0)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if !unpaid {
+		return invoices, nil
+	}
+
+	out := invoices[:0]
+	for _, inv := range invoices {
+		if inv.Status() != domain.InvoicePaid {
+			out = append(out, inv)
+		}
+	}
+
+	return out, nil
+}
+
+// PayInvoice records a payment of an invoice as an income on at; amount 0 pays what is left.
+func (s *InvoiceService) PayInvoice(ctx context.Context, userID, invoiceID int64, at time.Time, amount int64, note string) (domain.Invoice, int64, error) {
+	const op = "service.InvoiceService.PayInvoice"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Invoice{}, 0, validate.Wrap(op, err)
+	}
+
+	if amount < 0 {
+		return domain.Invoice{}, 0, validate.Wrap(op, validate.ErrInvalidAmount)
+	}
+
+	incomeID, err := s.store.PayInvoice(ctx, userID, invoiceID, period.Day(at), amount, strings.TrimSpace(note))
+	if err != nil {
+		return domain.Invoice{}, 0, validate.Wrap(op, err)
+	}
+
+	inv, err := s.GetInvoice(ctx, userID, invoiceID)
+	if err != nil {
+		return domain.Invoice{}, 0, validate.Wrap(op, err)
+	}
+
+	return inv, incomeID, nil
+}
+
+// OverdueInvoices returns the invoices of all users that are past due and were not
+// reminded of in the last domain.InvoiceRemindDays, for the reminder runner.
+func (s *InvoiceService) OverdueInvoices(ctx context.Context) ([]domain.Invoice, error) {
+	const op = "service.InvoiceService.OverdueInvoices"
+
+	today := period.Day(s.now())
+
+	invoices, err := s.store.OverdueInvoices(ctx, today, today.AddDate(0, 0, -domain.InvoiceRemindDays))
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return invoices, nil
+}
+
+// MarkReminded records that the user was reminded of the invoice today.
+func (s *InvoiceService) MarkReminded(ctx context.Context, invoiceID int64) error {
+	const op = "service.InvoiceService.MarkReminded"
+
+	if err := s.store.MarkInvoiceReminded(ctx, invoiceID, period.Day(s.now())); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func trimCounterparty(c domain.Counterparty) domain.Counterparty {
+	c.Name = strings.TrimSpace(c.Name)
+	c.INN = strings.TrimSpace(c.INN)
+	c.KPP = strings.ToUpper(strings.TrimSpace(c.KPP))
+	c.Address = strings.TrimSpace(c.Address)
+
+	return c
+}
+
+// validateCounterparty checks the INN and the KPP of a client if they are set;
+// only organizations (10-digit INN) have a KPP.
+func validateCounterparty(c domain.Counterparty) error {
+	if c.INN != "" {
+		if err := validate.ValidateINN(c.INN); err != nil {
+			return err
+		}
+	}
+
+	if c.KPP != "" {
+		if err := validate.ValidateKPP(c.KPP); err != nil {
+			return err
+		}
+
+		if len(c.INN) == 12 {
+			return validate.ErrInvalidRequisite
+		}
+	}
+
+	return nil
+}
+
+// validateRequisites checks the numbers of r that are set.
+func validateRequisites(r domain.Requisites) error {
+	checks := []struct {
+		value string
+		check func() error
+	}{
+		{r.INN, func() error { return validate.ValidateINN(r.INN) }},
+		{r.OGRNIP, func() error { return validate.ValidateOGRNIP(r.OGRNIP) }},
+		{r.BIK, func() error { return validate.ValidateBIK(r.BIK) }},
+		{r.Account, func() error { return validate.ValidateAccount(r.BIK, r.Account) }},
+		{r.CorrAccount, func() error { return validate.ValidateCorrAccount(r.BIK, r.CorrAccount) }},
+	}
+
+	for _, c := range checks {
+		if c.value == "" {
+			continue
+		}
+
+		if err := c.check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+func (s *TotalService) Limits(ctx context.Context, userID int64, now time.Time) (domain.Limits, error) {
+	return Limits(
+		ctx,
+		s.getUserScheme,
+		s.sumIncomes,
+		s.provider,
+		userID,
+		now,
+	)
+}
+
+// Limits compares the income of the year that contains ref, up to ref, with the limits of
+// the policy (USN limit, VAT threshold, reduced VAT rate limit) and finds the VAT rate on ref.
+func Limits(
+	ctx context.Context,
+	getUserScheme func(ctx context.Context, userID int64) (domain.TaxScheme, error),
+	sumIncomes func(ctx context.Context, userID int64, from, to time.Time) (int64, error),
+	provider tax.Provider,
+	userID int64,
+	ref time.Time,
+) (domain.Limits, error) {
+	const op = "service.total.Limits"
+
+	from, to := period.YearBounds(ref)
+	asOf := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, time.UTC)
+
+	scheme, err := getUserScheme(ctx, userID)
+	if err != nil {
+		return domain.Limits{}, validate.Wrap(op, err)
+	}
+
+	policy, err := provider.ForDate(scheme, to)
+	if err != nil {
+		return domain.Limits{}, validate.Wrap(op, err)
+	}
+
+	ytd, err := sumIncomes(ctx, userID, from, asOf)
+	if err != nil {
+		return domain.Limits{}, validate.Wrap(op, err)
+	}
+
+	vat, err := vatByMonth(ctx, sumIncomes, policy, userID, asOf)
+	if err != nil {
+		return domain.Limits{}, validate.Wrap(op, err)
+	}
+
+	l := domain.Limits{
+		Year:         ref.Year(),
+		AsOf:         asOf,
+		IncomeToDate: ytd,
+		VATRateBP:    vat.rateOn(asOf),
+		VATSince:     vat.since(asOf),
+	}
+
+	for _, k := range []struct {
+		kind   domain.LimitKind
+		amount int64
+	}{
+		{domain.LimitUSN, policy.IncomeLimit},
+		{domain.LimitVAT, policy.VATThreshold},
+		{domain.LimitVATRate, policy.VATRateLimit},
+	} {
+		if k.amount > 0 {
+			l.Usage = append(l.Usage, limitUsage(k.kind, k.amount, ytd))
+		}
+	}
+
+	return l, nil
+}
+
+// limitUsage rates ytd against a positive limit.
+func limitUsage(kind domain.LimitKind, limit, ytd int64) domain.LimitUsage {
+	u := domain.LimitUsage{Kind: kind, Amount: limit, UsedBP: mulDiv(ytd, domain.BpDen, limit)}
+
+	switch {
+	case ytd > limit:
+		u.Level = domain.LimitCrossed
+	case u.UsedBP >= domain.LimitNearBP:
+		u.Level = domain.LimitNear
+	}
+
+	return u
+}
+
+// vatMonths holds the VAT rate and the income of each month of a year (index 0 = January).
+type vatMonths struct {
+	year   int
+	rate   [12]int64
+	income [12]int64
+}
+
+// vatByMonth works out the VAT rate of every month of the year of through, up to its month.
+//
+// A USN payer whose income of the previous year exceeded the VAT threshold pays VAT from
+// 1 January; otherwise from the first day of the month after the income of the year
+// exceeds it. The rate is policy.VATRateBP, or policy.VATRateHighBP once the income
+// (of the previous year, or of this one in the same way) exceeds policy.VATRateLimit.
+// Policies without a VAT threshold yield no VAT and cost no queries.
+func vatByMonth(
+	ctx context.Context,
+	sumIncomes func(ctx context.Context, userID int64, from, to time.Time) (int64, error),
+	policy tax.Policy,
+	userID int64,
+	through time.Time,
+) (vatMonths, error) {
+	v := vatMonths{year: through.Year()}
+
+	if policy.VATThreshold <= 0 || policy.VATRateBP <= 0 {
+		return v, nil
+	}
+
+	rateFor := func(income int64) int64 {
+		switch {
+		case policy.VATRateLimit > 0 && income > policy.VATRateLimit:
+			return policy.VATRateHighBP
+		case income > policy.VATThreshold:
+			return policy.VATRateBP
+		}
+		return 0
+	}
+
+	yearStart, _ := period.YearBounds(through)
+	monthEnd := time.Date(through.Year(), through.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+
+	prevFrom, prevTo := period.YearBounds(yearStart.AddDate(-1, 0, 0))
+
+	prev, err := sumIncomes(ctx, userID, prevFrom, prevTo)
+	if err != nil {
+		return vatMonths{}, err
+	}
+
+	rate := rateFor(prev)
+
+	if rate == 0 {
+		// Most users never reach the threshold: one query settles the whole period.
+		total, err := sumIncomes(ctx, userID, yearStart, monthEnd)
+		if err != nil {
+			return vatMonths{}, err
+		}
+		if rateFor(total) == 0 {
+			return v, nil
+		}
+	}
+
+	var cum int64
+
+	for m := range int(through.Month()) {
+		start := time.Date(through.Year(), time.Month(m+1), 1, 0, 0, 0, 0, time.UTC)
+
+		income, err := sumIncomes(ctx, userID, start, start.AddDate(0, 1, -1))
+		if err != nil {
+			return vatMonths{}, err
+		}
+
+		v.rate[m], v.income[m] = rate, income
+
+		// The rate reached by the end of a month applies from the next one; it never goes down.
+		cum += income
+		rate = max(rate, rateFor(cum))
+	}
+
+	return v, nil
+}
+
+// rateOn returns the VAT rate of the month of t; 0 outside the year or if exempt.
+func (v vatMonths) rateOn(t time.Time) int64 {
+	if t.Year() != v.year {
+		return 0
+	}
+	return v.rate[t.Month()-1]
+}
+
+// since returns the first day of the month from which the rate of t has applied; zero if exempt.
+func (v vatMonths) since(t time.Time) time.Time {
+	rate := v.rateOn(t)
+	if rate == 0 {
+		return time.Time{}
+	}
+
+	m := int(t.Month()) - 1
+	for m > 0 && v.rate[m-1] == rate {
+		m--
+	}
+
+	return time.Date(v.year, time.Month(m+1), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// included returns the VAT included in the income of the months from..to of the year,
+// each month at its own rate: income * rate / (100% + rate), floored.
+func (v vatMonths) included(from, to time.Time) int64 {
+	var vat int64
+
+	for m := range v.rate {
+		month := time.Date(v.year, time.Month(m+1), 1, 0, 0, 0, 0, time.UTC)
+		if month.Before(time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)) || month.After(to) {
+			continue
+		}
+
+		if r := v.rate[m]; r > 0 {
+			vat += v.income[m] * r / (domain.BpDen + r)
+		}
+	}
+
+	return vat
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+const (
+	// LinkCodeTTL is how long a /link code stays valid.
+	LinkCodeTTL = 10 * time.Minute
+	// linkCodeLen is the number of symbols in a code (40 bits with the alphabet below).
+	linkCodeLen = 8
+	// linkCodeAlphabet has no look-alike symbols (0/O, 1/I/L), so codes are easy to retype.
+	linkCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+)
+
+// NewLinkService wires the link store. If now is nil, time.Now will be used.
+func NewLinkService(store LinkStore, now func() time.Time) *LinkService {
+	if now == nil {
+		now = time.Now
+	}
+	return &LinkService{store: store, now: now}
+}
+
+// IssueLinkCode creates a one-time code for userID, formatted as "XXXX-XXXX".
+// Only its SHA-256 hash is stored; a new code replaces the previous unused one.
+func (s *LinkService) IssueLinkCode(ctx context.Context, userID int64) (string, time.Time, error) {
+	const op = "service.LinkService.IssueLinkCode"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return "", time.Time{}, validate.Wrap(op, err)
+	}
+
+	code, err := randomLinkCode()
+	if err != nil {
+		return "", time.Time{}, validate.Wrap(op, err)
+	}
+
+	now := s.now().UTC()
+	expiresAt := now.Add(LinkCodeTTL)
+
+	if err := s.store.CreateLinkCode(ctx, userID, hashLinkCode(code), now, expiresAt); err != nil {
+		return "", time.Time{}, validate.Wrap(op, err)
+	}
+
+	return code[:linkCodeLen/2] + "-" + code[linkCodeLen/2:], expiresAt, nil
+}
+
+// RedeemLinkCode binds the calling identity to the account that issued the code.
+// Returns the user ID the identity now belongs to.
+func (s *LinkService) RedeemLinkCode(ctx context.Context, userID int64, transport, externalID, code string) (int64, error) {
+	const op = "service.LinkService.RedeemLinkCode"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	code = normalizeLinkCode(code)
+	if len(code) != linkCodeLen {
+		return 0, validate.Wrap(op, domain.ErrLinkCodeInvalid)
+	}
+
+	target, err := s.store.RedeemLinkCode(ctx, hashLinkCode(code), s.now().UTC(), userID, transport, externalID)
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return target, nil
+}
+
+// Unlink detaches identities of transport from userID; empty externalID means all of them.
+func (s *LinkService) Unlink(ctx context.Context, userID int64, transport, externalID string) (int64, error) {
+	const op = "service.LinkService.Unlink"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	n, err := s.store.UnbindIdentities(ctx, userID, transport, externalID)
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return n, nil
+}
+
+// randomLinkCode returns linkCodeLen symbols from linkCodeAlphabet without modulo bias.
+func randomLinkCode() (string, error) {
+	const max = 256 - 256%len(linkCodeAlphabet)
+
+	out := make([]byte, 0, linkCodeLen)
+	buf := make([]byte, 16)
+
+	for len(out) < linkCodeLen {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+
+		for _, b := range buf {
+			if int(b) >= max || len(out) == linkCodeLen {
+				continue
+			}
+			out = append(out, linkCodeAlphabet[int(b)%len(linkCodeAlphabet)])
+		}
+	}
+
+	return string(out), nil
+}
+
+// normalizeLinkCode uppercases the code and drops separators users may type.
+func normalizeLinkCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '\t':
+			return -1
+		}
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, code)
+}
+
+func hashLinkCode(code string) []byte {
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
@@ -14,9 +14,35 @@ func NewPaymentService(store PaymentStore) *PaymentService {
 	return &PaymentService{store: store}
 }
 
-func (s *PaymentService) AddPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType domain.PaymentType) error {
+func (s *PaymentService) AddPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, payoutType domain.PaymentType) (int64, error) {
 	const op = "service.PaymentService.AddPayment"
 
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateAmount(amount); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDate(at); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	note = strings.TrimSpace(note)
+
+	// Delegate to storage; it applies DATE cast and NULLIF on note.
+	id, err := s.store.InsertPayment(ctx, userID, at, amount, note, payoutType)
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	return id, nil
+}
+
+// UpdatePayment replaces date, amount and note of an active payment owned by userID.
+// Returns domain.ErrEntryNotFound if there is no such payment or it was undone.
+func (s *PaymentService) UpdatePayment(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) error {
+	const op = "service.PaymentService.UpdatePayment"
+
 	if err := validate.ValidateUserID(userID); err != nil {
 		return validate.Wrap(op, err)
 	}
@@ -30,10 +56,15 @@ func (s *PaymentService) AddPayment(ctx context.Context, userID int64, at time.T
 	}
 	note = strings.TrimSpace(note)
 
-	// Delegate to storage; it applies DATE cast and NULLIF on note.
-	if err := s.store.InsertPayment(ctx, userID, at, amount, note, payoutType); err != nil {
+	ok, err := s.store.UpdatePayment(ctx, userID, id, at, amount, note)
+	if err != nil {
 		return validate.Wrap(op, err)
 	}
+
+	if !ok {
+		return validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
 	return nil
 }
 
@@ -52,6 +83,40 @@ func (s *PaymentService) UndoLastYear(ctx context.Context, userID int64, now tim
 	return amount, at, note, pType, ok, nil
 }
 
+// UndoPayment voids the active payment id of userID if it is of paymentType, whatever its date.
+// ok=false if the user has no such payment.
+func (s *PaymentService) UndoPayment(ctx context.Context, userID, id int64, now time.Time, paymentType domain.PaymentType) (int64, time.Time, string, bool, error) {
+	const op = "service.PaymentService.UndoPayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	amount, at, note, ok, err := s.store.VoidPayment(ctx, userID, id, now.UTC(), paymentType)
+
+	if err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	return amount, at, note, ok, nil
+}
+
+// GetPayment returns the active payment id of userID; ok=false if there is none.
+func (s *PaymentService) GetPayment(ctx context.Context, userID, id int64) (domain.Payment, bool, error) {
+	const op = "service.PaymentService.GetPayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Payment{}, false, validate.Wrap(op, err)
+	}
+
+	payment, ok, err := s.store.GetPayment(ctx, userID, id)
+	if err != nil {
+		return domain.Payment{}, false, validate.Wrap(op, err)
+	}
+
+	return payment, ok, nil
+}
+
 func (s *PaymentService) SumPayments(ctx context.Context, userID int64, from, to time.Time) (int64, int64, error) {
 	const op = "service.PaymentService.SumPayments"
 
@@ -71,3 +136,23 @@ func (s *PaymentService) SumPayments(ctx context.Context, userID int64, from, to
 
 	return sumContrib, sumAdvance, nil
 }
+
+// ListPayments returns active payments of both types in [from..to] inclusive, oldest first.
+func (s *PaymentService) ListPayments(ctx context.Context, userID int64, from, to time.Time) ([]domain.Payment, error) {
+	const op = "service.PaymentService.ListPayments"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	items, err := s.store.ListPayments(ctx, userID, from, to)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return items, nil
+}
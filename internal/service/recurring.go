@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// NewRecurringService wires the recurring store. If now is nil, time.Now will be used.
+func NewRecurringService(store RecurringStore, now func() time.Time) *RecurringService {
+	if now == nil {
+		now = time.Now
+	}
+	return &RecurringService{store: store, now: now}
+}
+
+// AddRule schedules a recurring income; the first occurrence is the first matching day from today on.
+func (s *RecurringService) AddRule(ctx context.Context, userID int64, rule domain.RecurringRule) (domain.RecurringRule, error) {
+	const op = "service.RecurringService.AddRule"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateAmount(rule.Amount); err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, err)
+	}
+
+	if err := validateSchedule(rule.Period, rule.Day); err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, err)
+	}
+
+	rule.UserID = userID
+	rule.Note = strings.TrimSpace(rule.Note)
+	rule.Paused = false
+	rule.Pending = 0
+	rule.NextDue = nextDue(rule, s.now())
+
+	id, err := s.store.CreateRecurringRule(ctx, rule)
+	if err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, err)
+	}
+
+	rule.ID = id
+
+	return rule, nil
+}
+
+func (s *RecurringService) ListRules(ctx context.Context, userID int64) ([]domain.RecurringRule, error) {
+	const op = "service.RecurringService.ListRules"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rules, err := s.store.ListRecurringRules(ctx, userID)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return rules, nil
+}
+
+// PauseRule stops a rule from running until it is resumed.
+func (s *RecurringService) PauseRule(ctx context.Context, userID, ruleID int64) error {
+	const op = "service.RecurringService.PauseRule"
+
+	ok, err := s.store.SetRecurringPaused(ctx, userID, ruleID, true, time.Time{})
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	return nil
+}
+
+// ResumeRule restarts a paused rule from today: dates missed while it was paused are not caught up.
+func (s *RecurringService) ResumeRule(ctx context.Context, userID, ruleID int64) (domain.RecurringRule, error) {
+	const op = "service.RecurringService.ResumeRule"
+
+	rule, err := s.findRule(ctx, userID, ruleID)
+	if err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, err)
+	}
+
+	rule.Paused = false
+	rule.NextDue = nextDue(rule, s.now())
+
+	ok, err := s.store.SetRecurringPaused(ctx, userID, ruleID, false, rule.NextDue)
+	if err != nil {
+		return domain.RecurringRule{}, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return domain.RecurringRule{}, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	return rule, nil
+}
+
+func (s *RecurringService) DeleteRule(ctx context.Context, userID, ruleID int64) error {
+	const op = "service.RecurringService.DeleteRule"
+
+	ok, err := s.store.DeleteRecurringRule(ctx, userID, ruleID)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	return nil
+}
+
+// ConfirmRuns creates the incomes of the occurrences of ruleID waiting for confirmation.
+func (s *RecurringService) ConfirmRuns(ctx context.Context, userID, ruleID int64) ([]domain.RecurringRun, error) {
+	const op = "service.RecurringService.ConfirmRuns"
+
+	runs, err := s.resolve(ctx, userID, ruleID, true)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return runs, nil
+}
+
+// SkipRuns drops the occurrences of ruleID waiting for confirmation without creating incomes.
+func (s *RecurringService) SkipRuns(ctx context.Context, userID, ruleID int64) ([]domain.RecurringRun, error) {
+	const op = "service.RecurringService.SkipRuns"
+
+	runs, err := s.resolve(ctx, userID, ruleID, false)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return runs, nil
+}
+
+// RunDue runs every occurrence due up to today, catching up dates missed while the
+// bot was down; each (rule, date) runs once even if several instances call RunDue.
+// It returns the occurrences it ran, created or waiting for confirmation. A failing
+// rule does not stop the others; their errors are joined.
+func (s *RecurringService) RunDue(ctx context.Context) ([]domain.RecurringRun, error) {
+	const op = "service.RecurringService.RunDue"
+
+	today := period.Day(s.now())
+
+	rules, err := s.store.DueRecurringRules(ctx, today)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	var (
+		runs []domain.RecurringRun
+		errs []error
+	)
+
+	for _, rule := range rules {
+		for due := rule.NextDue; !due.After(today); due = nextAfter(rule, due) {
+			run, ok, err := s.store.RunRecurring(ctx, rule.ID, due, nextAfter(rule, due))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %d on %s: %w", rule.ID, due.Format(time.DateOnly), err))
+				break
+			}
+
+			if ok {
+				runs = append(runs, run)
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return runs, validate.Wrap(op, err)
+	}
+
+	return runs, nil
+}
+
+func (s *RecurringService) resolve(ctx context.Context, userID, ruleID int64, confirm bool) ([]domain.RecurringRun, error) {
+	if _, err := s.findRule(ctx, userID, ruleID); err != nil {
+		return nil, err
+	}
+
+	return s.store.ResolveRecurringRuns(ctx, userID, ruleID, confirm)
+}
+
+// findRule returns the rule of userID with ruleID, or domain.ErrEntryNotFound.
+func (s *RecurringService) findRule(ctx context.Context, userID, ruleID int64) (domain.RecurringRule, error) {
+	rules, err := s.store.ListRecurringRules(ctx, userID)
+	if err != nil {
+		return domain.RecurringRule{}, err
+	}
+
+	for _, r := range rules {
+		if r.ID == ruleID {
+			return r, nil
+		}
+	}
+
+	return domain.RecurringRule{}, domain.ErrEntryNotFound
+}
+
+// validateSchedule checks the day against the period: 1..31 for monthly, 1..7 for weekly.
+func validateSchedule(p domain.RecurringPeriod, day int) error {
+	switch {
+	case p == domain.RecurringMonthly && day >= 1 && day <= 31:
+		return nil
+	case p == domain.RecurringWeekly && day >= 1 && day <= 7:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s on %d", ErrInvalidSchedule, p, day)
+}
+
+// nextDue returns the first occurrence of rule on or after the day of t.
+func nextDue(rule domain.RecurringRule, t time.Time) time.Time {
+	if rule.Period == domain.RecurringWeekly {
+		// Day is ISO: 1 = Monday .. 7 = Sunday; time.Weekday has Sunday = 0.
+		return period.WeeklyOnOrAfter(t, time.Weekday(rule.Day%7))
+	}
+
+	return period.MonthlyOnOrAfter(t, rule.Day)
+}
+
+// nextAfter returns the occurrence of rule that follows due.
+func nextAfter(rule domain.RecurringRule, due time.Time) time.Time {
+	return nextDue(rule, due.AddDate(0, 0, 1))
+}
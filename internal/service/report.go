@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+func NewReportService(store ReportStore, provider tax.Provider) *ReportService {
+	return &ReportService{store: store, provider: provider}
+}
+
+// Report breaks the income of the days from..to (at most a year) down by calendar month or
+// ISO week. Every bucket of the period gets a row, empty ones too; the first and the last
+// are cut to the period. The tax of a row is charged at the rate of the policy of its first day.
+func (s *ReportService) Report(ctx context.Context, userID int64, bucket domain.ReportBucket, from, to time.Time) (domain.Report, error) {
+	const op = "service.ReportService.Report"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Report{}, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateReportBucket(bucket); err != nil {
+		return domain.Report{}, validate.Wrap(op, err)
+	}
+
+	from, to = period.Day(from), period.Day(to)
+
+	if to.Before(from) || !to.Before(from.AddDate(1, 0, 0)) {
+		return domain.Report{}, validate.Wrap(op, validate.ErrInvalidDateRange)
+	}
+
+	scheme, err := s.store.GetUserScheme(ctx, userID)
+	if err != nil {
+		return domain.Report{}, validate.Wrap(op, err)
+	}
+
+	stats, err := s.store.IncomeStatsByBucket(ctx, userID, bucket, from, to)
+	if err != nil {
+		return domain.Report{}, validate.Wrap(op, err)
+	}
+
+	bounds := period.MonthBounds
+	if bucket == domain.BucketWeek {
+		bounds = period.ISOWeekBounds
+	}
+
+	byStart := make(map[time.Time]domain.IncomeStats, len(stats))
+	for _, b := range stats {
+		byStart[b.Start] = b
+	}
+
+	r := domain.Report{Bucket: bucket, From: from, To: to, Total: domain.ReportRow{From: from, To: to}}
+
+	for start, end := bounds(from); !start.After(to); start, end = bounds(end.AddDate(0, 0, 1)) {
+		b := byStart[start]
+
+		row := domain.ReportRow{
+			From:  maxTime(start, from),
+			To:    minTime(end, to),
+			Count: b.Count,
+			Sum:   b.Sum,
+			Max:   b.Max,
+		}
+
+		if row.Count > 0 {
+			row.Avg = row.Sum / row.Count
+
+			policy, err := s.provider.ForDate(scheme, row.From)
+			if err != nil {
+				return domain.Report{}, validate.Wrap(op, err)
+			}
+
+			row.Tax = row.Sum * policy.BaseRateBP / domain.BpDen
+		}
+
+		r.Rows = append(r.Rows, row)
+
+		r.Total.Count += row.Count
+		r.Total.Sum += row.Sum
+		r.Total.Max = max(r.Total.Max, row.Max)
+		r.Total.Tax += row.Tax
+	}
+
+	if r.Total.Count > 0 {
+		r.Total.Avg = r.Total.Sum / r.Total.Count
+	}
+
+	return r, nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+func NewSearchService(store SearchStore) *SearchService {
+	return &SearchService{store: store}
+}
+
+// FindNotes returns up to limit active entries of the days from..to whose note contains
+// query, case-insensitively and with ё matching е; newest first.
+func (s *SearchService) FindNotes(ctx context.Context, userID int64, query string, from, to time.Time, limit int) ([]domain.NoteMatch, error) {
+	const op = "service.SearchService.FindNotes"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, validate.Wrap(op, validate.ErrEmptyString)
+	}
+
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	matches, err := s.store.SearchNotes(ctx, userID, query, period.Day(from), period.Day(to), limit)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/tax"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+// NewTaxPaymentService wires the requisites store and the totals the due tax comes from.
+// If now is nil, time.Now will be used.
+func NewTaxPaymentService(store TaxPaymentStore, total domain.TotalUsecase, now func() time.Time) *TaxPaymentService {
+	if now == nil {
+		now = time.Now
+	}
+	return &TaxPaymentService{store: store, total: total, now: now}
+}
+
+// PaymentOrder returns the order of the single tax payment dated today. Without an amount
+// it pays what /total shows as due for the quarter of ref: the tax of the quarter less
+// the contributions and advance payments made in it, which may be 0.
+func (s *TaxPaymentService) PaymentOrder(ctx context.Context, userID int64, ref time.Time, amount int64) (domain.PaymentOrder, error) {
+	const op = "service.TaxPaymentService.PaymentOrder"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.PaymentOrder{}, validate.Wrap(op, err)
+	}
+
+	if amount != 0 {
+		if err := validate.ValidateAmount(amount); err != nil {
+			return domain.PaymentOrder{}, validate.Wrap(op, err)
+		}
+	}
+
+	payer, err := s.store.GetRequisites(ctx, userID)
+	if err != nil {
+		return domain.PaymentOrder{}, validate.Wrap(op, err)
+	}
+
+	if !payer.Complete() {
+		return domain.PaymentOrder{}, validate.Wrap(op, domain.ErrRequisitesMissing)
+	}
+
+	if amount == 0 {
+		totals, err := s.total.SumQuarter(ctx, userID, ref)
+		if err != nil {
+			return domain.PaymentOrder{}, validate.Wrap(op, err)
+		}
+		amount = totals.Due
+	}
+
+	year, quarter := period.QuarterOf(ref)
+
+	return domain.PaymentOrder{
+		Year:    year,
+		Quarter: quarter,
+		Date:    period.Day(s.now()),
+		Amount:  amount,
+		Payer:   payer,
+		Payee:   tax.ENPPayee,
+		Purpose: fmt.Sprintf("Единый налоговый платеж за %d квартал %d г.", quarter, year),
+	}, nil
+}
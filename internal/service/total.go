@@ -53,6 +53,7 @@ func (s *TotalService) SumYearToDate(ctx context.Context, userID int64, now time
 // SumQuarter aggregates incomes and payments for the quarter that contains ref,
 // selects tax policy for the user's scheme at the quarter end, and computes totals.
 //   - 1% annual extra is NOT included here.
+//   - VAT (USN payers above the VAT threshold) is reported next to the tax, not deducted.
 func SumQuarter(
 	ctx context.Context,
 	getUserScheme func(ctx context.Context, userID int64) (domain.TaxScheme, error),
@@ -87,6 +88,11 @@ func SumQuarter(
 		return domain.Totals{}, validate.Wrap(op, err)
 	}
 
+	vat, err := vatByMonth(ctx, sumIncomes, policy, userID, to)
+	if err != nil {
+		return domain.Totals{}, validate.Wrap(op, err)
+	}
+
 	// Deterministic integer math (kopecks only).
 
 	taxAmount := incomeSum * policy.BaseRateBP / domain.BpDen
@@ -104,6 +110,8 @@ func SumQuarter(
 		AdvanceSum:     advanceSum,
 		ContribApplied: contribApplied,
 		Due:            due,
+		VATRateBP:      vat.rateOn(ref),
+		VAT:            vat.included(from, to),
 	}, nil
 }
 
@@ -140,6 +148,11 @@ func SumYearToDate(
 		return domain.Totals{}, validate.Wrap(op, err)
 	}
 
+	vat, err := vatByMonth(ctx, sumIncomes, policy, userID, to)
+	if err != nil {
+		return domain.Totals{}, validate.Wrap(op, err)
+	}
+
 	taxAmount := incomeSum * policy.BaseRateBP / domain.BpDen
 
 	contribApplied := min(contribSum, taxAmount)
@@ -155,5 +168,7 @@ func SumYearToDate(
 		AdvanceSum:     advanceSum,
 		ContribApplied: contribApplied,
 		Due:            due,
+		VATRateBP:      vat.rateOn(ref),
+		VAT:            vat.included(from, to),
 	}, nil
 }
@@ -18,6 +18,60 @@ type PaymentService struct {
 	store PaymentStore
 }
 
+// LinkService binds several transport identities to one account via one-time codes
+type LinkService struct {
+	store LinkStore
+	now   func() time.Time
+}
+
+// TokenService issues and verifies per-user API tokens
+type TokenService struct {
+	store TokenStore
+	now   func() time.Time
+}
+
+// RecurringService schedules recurring incomes and runs them when due
+type RecurringService struct {
+	store RecurringStore
+	now   func() time.Time
+}
+
+// ChartService builds the monthly income chart of a year
+type ChartService struct {
+	store    ChartStore
+	provider tax.Provider
+}
+
+// SearchService finds incomes and payments by their notes
+type SearchService struct {
+	store SearchStore
+}
+
+// ReportService breaks the income of a period down by month or week
+type ReportService struct {
+	store    ReportStore
+	provider tax.Provider
+}
+
+// InvoiceService issues invoices to clients, records their payments and finds overdue ones
+type InvoiceService struct {
+	store InvoiceStore
+	now   func() time.Time
+}
+
+// ActService issues acts of completed work for invoices and incomes
+type ActService struct {
+	store ActStore
+	now   func() time.Time
+}
+
+// TaxPaymentService prepares payment orders of the single tax payment due for a quarter
+type TaxPaymentService struct {
+	store TaxPaymentStore
+	total domain.TotalUsecase
+	now   func() time.Time
+}
+
 // TotalService handles total calculation business logic
 type TotalService struct {
 	getUserScheme func(ctx context.Context, userID int64) (domain.TaxScheme, error)
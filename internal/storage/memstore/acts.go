@@ -0,0 +1,126 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) CreateAct(ctx context.Context, act domain.Act) (id, number int64, err error) {
+	const op = "memstore.CreateAct"
+
+	if err := validate.ValidateUserID(act.UserID); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(act.Amount); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+	if (act.InvoiceID == 0) == (act.IncomeID == 0) {
+		return 0, 0, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	issued := utcDay(act.IssuedOn)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.counterparties[act.Client.ID]; !ok || c.UserID != act.UserID {
+		return 0, 0, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	if act.InvoiceID != 0 {
+		if inv, ok := s.invoices[act.InvoiceID]; !ok || inv.UserID != act.UserID {
+			return 0, 0, validate.Wrap(op, domain.ErrEntryNotFound)
+		}
+	}
+
+	if act.IncomeID != 0 {
+		if _, ok := s.activeIncome(act.UserID, act.IncomeID); !ok {
+			return 0, 0, validate.Wrap(op, domain.ErrEntryNotFound)
+		}
+	}
+
+	for _, r := range s.acts {
+		if (act.InvoiceID != 0 && r.InvoiceID == act.InvoiceID) || (act.IncomeID != 0 && r.IncomeID == act.IncomeID) {
+			return 0, 0, validate.Wrap(op, domain.ErrActExists)
+		}
+	}
+
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	for _, r := range s.acts {
+		if r.UserID == act.UserID && r.IssuedOn.Year() == issued.Year() {
+			number = max(number, r.Number)
+		}
+	}
+
+	id, number = s.nextActID, number+1
+
+	s.write(change{Op: opAct, Act: &ActRecord{
+		ID:          id,
+		UserID:      act.UserID,
+		Number:      number,
+		ClientID:    act.Client.ID,
+		IssuedOn:    issued,
+		Amount:      act.Amount,
+		Description: act.Description,
+		InvoiceID:   act.InvoiceID,
+		IncomeID:    act.IncomeID,
+	}})
+
+	if err := s.commit(); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	return id, number, nil
+}
+
+func (s *Store) ListActs(ctx context.Context, userID, actID int64) ([]domain.Act, error) {
+	const op = "memstore.ListActs"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []domain.Act
+
+	for _, r := range s.acts {
+		if r.UserID != userID || (actID != 0 && r.ID != actID) {
+			continue
+		}
+
+		act := domain.Act{
+			ID:          r.ID,
+			UserID:      r.UserID,
+			Number:      r.Number,
+			Client:      counterparty(s.counterparties[r.ClientID]),
+			IssuedOn:    r.IssuedOn,
+			Amount:      r.Amount,
+			Description: r.Description,
+			InvoiceID:   r.InvoiceID,
+			IncomeID:    r.IncomeID,
+		}
+
+		if inv, ok := s.invoices[r.InvoiceID]; ok {
+			act.InvoiceNumber, act.InvoiceIssuedOn = inv.Number, inv.IssuedOn
+		}
+
+		out = append(out, act)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].IssuedOn.Equal(out[j].IssuedOn) {
+			return out[i].IssuedOn.After(out[j].IssuedOn)
+		}
+		return out[i].ID > out[j].ID
+	})
+
+	return out, nil
+}
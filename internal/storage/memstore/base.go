@@ -2,17 +2,92 @@ package memstore
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
 func NewStore() *Store {
 	return &Store{
-		nextUserID: 1,
-		identities: make(map[string]UserRecord),
-		incomes:    make(map[int64][]IncomeRecord),
-		payments:   make(map[int64][]PaymentRecord),
+		nextUserID:         1,
+		nextIncomeID:       1,
+		nextPaymentID:      1,
+		nextTokenID:        1,
+		nextRecurringID:    1,
+		nextCounterpartyID: 1,
+		nextInvoiceID:      1,
+		nextActID:          1,
+		identities:         make(map[string]UserRecord),
+		users:              make(map[int64]domain.TaxScheme),
+		langs:              make(map[int64]string),
+		incomes:            make(map[int64][]IncomeRecord),
+		payments:           make(map[int64][]PaymentRecord),
+		apiTokens:          make(map[int64]*APITokenRecord),
+		linkCodes:          make(map[string]*LinkCodeRecord),
+		processed:          make(map[string]time.Time),
+		pollOffsets:        make(map[string]int64),
+		messages:           make(map[string]MessageEntryRecord),
+		chats:              make(map[int64]int64),
+		recurringRules:     make(map[int64]RecurringRuleRecord),
+		recurringRuns:      make(map[int64][]RecurringRunRecord),
+		counterparties:     make(map[int64]CounterpartyRecord),
+		requisites:         make(map[int64]domain.Requisites),
+		invoices:           make(map[int64]InvoiceRecord),
+		acts:               make(map[int64]ActRecord),
 	}
 }
 
+// Close writes a final snapshot and closes the journal of a file-backed store.
 func (s *Store) Close(ctx context.Context) error {
+	const op = "memstore.Close"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.journal == nil {
+		return nil
+	}
+
+	var err error
+	if s.failed == nil {
+		err = s.compact()
+	}
+
+	err = errors.Join(err, s.journal.close())
+	s.journal = nil
+
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Ping fails once a journal write has failed (used by the readiness probe).
+func (s *Store) Ping(ctx context.Context) error {
+	const op = "memstore.Ping"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.failed != nil {
+		return validate.Wrap(op, fmt.Errorf("%w: %w", ErrJournalFailed, s.failed))
+	}
+
 	return nil
 }
+
+// utcDay truncates t to midnight of its UTC calendar day, like the DATE columns in postgres.
+func utcDay(t time.Time) time.Time {
+	utc := t.UTC()
+
+	return time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// inRange reports whether the day at lies within [from..to], compared by calendar days.
+func inRange(at, from, to time.Time) bool {
+	return !at.Before(utcDay(from)) && !at.After(utcDay(to))
+}
@@ -0,0 +1,219 @@
+package memstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+)
+
+// Journal operations.
+const (
+	opUser           = "user"            // UserID, Scheme
+	opIdentity       = "identity"        // Key, UserID
+	opUnbind         = "unbind"          // Key
+	opLang           = "lang"            // UserID, Lang ("" clears it)
+	opIncome         = "income"          // UserID, Income
+	opPayment        = "payment"         // UserID, Payment
+	opToken          = "token"           // Token
+	opLinkCode       = "link_code"       // Key, LinkCode
+	opDropLinkCode   = "drop_link_code"  // Key
+	opProcessed      = "processed"       // Key, At
+	opPruneProcessed = "prune_processed" // At
+	opPollOffset     = "poll_offset"     // Key, Offset
+	opMessage        = "message"         // Key, Message
+	opChat           = "chat"            // UserID, ChatID
+	opRecurringRule  = "recurring_rule"  // Rule
+	opDropRecurring  = "drop_recurring"  // RuleID, with its runs
+	opRecurringRun   = "recurring_run"   // Run
+	opCounterparty   = "client"          // Client
+	opRequisites     = "requisites"      // UserID, Req
+	opInvoice        = "invoice"         // Invoice
+	opAct            = "act"             // Act
+)
+
+// write applies ch and, in file-backed mode, queues it for the journal. Every
+// mutation goes through here, so that replaying the journal repeats it exactly.
+// Caller must hold s.mu and call commit before releasing it.
+func (s *Store) write(ch change) {
+	_ = s.apply(ch)
+
+	if s.journal != nil {
+		s.pending = append(s.pending, ch)
+	}
+}
+
+// commit appends the changes queued by write as one journal record and compacts the
+// journal when it is due. After a failed append every later commit fails too: memory
+// may be ahead of the disk, and a restart goes back to what the journal holds.
+// Caller must hold s.mu.
+func (s *Store) commit() error {
+	pending := s.pending
+	s.pending = nil
+
+	if s.journal == nil || len(pending) == 0 {
+		return nil
+	}
+
+	if s.failed != nil {
+		return fmt.Errorf("%w: %w", ErrJournalFailed, s.failed)
+	}
+
+	payload, err := json.Marshal(journalRecord{Seq: s.seq + 1, Changes: pending})
+	if err == nil {
+		err = s.journal.append(payload)
+	}
+
+	if err != nil {
+		s.failed = err
+		return fmt.Errorf("%w: %w", ErrJournalFailed, err)
+	}
+
+	s.seq++
+
+	if s.journal.records >= s.journal.opts.SnapshotEvery {
+		// The record is durable as far as the policy goes: a failed compaction
+		// only leaves a longer journal, retried at the next write.
+		_ = s.compact()
+	}
+
+	return nil
+}
+
+// apply performs ch on the maps. It is used both live and when replaying the journal.
+func (s *Store) apply(ch change) error {
+	switch ch.Op {
+	case opUser:
+		s.users[ch.UserID] = ch.Scheme
+		s.nextUserID = max(s.nextUserID, ch.UserID+1)
+
+	case opIdentity:
+		s.identities[ch.Key] = UserRecord{UserID: ch.UserID, Scheme: domain.TaxSchemeUSN6}
+
+	case opUnbind:
+		delete(s.identities, ch.Key)
+
+	case opLang:
+		if ch.Lang == "" {
+			delete(s.langs, ch.UserID)
+		} else {
+			s.langs[ch.UserID] = ch.Lang
+		}
+
+	case opIncome:
+		if ch.Income == nil {
+			return fmt.Errorf("%s without income", ch.Op)
+		}
+		s.incomes[ch.UserID] = upsertRow(s.incomes[ch.UserID], *ch.Income, func(r IncomeRecord) int64 { return r.ID })
+		s.nextIncomeID = max(s.nextIncomeID, ch.Income.ID+1)
+
+	case opPayment:
+		if ch.Payment == nil {
+			return fmt.Errorf("%s without payment", ch.Op)
+		}
+		s.payments[ch.UserID] = upsertRow(s.payments[ch.UserID], *ch.Payment, func(r PaymentRecord) int64 { return r.ID })
+		s.nextPaymentID = max(s.nextPaymentID, ch.Payment.ID+1)
+
+	case opToken:
+		if ch.Token == nil {
+			return fmt.Errorf("%s without token", ch.Op)
+		}
+		t := *ch.Token
+		s.apiTokens[t.ID] = &t
+		s.nextTokenID = max(s.nextTokenID, t.ID+1)
+
+	case opLinkCode:
+		if ch.LinkCode == nil {
+			return fmt.Errorf("%s without link code", ch.Op)
+		}
+		c := *ch.LinkCode
+		s.linkCodes[ch.Key] = &c
+
+	case opDropLinkCode:
+		delete(s.linkCodes, ch.Key)
+
+	case opProcessed:
+		s.processed[ch.Key] = ch.At
+
+	case opPruneProcessed:
+		for k, at := range s.processed {
+			if at.Before(ch.At) {
+				delete(s.processed, k)
+			}
+		}
+
+	case opPollOffset:
+		s.pollOffsets[ch.Key] = max(s.pollOffsets[ch.Key], ch.Offset)
+
+	case opMessage:
+		if ch.Message == nil {
+			return fmt.Errorf("%s without message", ch.Op)
+		}
+		s.messages[ch.Key] = *ch.Message
+
+	case opChat:
+		s.chats[ch.UserID] = ch.ChatID
+
+	case opRecurringRule:
+		if ch.Rule == nil {
+			return fmt.Errorf("%s without rule", ch.Op)
+		}
+		s.recurringRules[ch.Rule.ID] = *ch.Rule
+		s.nextRecurringID = max(s.nextRecurringID, ch.Rule.ID+1)
+
+	case opDropRecurring:
+		delete(s.recurringRules, ch.RuleID)
+		delete(s.recurringRuns, ch.RuleID)
+
+	case opRecurringRun:
+		if ch.Run == nil {
+			return fmt.Errorf("%s without run", ch.Op)
+		}
+		r := *ch.Run
+		s.recurringRuns[r.RuleID] = upsertRow(s.recurringRuns[r.RuleID], r, func(r RecurringRunRecord) int64 { return r.Due.Unix() })
+
+	case opCounterparty:
+		if ch.Client == nil {
+			return fmt.Errorf("%s without client", ch.Op)
+		}
+		s.counterparties[ch.Client.ID] = *ch.Client
+		s.nextCounterpartyID = max(s.nextCounterpartyID, ch.Client.ID+1)
+
+	case opRequisites:
+		if ch.Req == nil {
+			return fmt.Errorf("%s without requisites", ch.Op)
+		}
+		s.requisites[ch.UserID] = *ch.Req
+
+	case opInvoice:
+		if ch.Invoice == nil {
+			return fmt.Errorf("%s without invoice", ch.Op)
+		}
+		s.invoices[ch.Invoice.ID] = *ch.Invoice
+		s.nextInvoiceID = max(s.nextInvoiceID, ch.Invoice.ID+1)
+
+	case opAct:
+		if ch.Act == nil {
+			return fmt.Errorf("%s without act", ch.Op)
+		}
+		s.acts[ch.Act.ID] = *ch.Act
+		s.nextActID = max(s.nextActID, ch.Act.ID+1)
+
+	default:
+		return fmt.Errorf("unknown operation %q", ch.Op)
+	}
+
+	return nil
+}
+
+// upsertRow replaces the row with the same ID, keeping its position, or appends it.
+func upsertRow[R any](rows []R, row R, id func(R) int64) []R {
+	for i := range rows {
+		if id(rows[i]) == id(row) {
+			rows[i] = row
+			return rows
+		}
+	}
+
+	return append(rows, row)
+}
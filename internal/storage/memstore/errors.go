@@ -0,0 +1,13 @@
+package memstore
+
+import "errors"
+
+var (
+	ErrEmptyDSN       = errors.New("dsn is empty")
+	ErrInvalidDSN     = errors.New("dsn must look like memstore:path/to/dir?fsync=always|interval|never&snapshot_every=N")
+	ErrOpen           = errors.New("open error")
+	ErrSnapshot       = errors.New("snapshot error")
+	ErrCorruptJournal = errors.New("journal is corrupt")
+	ErrCorruptState   = errors.New("snapshot is corrupt")
+	ErrJournalFailed  = errors.New("journal write failed, changes are not persisted until restart")
+)
@@ -0,0 +1,12 @@
+package memstore
+
+// Crash closes the journal without the final snapshot, as if the process died.
+func (s *Store) Crash() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.journal.close()
+	s.journal = nil
+
+	return err
+}
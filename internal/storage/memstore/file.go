@@ -0,0 +1,342 @@
+package memstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+const (
+	// Scheme is the DATABASE_URL scheme selecting the file-backed store.
+	Scheme = "memstore"
+
+	defaultSyncInterval  = time.Second
+	defaultSnapshotEvery = 1000
+)
+
+// DefaultOptions fsync every write and compact the journal every 1000 records.
+func DefaultOptions() Options {
+	return Options{
+		Sync:          SyncAlways,
+		SyncInterval:  defaultSyncInterval,
+		SnapshotEvery: defaultSnapshotEvery,
+	}
+}
+
+// Open opens a file-backed store: the state is kept in memory as with NewStore, every
+// write is appended to a journal in dir, and the journal is compacted into a snapshot
+// every Options.SnapshotEvery records and on Close. On open the snapshot is loaded and
+// the journal replayed; a record torn by a crash at the end of the journal is dropped.
+// Only one process may use dir at a time.
+// DSN examples: memstore:data/bot, memstore:///var/lib/ipbot?fsync=interval&fsync_interval=500ms&snapshot_every=5000.
+func Open(ctx context.Context, dsn string) (*Store, error) {
+	const op = "memstore.Open"
+
+	dir, opts, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s, err := OpenDir(dir, opts)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return s, nil
+}
+
+// ParseDSN returns the directory and options of a memstore: URL.
+func ParseDSN(dsn string) (string, Options, error) {
+	const op = "memstore.ParseDSN"
+
+	opts := DefaultOptions()
+	dsn = strings.TrimSpace(dsn)
+
+	if dsn == "" {
+		return "", opts, validate.Wrap(op, ErrEmptyDSN)
+	}
+
+	rest, ok := strings.CutPrefix(dsn, Scheme+":")
+	if !ok {
+		return "", opts, validate.Wrap(op, ErrInvalidDSN)
+	}
+
+	// memstore:///abs/dir keeps the leading slash, memstore://rel/dir does not.
+	rest = strings.TrimPrefix(rest, "//")
+	rest, query, _ := strings.Cut(rest, "?")
+
+	dir, err := url.PathUnescape(rest)
+	if err != nil || dir == "" {
+		return "", opts, validate.Wrap(op, ErrInvalidDSN)
+	}
+
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return "", opts, validate.Wrap(op, ErrInvalidDSN)
+	}
+
+	for key, vals := range q {
+		v := vals[len(vals)-1]
+
+		switch key {
+		case "fsync":
+			switch p := SyncPolicy(v); p {
+			case SyncAlways, SyncInterval, SyncNever:
+				opts.Sync = p
+			default:
+				return "", opts, validate.Wrap(op, fmt.Errorf("%w: fsync=%q", ErrInvalidDSN, v))
+			}
+		case "fsync_interval":
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return "", opts, validate.Wrap(op, fmt.Errorf("%w: fsync_interval=%q", ErrInvalidDSN, v))
+			}
+			opts.SyncInterval = d
+		case "snapshot_every":
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return "", opts, validate.Wrap(op, fmt.Errorf("%w: snapshot_every=%q", ErrInvalidDSN, v))
+			}
+			opts.SnapshotEvery = n
+		default:
+			return "", opts, validate.Wrap(op, fmt.Errorf("%w: unknown parameter %q", ErrInvalidDSN, key))
+		}
+	}
+
+	return dir, opts, nil
+}
+
+// OpenDir opens a file-backed store in dir (created if needed), see Open.
+func OpenDir(dir string, opts Options) (*Store, error) {
+	const op = "memstore.OpenDir"
+
+	def := DefaultOptions()
+	if opts.Sync == "" {
+		opts.Sync = def.Sync
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = def.SyncInterval
+	}
+	if opts.SnapshotEvery <= 0 {
+		opts.SnapshotEvery = def.SnapshotEvery
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, validate.Wrap(op, fmt.Errorf("%w: %w", ErrOpen, err))
+	}
+
+	s := NewStore()
+
+	if err := s.loadSnapshot(dir); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	replayed := 0
+
+	size, err := readJournal(dir, func(payload []byte) error {
+		var rec journalRecord
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return err
+		}
+
+		// Records up to the snapshot are left over from a crash between writing
+		// the snapshot and emptying the journal.
+		if rec.Seq <= s.seq {
+			return nil
+		}
+		if rec.Seq != s.seq+1 {
+			return fmt.Errorf("record %d after %d", rec.Seq, s.seq)
+		}
+
+		for _, ch := range rec.Changes {
+			if err := s.apply(ch); err != nil {
+				return err
+			}
+		}
+
+		s.seq = rec.Seq
+		replayed++
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	j, err := openJournal(dir, size, opts)
+	if err != nil {
+		return nil, validate.Wrap(op, fmt.Errorf("%w: %w", ErrOpen, err))
+	}
+
+	j.records = replayed
+	s.journal = j
+
+	return s, nil
+}
+
+// loadSnapshot replaces the state with the snapshot in dir, if there is one.
+func (s *Store) loadSnapshot(dir string) error {
+	b, err := os.ReadFile(filepath.Join(dir, snapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOpen, err)
+	}
+
+	payload, err := readFrame(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCorruptState, err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(payload, &snap); err != nil {
+		return fmt.Errorf("%w: %w", ErrCorruptState, err)
+	}
+
+	s.restore(snap)
+
+	return nil
+}
+
+// compact writes a snapshot of the whole state and empties the journal. The snapshot
+// replaces the previous one atomically, so a crash leaves either of them intact.
+// Caller must hold s.mu.
+func (s *Store) compact() error {
+	payload, err := json.Marshal(s.state())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSnapshot, err)
+	}
+
+	dir := s.journal.dir
+	tmp := filepath.Join(dir, snapshotFile+".tmp")
+
+	if err := writeFileSync(tmp, appendFrame(nil, payload)); err != nil {
+		return fmt.Errorf("%w: %w", ErrSnapshot, err)
+	}
+
+	if err := os.Rename(tmp, filepath.Join(dir, snapshotFile)); err != nil {
+		return fmt.Errorf("%w: %w", ErrSnapshot, err)
+	}
+
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("%w: %w", ErrSnapshot, err)
+	}
+
+	if err := s.journal.reset(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSnapshot, err)
+	}
+
+	return nil
+}
+
+func writeFileSync(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// syncDir makes a rename in dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = d.Sync()
+
+	return errors.Join(err, d.Close())
+}
+
+// state returns a snapshot sharing the maps of the store, for marshaling. Caller must hold s.mu.
+func (s *Store) state() snapshot {
+	return snapshot{
+		Seq:             s.seq,
+		NextUserID:      s.nextUserID,
+		NextIncomeID:    s.nextIncomeID,
+		NextPaymentID:   s.nextPaymentID,
+		NextTokenID:     s.nextTokenID,
+		NextRecurringID: s.nextRecurringID,
+		Identities:      s.identities,
+		Users:           s.users,
+		Langs:           s.langs,
+		Incomes:         s.incomes,
+		Payments:        s.payments,
+		APITokens:       s.apiTokens,
+		LinkCodes:       s.linkCodes,
+		Processed:       s.processed,
+		PollOffsets:     s.pollOffsets,
+		Messages:        s.messages,
+		Chats:           s.chats,
+		RecurringRules:  s.recurringRules,
+		RecurringRuns:   s.recurringRuns,
+		NextClientID:    s.nextCounterpartyID,
+		NextInvoiceID:   s.nextInvoiceID,
+		Clients:         s.counterparties,
+		Requisites:      s.requisites,
+		Invoices:        s.invoices,
+		NextActID:       s.nextActID,
+		Acts:            s.acts,
+	}
+}
+
+// restore replaces the state with snap, keeping the empty maps of NewStore for missing ones.
+func (s *Store) restore(snap snapshot) {
+	s.seq = snap.Seq
+	s.nextUserID = snap.NextUserID
+	s.nextIncomeID = snap.NextIncomeID
+	s.nextPaymentID = snap.NextPaymentID
+	s.nextTokenID = snap.NextTokenID
+	s.nextRecurringID = max(snap.NextRecurringID, 1)
+	s.nextCounterpartyID = max(snap.NextClientID, 1)
+	s.nextInvoiceID = max(snap.NextInvoiceID, 1)
+	s.nextActID = max(snap.NextActID, 1)
+
+	restoreMap(&s.identities, snap.Identities)
+	restoreMap(&s.users, snap.Users)
+	restoreMap(&s.langs, snap.Langs)
+	restoreMap(&s.incomes, snap.Incomes)
+	restoreMap(&s.payments, snap.Payments)
+	restoreMap(&s.apiTokens, snap.APITokens)
+	restoreMap(&s.linkCodes, snap.LinkCodes)
+	restoreMap(&s.processed, snap.Processed)
+	restoreMap(&s.pollOffsets, snap.PollOffsets)
+	restoreMap(&s.messages, snap.Messages)
+	restoreMap(&s.chats, snap.Chats)
+	restoreMap(&s.recurringRules, snap.RecurringRules)
+	restoreMap(&s.recurringRuns, snap.RecurringRuns)
+	restoreMap(&s.counterparties, snap.Clients)
+	restoreMap(&s.requisites, snap.Requisites)
+	restoreMap(&s.invoices, snap.Invoices)
+	restoreMap(&s.acts, snap.Acts)
+}
+
+func restoreMap[K comparable, V any](dst *map[K]V, src map[K]V) {
+	if src != nil {
+		*dst = src
+	}
+}
@@ -0,0 +1,275 @@
+package memstore_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/storetest"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func openDir(t *testing.T, dir string, opts memstore.Options) *memstore.Store {
+	t.Helper()
+
+	s, err := memstore.OpenDir(dir, opts)
+	if err != nil {
+		t.Fatalf("OpenDir: %v", err)
+	}
+
+	return s
+}
+
+// fill writes one of each kind of change and returns the user.
+func fill(t *testing.T, s *memstore.Store) int64 {
+	t.Helper()
+
+	ctx := context.Background()
+
+	uid, err := s.UpsertIdentity(ctx, domain.TransportTelegram, "42", 42)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	if _, err := s.InsertIncome(ctx, uid, day("2025-02-10"), 10000, "first"); err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+	id, err := s.InsertIncome(ctx, uid, day("2025-02-11"), 20000, "second")
+	if err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+	if ok, err := s.UpdateIncome(ctx, uid, id, day("2025-02-12"), 25000, "second, fixed"); err != nil || !ok {
+		t.Fatalf("UpdateIncome = %v, %v", ok, err)
+	}
+	if _, err := s.InsertIncome(ctx, uid, day("2025-02-13"), 5000, "voided"); err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day("2025-01-01"), day("2025-12-31"), time.Now()); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+
+	if _, err := s.InsertPayment(ctx, uid, day("2025-03-01"), 3000, "", domain.PaymentTypeContrib); err != nil {
+		t.Fatalf("InsertPayment: %v", err)
+	}
+	if err := s.SetUserLang(ctx, uid, "en"); err != nil {
+		t.Fatalf("SetUserLang: %v", err)
+	}
+	if err := s.SetPollOffset(ctx, domain.TransportTelegram, 77); err != nil {
+		t.Fatalf("SetPollOffset: %v", err)
+	}
+	if _, err := s.CreateAPIToken(ctx, uid, []byte("token"), "ci"); err != nil {
+		t.Fatalf("CreateAPIToken: %v", err)
+	}
+
+	return uid
+}
+
+// check verifies what fill wrote.
+func check(t *testing.T, s *memstore.Store, uid int64) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	again, err := s.UpsertIdentity(ctx, domain.TransportTelegram, "42", 42)
+	if err != nil || again != uid {
+		t.Fatalf("UpsertIdentity = %d, %v; want %d", again, err, uid)
+	}
+
+	incomes, err := s.ListIncomes(ctx, uid, day("2025-01-01"), day("2025-12-31"))
+	if err != nil {
+		t.Fatalf("ListIncomes: %v", err)
+	}
+	if len(incomes) != 2 || incomes[1].Amount != 25000 || incomes[1].Note != "second, fixed" {
+		t.Fatalf("incomes = %+v", incomes)
+	}
+
+	contrib, _, err := s.SumPayments(ctx, uid, day("2025-01-01"), day("2025-12-31"))
+	if err != nil || contrib != 3000 {
+		t.Fatalf("SumPayments = %d, %v; want 3000", contrib, err)
+	}
+
+	if lang, _ := s.GetIdentityLang(ctx, domain.TransportTelegram, "42"); lang != "en" {
+		t.Fatalf("lang = %q, want en", lang)
+	}
+	if off, _ := s.GetPollOffset(ctx, domain.TransportTelegram); off != 77 {
+		t.Fatalf("poll offset = %d, want 77", off)
+	}
+	if got, ok, _ := s.ResolveAPIToken(ctx, []byte("token"), time.Now()); !ok || got != uid {
+		t.Fatalf("ResolveAPIToken = %d, %v", got, ok)
+	}
+
+	// IDs continue where they stopped.
+	other, err := s.UpsertIdentity(ctx, domain.TransportTelegram, "43", 43)
+	if err != nil || other == uid {
+		t.Fatalf("new user = %d, %v; must differ from %d", other, err, uid)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	dir, opts, err := memstore.ParseDSN("memstore:///var/lib/ipbot?fsync=interval&fsync_interval=250ms&snapshot_every=10")
+	if err != nil {
+		t.Fatalf("ParseDSN: %v", err)
+	}
+	if dir != "/var/lib/ipbot" || opts.Sync != memstore.SyncInterval || opts.SyncInterval != 250*time.Millisecond || opts.SnapshotEvery != 10 {
+		t.Fatalf("ParseDSN = %q, %+v", dir, opts)
+	}
+
+	dir, opts, err = memstore.ParseDSN("memstore:data/bot")
+	if err != nil || dir != "data/bot" || opts != memstore.DefaultOptions() {
+		t.Fatalf("ParseDSN = %q, %+v, %v", dir, opts, err)
+	}
+
+	for _, in := range []string{"", "sqlite:bot.db", "memstore:", "memstore:d?fsync=sometimes", "memstore:d?snapshot_every=0", "memstore:d?mode=ro"} {
+		if _, _, err := memstore.ParseDSN(in); !errors.Is(err, memstore.ErrInvalidDSN) && !errors.Is(err, memstore.ErrEmptyDSN) {
+			t.Errorf("ParseDSN(%q) = %v, want an invalid DSN error", in, err)
+		}
+	}
+}
+
+func TestOpenDir_ReplaysJournalAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openDir(t, dir, memstore.Options{Sync: memstore.SyncAlways})
+	uid := fill(t, s)
+
+	if err := s.Crash(); err != nil {
+		t.Fatalf("Crash: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "snapshot.dat")); !os.IsNotExist(err) {
+		t.Fatalf("snapshot written before compaction was due: %v", err)
+	}
+
+	s = openDir(t, dir, memstore.Options{})
+	defer s.Close(context.Background())
+
+	check(t, s, uid)
+}
+
+func TestOpenDir_CompactsIntoSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openDir(t, dir, memstore.Options{Sync: memstore.SyncNever, SnapshotEvery: 3})
+	uid := fill(t, s)
+
+	if err := s.Crash(); err != nil {
+		t.Fatalf("Crash: %v", err)
+	}
+
+	// fill makes 10 journal records: three compactions, one record left in the journal.
+	if _, err := os.Stat(filepath.Join(dir, "snapshot.dat")); err != nil {
+		t.Fatalf("no snapshot: %v", err)
+	}
+
+	s = openDir(t, dir, memstore.Options{})
+	check(t, s, uid)
+
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if fi, err := os.Stat(filepath.Join(dir, "journal.log")); err != nil || fi.Size() != 0 {
+		t.Fatalf("journal after Close: %v, %v; want it empty", fi, err)
+	}
+
+	s = openDir(t, dir, memstore.Options{})
+	defer s.Close(context.Background())
+
+	check(t, s, uid)
+}
+
+func TestOpenDir_DropsTornTail(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	s := openDir(t, dir, memstore.Options{})
+	uid := fill(t, s)
+	_ = s.Crash()
+
+	journal := filepath.Join(dir, "journal.log")
+
+	// Half of a record header and payload, as left by a crash in the middle of a write.
+	f, err := os.OpenFile(journal, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	_, _ = f.Write([]byte{200, 0, 0, 0, 1, 2, 3, 4, '{', '"'})
+	_ = f.Close()
+
+	s = openDir(t, dir, memstore.Options{})
+	check(t, s, uid)
+
+	// New records go right after the intact ones.
+	if _, err := s.InsertIncome(ctx, uid, day("2025-04-01"), 100, "after"); err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+	_ = s.Crash()
+
+	s = openDir(t, dir, memstore.Options{})
+	defer s.Close(ctx)
+
+	sum, err := s.SumIncomes(ctx, uid, day("2025-01-01"), day("2025-12-31"))
+	if err != nil || sum != 35100 {
+		t.Fatalf("SumIncomes = %d, %v; want 35100", sum, err)
+	}
+}
+
+func TestOpenDir_RejectsCorruptRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openDir(t, dir, memstore.Options{})
+	fill(t, s)
+	_ = s.Crash()
+
+	journal := filepath.Join(dir, "journal.log")
+
+	b, err := os.ReadFile(journal)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+
+	// A flipped bit in the payload of the first record, with intact records after it.
+	b[10] ^= 0x01
+	if err := os.WriteFile(journal, b, 0o600); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+
+	if _, err := memstore.OpenDir(dir, memstore.Options{}); !errors.Is(err, memstore.ErrCorruptJournal) {
+		t.Fatalf("OpenDir = %v, want ErrCorruptJournal", err)
+	}
+}
+
+func TestOpen_IntervalSync(t *testing.T) {
+	dsn := "memstore:" + t.TempDir() + "?fsync=interval&fsync_interval=5ms"
+
+	s, err := memstore.Open(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	uid := fill(t, s)
+	time.Sleep(20 * time.Millisecond)
+	_ = s.Crash()
+
+	s, err = memstore.Open(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close(context.Background())
+
+	check(t, s, uid)
+}
+
+func TestConformance_FileBacked(t *testing.T) {
+	s := openDir(t, t.TempDir(), memstore.Options{Sync: memstore.SyncNever, SnapshotEvery: 50})
+	t.Cleanup(func() { _ = s.Close(context.Background()) })
+
+	storetest.Run(t, func(t *testing.T) storetest.Store { return s })
+}
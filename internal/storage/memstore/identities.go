@@ -2,39 +2,87 @@ package memstore
 
 import (
 	"context"
-	"strconv"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
 func (s *Store) UpsertIdentity(ctx context.Context, transport, externalID string, chatID int64) (int64, error) {
+	const op = "memstore.UpsertIdentity"
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return getUserID(s, transport, externalID)
+	userID := getUserID(s, transport, externalID)
+
+	// Kept in plain text: unlike the database stores the memstore has no PII table.
+	if chatID != 0 && s.chats[userID] != chatID {
+		s.write(change{Op: opChat, UserID: userID, ChatID: chatID})
+	}
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return userID, nil
 }
 
-func getUserID(s *Store, transport, externalID string) (int64, error) {
+func getUserID(s *Store, transport, externalID string) int64 {
 	key := transport + ":" + externalID
 
 	user, exists := s.identities[key]
 
 	if !exists {
 		userID := s.nextUserID
-		s.nextUserID++
-		s.identities[key] = UserRecord{
-			UserID: userID,
-			Scheme: domain.TaxSchemeUSN6,
-		}
-		return userID, nil
+		s.write(change{Op: opUser, UserID: userID, Scheme: domain.TaxSchemeUSN6})
+		s.write(change{Op: opIdentity, Key: key, UserID: userID})
+		return userID
+	}
+
+	return user.UserID
+}
+
+// BindIdentity maps (transport, externalID) to an existing user, replacing any previous mapping.
+func (s *Store) BindIdentity(ctx context.Context, transport, externalID string, userID int64) error {
+	const op = "memstore.BindIdentity"
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return validate.Wrap(op, err)
+	}
+	if err := validate.ValidateExternalID(externalID); err != nil {
+		return validate.Wrap(op, err)
 	}
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bindIdentity(s, transport, externalID, userID)
+
+	if err := s.commit(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
 
-	return user.UserID, nil
+// bindIdentity maps (transport, externalID) to userID. Caller must hold s.mu.
+func bindIdentity(s *Store, transport, externalID string, userID int64) {
+	s.write(change{Op: opIdentity, Key: transport + ":" + externalID, UserID: userID})
 }
 
 func (s *Store) GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error) {
+	const op = "memstore.GetUserScheme"
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.identities[strconv.FormatInt(userID, 10)].Scheme, nil
+	scheme, ok := s.users[userID]
+	if !ok {
+		return "", validate.Wrap(op, validate.ErrNotFound)
+	}
+
+	return scheme, nil
 }
@@ -0,0 +1,30 @@
+package memstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func TestGetUserScheme(t *testing.T) {
+	ctx := context.Background()
+	s := memstore.NewStore()
+
+	uid, err := s.UpsertIdentity(ctx, domain.TransportTelegram, "42", 42)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	scheme, err := s.GetUserScheme(ctx, uid)
+	if err != nil || scheme != domain.TaxSchemeUSN6 {
+		t.Errorf("GetUserScheme(%d) = %q, %v; want %q", uid, scheme, err, domain.TaxSchemeUSN6)
+	}
+
+	if _, err := s.GetUserScheme(ctx, uid+1); !errors.Is(err, validate.ErrNotFound) {
+		t.Errorf("GetUserScheme(unknown) error = %v, want ErrNotFound", err)
+	}
+}
@@ -2,31 +2,85 @@ package memstore
 
 import (
 	"context"
+	"sort"
 	"time"
 
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
 )
 
-func (s *Store) InsertIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) error {
+func (s *Store) InsertIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) (int64, error) {
 	const op = "memstore.InsertIncome"
 
 	if err := validate.ValidateAmount(amount); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
-	utc := at.UTC()
-	day := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+	day := utcDay(at)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.incomes[userID] = append(s.incomes[userID], IncomeRecord{
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	id := s.nextIncomeID
+
+	s.write(change{Op: opIncome, UserID: userID, Income: &IncomeRecord{
+		ID:     id,
 		At:     day,
 		Amount: amount,
 		Note:   note,
-	})
+	}})
+
+	recordMessageEntry(ctx, s, userID, domain.EntryKindIncome, id)
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) UpdateIncome(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (bool, error) {
+	const op = "memstore.UpdateIncome"
+
+	if err := validate.ValidateAmount(amount); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	day := utcDay(at)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return nil
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	found := false
+
+	for _, income := range s.incomes[userID] {
+		if income.ID != id || !income.VoidedAt.IsZero() {
+			continue
+		}
+
+		income.At = day
+		income.Amount = amount
+		income.Note = note
+		s.write(change{Op: opIncome, UserID: userID, Income: &income})
+		found = true
+
+		break
+	}
+
+	if err := s.commit(); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return found, nil
 }
 
 func (s *Store) VoidLastIncomeInRange(ctx context.Context, userID int64, from, to, now time.Time) (
@@ -41,17 +95,17 @@ func (s *Store) VoidLastIncomeInRange(ctx context.Context, userID int64, from, t
 		return 0, time.Time{}, "", false, validate.Wrap(op, err)
 	}
 
-	incomes := s.incomes[userID]
-
-	if len(incomes) == 0 {
-		return 0, time.Time{}, "", false, nil
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
 	}
 
+	incomes := s.incomes[userID]
+
 	bestIdx := -1
 	bestAt := time.Time{}
 
 	for i, income := range incomes {
-		if !income.At.Before(from) && !income.At.After(to) && income.VoidedAt.IsZero() {
+		if inRange(income.At, from, to) && income.VoidedAt.IsZero() {
 
 			if bestIdx == -1 || income.At.After(bestAt) || (income.At.Equal(bestAt) && i > bestIdx) {
 				bestIdx = i
@@ -60,25 +114,197 @@ func (s *Store) VoidLastIncomeInRange(ctx context.Context, userID int64, from, t
 		}
 	}
 
+	if bestIdx != -1 {
+		voided := incomes[bestIdx]
+		voided.VoidedAt = now
+		s.write(change{Op: opIncome, UserID: userID, Income: &voided})
+	}
+
+	// The claimed idempotency key is journaled even when there is nothing to void.
+	if err := s.commit(); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
 	if bestIdx == -1 {
 		return 0, time.Time{}, "", false, nil
 	}
 
-	incomes[bestIdx].VoidedAt = now
-
 	return incomes[bestIdx].Amount, incomes[bestIdx].At, incomes[bestIdx].Note, true, nil
 }
 
+func (s *Store) VoidIncome(ctx context.Context, userID, id int64, now time.Time) (
+	amount int64, at time.Time, note string, ok bool, err error,
+) {
+	const op = "memstore.VoidIncome"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	income, ok := s.activeIncome(userID, id)
+	if ok {
+		voided := income
+		voided.VoidedAt = now
+		s.write(change{Op: opIncome, UserID: userID, Income: &voided})
+	}
+
+	// The claimed idempotency key is journaled even when there is nothing to void.
+	if err := s.commit(); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
+	return income.Amount, income.At, income.Note, true, nil
+}
+
 func (s *Store) SumIncomes(ctx context.Context, userID int64, from, to time.Time) (int64, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	sum := int64(0)
 	for _, income := range s.incomes[userID] {
-		if !income.At.After(from) && !income.At.Before(to) && income.VoidedAt.IsZero() {
+		if inRange(income.At, from, to) && income.VoidedAt.IsZero() {
 			sum += income.Amount
 		}
 	}
 
 	return sum, nil
 }
+
+// SumIncomesByMonth returns active incomes in [from..to] inclusive summed per calendar month, ordered by month.
+func (s *Store) SumIncomesByMonth(ctx context.Context, userID int64, from, to time.Time) ([]domain.MonthSum, error) {
+	const op = "memstore.SumIncomesByMonth"
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sums := make(map[time.Time]int64)
+
+	for _, income := range s.incomes[userID] {
+		if inRange(income.At, from, to) && income.VoidedAt.IsZero() {
+			month := time.Date(income.At.Year(), income.At.Month(), 1, 0, 0, 0, 0, time.UTC)
+			sums[month] += income.Amount
+		}
+	}
+
+	out := make([]domain.MonthSum, 0, len(sums))
+	for month, sum := range sums {
+		out = append(out, domain.MonthSum{Month: month, Sum: sum})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Month.Before(out[j].Month) })
+
+	return out, nil
+}
+
+// IncomeStatsByBucket returns the count, sum and largest of the active incomes in [from..to]
+// inclusive per calendar month or ISO week, ordered by bucket start.
+func (s *Store) IncomeStatsByBucket(ctx context.Context, userID int64, bucket domain.ReportBucket, from, to time.Time) ([]domain.IncomeStats, error) {
+	const op = "memstore.IncomeStatsByBucket"
+
+	if err := validate.ValidateReportBucket(bucket); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	bounds := period.MonthBounds
+	if bucket == domain.BucketWeek {
+		bounds = period.ISOWeekBounds
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := make(map[time.Time]*domain.IncomeStats)
+
+	for _, income := range s.incomes[userID] {
+		if !inRange(income.At, from, to) || !income.VoidedAt.IsZero() {
+			continue
+		}
+
+		start, _ := bounds(income.At)
+
+		b, ok := stats[start]
+		if !ok {
+			b = &domain.IncomeStats{Start: start}
+			stats[start] = b
+		}
+
+		b.Count++
+		b.Sum += income.Amount
+		b.Max = max(b.Max, income.Amount)
+	}
+
+	out := make([]domain.IncomeStats, 0, len(stats))
+	for _, b := range stats {
+		out = append(out, *b)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+
+	return out, nil
+}
+
+func (s *Store) ListIncomes(ctx context.Context, userID int64, from, to time.Time) ([]domain.Income, error) {
+	const op = "memstore.ListIncomes"
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []domain.Income
+
+	for _, income := range s.incomes[userID] {
+		if inRange(income.At, from, to) && income.VoidedAt.IsZero() {
+			out = append(out, domain.Income{
+				ID:     income.ID,
+				At:     income.At,
+				Amount: income.Amount,
+				Note:   income.Note,
+			})
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+
+	return out, nil
+}
+
+func (s *Store) GetIncome(ctx context.Context, userID, id int64) (domain.Income, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	income, ok := s.activeIncome(userID, id)
+	if !ok {
+		return domain.Income{}, false, nil
+	}
+
+	return domain.Income{ID: income.ID, At: income.At, Amount: income.Amount, Note: income.Note}, true, nil
+}
+
+// activeIncome returns the income of userID with id unless it was voided. Caller must hold s.mu.
+func (s *Store) activeIncome(userID, id int64) (IncomeRecord, bool) {
+	for _, income := range s.incomes[userID] {
+		if income.ID == id && income.VoidedAt.IsZero() {
+			return income, true
+		}
+	}
+
+	return IncomeRecord{}, false
+}
@@ -0,0 +1,33 @@
+package memstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+)
+
+func TestSumIncomes_CountsIncomesInsideRange(t *testing.T) {
+	ctx := context.Background()
+	s := memstore.NewStore()
+
+	uid, err := s.UpsertIdentity(ctx, domain.TransportTelegram, "42", 42)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	for _, at := range []string{"2025-01-31", "2025-02-01", "2025-02-15", "2025-02-28", "2025-03-01"} {
+		if _, err := s.InsertIncome(ctx, uid, day(at), 100, ""); err != nil {
+			t.Fatalf("InsertIncome(%s): %v", at, err)
+		}
+	}
+
+	got, err := s.SumIncomes(ctx, uid, day("2025-02-01"), day("2025-02-28"))
+	if err != nil {
+		t.Fatalf("SumIncomes: %v", err)
+	}
+	if got != 300 {
+		t.Errorf("SumIncomes(February) = %d, want 300", got)
+	}
+}
@@ -0,0 +1,345 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) CreateCounterparty(ctx context.Context, c domain.Counterparty) (int64, error) {
+	const op = "memstore.CreateCounterparty"
+
+	if err := validate.ValidateUserID(c.UserID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	norm := strings.ToLower(strings.TrimSpace(c.Name))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.counterparties {
+		if r.UserID == c.UserID && strings.ToLower(strings.TrimSpace(r.Name)) == norm {
+			return 0, validate.Wrap(op, domain.ErrClientExists)
+		}
+	}
+
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	id := s.nextCounterpartyID
+
+	s.write(change{Op: opCounterparty, Client: &CounterpartyRecord{
+		ID:      id,
+		UserID:  c.UserID,
+		Name:    c.Name,
+		INN:     c.INN,
+		KPP:     c.KPP,
+		Address: c.Address,
+	}})
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) UpdateCounterparty(ctx context.Context, c domain.Counterparty) (bool, error) {
+	const op = "memstore.UpdateCounterparty"
+
+	if err := validate.ValidateUserID(c.UserID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.counterparties[c.ID]
+	if !ok || r.UserID != c.UserID {
+		return false, nil
+	}
+
+	r.INN, r.KPP, r.Address = c.INN, c.KPP, c.Address
+	s.write(change{Op: opCounterparty, Client: &r})
+
+	if err := s.commit(); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return true, nil
+}
+
+func (s *Store) ListCounterparties(ctx context.Context, userID int64) ([]domain.Counterparty, error) {
+	const op = "memstore.ListCounterparties"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []domain.Counterparty
+
+	for _, r := range s.counterparties {
+		if r.UserID == userID {
+			out = append(out, counterparty(r))
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out, nil
+}
+
+func (s *Store) GetRequisites(ctx context.Context, userID int64) (domain.Requisites, error) {
+	const op = "memstore.GetRequisites"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.requisites[userID], nil
+}
+
+func (s *Store) SetRequisites(ctx context.Context, userID int64, r domain.Requisites) error {
+	const op = "memstore.SetRequisites"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.write(change{Op: opRequisites, UserID: userID, Req: &r})
+
+	if err := s.commit(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) CreateInvoice(ctx context.Context, inv domain.Invoice) (id, number int64, err error) {
+	const op = "memstore.CreateInvoice"
+
+	if err := validate.ValidateUserID(inv.UserID); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(inv.Amount); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	issued := utcDay(inv.IssuedOn)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.counterparties[inv.Client.ID]; !ok || c.UserID != inv.UserID {
+		return 0, 0, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	for _, r := range s.invoices {
+		if r.UserID == inv.UserID && r.IssuedOn.Year() == issued.Year() {
+			number = max(number, r.Number)
+		}
+	}
+
+	id, number = s.nextInvoiceID, number+1
+
+	s.write(change{Op: opInvoice, Invoice: &InvoiceRecord{
+		ID:          id,
+		UserID:      inv.UserID,
+		Number:      number,
+		ClientID:    inv.Client.ID,
+		IssuedOn:    issued,
+		DueOn:       utcDay(inv.DueOn),
+		Amount:      inv.Amount,
+		Description: inv.Description,
+	}})
+
+	if err := s.commit(); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	return id, number, nil
+}
+
+func (s *Store) ListInvoices(ctx context.Context, userID, invoiceID int64) ([]domain.Invoice, error) {
+	const op = "memstore.ListInvoices"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := s.invoicesWhere(func(inv domain.Invoice) bool {
+		return inv.UserID == userID && (invoiceID == 0 || inv.ID == invoiceID)
+	})
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].IssuedOn.Equal(out[j].IssuedOn) {
+			return out[i].IssuedOn.After(out[j].IssuedOn)
+		}
+		return out[i].ID > out[j].ID
+	})
+
+	return out, nil
+}
+
+func (s *Store) OverdueInvoices(ctx context.Context, today, remindedBefore time.Time) ([]domain.Invoice, error) {
+	today, remindedBefore = utcDay(today), utcDay(remindedBefore)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := s.invoicesWhere(func(inv domain.Invoice) bool {
+		return inv.Overdue(today) && !inv.RemindedOn.After(remindedBefore)
+	})
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].DueOn.Equal(out[j].DueOn) {
+			return out[i].DueOn.Before(out[j].DueOn)
+		}
+		return out[i].ID < out[j].ID
+	})
+
+	return out, nil
+}
+
+// invoicesWhere returns the invoices matching keep with their clients and paid sums. Caller must hold s.mu.
+func (s *Store) invoicesWhere(keep func(domain.Invoice) bool) []domain.Invoice {
+	var out []domain.Invoice
+
+	for _, r := range s.invoices {
+		inv := domain.Invoice{
+			ID:          r.ID,
+			UserID:      r.UserID,
+			Number:      r.Number,
+			Client:      counterparty(s.counterparties[r.ClientID]),
+			IssuedOn:    r.IssuedOn,
+			DueOn:       r.DueOn,
+			Amount:      r.Amount,
+			Description: r.Description,
+			Paid:        s.invoicePaid(r),
+			RemindedOn:  r.RemindedOn,
+		}
+
+		if keep(inv) {
+			out = append(out, inv)
+		}
+	}
+
+	return out
+}
+
+// invoicePaid sums the active incomes that paid r. Caller must hold s.mu.
+func (s *Store) invoicePaid(r InvoiceRecord) int64 {
+	var paid int64
+
+	for _, income := range s.incomes[r.UserID] {
+		if income.VoidedAt.IsZero() && slices.Contains(r.IncomeIDs, income.ID) {
+			paid += income.Amount
+		}
+	}
+
+	return paid
+}
+
+func (s *Store) MarkInvoiceReminded(ctx context.Context, invoiceID int64, day time.Time) error {
+	const op = "memstore.MarkInvoiceReminded"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.invoices[invoiceID]
+	if !ok {
+		return nil
+	}
+
+	r.RemindedOn = utcDay(day)
+	s.write(change{Op: opInvoice, Invoice: &r})
+
+	if err := s.commit(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// PayInvoice creates an income and links it to an invoice of userID, see service.InvoiceStore.
+// The income and the invoice are journaled as one record.
+func (s *Store) PayInvoice(ctx context.Context, userID, invoiceID int64, at time.Time, amount int64, note string) (int64, error) {
+	const op = "memstore.PayInvoice"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	day := utcDay(at)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.invoices[invoiceID]
+	if !ok || r.UserID != userID {
+		return 0, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	amount, err := (domain.Invoice{Amount: r.Amount, Paid: s.invoicePaid(r)}).Payment(amount)
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	id := s.nextIncomeID
+
+	s.write(change{Op: opIncome, UserID: userID, Income: &IncomeRecord{
+		ID:     id,
+		At:     day,
+		Amount: amount,
+		Note:   note,
+	}})
+
+	r.IncomeIDs = append(slices.Clone(r.IncomeIDs), id)
+	s.write(change{Op: opInvoice, Invoice: &r})
+
+	recordMessageEntry(ctx, s, userID, domain.EntryKindIncome, id)
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+func counterparty(r CounterpartyRecord) domain.Counterparty {
+	return domain.Counterparty{
+		ID:      r.ID,
+		UserID:  r.UserID,
+		Name:    r.Name,
+		INN:     r.INN,
+		KPP:     r.KPP,
+		Address: r.Address,
+	}
+}
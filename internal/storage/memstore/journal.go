@@ -0,0 +1,219 @@
+package memstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	journalFile  = "journal.log"
+	snapshotFile = "snapshot.dat"
+
+	// Every record and the snapshot are framed as: payload length and CRC-32C
+	// of the payload (both uint32, little endian), then the JSON payload.
+	frameHeaderSize = 8
+	maxFrameSize    = 1 << 30
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// appendFrame appends the framed payload to buf.
+func appendFrame(buf, payload []byte) []byte {
+	var hdr [frameHeaderSize]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(hdr[4:8], crc32.Checksum(payload, crcTable))
+
+	return append(append(buf, hdr[:]...), payload...)
+}
+
+// errTornFrame means the frame ends past the end of the file or fails its checksum
+// there: the last write was cut short by a crash.
+var errTornFrame = errors.New("torn frame")
+
+// readFrame reads one frame from r; size is what is left of the file including it.
+// It returns io.EOF at a clean end, errTornFrame for a broken last frame and
+// ErrCorruptJournal for a broken frame followed by more data.
+func readFrame(r io.Reader, size int64) ([]byte, error) {
+	var hdr [frameHeaderSize]byte
+
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, errTornFrame
+	}
+
+	n := int64(binary.LittleEndian.Uint32(hdr[0:4]))
+	sum := binary.LittleEndian.Uint32(hdr[4:8])
+
+	if n > maxFrameSize || frameHeaderSize+n > size {
+		return nil, errTornFrame
+	}
+
+	// Records are never empty: a zero header is a tail the filesystem zero-filled
+	// after a crash, unless something other than zeros follows.
+	if n == 0 {
+		rest, err := io.ReadAll(r)
+		if err != nil || bytes.ContainsFunc(rest, func(r rune) bool { return r != 0 }) {
+			return nil, ErrCorruptJournal
+		}
+		return nil, errTornFrame
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, errTornFrame
+	}
+
+	if crc32.Checksum(payload, crcTable) != sum {
+		if frameHeaderSize+n == size {
+			return nil, errTornFrame
+		}
+		return nil, ErrCorruptJournal
+	}
+
+	return payload, nil
+}
+
+// readJournal calls fn for every record of the journal in dir and returns the
+// length of its intact prefix; a torn last record is left to the caller to cut off.
+func readJournal(dir string, fn func(payload []byte) error) (int64, error) {
+	f, err := os.Open(filepath.Join(dir, journalFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	r := bufio.NewReader(f)
+	size, good := fi.Size(), int64(0)
+
+	for {
+		payload, err := readFrame(r, size-good)
+
+		switch {
+		case err == io.EOF, errors.Is(err, errTornFrame):
+			return good, nil
+		case err != nil:
+			return good, fmt.Errorf("%w: at offset %d", err, good)
+		}
+
+		if err := fn(payload); err != nil {
+			return good, fmt.Errorf("%w: at offset %d: %w", ErrCorruptJournal, good, err)
+		}
+
+		good += frameHeaderSize + int64(len(payload))
+	}
+}
+
+// openJournal opens the journal for appending, cutting it to its intact prefix of size bytes.
+func openJournal(dir string, size int64, opts Options) (*journal, error) {
+	f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.Truncate(size); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	j := &journal{dir: dir, opts: opts, f: f}
+
+	if opts.Sync == SyncInterval {
+		j.stop = make(chan struct{})
+		j.done = make(chan struct{})
+
+		go j.syncLoop()
+	}
+
+	return j, nil
+}
+
+// append writes one framed record and syncs it as the policy says.
+func (j *journal) append(payload []byte) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.f.Write(appendFrame(nil, payload)); err != nil {
+		return err
+	}
+
+	j.records++
+
+	if j.opts.Sync == SyncAlways {
+		return j.f.Sync()
+	}
+
+	j.dirty = true
+
+	return nil
+}
+
+// reset empties the journal once a snapshot covers all of its records.
+func (j *journal) reset() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.f.Truncate(0); err != nil {
+		return err
+	}
+
+	j.records = 0
+	j.dirty = false
+
+	return j.f.Sync()
+}
+
+func (j *journal) syncLoop() {
+	defer close(j.done)
+
+	t := time.NewTicker(j.opts.SyncInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-j.stop:
+			return
+		case <-t.C:
+			j.mu.Lock()
+			if j.dirty && j.f.Sync() == nil {
+				j.dirty = false
+			}
+			j.mu.Unlock()
+		}
+	}
+}
+
+// close stops the syncer and closes the file, syncing it unless the policy is never.
+func (j *journal) close() error {
+	if j.stop != nil {
+		close(j.stop)
+		<-j.done
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var err error
+	if j.opts.Sync != SyncNever {
+		err = j.f.Sync()
+	}
+
+	return errors.Join(err, j.f.Close())
+}
@@ -0,0 +1,45 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) GetIdentityLang(ctx context.Context, transport, externalID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.identities[transport+":"+externalID]
+	if !ok {
+		return "", nil
+	}
+
+	return s.langs[user.UserID], nil
+}
+
+func (s *Store) SetUserLang(ctx context.Context, userID int64, lang string) error {
+	const op = "memstore.SetUserLang"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+	if err := validate.ValidateLang(lang); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return validate.Wrap(op, validate.ErrNotFound)
+	}
+
+	s.write(change{Op: opLang, UserID: userID, Lang: lang})
+
+	if err := s.commit(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package memstore
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) CreateLinkCode(ctx context.Context, userID int64, codeHash []byte, now, expiresAt time.Time) error {
+	const op = "memstore.CreateLinkCode"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// One active code per user: drop the previous unused ones.
+	for hash, c := range s.linkCodes {
+		if c.UserID == userID && c.UsedAt.IsZero() {
+			s.write(change{Op: opDropLinkCode, Key: hash})
+		}
+	}
+
+	s.write(change{Op: opLinkCode, Key: hex.EncodeToString(codeHash), LinkCode: &LinkCodeRecord{
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}})
+
+	if err := s.commit(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) RedeemLinkCode(ctx context.Context, codeHash []byte, now time.Time, currentUserID int64, transport, externalID string) (int64, error) {
+	const op = "memstore.RedeemLinkCode"
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateExternalID(externalID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := hex.EncodeToString(codeHash)
+
+	c, ok := s.linkCodes[hash]
+	if !ok || !c.UsedAt.IsZero() || !now.Before(c.ExpiresAt) {
+		return 0, validate.Wrap(op, domain.ErrLinkCodeInvalid)
+	}
+
+	if c.UserID == currentUserID {
+		return 0, validate.Wrap(op, domain.ErrAlreadyLinked)
+	}
+
+	if len(s.incomes[currentUserID]) > 0 || len(s.payments[currentUserID]) > 0 {
+		return 0, validate.Wrap(op, domain.ErrLinkHasEntries)
+	}
+
+	used := *c
+	used.UsedAt = now
+	s.write(change{Op: opLinkCode, Key: hash, LinkCode: &used})
+	bindIdentity(s, transport, externalID, c.UserID)
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return c.UserID, nil
+}
+
+func (s *Store) UnbindIdentities(ctx context.Context, userID int64, transport, externalID string) (int64, error) {
+	const op = "memstore.UnbindIdentities"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		drop []string
+		kept int
+	)
+
+	for key, rec := range s.identities {
+		if rec.UserID != userID {
+			continue
+		}
+
+		t, ext, _ := strings.Cut(key, ":")
+
+		if t == transport && (externalID == "" || ext == externalID) {
+			drop = append(drop, key)
+			continue
+		}
+
+		if t != domain.TransportAPI {
+			kept++
+		}
+	}
+
+	if len(drop) > 0 && kept == 0 {
+		return 0, validate.Wrap(op, domain.ErrLastIdentity)
+	}
+
+	for _, key := range drop {
+		s.write(change{Op: opUnbind, Key: key})
+	}
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return int64(len(drop)), nil
+}
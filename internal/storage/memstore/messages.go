@@ -0,0 +1,29 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+)
+
+func (s *Store) FindMessageEntry(ctx context.Context, userID int64, ref domain.MessageRef) (domain.MessageEntry, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.messages[ref.Transport+":"+ref.Key]
+	if !ok || rec.UserID != userID {
+		return domain.MessageEntry{}, false, nil
+	}
+
+	return domain.MessageEntry{Kind: rec.Kind, ID: rec.ID}, true, nil
+}
+
+// recordMessageEntry links the message from ctx, if any, to the created entry. Caller must hold s.mu.
+func recordMessageEntry(ctx context.Context, s *Store, userID int64, kind domain.EntryKind, id int64) {
+	ref, ok := domain.MessageRefFrom(ctx)
+	if !ok {
+		return
+	}
+
+	s.write(change{Op: opMessage, Key: ref.Transport + ":" + ref.Key, Message: &MessageEntryRecord{UserID: userID, Kind: kind, ID: id}})
+}
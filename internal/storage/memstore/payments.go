@@ -2,45 +2,103 @@ package memstore
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
-func (s *Store) InsertPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, paymentType domain.PaymentType) error {
+func (s *Store) InsertPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, paymentType domain.PaymentType) (int64, error) {
 	const op = "memstore.InsertPayment"
 
 	if err := validate.ValidateUserID(userID); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	if err := validate.ValidateAmount(amount); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	if err := validate.ValidatePaymentType(domain.PaymentType(paymentType)); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	if err := validate.ValidateDateRangeUTC(at, at); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	at = at.UTC()
-	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	day := utcDay(at)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.payments[userID] = append(s.payments[userID], PaymentRecord{
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	id := s.nextPaymentID
+
+	s.write(change{Op: opPayment, UserID: userID, Payment: &PaymentRecord{
+		ID:     id,
 		At:     day,
 		Amount: amount,
 		Note:   note,
 		Type:   domain.PaymentType(paymentType),
-	})
+	}})
+
+	recordMessageEntry(ctx, s, userID, domain.EntryKind(paymentType), id)
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
 
-	return nil
+	return id, nil
+}
+
+func (s *Store) UpdatePayment(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (bool, error) {
+	const op = "memstore.UpdatePayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateAmount(amount); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	at = at.UTC()
+	day := utcDay(at)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	found := false
+
+	for _, payment := range s.payments[userID] {
+		if payment.ID != id || !payment.VoidedAt.IsZero() {
+			continue
+		}
+
+		payment.At = day
+		payment.Amount = amount
+		payment.Note = note
+		s.write(change{Op: opPayment, UserID: userID, Payment: &payment})
+		found = true
+
+		break
+	}
+
+	if err := s.commit(); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return found, nil
 }
 
 func (s *Store) VoidLastPaymentInRange(ctx context.Context, userID int64, from, to, now time.Time, paymentType domain.PaymentType) (
@@ -55,17 +113,17 @@ func (s *Store) VoidLastPaymentInRange(ctx context.Context, userID int64, from,
 		return 0, time.Time{}, "", "", false, validate.Wrap(op, err)
 	}
 
-	payments := s.payments[userID]
-
-	if len(payments) == 0 {
-		return 0, time.Time{}, "", "", false, nil
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, time.Time{}, "", "", false, validate.Wrap(op, err)
 	}
 
+	payments := s.payments[userID]
+
 	bestIdx := -1
 	bestAt := time.Time{}
 
 	for i, payment := range payments {
-		if !payment.At.Before(from) && !payment.At.After(to) && payment.VoidedAt.IsZero() && payment.Type == domain.PaymentType(paymentType) {
+		if inRange(payment.At, from, to) && payment.VoidedAt.IsZero() && payment.Type == domain.PaymentType(paymentType) {
 			if bestIdx == -1 || payment.At.After(bestAt) || (payment.At.Equal(bestAt) && i > bestIdx) {
 				bestIdx = i
 				bestAt = payment.At
@@ -73,14 +131,85 @@ func (s *Store) VoidLastPaymentInRange(ctx context.Context, userID int64, from,
 		}
 	}
 
+	if bestIdx != -1 {
+		voided := payments[bestIdx]
+		voided.VoidedAt = now
+		s.write(change{Op: opPayment, UserID: userID, Payment: &voided})
+	}
+
+	// The claimed idempotency key is journaled even when there is nothing to void.
+	if err := s.commit(); err != nil {
+		return 0, time.Time{}, "", "", false, validate.Wrap(op, err)
+	}
+
 	if bestIdx == -1 {
 		return 0, time.Time{}, "", "", false, nil
 	}
 
-	payments[bestIdx].VoidedAt = now
 	return payments[bestIdx].Amount, payments[bestIdx].At, payments[bestIdx].Note, payments[bestIdx].Type, true, nil
 }
 
+func (s *Store) VoidPayment(ctx context.Context, userID, id int64, now time.Time, paymentType domain.PaymentType) (
+	amount int64, at time.Time, note string, ok bool, err error,
+) {
+	const op = "memstore.VoidPayment"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := claimIdempotencyKey(ctx, s); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	payment, ok := s.activePayment(userID, id)
+	ok = ok && payment.Type == paymentType
+	if ok {
+		voided := payment
+		voided.VoidedAt = now
+		s.write(change{Op: opPayment, UserID: userID, Payment: &voided})
+	}
+
+	// The claimed idempotency key is journaled even when there is nothing to void.
+	if err := s.commit(); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
+	return payment.Amount, payment.At, payment.Note, true, nil
+}
+
+func (s *Store) GetPayment(ctx context.Context, userID, id int64) (domain.Payment, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payment, ok := s.activePayment(userID, id)
+	if !ok {
+		return domain.Payment{}, false, nil
+	}
+
+	return domain.Payment{
+		ID:     payment.ID,
+		At:     payment.At,
+		Amount: payment.Amount,
+		Note:   payment.Note,
+		Type:   payment.Type,
+	}, true, nil
+}
+
+// activePayment returns the payment of userID with id unless it was voided. Caller must hold s.mu.
+func (s *Store) activePayment(userID, id int64) (PaymentRecord, bool) {
+	for _, payment := range s.payments[userID] {
+		if payment.ID == id && payment.VoidedAt.IsZero() {
+			return payment, true
+		}
+	}
+
+	return PaymentRecord{}, false
+}
+
 func (s *Store) SumPayments(ctx context.Context, userID int64, from, to time.Time) (int64, int64, error) {
 	const op = "memstore.SumPayments"
 
@@ -97,7 +226,7 @@ func (s *Store) SumPayments(ctx context.Context, userID int64, from, to time.Tim
 	sumAdvance := int64(0)
 
 	for _, payment := range payments {
-		if !payment.At.Before(from) && !payment.At.After(to) && payment.VoidedAt.IsZero() {
+		if inRange(payment.At, from, to) && payment.VoidedAt.IsZero() {
 			if payment.Type == domain.PaymentTypeContrib {
 				sumContrib += payment.Amount
 			} else {
@@ -108,3 +237,32 @@ func (s *Store) SumPayments(ctx context.Context, userID int64, from, to time.Tim
 
 	return sumContrib, sumAdvance, nil
 }
+
+func (s *Store) ListPayments(ctx context.Context, userID int64, from, to time.Time) ([]domain.Payment, error) {
+	const op = "memstore.ListPayments"
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []domain.Payment
+
+	for _, payment := range s.payments[userID] {
+		if inRange(payment.At, from, to) && payment.VoidedAt.IsZero() {
+			out = append(out, domain.Payment{
+				ID:     payment.ID,
+				At:     payment.At,
+				Amount: payment.Amount,
+				Note:   payment.Note,
+				Type:   payment.Type,
+			})
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+
+	return out, nil
+}
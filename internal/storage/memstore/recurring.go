@@ -0,0 +1,297 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) CreateRecurringRule(ctx context.Context, rule domain.RecurringRule) (int64, error) {
+	const op = "memstore.CreateRecurringRule"
+
+	if err := validate.ValidateUserID(rule.UserID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(rule.Amount); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextRecurringID
+
+	s.write(change{Op: opRecurringRule, Rule: &RecurringRuleRecord{
+		ID:      id,
+		UserID:  rule.UserID,
+		Amount:  rule.Amount,
+		Note:    rule.Note,
+		Period:  rule.Period,
+		Day:     rule.Day,
+		Confirm: rule.Confirm,
+		NextDue: utcDay(rule.NextDue),
+	}})
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) ListRecurringRules(ctx context.Context, userID int64) ([]domain.RecurringRule, error) {
+	const op = "memstore.ListRecurringRules"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.recurringRulesWhere(func(r RecurringRuleRecord) bool { return r.UserID == userID }), nil
+}
+
+func (s *Store) DueRecurringRules(ctx context.Context, today time.Time) ([]domain.RecurringRule, error) {
+	day := utcDay(today)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := s.recurringRulesWhere(func(r RecurringRuleRecord) bool {
+		return r.PausedAt.IsZero() && !r.NextDue.After(day)
+	})
+
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].NextDue.Before(rules[j].NextDue) })
+
+	return rules, nil
+}
+
+// recurringRulesWhere returns the rules matching keep ordered by ID. Caller must hold s.mu.
+func (s *Store) recurringRulesWhere(keep func(RecurringRuleRecord) bool) []domain.RecurringRule {
+	var out []domain.RecurringRule
+
+	for _, r := range s.recurringRules {
+		if !keep(r) {
+			continue
+		}
+
+		pending := 0
+		for _, run := range s.recurringRuns[r.ID] {
+			if run.Status == domain.RecurringPending {
+				pending++
+			}
+		}
+
+		out = append(out, domain.RecurringRule{
+			ID:      r.ID,
+			UserID:  r.UserID,
+			Amount:  r.Amount,
+			Note:    r.Note,
+			Period:  r.Period,
+			Day:     r.Day,
+			Confirm: r.Confirm,
+			Paused:  !r.PausedAt.IsZero(),
+			NextDue: r.NextDue,
+			Pending: pending,
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+func (s *Store) SetRecurringPaused(ctx context.Context, userID, ruleID int64, paused bool, nextDue time.Time) (bool, error) {
+	const op = "memstore.SetRecurringPaused"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.recurringRules[ruleID]
+	if !ok || r.UserID != userID {
+		return false, nil
+	}
+
+	switch {
+	case !paused:
+		r.PausedAt = time.Time{}
+	case r.PausedAt.IsZero():
+		r.PausedAt = time.Now().UTC()
+	}
+
+	if !nextDue.IsZero() {
+		r.NextDue = utcDay(nextDue)
+	}
+
+	s.write(change{Op: opRecurringRule, Rule: &r})
+
+	if err := s.commit(); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return true, nil
+}
+
+func (s *Store) DeleteRecurringRule(ctx context.Context, userID, ruleID int64) (bool, error) {
+	const op = "memstore.DeleteRecurringRule"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.recurringRules[ruleID]
+	if !ok || r.UserID != userID {
+		return false, nil
+	}
+
+	s.write(change{Op: opDropRecurring, RuleID: ruleID})
+
+	if err := s.commit(); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return true, nil
+}
+
+// RunRecurring runs the occurrence of ruleID on due, see service.RecurringStore.
+// The rule, the run and the income are journaled as one record.
+func (s *Store) RunRecurring(ctx context.Context, ruleID int64, due, next time.Time) (domain.RecurringRun, bool, error) {
+	const op = "memstore.RunRecurring"
+
+	due = utcDay(due)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.recurringRules[ruleID]
+	if !ok || !r.PausedAt.IsZero() || !r.NextDue.Equal(due) {
+		return domain.RecurringRun{}, false, nil
+	}
+
+	r.NextDue = utcDay(next)
+	s.write(change{Op: opRecurringRule, Rule: &r})
+
+	ran := false
+	for _, run := range s.recurringRuns[ruleID] {
+		if run.Due.Equal(due) {
+			ran = true
+			break
+		}
+	}
+
+	run := RecurringRunRecord{RuleID: ruleID, Due: due, Status: domain.RecurringCreated}
+
+	if !ran {
+		if r.Confirm {
+			run.Status = domain.RecurringPending
+		} else {
+			run.IncomeID = s.writeRecurringIncome(r, due)
+		}
+
+		s.write(change{Op: opRecurringRun, Run: &run})
+	}
+
+	if err := s.commit(); err != nil {
+		return domain.RecurringRun{}, false, validate.Wrap(op, err)
+	}
+
+	if ran {
+		return domain.RecurringRun{}, false, nil
+	}
+
+	return recurringRun(r, run), true, nil
+}
+
+func (s *Store) ResolveRecurringRuns(ctx context.Context, userID, ruleID int64, confirm bool) ([]domain.RecurringRun, error) {
+	const op = "memstore.ResolveRecurringRuns"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.recurringRules[ruleID]
+	if !ok || r.UserID != userID {
+		return nil, nil
+	}
+
+	var out []domain.RecurringRun
+
+	for _, run := range s.recurringRuns[ruleID] {
+		if run.Status != domain.RecurringPending {
+			continue
+		}
+
+		run.Status = domain.RecurringSkipped
+		if confirm {
+			run.Status = domain.RecurringCreated
+			run.IncomeID = s.writeRecurringIncome(r, run.Due)
+		}
+
+		s.write(change{Op: opRecurringRun, Run: &run})
+		out = append(out, recurringRun(r, run))
+	}
+
+	if err := s.commit(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Due.Before(out[j].Due) })
+
+	return out, nil
+}
+
+// writeRecurringIncome writes the income of an occurrence of r on due. Caller must hold s.mu.
+func (s *Store) writeRecurringIncome(r RecurringRuleRecord, due time.Time) int64 {
+	id := s.nextIncomeID
+
+	s.write(change{Op: opIncome, UserID: r.UserID, Income: &IncomeRecord{
+		ID:     id,
+		At:     due,
+		Amount: r.Amount,
+		Note:   r.Note,
+	}})
+
+	return id
+}
+
+func recurringRun(r RecurringRuleRecord, run RecurringRunRecord) domain.RecurringRun {
+	return domain.RecurringRun{
+		RuleID:   r.ID,
+		UserID:   r.UserID,
+		Due:      run.Due,
+		Amount:   r.Amount,
+		Note:     r.Note,
+		Status:   run.Status,
+		IncomeID: run.IncomeID,
+	}
+}
+
+func (s *Store) GetTelegramChatID(ctx context.Context, userID int64) (int64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chatID, ok := s.chats[userID]
+
+	return chatID, ok, nil
+}
+
+func (s *Store) GetUserLang(ctx context.Context, userID int64) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.langs[userID], nil
+}
@@ -0,0 +1,72 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// SearchNotes returns up to limit active incomes and payments in [from..to] inclusive whose
+// folded note contains the folded query, newest first (by date, then ID).
+func (s *Store) SearchNotes(ctx context.Context, userID int64, query string, from, to time.Time, limit int) ([]domain.NoteMatch, error) {
+	const op = "memstore.SearchNotes"
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	query = domain.FoldNote(query)
+
+	matches := func(note string, at time.Time, voided bool) bool {
+		return note != "" && !voided && inRange(at, from, to) && strings.Contains(domain.FoldNote(note), query)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []domain.NoteMatch
+
+	for _, income := range s.incomes[userID] {
+		if matches(income.Note, income.At, !income.VoidedAt.IsZero()) {
+			out = append(out, domain.NoteMatch{
+				Kind:   domain.EntryKindIncome,
+				ID:     income.ID,
+				At:     income.At,
+				Amount: income.Amount,
+				Note:   income.Note,
+			})
+		}
+	}
+
+	for _, payment := range s.payments[userID] {
+		if matches(payment.Note, payment.At, !payment.VoidedAt.IsZero()) {
+			out = append(out, domain.NoteMatch{
+				Kind:   domain.EntryKind(payment.Type),
+				ID:     payment.ID,
+				At:     payment.At,
+				Amount: payment.Amount,
+				Note:   payment.Note,
+			})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].At.Equal(out[j].At) {
+			return out[i].At.After(out[j].At)
+		}
+		if out[i].ID != out[j].ID {
+			return out[i].ID > out[j].ID
+		}
+		return out[i].Kind < out[j].Kind
+	})
+
+	if len(out) > limit {
+		out = out[:max(limit, 0)]
+	}
+
+	return out, nil
+}
@@ -0,0 +1,14 @@
+package memstore_test
+
+import (
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/memstore"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return memstore.NewStore()
+	})
+}
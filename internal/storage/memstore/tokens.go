@@ -0,0 +1,137 @@
+package memstore
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) CreateAPIToken(ctx context.Context, userID int64, tokenHash []byte, name string) (int64, error) {
+	const op = "memstore.CreateAPIToken"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextTokenID
+
+	s.write(change{Op: opToken, Token: &APITokenRecord{
+		ID:        id,
+		UserID:    userID,
+		Hash:      hex.EncodeToString(tokenHash),
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+	}})
+
+	bindIdentity(s, domain.TransportAPI, strconv.FormatInt(id, 10), userID)
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) ResolveAPIToken(ctx context.Context, tokenHash []byte, now time.Time) (int64, bool, error) {
+	const op = "memstore.ResolveAPIToken"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := hex.EncodeToString(tokenHash)
+
+	for _, t := range s.apiTokens {
+		if t.Hash == hash && t.RevokedAt.IsZero() {
+			used := *t
+			used.LastUsedAt = now
+			s.write(change{Op: opToken, Token: &used})
+
+			if err := s.commit(); err != nil {
+				return 0, false, validate.Wrap(op, err)
+			}
+
+			return t.UserID, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func (s *Store) ListAPITokens(ctx context.Context, userID int64) ([]domain.APIToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []domain.APIToken
+
+	for _, t := range s.apiTokens {
+		if t.UserID == userID && t.RevokedAt.IsZero() {
+			out = append(out, domain.APIToken{
+				ID:         t.ID,
+				Name:       t.Name,
+				CreatedAt:  t.CreatedAt,
+				LastUsedAt: t.LastUsedAt,
+			})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+
+	return out, nil
+}
+
+func (s *Store) RevokeAPIToken(ctx context.Context, userID, tokenID int64, now time.Time) (bool, error) {
+	const op = "memstore.RevokeAPIToken"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.apiTokens[tokenID]
+
+	if !ok || t.UserID != userID || !t.RevokedAt.IsZero() {
+		return false, nil
+	}
+
+	revoked := *t
+	revoked.RevokedAt = now
+	s.write(change{Op: opToken, Token: &revoked})
+	s.write(change{Op: opUnbind, Key: domain.TransportAPI + ":" + strconv.FormatInt(tokenID, 10)})
+
+	if err := s.commit(); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return true, nil
+}
+
+func (s *Store) RevokeAllAPITokens(ctx context.Context, userID int64, now time.Time) (int64, error) {
+	const op = "memstore.RevokeAllAPITokens"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := int64(0)
+
+	for _, t := range s.apiTokens {
+		if t.UserID == userID && t.RevokedAt.IsZero() {
+			revoked := *t
+			revoked.RevokedAt = now
+			s.write(change{Op: opToken, Token: &revoked})
+			s.write(change{Op: opUnbind, Key: domain.TransportAPI + ":" + strconv.FormatInt(t.ID, 10)})
+			n++
+		}
+	}
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return n, nil
+}
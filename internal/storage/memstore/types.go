@@ -1,6 +1,7 @@
 package memstore
 
 import (
+	"os"
 	"sync"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 // IncomeRecord represents an income entry in memory storage
 type IncomeRecord struct {
+	ID       int64
 	At       time.Time
 	Amount   int64
 	Note     string
@@ -18,6 +20,7 @@ type IncomeRecord struct {
 
 // PaymentRecord represents a payment entry in memory storage
 type PaymentRecord struct {
+	ID       int64
 	At       time.Time
 	Amount   int64
 	Note     string
@@ -31,12 +34,211 @@ type UserRecord struct {
 	Scheme domain.TaxScheme
 }
 
+// APITokenRecord represents an API token in memory storage (hash only)
+type APITokenRecord struct {
+	ID         int64
+	UserID     int64
+	Hash       string
+	Name       string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+}
+
+// LinkCodeRecord represents a one-time /link code in memory storage (hash only)
+type LinkCodeRecord struct {
+	UserID    int64
+	ExpiresAt time.Time
+	UsedAt    time.Time
+}
+
+// MessageEntryRecord links a transport message to the entry it created
+type MessageEntryRecord struct {
+	UserID int64
+	Kind   domain.EntryKind
+	ID     int64
+}
+
+// RecurringRuleRecord represents a /recurring rule in memory storage
+type RecurringRuleRecord struct {
+	ID       int64
+	UserID   int64
+	Amount   int64
+	Note     string
+	Period   domain.RecurringPeriod
+	Day      int
+	Confirm  bool
+	NextDue  time.Time
+	PausedAt time.Time
+}
+
+// RecurringRunRecord represents one occurrence of a recurring rule
+type RecurringRunRecord struct {
+	RuleID   int64
+	Due      time.Time
+	Status   domain.RecurringStatus
+	IncomeID int64
+}
+
+// CounterpartyRecord represents a client in memory storage
+type CounterpartyRecord struct {
+	ID      int64
+	UserID  int64
+	Name    string
+	INN     string
+	KPP     string
+	Address string
+}
+
+// InvoiceRecord represents an invoice in memory storage with the incomes that paid it
+type InvoiceRecord struct {
+	ID          int64
+	UserID      int64
+	Number      int64
+	ClientID    int64
+	IssuedOn    time.Time
+	DueOn       time.Time
+	Amount      int64
+	Description string
+	RemindedOn  time.Time
+	IncomeIDs   []int64
+}
+
+// ActRecord represents an act of completed work in memory storage
+type ActRecord struct {
+	ID          int64
+	UserID      int64
+	Number      int64
+	ClientID    int64
+	IssuedOn    time.Time
+	Amount      int64
+	Description string
+	InvoiceID   int64
+	IncomeID    int64
+}
+
 // Store provides in-memory storage with cryptographic capabilities
 type Store struct {
 	cryptostore.BaseCryptoStore // Embed crypto capabilities
 	mu                          sync.RWMutex
 	nextUserID                  int64
+	nextIncomeID                int64
+	nextPaymentID               int64
+	nextTokenID                 int64
+	nextRecurringID             int64
+	nextCounterpartyID          int64
+	nextInvoiceID               int64
+	nextActID                   int64
 	identities                  map[string]UserRecord
+	users                       map[int64]domain.TaxScheme // key = user ID
+	langs                       map[int64]string           // key = user ID, set by /lang
 	incomes                     map[int64][]IncomeRecord
 	payments                    map[int64][]PaymentRecord
+	apiTokens                   map[int64]*APITokenRecord      // key = token ID
+	linkCodes                   map[string]*LinkCodeRecord     // key = hex(code hash)
+	processed                   map[string]time.Time           // key = transport + ":" + update key
+	pollOffsets                 map[string]int64               // key = transport
+	messages                    map[string]MessageEntryRecord  // key = transport + ":" + message key
+	chats                       map[int64]int64                // key = user ID, Telegram chat ID
+	recurringRules              map[int64]RecurringRuleRecord  // key = rule ID
+	recurringRuns               map[int64][]RecurringRunRecord // key = rule ID
+	counterparties              map[int64]CounterpartyRecord   // key = counterparty ID
+	requisites                  map[int64]domain.Requisites    // key = user ID
+	invoices                    map[int64]InvoiceRecord        // key = invoice ID
+	acts                        map[int64]ActRecord            // key = act ID
+
+	// File-backed mode (see Open); all nil/zero for NewStore.
+	journal *journal
+	seq     uint64   // sequence number of the last journal record
+	pending []change // changes made under the current lock, not yet journaled
+	failed  error    // first journal write error; no writes are accepted after it
+}
+
+// SyncPolicy says when journal writes are flushed to disk with fsync.
+type SyncPolicy string
+
+const (
+	SyncAlways   SyncPolicy = "always"   // fsync every record before the write returns
+	SyncInterval SyncPolicy = "interval" // fsync in the background every Options.SyncInterval
+	SyncNever    SyncPolicy = "never"    // leave flushing to the OS
+)
+
+// Options configure the file-backed mode.
+type Options struct {
+	Sync          SyncPolicy
+	SyncInterval  time.Duration // for SyncInterval
+	SnapshotEvery int           // journal records between compacted snapshots
+}
+
+// change is one mutation as written to the journal. Rows are carried whole, as
+// they are after the change, so replaying a change is an idempotent upsert.
+type change struct {
+	Op       string               `json:"op"`
+	UserID   int64                `json:"user_id,omitempty"`
+	Key      string               `json:"key,omitempty"`
+	Scheme   domain.TaxScheme     `json:"scheme,omitempty"`
+	Lang     string               `json:"lang,omitempty"`
+	Offset   int64                `json:"offset,omitempty"`
+	At       time.Time            `json:"at,omitzero"`
+	Income   *IncomeRecord        `json:"income,omitempty"`
+	Payment  *PaymentRecord       `json:"payment,omitempty"`
+	Token    *APITokenRecord      `json:"token,omitempty"`
+	LinkCode *LinkCodeRecord      `json:"link_code,omitempty"`
+	Message  *MessageEntryRecord  `json:"message,omitempty"`
+	ChatID   int64                `json:"chat_id,omitempty"`
+	RuleID   int64                `json:"rule_id,omitempty"`
+	Rule     *RecurringRuleRecord `json:"rule,omitempty"`
+	Run      *RecurringRunRecord  `json:"run,omitempty"`
+	Client   *CounterpartyRecord  `json:"client,omitempty"`
+	Req      *domain.Requisites   `json:"requisites,omitempty"`
+	Invoice  *InvoiceRecord       `json:"invoice,omitempty"`
+	Act      *ActRecord           `json:"act,omitempty"`
+}
+
+// journalRecord holds the changes of one store call; they are replayed all or none.
+type journalRecord struct {
+	Seq     uint64   `json:"seq"`
+	Changes []change `json:"changes"`
+}
+
+// snapshot is the whole state as of journal record Seq.
+type snapshot struct {
+	Seq             uint64                         `json:"seq"`
+	NextUserID      int64                          `json:"next_user_id"`
+	NextIncomeID    int64                          `json:"next_income_id"`
+	NextPaymentID   int64                          `json:"next_payment_id"`
+	NextTokenID     int64                          `json:"next_token_id"`
+	NextRecurringID int64                          `json:"next_recurring_id"`
+	Identities      map[string]UserRecord          `json:"identities"`
+	Users           map[int64]domain.TaxScheme     `json:"users"`
+	Langs           map[int64]string               `json:"langs"`
+	Incomes         map[int64][]IncomeRecord       `json:"incomes"`
+	Payments        map[int64][]PaymentRecord      `json:"payments"`
+	APITokens       map[int64]*APITokenRecord      `json:"api_tokens"`
+	LinkCodes       map[string]*LinkCodeRecord     `json:"link_codes"`
+	Processed       map[string]time.Time           `json:"processed"`
+	PollOffsets     map[string]int64               `json:"poll_offsets"`
+	Messages        map[string]MessageEntryRecord  `json:"messages"`
+	Chats           map[int64]int64                `json:"chats"`
+	RecurringRules  map[int64]RecurringRuleRecord  `json:"recurring_rules"`
+	RecurringRuns   map[int64][]RecurringRunRecord `json:"recurring_runs"`
+	NextClientID    int64                          `json:"next_client_id"`
+	NextInvoiceID   int64                          `json:"next_invoice_id"`
+	Clients         map[int64]CounterpartyRecord   `json:"clients"`
+	Requisites      map[int64]domain.Requisites    `json:"requisites"`
+	Invoices        map[int64]InvoiceRecord        `json:"invoices"`
+	NextActID       int64                          `json:"next_act_id"`
+	Acts            map[int64]ActRecord            `json:"acts"`
+}
+
+// journal is the append-only log of a file-backed store.
+type journal struct {
+	dir     string
+	opts    Options
+	mu      sync.Mutex // guards f between the store and the background syncer
+	f       *os.File
+	records int  // records since the last snapshot
+	dirty   bool // written but not yet synced
+	stop    chan struct{}
+	done    chan struct{}
 }
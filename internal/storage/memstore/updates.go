@@ -0,0 +1,105 @@
+package memstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) GetPollOffset(ctx context.Context, transport string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.pollOffsets[transport], nil
+}
+
+func (s *Store) SetPollOffset(ctx context.Context, transport string, offset int64) error {
+	const op = "memstore.SetPollOffset"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if offset > s.pollOffsets[transport] {
+		s.write(change{Op: opPollOffset, Key: transport, Offset: offset})
+	}
+
+	if err := s.commit(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) IsProcessed(ctx context.Context, transport, key string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.processed[transport+":"+key]
+
+	return ok, nil
+}
+
+func (s *Store) MarkProcessed(ctx context.Context, transport, key string) error {
+	const op = "memstore.MarkProcessed"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := transport + ":" + key
+
+	if _, ok := s.processed[k]; !ok {
+		s.write(change{Op: opProcessed, Key: k, At: time.Now().UTC()})
+	}
+
+	if err := s.commit(); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) PruneProcessed(ctx context.Context, before time.Time) (int64, error) {
+	const op = "memstore.PruneProcessed"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+
+	for _, at := range s.processed {
+		if at.Before(before) {
+			n++
+		}
+	}
+
+	if n > 0 {
+		s.write(change{Op: opPruneProcessed, At: before})
+	}
+
+	if err := s.commit(); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return n, nil
+}
+
+// claimIdempotencyKey records the idempotency key from ctx, if any, and returns
+// domain.ErrDuplicateUpdate if it was already recorded. Caller must hold s.mu.
+func claimIdempotencyKey(ctx context.Context, s *Store) error {
+	k, ok := domain.IdempotencyKeyFrom(ctx)
+	if !ok {
+		return nil
+	}
+
+	key := k.Transport + ":" + k.Key
+
+	if _, dup := s.processed[key]; dup {
+		return domain.ErrDuplicateUpdate
+	}
+
+	s.write(change{Op: opProcessed, Key: key, At: time.Now().UTC()})
+
+	return nil
+}
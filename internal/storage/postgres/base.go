@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -57,6 +58,21 @@ func (s *Store) Close(ctx context.Context) error {
 	return nil
 }
 
+// Ping checks that the database is reachable (used by the readiness probe).
+func (s *Store) Ping(ctx context.Context) error {
+	const op = "postgres.Ping"
+
+	if s == nil || s.Pool == nil {
+		return validate.Wrap(op, ErrEmptyPool)
+	}
+
+	if err := s.Pool.Ping(ctx); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
 func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
 	const op = "postgres.WithTx"
 
@@ -75,7 +91,7 @@ func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context, tx pgx.
 	if err := fn(ctx, tx); err != nil {
 		_ = tx.Rollback(ctx)
 
-		return validate.Wrap(op, ErrTx)
+		return validate.Wrap(op, fmt.Errorf("%w: %w", ErrTx, err))
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tuor4eg/ip_accounting_bot/internal/crypto"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// GetTelegramChatID returns the decrypted Telegram chat_id of userID; ok is false
+// if the user has none stored.
+func (s *Store) GetTelegramChatID(ctx context.Context, userID int64) (int64, bool, error) {
+	const op = "postgres.GetTelegramChatID"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, false, validate.Wrap(op, err)
+	}
+
+	var (
+		chatEnc []byte
+		encKid  int16
+	)
+
+	err := s.Pool.QueryRow(ctx, `SELECT chat_enc, enc_kid FROM pii.telegram WHERE user_id = $1`, userID).Scan(&chatEnc, &encKid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, validate.Wrap(op, err)
+	}
+
+	box := s.GetAEADBoxByKid(encKid)
+	if box == nil {
+		return 0, false, validate.Wrap(op, fmt.Errorf("%w: user_id=%d enc_kid=%d", ErrUnknownKid, userID, encKid))
+	}
+
+	chatID, err := crypto.DecryptInt64(box, chatEnc, []byte(piiTelegramAAD))
+	if err != nil {
+		return 0, false, validate.Wrap(op, err)
+	}
+
+	return chatID, true, nil
+}
+
+// GetUserLang returns users.lang of userID, "" if it is not set.
+func (s *Store) GetUserLang(ctx context.Context, userID int64) (string, error) {
+	const op = "postgres.GetUserLang"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	var lang string
+
+	err := s.Pool.QueryRow(ctx, `SELECT COALESCE(lang, '') FROM users WHERE id = $1`, userID).Scan(&lang)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", validate.Wrap(op, err)
+	}
+
+	return lang, nil
+}
@@ -11,4 +11,6 @@ var (
 	ErrBeginTx     = errors.New("begin tx error")
 	ErrTx          = errors.New("tx error")
 	ErrTxCommit    = errors.New("tx commit error")
+	ErrUnknownKid  = errors.New("row is encrypted with an unknown key id")
+	ErrNoCryptoKey = errors.New("crypto keys are not configured")
 )
@@ -33,21 +33,20 @@ func (s *Store) UpsertIdentity(ctx context.Context, transport, externalID string
 	var uid int64
 
 	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		// 1) Check if mapping already exists.
-		if err := tx.QueryRow(ctx,
-			`SELECT user_id
-			   FROM user_identities
-			  WHERE transport = $1 AND external_hash = $2 AND hmac_kid = $3`,
-			transport, extHash, s.GetHMACKid(),
-		).Scan(&uid); err == nil { // Optional: refresh pii.telegram if chatID provided (e.g., chat migrated)
+		// 1) Check if mapping already exists (active kid first, then older kids).
+		found, err := s.lookupIdentity(ctx, tx, transport, externalID, &uid)
+		if err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if found {
+			// Optional: refresh pii.telegram if chatID provided (e.g., chat migrated)
 			if chatID != 0 {
 				if err := upsertPIITelegram(ctx, tx, s.GetAEADBox(), s.GetAEADKid(), uid, chatID, aad); err != nil {
 					return validate.Wrap(op, err)
 				}
 			}
 			return nil
-		} else if !errors.Is(err, pgx.ErrNoRows) {
-			return validate.Wrap(op, err)
 		}
 
 		// 2) Create new user if not exists.
@@ -58,7 +57,7 @@ func (s *Store) UpsertIdentity(ctx context.Context, transport, externalID string
 
 		// 3) Try to bind identity (could race with a parallel insert).
 		var insertedUserID int64
-		err := tx.QueryRow(ctx, `
+		err = tx.QueryRow(ctx, `
 			INSERT INTO user_identities (user_id, transport, external_hash, hmac_kid)
 			VALUES ($1, $2, $3, $4)
 			ON CONFLICT (transport, external_hash, hmac_kid) DO NOTHING
@@ -104,6 +103,147 @@ func (s *Store) UpsertIdentity(ctx context.Context, transport, externalID string
 	return uid, nil
 }
 
+// lookupIdentity finds the user bound to (transport, externalID).
+// The active HMAC kid is tried first; on a hit under an older kid the row is
+// moved to the active kid so that the next lookup succeeds on the first query.
+func (s *Store) lookupIdentity(ctx context.Context, tx pgx.Tx, transport, externalID string, uid *int64) (bool, error) {
+	const op = "postgres.lookupIdentity"
+
+	activeKid := s.GetHMACKid()
+
+	for _, kid := range s.HMACKids() {
+		extHash := s.ExternalHashForKid(kid, transport, externalID)
+
+		err := tx.QueryRow(ctx,
+			`SELECT user_id
+			   FROM user_identities
+			  WHERE transport = $1 AND external_hash = $2 AND hmac_kid = $3`,
+			transport, extHash, kid,
+		).Scan(uid)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+
+		if err != nil {
+			return false, validate.Wrap(op, err)
+		}
+
+		if kid == activeKid {
+			return true, nil
+		}
+
+		// Move the row to the active kid. A concurrent UpsertIdentity may have inserted
+		// it there already: then nothing is inserted and that row is read back instead.
+		activeHash := s.ExternalHash(transport, externalID)
+
+		err = tx.QueryRow(ctx, `
+			WITH old AS (
+				DELETE FROM user_identities
+				 WHERE transport = $3 AND external_hash = $4 AND hmac_kid = $5
+				RETURNING user_id
+			)
+			INSERT INTO user_identities (user_id, transport, external_hash, hmac_kid)
+			SELECT user_id, $3, $1, $2 FROM old
+			ON CONFLICT (transport, external_hash, hmac_kid) DO NOTHING
+			RETURNING user_id
+		`, activeHash, activeKid, transport, extHash, kid).Scan(uid)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = tx.QueryRow(ctx,
+				`SELECT user_id
+				   FROM user_identities
+				  WHERE transport = $1 AND external_hash = $2 AND hmac_kid = $3`,
+				transport, activeHash, activeKid,
+			).Scan(uid)
+		}
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Unbound concurrently.
+			return false, nil
+		}
+
+		if err != nil {
+			return false, validate.Wrap(op, err)
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// BindIdentity maps (transport, externalID) to an existing user, replacing any previous mapping.
+func (s *Store) BindIdentity(ctx context.Context, transport, externalID string, userID int64) error {
+	const op = "postgres.BindIdentity"
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return validate.Wrap(op, err)
+	}
+	if err := validate.ValidateExternalID(externalID); err != nil {
+		return validate.Wrap(op, err)
+	}
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return s.bindIdentity(ctx, tx, transport, externalID, userID)
+	}); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// bindIdentity upserts the identity row under the active HMAC kid and drops
+// rows for the same identity hashed with older kids.
+func (s *Store) bindIdentity(ctx context.Context, tx pgx.Tx, transport, externalID string, userID int64) error {
+	const op = "postgres.bindIdentity"
+
+	activeKid := s.GetHMACKid()
+
+	for _, kid := range s.HMACKids() {
+		if kid == activeKid {
+			continue
+		}
+
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM user_identities WHERE transport = $1 AND external_hash = $2 AND hmac_kid = $3`,
+			transport, s.ExternalHashForKid(kid, transport, externalID), kid,
+		); err != nil {
+			return validate.Wrap(op, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO user_identities (user_id, transport, external_hash, hmac_kid)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (transport, external_hash, hmac_kid) DO UPDATE
+		    SET user_id = EXCLUDED.user_id
+	`, userID, transport, s.ExternalHash(transport, externalID), activeKid); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// unbindIdentity deletes (transport, externalID) under every known kid.
+func (s *Store) unbindIdentity(ctx context.Context, tx pgx.Tx, transport, externalID string) error {
+	const op = "postgres.unbindIdentity"
+
+	for _, kid := range s.HMACKids() {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM user_identities WHERE transport = $1 AND external_hash = $2 AND hmac_kid = $3`,
+			transport, s.ExternalHashForKid(kid, transport, externalID), kid,
+		); err != nil {
+			return validate.Wrap(op, err)
+		}
+	}
+
+	return nil
+}
+
 func (s *Store) GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error) {
 	const op = "postgres.GetUserScheme"
 
@@ -0,0 +1,99 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/postgres"
+	"github.com/tuor4eg/ip_accounting_bot/migrations"
+)
+
+// Concurrent lookups of an identity hashed with an old kid move it to the active kid
+// once and all resolve to its user.
+func TestUpsertIdentity_KidMigrationRace(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	const (
+		oldKey  = "identities-test-hmac-old"
+		newKey  = "identities-test-hmac-new"
+		aeadKey = "0123456789abcdef0123456789abcdef"
+		workers = 16
+	)
+
+	ctx := context.Background()
+
+	open := func(t *testing.T) *postgres.Store {
+		t.Helper()
+
+		store, err := postgres.Open(ctx, dsn)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close(ctx) })
+
+		return store
+	}
+
+	before := open(t)
+
+	if _, err := migrations.ApplyUp(ctx, before.Pool, migrations.FS, "sql"); err != nil {
+		t.Fatalf("ApplyUp: %v", err)
+	}
+
+	if err := before.SetCryptoKeys(oldKey, 1, aeadKey, 1); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+
+	externalID := fmt.Sprintf("kid-race-%d", time.Now().UnixNano())
+
+	want, err := before.UpsertIdentity(ctx, domain.TransportTelegram, externalID, 0)
+	if err != nil {
+		t.Fatalf("UpsertIdentity(old kid): %v", err)
+	}
+
+	after := open(t)
+
+	if err := after.SetCryptoKeys(newKey, 2, aeadKey, 1); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+	if err := after.AddHMACKey(1, oldKey); err != nil {
+		t.Fatalf("AddHMACKey: %v", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		uids [workers]int64
+		errs [workers]error
+	)
+
+	start := make(chan struct{})
+
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			uids[i], errs[i] = after.UpsertIdentity(ctx, domain.TransportTelegram, externalID, 0)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	for i := range workers {
+		if errs[i] != nil {
+			t.Fatalf("UpsertIdentity #%d: %v", i, errs[i])
+		}
+		if uids[i] != want {
+			t.Fatalf("UpsertIdentity #%d = user %d, want %d", i, uids[i], want)
+		}
+	}
+}
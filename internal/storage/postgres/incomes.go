@@ -7,31 +7,73 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
 // InsertIncome inserts a single income record.
 // 'amount' is in minor currency units (e.g., kopecks), must be >= 0.
 // 'at' is the income date; only the date part is stored (cast to DATE in SQL).
-func (s *Store) InsertIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) error {
+// Returns the ID of the inserted row.
+func (s *Store) InsertIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) (int64, error) {
 	const op = "postgres.InsertIncome"
 
 	if err := validate.ValidateUserID(userID); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 	if err := validate.ValidateAmount(amount); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	// Persist only the calendar day for 'at'; NULLIF trims empty notes to NULL.
-	_, err := s.Pool.Exec(ctx, `
-		INSERT INTO incomes (user_id, at, amount, note)
-		VALUES ($1, $2::date, $3, NULLIF($4, ''))
-	`, userID, at, amount, note)
+	var id int64
+	err := s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO incomes (user_id, at, amount, note)
+			VALUES ($1, $2::date, $3, NULLIF($4, ''))
+			RETURNING id
+		`, userID, at, amount, note).Scan(&id); err != nil {
+			return err
+		}
+
+		return s.recordMessageEntry(ctx, tx, userID, domain.EntryKindIncome, id)
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	return id, nil
+}
+
+// UpdateIncome replaces date, amount and note of an active income owned by userID.
+// ok=false if there is no such income or it was voided.
+func (s *Store) UpdateIncome(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (ok bool, err error) {
+	const op = "postgres.UpdateIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(amount); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE incomes
+			   SET at = $3::date, amount = $4, note = NULLIF($5, '')
+			 WHERE id = $1 AND user_id = $2 AND voided_at IS NULL
+		`, id, userID, at, amount, note)
+		if err != nil {
+			return err
+		}
+
+		ok = tag.RowsAffected() > 0
+		return nil
+	})
 	if err != nil {
-		return validate.Wrap(op, err)
+		return false, validate.Wrap(op, err)
 	}
-	return nil
+
+	return ok, nil
 }
 
 // VoidLastIncomeInRange marks the newest "active" income in [from,to] as voided (soft-delete).
@@ -55,7 +97,7 @@ func (s *Store) VoidLastIncomeInRange(ctx context.Context, userID int64, from, t
 		SELECT id
 		FROM incomes
 		WHERE user_id = $1
-		  AND at BETWEEN $2 AND $3
+		  AND at BETWEEN $2::date AND $3::date
 		  AND voided_at IS NULL
 		ORDER BY at DESC, created_at DESC, id DESC
 		LIMIT 1
@@ -67,17 +109,69 @@ func (s *Store) VoidLastIncomeInRange(ctx context.Context, userID int64, from, t
 	RETURNING i.amount, i.at, i.note;
 	`
 
-	row := s.Pool.QueryRow(ctx, q, userID, from, to, now)
+	var noteNull sql.NullString
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, q, userID, from, to, now).Scan(&amount, &at, &noteNull)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			ok = true
+		}
+		return err
+	})
+	if err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
+	if noteNull.Valid {
+		note = noteNull.String
+	}
+
+	return amount, at, note, true, nil
+}
+
+// VoidIncome marks the active income id of userID as voided and returns its (amount, at, note).
+// ok=false if the user has no such active income.
+func (s *Store) VoidIncome(ctx context.Context, userID, id int64, now time.Time) (
+	amount int64, at time.Time, note string, ok bool, err error,
+) {
+	const op = "postgres.VoidIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
 
 	var noteNull sql.NullString
 
-	if err := row.Scan(&amount, &at, &noteNull); err != nil {
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE incomes
+			SET voided_at = $3
+			WHERE id = $1 AND user_id = $2 AND voided_at IS NULL
+			RETURNING amount, at, note
+		`, id, userID, now).Scan(&amount, &at, &noteNull)
 		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, time.Time{}, "", false, nil
+			return nil
 		}
+		if err == nil {
+			ok = true
+		}
+		return err
+	})
+	if err != nil {
 		return 0, time.Time{}, "", false, validate.Wrap(op, err)
 	}
 
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
 	if noteNull.Valid {
 		note = noteNull.String
 	}
@@ -112,3 +206,168 @@ func (s *Store) SumIncomes(ctx context.Context, userID int64, from, to time.Time
 	}
 	return sum, nil
 }
+
+// SumIncomesByMonth returns active incomes in [from..to] inclusive summed per calendar month, ordered by month.
+func (s *Store) SumIncomesByMonth(ctx context.Context, userID int64, from, to time.Time) ([]domain.MonthSum, error) {
+	const op = "postgres.SumIncomesByMonth"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.Pool.Query(ctx, `
+		SELECT date_trunc('month', at)::date AS month, SUM(amount)::bigint
+		FROM incomes
+		WHERE user_id = $1
+		AND at BETWEEN $2::date AND $3::date
+		AND voided_at IS NULL
+		GROUP BY month
+		ORDER BY month;
+	`, userID, from, to)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.MonthSum
+
+	for rows.Next() {
+		var m domain.MonthSum
+
+		if err := rows.Scan(&m.Month, &m.Sum); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// IncomeStatsByBucket returns the count, sum and largest of the active incomes in [from..to]
+// inclusive per calendar month or ISO week, ordered by bucket start.
+func (s *Store) IncomeStatsByBucket(ctx context.Context, userID int64, bucket domain.ReportBucket, from, to time.Time) ([]domain.IncomeStats, error) {
+	const op = "postgres.IncomeStatsByBucket"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateReportBucket(bucket); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	// Bucket names are date_trunc fields; a 'week' starts on the ISO Monday.
+	rows, err := s.Pool.Query(ctx, `
+		SELECT date_trunc($4::text, at)::date AS start, COUNT(*), SUM(amount)::bigint, MAX(amount)::bigint
+		FROM incomes
+		WHERE user_id = $1
+		AND at BETWEEN $2::date AND $3::date
+		AND voided_at IS NULL
+		GROUP BY start
+		ORDER BY start;
+	`, userID, from, to, string(bucket))
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.IncomeStats
+
+	for rows.Next() {
+		var b domain.IncomeStats
+
+		if err := rows.Scan(&b.Start, &b.Count, &b.Sum, &b.Max); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// ListIncomes returns active incomes for a user in [from..to] inclusive, ordered by (at, id).
+func (s *Store) ListIncomes(ctx context.Context, userID int64, from, to time.Time) ([]domain.Income, error) {
+	const op = "postgres.ListIncomes"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.Pool.Query(ctx, `
+		SELECT id, at, amount, COALESCE(note, '')
+		FROM incomes
+		WHERE user_id = $1
+		  AND at BETWEEN $2::date AND $3::date
+		  AND voided_at IS NULL
+		ORDER BY at, id
+	`, userID, from, to)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.Income
+
+	for rows.Next() {
+		var it domain.Income
+
+		if err := rows.Scan(&it.ID, &it.At, &it.Amount, &it.Note); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// GetIncome returns an active income of userID; ok=false if there is none with this id.
+func (s *Store) GetIncome(ctx context.Context, userID, id int64) (domain.Income, bool, error) {
+	const op = "postgres.GetIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Income{}, false, validate.Wrap(op, err)
+	}
+
+	var it domain.Income
+
+	err := s.Pool.QueryRow(ctx, `
+		SELECT id, at, amount, COALESCE(note, '')
+		FROM incomes
+		WHERE id = $1 AND user_id = $2 AND voided_at IS NULL
+	`, id, userID).Scan(&it.ID, &it.At, &it.Amount, &it.Note)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Income{}, false, nil
+	}
+	if err != nil {
+		return domain.Income{}, false, validate.Wrap(op, err)
+	}
+
+	return it, true, nil
+}
@@ -0,0 +1,347 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// CreateCounterparty stores a client of c.UserID and returns its ID.
+// The unique index of migration 0001 compares names trimmed and lower-cased.
+func (s *Store) CreateCounterparty(ctx context.Context, c domain.Counterparty) (int64, error) {
+	const op = "postgres.CreateCounterparty"
+
+	if err := validate.ValidateUserID(c.UserID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var id int64
+	err := s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO counterparties (user_id, name, inn, kpp, address)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''))
+			ON CONFLICT (user_id, name_norm) DO NOTHING
+			RETURNING id
+		`, c.UserID, c.Name, c.INN, c.KPP, c.Address).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrClientExists
+		}
+
+		return err
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+// UpdateCounterparty replaces the INN, KPP and address of a client of c.UserID.
+func (s *Store) UpdateCounterparty(ctx context.Context, c domain.Counterparty) (bool, error) {
+	const op = "postgres.UpdateCounterparty"
+
+	if err := validate.ValidateUserID(c.UserID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	tag, err := s.Pool.Exec(ctx, `
+		UPDATE counterparties
+		   SET inn = NULLIF($3, ''), kpp = NULLIF($4, ''), address = NULLIF($5, '')
+		 WHERE id = $1 AND user_id = $2 AND archived_at IS NULL
+	`, c.ID, c.UserID, c.INN, c.KPP, c.Address)
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListCounterparties returns the clients of userID that are not archived, ordered by ID.
+func (s *Store) ListCounterparties(ctx context.Context, userID int64) ([]domain.Counterparty, error) {
+	const op = "postgres.ListCounterparties"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.Pool.Query(ctx, `
+		SELECT id, user_id, name, COALESCE(inn, ''), COALESCE(kpp, ''), COALESCE(address, '')
+		FROM counterparties
+		WHERE user_id = $1 AND archived_at IS NULL
+		ORDER BY id
+	`, userID)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.Counterparty
+
+	for rows.Next() {
+		var c domain.Counterparty
+
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.INN, &c.KPP, &c.Address); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// GetRequisites returns the requisites of userID; all fields are empty if none were set.
+func (s *Store) GetRequisites(ctx context.Context, userID int64) (domain.Requisites, error) {
+	const op = "postgres.GetRequisites"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	var r domain.Requisites
+
+	err := s.Pool.QueryRow(ctx, `
+		SELECT name, inn, ogrnip, address, bank, bik, account, corr_account
+		FROM user_requisites
+		WHERE user_id = $1
+	`, userID).Scan(&r.Name, &r.INN, &r.OGRNIP, &r.Address, &r.Bank, &r.BIK, &r.Account, &r.CorrAccount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Requisites{}, nil
+	}
+	if err != nil {
+		return domain.Requisites{}, validate.Wrap(op, err)
+	}
+
+	return r, nil
+}
+
+// SetRequisites replaces the requisites of userID.
+func (s *Store) SetRequisites(ctx context.Context, userID int64, r domain.Requisites) error {
+	const op = "postgres.SetRequisites"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if _, err := s.Pool.Exec(ctx, `
+		INSERT INTO user_requisites (user_id, name, inn, ogrnip, address, bank, bik, account, corr_account)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		   SET name = excluded.name, inn = excluded.inn, ogrnip = excluded.ogrnip,
+		       address = excluded.address, bank = excluded.bank, bik = excluded.bik,
+		       account = excluded.account, corr_account = excluded.corr_account,
+		       updated_at = now()
+	`, userID, r.Name, r.INN, r.OGRNIP, r.Address, r.Bank, r.BIK, r.Account, r.CorrAccount); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// CreateInvoice stores inv with the next number of its user in the year of inv.IssuedOn.
+// The user row is locked so that concurrent invoices do not take the same number.
+func (s *Store) CreateInvoice(ctx context.Context, inv domain.Invoice) (id, number int64, err error) {
+	const op = "postgres.CreateInvoice"
+
+	if err := validate.ValidateUserID(inv.UserID); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(inv.Amount); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, inv.UserID); err != nil {
+			return err
+		}
+
+		var clientID int64
+
+		err := tx.QueryRow(ctx, `
+			SELECT id FROM counterparties WHERE id = $1 AND user_id = $2 AND archived_at IS NULL
+		`, inv.Client.ID, inv.UserID).Scan(&clientID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrEntryNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryRow(ctx, `
+			SELECT COALESCE(MAX(number), 0) + 1 FROM invoices WHERE user_id = $1 AND year = $2
+		`, inv.UserID, inv.IssuedOn.Year()).Scan(&number); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(ctx, `
+			INSERT INTO invoices (user_id, year, number, counterparty_id, issued_on, due_on, amount, description)
+			VALUES ($1, $2, $3, $4, $5::date, $6::date, $7, NULLIF($8, ''))
+			RETURNING id
+		`, inv.UserID, inv.IssuedOn.Year(), number, clientID, inv.IssuedOn, inv.DueOn, inv.Amount, inv.Description).Scan(&id)
+	})
+	if err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	return id, number, nil
+}
+
+// invoiceSelect reads invoices with their clients and the sum of their active incomes.
+const invoiceSelect = `
+	SELECT i.id, i.user_id, i.number, i.issued_on, i.due_on, i.amount, COALESCE(i.description, ''), i.reminded_on,
+	       c.id, c.user_id, c.name, COALESCE(c.inn, ''), COALESCE(c.kpp, ''), COALESCE(c.address, ''),
+	       COALESCE(p.paid, 0)
+	FROM invoices i
+	JOIN counterparties c ON c.id = i.counterparty_id
+	LEFT JOIN LATERAL (
+		SELECT SUM(n.amount)::bigint AS paid
+		FROM invoice_payments ip
+		JOIN incomes n ON n.id = ip.income_id AND n.voided_at IS NULL
+		WHERE ip.invoice_id = i.id
+	) p ON true
+`
+
+// ListInvoices returns the invoices of userID, newest first; a non-zero invoiceID keeps only that one.
+func (s *Store) ListInvoices(ctx context.Context, userID, invoiceID int64) ([]domain.Invoice, error) {
+	const op = "postgres.ListInvoices"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	invoices, err := s.queryInvoices(ctx, invoiceSelect+`
+		WHERE i.user_id = $1 AND ($2::bigint = 0 OR i.id = $2)
+		ORDER BY i.issued_on DESC, i.id DESC
+	`, userID, invoiceID)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return invoices, nil
+}
+
+// OverdueInvoices returns the invoices of all users not paid in full with due_on before today
+// and reminded_on unset or not after remindedBefore, ordered by due date.
+func (s *Store) OverdueInvoices(ctx context.Context, today, remindedBefore time.Time) ([]domain.Invoice, error) {
+	const op = "postgres.OverdueInvoices"
+
+	invoices, err := s.queryInvoices(ctx, invoiceSelect+`
+		WHERE i.due_on < $1::date
+		  AND (i.reminded_on IS NULL OR i.reminded_on <= $2::date)
+		  AND COALESCE(p.paid, 0) < i.amount
+		ORDER BY i.due_on, i.id
+	`, today, remindedBefore)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return invoices, nil
+}
+
+func (s *Store) queryInvoices(ctx context.Context, q string, args ...any) ([]domain.Invoice, error) {
+	rows, err := s.Pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Invoice
+
+	for rows.Next() {
+		var (
+			inv      domain.Invoice
+			reminded *time.Time
+		)
+
+		if err := rows.Scan(&inv.ID, &inv.UserID, &inv.Number, &inv.IssuedOn, &inv.DueOn, &inv.Amount, &inv.Description, &reminded,
+			&inv.Client.ID, &inv.Client.UserID, &inv.Client.Name, &inv.Client.INN, &inv.Client.KPP, &inv.Client.Address,
+			&inv.Paid); err != nil {
+			return nil, err
+		}
+
+		if reminded != nil {
+			inv.RemindedOn = *reminded
+		}
+
+		out = append(out, inv)
+	}
+
+	return out, rows.Err()
+}
+
+// MarkInvoiceReminded records the day of the last overdue reminder of an invoice.
+func (s *Store) MarkInvoiceReminded(ctx context.Context, invoiceID int64, day time.Time) error {
+	const op = "postgres.MarkInvoiceReminded"
+
+	if _, err := s.Pool.Exec(ctx, `UPDATE invoices SET reminded_on = $2::date WHERE id = $1`, invoiceID, day); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// PayInvoice creates an income and links it to an invoice of userID in one transaction,
+// see service.InvoiceStore. The invoice row is locked while what is left is computed.
+func (s *Store) PayInvoice(ctx context.Context, userID, invoiceID int64, at time.Time, amount int64, note string) (int64, error) {
+	const op = "postgres.PayInvoice"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var id int64
+
+	err := s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var total, paid int64
+
+		err := tx.QueryRow(ctx, `
+			SELECT i.amount,
+			       (SELECT COALESCE(SUM(n.amount), 0)::bigint
+			          FROM invoice_payments ip
+			          JOIN incomes n ON n.id = ip.income_id AND n.voided_at IS NULL
+			         WHERE ip.invoice_id = i.id)
+			FROM invoices i
+			WHERE i.id = $1 AND i.user_id = $2
+			FOR UPDATE
+		`, invoiceID, userID).Scan(&total, &paid)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrEntryNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if amount, err = (domain.Invoice{Amount: total, Paid: paid}).Payment(amount); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO incomes (user_id, at, amount, note)
+			VALUES ($1, $2::date, $3, NULLIF($4, ''))
+			RETURNING id
+		`, userID, at, amount, note).Scan(&id); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO invoice_payments (income_id, invoice_id) VALUES ($1, $2)
+		`, id, invoiceID); err != nil {
+			return err
+		}
+
+		return s.recordMessageEntry(ctx, tx, userID, domain.EntryKindIncome, id)
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// GetIdentityLang returns users.lang of the user bound to (transport, externalID).
+// Unlike UpsertIdentity it does not create a user for an unknown identity.
+func (s *Store) GetIdentityLang(ctx context.Context, transport, externalID string) (string, error) {
+	const op = "postgres.GetIdentityLang"
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+	if err := validate.ValidateExternalID(externalID); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	var lang string
+
+	if err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var uid int64
+
+		found, err := s.lookupIdentity(ctx, tx, transport, externalID, &uid)
+		if err != nil || !found {
+			return err
+		}
+
+		return tx.QueryRow(ctx, `SELECT COALESCE(lang, '') FROM users WHERE id = $1`, uid).Scan(&lang)
+	}); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return lang, nil
+}
+
+// SetUserLang stores the language chosen with /lang; "" resets it to NULL.
+func (s *Store) SetUserLang(ctx context.Context, userID int64, lang string) error {
+	const op = "postgres.SetUserLang"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+	if err := validate.ValidateLang(lang); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	tag, err := s.Pool.Exec(ctx, `UPDATE users SET lang = NULLIF($2, '') WHERE id = $1`, userID, lang)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return validate.Wrap(op, validate.ErrNotFound)
+	}
+
+	return nil
+}
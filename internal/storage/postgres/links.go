@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// CreateLinkCode stores a code hash for userID and drops the user's previous unused codes.
+func (s *Store) CreateLinkCode(ctx context.Context, userID int64, codeHash []byte, now, expiresAt time.Time) error {
+	const op = "postgres.CreateLinkCode"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM link_codes WHERE user_id = $1 AND (used_at IS NULL OR expires_at <= $2)`,
+			userID, now,
+		); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO link_codes (user_id, code_hash, created_at, expires_at)
+			VALUES ($1, $2, $3, $4)
+		`, userID, codeHash, now, expiresAt); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// RedeemLinkCode consumes an active code and binds (transport, externalID) to its owner in one transaction.
+// The identity's current account must not have any incomes or payments, otherwise they would be orphaned.
+func (s *Store) RedeemLinkCode(ctx context.Context, codeHash []byte, now time.Time, currentUserID int64, transport, externalID string) (int64, error) {
+	const op = "postgres.RedeemLinkCode"
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateExternalID(externalID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var target int64
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var codeID int64
+
+		err := tx.QueryRow(ctx, `
+			SELECT id, user_id
+			FROM link_codes
+			WHERE code_hash = $1 AND used_at IS NULL AND expires_at > $2
+			FOR UPDATE
+		`, codeHash, now).Scan(&codeID, &target)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrLinkCodeInvalid
+		}
+		if err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if target == currentUserID {
+			return domain.ErrAlreadyLinked
+		}
+
+		var hasEntries bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM incomes WHERE user_id = $1)
+			    OR EXISTS (SELECT 1 FROM payments WHERE user_id = $1)
+		`, currentUserID).Scan(&hasEntries); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if hasEntries {
+			return domain.ErrLinkHasEntries
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE link_codes SET used_at = $2 WHERE id = $1`, codeID, now); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		return s.bindIdentity(ctx, tx, transport, externalID, target)
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return target, nil
+}
+
+// UnbindIdentities removes identities of transport from userID (all of them if externalID is empty).
+// Fails with domain.ErrLastIdentity if no non-API identity would remain.
+func (s *Store) UnbindIdentities(ctx context.Context, userID int64, transport, externalID string) (int64, error) {
+	const op = "postgres.UnbindIdentities"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var removed int64
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Serialize concurrent unlinks of the same account.
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if externalID == "" {
+			tag, err := tx.Exec(ctx,
+				`DELETE FROM user_identities WHERE user_id = $1 AND transport = $2`,
+				userID, transport,
+			)
+			if err != nil {
+				return validate.Wrap(op, err)
+			}
+			removed = tag.RowsAffected()
+		} else {
+			// The identity may still be hashed with an older kid.
+			for _, kid := range s.HMACKids() {
+				tag, err := tx.Exec(ctx, `
+					DELETE FROM user_identities
+					WHERE user_id = $1 AND transport = $2 AND external_hash = $3 AND hmac_kid = $4
+				`, userID, transport, s.ExternalHashForKid(kid, transport, externalID), kid)
+				if err != nil {
+					return validate.Wrap(op, err)
+				}
+				removed += tag.RowsAffected()
+			}
+		}
+
+		if removed == 0 {
+			return nil
+		}
+
+		var kept int64
+		if err := tx.QueryRow(ctx,
+			`SELECT count(*) FROM user_identities WHERE user_id = $1 AND transport <> $2`,
+			userID, domain.TransportAPI,
+		).Scan(&kept); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if kept == 0 {
+			return domain.ErrLastIdentity
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return removed, nil
+}
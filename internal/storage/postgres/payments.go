@@ -11,34 +11,79 @@ import (
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
-func (s *Store) InsertPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, paymentType domain.PaymentType) error {
+func (s *Store) InsertPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, paymentType domain.PaymentType) (int64, error) {
 	const op = "postgres.InsertPayment"
 
 	if err := validate.ValidateUserID(userID); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	if err := validate.ValidateAmount(amount); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	if err := validate.OneOf(paymentType, domain.PaymentTypeContrib, domain.PaymentTypeAdvance); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
 	if err := validate.ValidateDateRangeUTC(at, at); err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
-	_, err := s.Pool.Exec(ctx, `
-		INSERT INTO payments (user_id, at, amount, note, type)
-		VALUES ($1, $2::date, $3, NULLIF($4, ''), $5)
-	`, userID, at, amount, note, paymentType)
+	var id int64
+	err := s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO payments (user_id, at, amount, note, type)
+			VALUES ($1, $2::date, $3, NULLIF($4, ''), $5)
+			RETURNING id
+		`, userID, at, amount, note, paymentType).Scan(&id); err != nil {
+			return err
+		}
+
+		return s.recordMessageEntry(ctx, tx, userID, domain.EntryKind(paymentType), id)
+	})
 	if err != nil {
-		return validate.Wrap(op, err)
+		return 0, validate.Wrap(op, err)
 	}
 
-	return nil
+	return id, nil
+}
+
+// UpdatePayment replaces date, amount and note of an active payment owned by userID; the type is kept.
+// ok=false if there is no such payment or it was voided.
+func (s *Store) UpdatePayment(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (ok bool, err error) {
+	const op = "postgres.UpdatePayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateAmount(amount); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(at, at); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE payments
+			   SET at = $3::date, amount = $4, note = NULLIF($5, '')
+			 WHERE id = $1 AND user_id = $2 AND voided_at IS NULL
+		`, id, userID, at, amount, note)
+		if err != nil {
+			return err
+		}
+
+		ok = tag.RowsAffected() > 0
+		return nil
+	})
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return ok, nil
 }
 
 func (s *Store) VoidLastPaymentInRange(ctx context.Context, userID int64, from, to, now time.Time, paymentType domain.PaymentType) (
@@ -63,7 +108,7 @@ func (s *Store) VoidLastPaymentInRange(ctx context.Context, userID int64, from,
 		SELECT id
 		FROM payments
 		WHERE user_id = $1
-		  AND at BETWEEN $2 AND $3
+		  AND at BETWEEN $2::date AND $3::date
 		  AND type = $4
 		  AND voided_at IS NULL
 		ORDER BY at DESC, created_at DESC, id DESC
@@ -76,23 +121,104 @@ func (s *Store) VoidLastPaymentInRange(ctx context.Context, userID int64, from,
 	RETURNING p.amount, p.at, p.note, p.type;
 	`
 
-	row := s.Pool.QueryRow(ctx, q, userID, from, to, paymentType, now)
-
 	var noteNull sql.NullString
 
-	if err := row.Scan(&amount, &at, &noteNull, &pType); err != nil {
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, q, userID, from, to, paymentType, now).Scan(&amount, &at, &noteNull, &pType)
 		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, time.Time{}, "", domain.PaymentType(""), false, nil
+			return nil
+		}
+		if err == nil {
+			ok = true
 		}
+		return err
+	})
+	if err != nil {
 		return 0, time.Time{}, "", domain.PaymentType(""), false, validate.Wrap(op, err)
 	}
 
+	if !ok {
+		return 0, time.Time{}, "", domain.PaymentType(""), false, nil
+	}
+
 	if noteNull.Valid {
 		note = noteNull.String
 	}
 	return amount, at, note, pType, true, nil
 }
 
+// VoidPayment marks the active payment id of userID as voided if it is of paymentType and
+// returns its (amount, at, note). ok=false if the user has no such active payment.
+func (s *Store) VoidPayment(ctx context.Context, userID, id int64, now time.Time, paymentType domain.PaymentType) (
+	amount int64, at time.Time, note string, ok bool, err error,
+) {
+	const op = "postgres.VoidPayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if err := validate.OneOf(paymentType, domain.PaymentTypeContrib, domain.PaymentTypeAdvance); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	var noteNull sql.NullString
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE payments
+			SET voided_at = $4
+			WHERE id = $1 AND user_id = $2 AND type = $3 AND voided_at IS NULL
+			RETURNING amount, at, note
+		`, id, userID, paymentType, now).Scan(&amount, &at, &noteNull)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			ok = true
+		}
+		return err
+	})
+	if err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
+	if noteNull.Valid {
+		note = noteNull.String
+	}
+
+	return amount, at, note, true, nil
+}
+
+// GetPayment returns an active payment of userID; ok=false if there is none with this id.
+func (s *Store) GetPayment(ctx context.Context, userID, id int64) (domain.Payment, bool, error) {
+	const op = "postgres.GetPayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Payment{}, false, validate.Wrap(op, err)
+	}
+
+	var p domain.Payment
+
+	err := s.Pool.QueryRow(ctx, `
+		SELECT id, at, amount, COALESCE(note, ''), type
+		FROM payments
+		WHERE id = $1 AND user_id = $2 AND voided_at IS NULL
+	`, id, userID).Scan(&p.ID, &p.At, &p.Amount, &p.Note, &p.Type)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Payment{}, false, nil
+	}
+	if err != nil {
+		return domain.Payment{}, false, validate.Wrap(op, err)
+	}
+
+	return p, true, nil
+}
+
 func (s *Store) SumPayments(ctx context.Context, userID int64, from, to time.Time) (int64, int64, error) {
 	const op = "postgres.SumPayments"
 
@@ -121,3 +247,47 @@ func (s *Store) SumPayments(ctx context.Context, userID int64, from, to time.Tim
 	}
 	return sumContrib, sumAdvance, nil
 }
+
+// ListPayments returns active payments of both types in [from..to] inclusive, ordered by (at, id).
+func (s *Store) ListPayments(ctx context.Context, userID int64, from, to time.Time) ([]domain.Payment, error) {
+	const op = "postgres.ListPayments"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.Pool.Query(ctx, `
+		SELECT id, at, amount, COALESCE(note, ''), type
+		FROM payments
+		WHERE user_id = $1
+		  AND at BETWEEN $2::date AND $3::date
+		  AND voided_at IS NULL
+		ORDER BY at, id
+	`, userID, from, to)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.Payment
+
+	for rows.Next() {
+		var it domain.Payment
+
+		if err := rows.Scan(&it.ID, &it.At, &it.Amount, &it.Note, &it.Type); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
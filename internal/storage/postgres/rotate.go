@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tuor4eg/ip_accounting_bot/internal/crypto"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// piiTelegramAAD must match the AAD used by UpsertIdentity for the telegram transport.
+const piiTelegramAAD = "telegram"
+
+// CountPIITelegramToRotate returns how many pii.telegram rows are not yet encrypted with the active AEAD kid.
+func (s *Store) CountPIITelegramToRotate(ctx context.Context) (int64, error) {
+	const op = "postgres.CountPIITelegramToRotate"
+
+	if !s.HasCryptoKeys() {
+		return 0, validate.Wrap(op, ErrNoCryptoKey)
+	}
+
+	var n int64
+
+	if err := s.Pool.QueryRow(ctx,
+		`SELECT count(*) FROM pii.telegram WHERE enc_kid <> $1`,
+		s.GetAEADKid(),
+	).Scan(&n); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return n, nil
+}
+
+// RotatePIITelegramBatch re-encrypts up to limit pii.telegram rows with user_id > afterUserID
+// whose enc_kid differs from the active AEAD kid. Each batch runs in its own transaction.
+// Returns the number of rotated rows and the last user_id seen (the resume cursor);
+// rotated == 0 means there is nothing left after the cursor.
+func (s *Store) RotatePIITelegramBatch(ctx context.Context, afterUserID int64, limit int) (rotated int, lastUserID int64, err error) {
+	const op = "postgres.RotatePIITelegramBatch"
+
+	if !s.HasCryptoKeys() {
+		return 0, afterUserID, validate.Wrap(op, ErrNoCryptoKey)
+	}
+
+	lastUserID = afterUserID
+	newKid := s.GetAEADKid()
+	newBox := s.GetAEADBox()
+	aad := []byte(piiTelegramAAD)
+
+	err = s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT user_id, chat_enc, enc_kid
+			  FROM pii.telegram
+			 WHERE user_id > $1 AND enc_kid <> $2
+			 ORDER BY user_id
+			 LIMIT $3
+			   FOR UPDATE
+		`, afterUserID, newKid, limit)
+		if err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		var batch []piiTelegramRow
+
+		for rows.Next() {
+			var r piiTelegramRow
+
+			if err := rows.Scan(&r.userID, &r.chatEnc, &r.encKid); err != nil {
+				rows.Close()
+				return validate.Wrap(op, err)
+			}
+
+			batch = append(batch, r)
+		}
+
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		for _, r := range batch {
+			oldBox := s.GetAEADBoxByKid(r.encKid)
+
+			if oldBox == nil {
+				return validate.Wrap(op, fmt.Errorf("%w: user_id=%d enc_kid=%d", ErrUnknownKid, r.userID, r.encKid))
+			}
+
+			chatID, err := crypto.DecryptInt64(oldBox, r.chatEnc, aad)
+			if err != nil {
+				return validate.Wrap(op, err)
+			}
+
+			chatEnc, err := crypto.EncryptInt64(newBox, chatID, aad)
+			if err != nil {
+				return validate.Wrap(op, err)
+			}
+
+			// enc_kid in WHERE keeps the update idempotent if another rotation touched the row.
+			if _, err := tx.Exec(ctx, `
+				UPDATE pii.telegram
+				   SET chat_enc = $1, enc_kid = $2, updated_at = now()
+				 WHERE user_id = $3 AND enc_kid = $4
+			`, chatEnc, newKid, r.userID, r.encKid); err != nil {
+				return validate.Wrap(op, err)
+			}
+
+			rotated++
+			lastUserID = r.userID
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, afterUserID, validate.Wrap(op, err)
+	}
+
+	return rotated, lastUserID, nil
+}
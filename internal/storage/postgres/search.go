@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// likeEscaper escapes the LIKE wildcards of user input; backslash is the default escape.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchNotes returns up to limit active incomes and payments in [from..to] inclusive whose
+// folded note contains the folded query, newest first (by date, then ID).
+// The trigram indexes of migration 0008 serve queries of three or more characters.
+func (s *Store) SearchNotes(ctx context.Context, userID int64, query string, from, to time.Time, limit int) ([]domain.NoteMatch, error) {
+	const op = "postgres.SearchNotes"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	pattern := "%" + likeEscaper.Replace(domain.FoldNote(query)) + "%"
+
+	rows, err := s.Pool.Query(ctx, `
+		SELECT kind, id, at, amount, note
+		FROM (
+			SELECT 'income' AS kind, id, at, amount, note
+			FROM incomes
+			WHERE user_id = $1
+			  AND at BETWEEN $2::date AND $3::date
+			  AND voided_at IS NULL
+			  AND note IS NOT NULL
+			  AND note_fold(note) LIKE $4
+			UNION ALL
+			SELECT type, id, at, amount, note
+			FROM payments
+			WHERE user_id = $1
+			  AND at BETWEEN $2::date AND $3::date
+			  AND voided_at IS NULL
+			  AND note IS NOT NULL
+			  AND note_fold(note) LIKE $4
+		) m
+		ORDER BY at DESC, id DESC, kind
+		LIMIT $5
+	`, userID, from, to, pattern, limit)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.NoteMatch
+
+	for rows.Next() {
+		var m domain.NoteMatch
+
+		if err := rows.Scan(&m.Kind, &m.ID, &m.At, &m.Amount, &m.Note); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,41 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/postgres"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/storetest"
+	"github.com/tuor4eg/ip_accounting_bot/migrations"
+)
+
+// TestConformance runs against a real database: TEST_DATABASE_URL=postgres://... go test ./...
+// The schema is migrated first; every case creates users of its own, so the database
+// need not be empty, but do not point it at production data.
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	store, err := postgres.Open(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close(ctx) })
+
+	if _, err := migrations.ApplyUp(ctx, store.Pool, migrations.FS, "sql"); err != nil {
+		t.Fatalf("ApplyUp: %v", err)
+	}
+
+	if err := store.SetCryptoKeys("storetest-hmac", 1, "0123456789abcdef0123456789abcdef", 1); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return store
+	})
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// CreateAPIToken stores a token hash and binds the "api" identity (external_id = token id)
+// to the owner in the same transaction.
+func (s *Store) CreateAPIToken(ctx context.Context, userID int64, tokenHash []byte, name string) (int64, error) {
+	const op = "postgres.CreateAPIToken"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var id int64
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO api_tokens (user_id, token_hash, name)
+			VALUES ($1, $2, NULLIF($3, ''))
+			RETURNING id
+		`, userID, tokenHash, name).Scan(&id); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		return s.bindIdentity(ctx, tx, domain.TransportAPI, strconv.FormatInt(id, 10), userID)
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+// ResolveAPIToken returns the owner of an active token and bumps last_used_at.
+func (s *Store) ResolveAPIToken(ctx context.Context, tokenHash []byte, now time.Time) (int64, bool, error) {
+	const op = "postgres.ResolveAPIToken"
+
+	var userID int64
+
+	err := s.Pool.QueryRow(ctx, `
+		UPDATE api_tokens
+		   SET last_used_at = $2
+		 WHERE token_hash = $1 AND revoked_at IS NULL
+		RETURNING user_id
+	`, tokenHash, now).Scan(&userID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, validate.Wrap(op, err)
+	}
+
+	return userID, true, nil
+}
+
+// ListAPITokens returns active tokens of the user, newest first.
+func (s *Store) ListAPITokens(ctx context.Context, userID int64) ([]domain.APIToken, error) {
+	const op = "postgres.ListAPITokens"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.Pool.Query(ctx, `
+		SELECT id, COALESCE(name, ''), created_at, last_used_at
+		FROM api_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY id DESC
+	`, userID)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.APIToken
+
+	for rows.Next() {
+		var (
+			t        domain.APIToken
+			lastUsed sql.NullTime
+		)
+
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt, &lastUsed); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		if lastUsed.Valid {
+			t.LastUsedAt = lastUsed.Time
+		}
+
+		out = append(out, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// RevokeAPIToken revokes one active token owned by userID and drops its "api" identity.
+// ok=false if nothing was revoked.
+func (s *Store) RevokeAPIToken(ctx context.Context, userID, tokenID int64, now time.Time) (bool, error) {
+	const op = "postgres.RevokeAPIToken"
+
+	var ok bool
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE api_tokens
+			   SET revoked_at = $3
+			 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		`, tokenID, userID, now)
+		if err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if ok = tag.RowsAffected() > 0; !ok {
+			return nil
+		}
+
+		return s.unbindIdentity(ctx, tx, domain.TransportAPI, strconv.FormatInt(tokenID, 10))
+	})
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return ok, nil
+}
+
+// RevokeAllAPITokens revokes every active token owned by userID and drops their "api" identities.
+func (s *Store) RevokeAllAPITokens(ctx context.Context, userID int64, now time.Time) (int64, error) {
+	const op = "postgres.RevokeAllAPITokens"
+
+	var ids []int64
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			UPDATE api_tokens
+			   SET revoked_at = $2
+			 WHERE user_id = $1 AND revoked_at IS NULL
+			RETURNING id
+		`, userID, now)
+		if err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return validate.Wrap(op, err)
+			}
+			ids = append(ids, id)
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		for _, id := range ids {
+			if err := s.unbindIdentity(ctx, tx, domain.TransportAPI, strconv.FormatInt(id, 10)); err != nil {
+				return validate.Wrap(op, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return int64(len(ids)), nil
+}
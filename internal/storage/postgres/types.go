@@ -10,3 +10,10 @@ type Store struct {
 	cryptostore.BaseCryptoStore // Embed crypto capabilities
 	Pool                        *pgxpool.Pool
 }
+
+// piiTelegramRow is a raw pii.telegram row used during key rotation
+type piiTelegramRow struct {
+	userID  int64
+	chatEnc []byte
+	encKid  int16
+}
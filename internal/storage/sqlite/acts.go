@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// CreateAct stores act with the next number of its user in the year of act.IssuedOn,
+// see service.ActStore.
+func (s *Store) CreateAct(ctx context.Context, act domain.Act) (id, number int64, err error) {
+	const op = "sqlite.CreateAct"
+
+	if err := validate.ValidateUserID(act.UserID); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(act.Amount); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+	if (act.InvoiceID == 0) == (act.IncomeID == 0) {
+		return 0, 0, validate.Wrap(op, domain.ErrEntryNotFound)
+	}
+
+	year := act.IssuedOn.UTC().Year()
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		// The client, the invoice or the income must be the user's; the income must be active.
+		var found bool
+
+		if err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM counterparties WHERE id = ?2 AND user_id = ?1 AND archived_at IS NULL)
+			   AND (?3 = 0 OR EXISTS (SELECT 1 FROM invoices WHERE id = ?3 AND user_id = ?1))
+			   AND (?4 = 0 OR EXISTS (SELECT 1 FROM incomes WHERE id = ?4 AND user_id = ?1 AND voided_at IS NULL))
+		`, act.UserID, act.Client.ID, act.InvoiceID, act.IncomeID).Scan(&found); err != nil {
+			return err
+		}
+		if !found {
+			return domain.ErrEntryNotFound
+		}
+
+		var exists bool
+
+		if err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM acts
+				WHERE (?1 <> 0 AND invoice_id = ?1) OR (?2 <> 0 AND income_id = ?2)
+			)
+		`, act.InvoiceID, act.IncomeID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrActExists
+		}
+
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COALESCE(MAX(number), 0) + 1 FROM acts WHERE user_id = ?1 AND year = ?2
+		`, act.UserID, year).Scan(&number); err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx, `
+			INSERT INTO acts (user_id, year, number, counterparty_id, issued_on, amount, description, invoice_id, income_id)
+			VALUES (?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), NULLIF(?8, 0), NULLIF(?9, 0))
+			RETURNING id
+		`, act.UserID, year, number, act.Client.ID, day(act.IssuedOn), act.Amount, act.Description,
+			act.InvoiceID, act.IncomeID).Scan(&id)
+	})
+	if err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	return id, number, nil
+}
+
+// ListActs returns the acts of userID with their clients and invoices, newest first;
+// a non-zero actID keeps only that one.
+func (s *Store) ListActs(ctx context.Context, userID, actID int64) ([]domain.Act, error) {
+	const op = "sqlite.ListActs"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT a.id, a.user_id, a.number, a.issued_on, a.amount, COALESCE(a.description, ''),
+		       COALESCE(a.invoice_id, 0), COALESCE(i.number, 0), i.issued_on, COALESCE(a.income_id, 0),
+		       c.id, c.user_id, c.name, COALESCE(c.inn, ''), COALESCE(c.kpp, ''), COALESCE(c.address, '')
+		FROM acts a
+		JOIN counterparties c ON c.id = a.counterparty_id
+		LEFT JOIN invoices i ON i.id = a.invoice_id
+		WHERE a.user_id = ?1 AND (?2 = 0 OR a.id = ?2)
+		ORDER BY a.issued_on DESC, a.id DESC
+	`, userID, actID)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.Act
+
+	for rows.Next() {
+		var (
+			act           domain.Act
+			issued        string
+			invoiceIssued sql.NullString
+		)
+
+		if err := rows.Scan(&act.ID, &act.UserID, &act.Number, &issued, &act.Amount, &act.Description,
+			&act.InvoiceID, &act.InvoiceNumber, &invoiceIssued, &act.IncomeID,
+			&act.Client.ID, &act.Client.UserID, &act.Client.Name, &act.Client.INN, &act.Client.KPP, &act.Client.Address); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		if act.IssuedOn, err = parseDay(issued); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+		if invoiceIssued.Valid {
+			if act.InvoiceIssuedOn, err = parseDay(invoiceIssued.String); err != nil {
+				return nil, validate.Wrap(op, err)
+			}
+		}
+
+		out = append(out, act)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
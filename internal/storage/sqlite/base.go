@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+
+	_ "modernc.org/sqlite" // pure-Go driver "sqlite"
+)
+
+const (
+	// Scheme is the DATABASE_URL scheme selecting this store.
+	Scheme = "sqlite"
+
+	// MemoryPath keeps the database in memory, e.g. "sqlite::memory:" in tests.
+	MemoryPath = ":memory:"
+
+	busyTimeoutMs = 5000
+)
+
+// Open opens (creating if needed) the database file from DSN and applies the embedded migrations.
+// DSN examples: sqlite:bot.db, sqlite://data/bot.db (relative), sqlite:///var/lib/ipbot/bot.db (absolute).
+func Open(ctx context.Context, dsn string) (*Store, error) {
+	const op = "sqlite.Open"
+
+	path, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	db, err := sql.Open("sqlite", driverDSN(path))
+	if err != nil {
+		return nil, validate.Wrap(op, fmt.Errorf("%w: %w", ErrOpen, err))
+	}
+
+	// SQLite has a single writer: one connection serializes transactions in this
+	// process, and keeps a ":memory:" database alive for the life of the store.
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxIdleTime(0)
+	db.SetConnMaxLifetime(0)
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(pingCtx); err != nil {
+		_ = db.Close()
+		return nil, validate.Wrap(op, fmt.Errorf("%w: %w", ErrPing, err))
+	}
+
+	s := &Store{DB: db}
+
+	if err := s.migrate(ctx); err != nil {
+		_ = db.Close()
+		return nil, validate.Wrap(op, fmt.Errorf("%w: %w", ErrMigrate, err))
+	}
+
+	return s, nil
+}
+
+// ParseDSN returns the file path of a sqlite: URL.
+func ParseDSN(dsn string) (string, error) {
+	const op = "sqlite.ParseDSN"
+
+	dsn = strings.TrimSpace(dsn)
+
+	if dsn == "" {
+		return "", validate.Wrap(op, ErrEmptyDSN)
+	}
+
+	rest, ok := strings.CutPrefix(dsn, Scheme+":")
+	if !ok {
+		return "", validate.Wrap(op, ErrInvalidDSN)
+	}
+
+	// sqlite:///abs/path keeps the leading slash, sqlite://rel/path does not.
+	rest = strings.TrimPrefix(rest, "//")
+
+	path, err := url.PathUnescape(rest)
+	if err != nil || path == "" || strings.ContainsAny(path, "?#") {
+		return "", validate.Wrap(op, ErrInvalidDSN)
+	}
+
+	return path, nil
+}
+
+// driverDSN builds the modernc.org/sqlite DSN: foreign keys on, writers wait for
+// each other instead of failing with SQLITE_BUSY, transactions take the write lock up front.
+func driverDSN(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeoutMs))
+	if path != MemoryPath {
+		q.Add("_pragma", "journal_mode(WAL)")
+		q.Add("_pragma", "synchronous(NORMAL)")
+	}
+	q.Set("_txlock", "immediate")
+
+	return "file:" + path + "?" + q.Encode()
+}
+
+func (s *Store) Close(ctx context.Context) error {
+	if s == nil || s.DB == nil {
+		return nil
+	}
+
+	return s.DB.Close()
+}
+
+// Ping checks that the database is reachable (used by the readiness probe).
+func (s *Store) Ping(ctx context.Context) error {
+	const op = "sqlite.Ping"
+
+	if s == nil || s.DB == nil {
+		return validate.Wrap(op, ErrEmptyDB)
+	}
+
+	if err := s.DB.PingContext(ctx); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	const op = "sqlite.WithTx"
+
+	if s == nil || s.DB == nil {
+		return validate.Wrap(op, ErrEmptyDB)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return validate.Wrap(op, ErrBeginTx)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(ctx, tx); err != nil {
+		_ = tx.Rollback()
+
+		return validate.Wrap(op, fmt.Errorf("%w: %w", ErrTx, err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return validate.Wrap(op, ErrTxCommit)
+	}
+
+	return nil
+}
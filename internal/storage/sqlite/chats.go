@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/crypto"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// piiTelegramAAD is the AAD of pii_telegram rows: UpsertIdentity uses the lowercased transport.
+const piiTelegramAAD = "telegram"
+
+// GetTelegramChatID returns the decrypted Telegram chat_id of userID; ok is false
+// if the user has none stored.
+func (s *Store) GetTelegramChatID(ctx context.Context, userID int64) (int64, bool, error) {
+	const op = "sqlite.GetTelegramChatID"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, false, validate.Wrap(op, err)
+	}
+
+	var (
+		chatEnc []byte
+		encKid  int16
+	)
+
+	err := s.DB.QueryRowContext(ctx, `SELECT chat_enc, enc_kid FROM pii_telegram WHERE user_id = ?1`, userID).Scan(&chatEnc, &encKid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, validate.Wrap(op, err)
+	}
+
+	box := s.GetAEADBoxByKid(encKid)
+	if box == nil {
+		return 0, false, validate.Wrap(op, fmt.Errorf("%w: user_id=%d enc_kid=%d", ErrUnknownKid, userID, encKid))
+	}
+
+	chatID, err := crypto.DecryptInt64(box, chatEnc, []byte(piiTelegramAAD))
+	if err != nil {
+		return 0, false, validate.Wrap(op, err)
+	}
+
+	return chatID, true, nil
+}
+
+// GetUserLang returns users.lang of userID, "" if it is not set.
+func (s *Store) GetUserLang(ctx context.Context, userID int64) (string, error) {
+	const op = "sqlite.GetUserLang"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	var lang string
+
+	err := s.DB.QueryRowContext(ctx, `SELECT COALESCE(lang, '') FROM users WHERE id = ?1`, userID).Scan(&lang)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", validate.Wrap(op, err)
+	}
+
+	return lang, nil
+}
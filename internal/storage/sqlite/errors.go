@@ -0,0 +1,17 @@
+package sqlite
+
+import "errors"
+
+var (
+	ErrEmptyDSN   = errors.New("dsn is empty")
+	ErrInvalidDSN = errors.New("dsn must look like sqlite:path/to/file.db")
+	ErrEmptyDB    = errors.New("db is empty")
+	ErrOpen       = errors.New("open error")
+	ErrPing       = errors.New("ping error")
+	ErrMigrate    = errors.New("migrate error")
+	ErrBeginTx    = errors.New("begin tx error")
+	ErrTx         = errors.New("tx error")
+	ErrTxCommit   = errors.New("tx commit error")
+	ErrBadTime    = errors.New("malformed date or timestamp in database")
+	ErrUnknownKid = errors.New("row is encrypted with an unknown key id")
+)
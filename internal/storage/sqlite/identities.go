@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/crypto"
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// UpsertIdentity returns the user bound to (transport, externalID), creating the user on first contact.
+// A non-zero chatID is stored encrypted in pii_telegram, like pii.telegram in PostgreSQL.
+func (s *Store) UpsertIdentity(ctx context.Context, transport, externalID string, chatID int64) (int64, error) {
+	const op = "sqlite.UpsertIdentity"
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateExternalID(externalID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	// AAD ties ciphertext to transport (prevents cross-transport reuse)
+	aad := []byte(strings.ToLower(transport))
+
+	var uid int64
+
+	// Transactions take the write lock up front (see driverDSN), so two first
+	// contacts of the same identity cannot both create a user.
+	err := s.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		found, err := s.lookupIdentity(ctx, tx, transport, externalID, &uid)
+		if err != nil {
+			return validate.Wrap(op, err)
+		}
+
+		if !found {
+			if err := tx.QueryRowContext(ctx, `INSERT INTO users DEFAULT VALUES RETURNING id`).Scan(&uid); err != nil {
+				return validate.Wrap(op, err)
+			}
+
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO user_identities (user_id, transport, external_hash, hmac_kid)
+				VALUES (?1, ?2, ?3, ?4)
+			`, uid, transport, s.ExternalHash(transport, externalID), s.GetHMACKid()); err != nil {
+				return validate.Wrap(op, err)
+			}
+		}
+
+		if chatID != 0 {
+			if err := upsertPIITelegram(ctx, tx, s.GetAEADBox(), s.GetAEADKid(), uid, chatID, aad); err != nil {
+				return validate.Wrap(op, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	return uid, nil
+}
+
+// lookupIdentity finds the user bound to (transport, externalID).
+// The active HMAC kid is tried first; on a hit under an older kid the row is
+// re-hashed in place so that the next lookup succeeds on the first query.
+func (s *Store) lookupIdentity(ctx context.Context, tx *sql.Tx, transport, externalID string, uid *int64) (bool, error) {
+	const op = "sqlite.lookupIdentity"
+
+	activeKid := s.GetHMACKid()
+
+	for _, kid := range s.HMACKids() {
+		extHash := s.ExternalHashForKid(kid, transport, externalID)
+
+		err := tx.QueryRowContext(ctx,
+			`SELECT user_id
+			   FROM user_identities
+			  WHERE transport = ?1 AND external_hash = ?2 AND hmac_kid = ?3`,
+			transport, extHash, kid,
+		).Scan(uid)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+
+		if err != nil {
+			return false, validate.Wrap(op, err)
+		}
+
+		if kid != activeKid {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE user_identities
+				   SET external_hash = ?1, hmac_kid = ?2
+				 WHERE transport = ?3 AND external_hash = ?4 AND hmac_kid = ?5
+			`, s.ExternalHash(transport, externalID), activeKid, transport, extHash, kid); err != nil {
+				return false, validate.Wrap(op, err)
+			}
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// BindIdentity maps (transport, externalID) to an existing user, replacing any previous mapping.
+func (s *Store) BindIdentity(ctx context.Context, transport, externalID string, userID int64) error {
+	const op = "sqlite.BindIdentity"
+
+	if err := validate.ValidateTransport(transport); err != nil {
+		return validate.Wrap(op, err)
+	}
+	if err := validate.ValidateExternalID(externalID); err != nil {
+		return validate.Wrap(op, err)
+	}
+	if err := validate.ValidateUserID(userID); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	if err := s.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return s.bindIdentity(ctx, tx, transport, externalID, userID)
+	}); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// bindIdentity upserts the identity row under the active HMAC kid and drops
+// rows for the same identity hashed with older kids.
+func (s *Store) bindIdentity(ctx context.Context, tx *sql.Tx, transport, externalID string, userID int64) error {
+	const op = "sqlite.bindIdentity"
+
+	activeKid := s.GetHMACKid()
+
+	for _, kid := range s.HMACKids() {
+		if kid == activeKid {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM user_identities WHERE transport = ?1 AND external_hash = ?2 AND hmac_kid = ?3`,
+			transport, s.ExternalHashForKid(kid, transport, externalID), kid,
+		); err != nil {
+			return validate.Wrap(op, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_identities (user_id, transport, external_hash, hmac_kid)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (transport, external_hash, hmac_kid) DO UPDATE
+		    SET user_id = excluded.user_id
+	`, userID, transport, s.ExternalHash(transport, externalID), activeKid); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// unbindIdentity deletes (transport, externalID) under every known kid.
+func (s *Store) unbindIdentity(ctx context.Context, tx *sql.Tx, transport, externalID string) error {
+	const op = "sqlite.unbindIdentity"
+
+	for _, kid := range s.HMACKids() {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM user_identities WHERE transport = ?1 AND external_hash = ?2 AND hmac_kid = ?3`,
+			transport, s.ExternalHashForKid(kid, transport, externalID), kid,
+		); err != nil {
+			return validate.Wrap(op, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error) {
+	const op = "sqlite.GetUserScheme"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	var scheme domain.TaxScheme
+
+	if err := s.DB.QueryRowContext(ctx, `SELECT tax_scheme FROM users WHERE id = ?1`, userID).Scan(&scheme); err != nil {
+		return "", validate.Wrap(op, err)
+	}
+
+	return scheme, nil
+}
+
+// upsertPIITelegram encrypts and upserts chatID into pii_telegram for given user.
+func upsertPIITelegram(ctx context.Context, tx *sql.Tx, box *crypto.AEADBox, encKid int16, userID int64, chatID int64, aad []byte) error {
+	chatEnc, err := crypto.EncryptInt64(box, chatID, aad)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pii_telegram (user_id, chat_enc, enc_kid)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (user_id) DO UPDATE
+		    SET chat_enc   = excluded.chat_enc,
+		        enc_kid    = excluded.enc_kid,
+		        updated_at = strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')
+	`, userID, chatEnc, encKid)
+	return err
+}
@@ -0,0 +1,417 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// InsertIncome inserts a single income record.
+// 'amount' is in minor currency units (e.g., kopecks), must be >= 0.
+// 'at' is the income date; only the UTC calendar day is stored.
+// Returns the ID of the inserted row.
+func (s *Store) InsertIncome(ctx context.Context, userID int64, at time.Time, amount int64, note string) (int64, error) {
+	const op = "sqlite.InsertIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(amount); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var id int64
+	err := s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO incomes (user_id, at, amount, note)
+			VALUES (?1, ?2, ?3, NULLIF(?4, ''))
+			RETURNING id
+		`, userID, day(at), amount, note).Scan(&id); err != nil {
+			return err
+		}
+
+		return s.recordMessageEntry(ctx, tx, userID, domain.EntryKindIncome, id)
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	return id, nil
+}
+
+// UpdateIncome replaces date, amount and note of an active income owned by userID.
+// ok=false if there is no such income or it was voided.
+func (s *Store) UpdateIncome(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (ok bool, err error) {
+	const op = "sqlite.UpdateIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(amount); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE incomes
+			   SET at = ?3, amount = ?4, note = NULLIF(?5, '')
+			 WHERE id = ?1 AND user_id = ?2 AND voided_at IS NULL
+		`, id, userID, day(at), amount, note)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		ok = n > 0
+		return err
+	})
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return ok, nil
+}
+
+// VoidLastIncomeInRange marks the newest "active" income in [from,to] as voided (soft-delete).
+// "Newest" is determined by (at DESC, created_at DESC, id DESC).
+// Returns the voided record's (amount, at, note). ok=false if nothing to void.
+func (s *Store) VoidLastIncomeInRange(ctx context.Context, userID int64, from, to, now time.Time) (
+	amount int64, at time.Time, note string, ok bool, err error,
+) {
+	const op = "sqlite.VoidLastIncomeInRange"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	const q = `
+	UPDATE incomes
+	SET voided_at = ?4
+	WHERE id = (
+		SELECT id
+		FROM incomes
+		WHERE user_id = ?1
+		  AND at BETWEEN ?2 AND ?3
+		  AND voided_at IS NULL
+		ORDER BY at DESC, created_at DESC, id DESC
+		LIMIT 1
+	)
+	RETURNING amount, at, note;
+	`
+
+	var (
+		atStr    string
+		noteNull sql.NullString
+	)
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, q, userID, day(from), day(to), ts(now)).Scan(&amount, &atStr, &noteNull)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			ok = true
+		}
+		return err
+	})
+	if err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
+	if at, err = parseDay(atStr); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if noteNull.Valid {
+		note = noteNull.String
+	}
+
+	return amount, at, note, true, nil
+}
+
+// VoidIncome marks the active income id of userID as voided and returns its (amount, at, note).
+// ok=false if the user has no such active income.
+func (s *Store) VoidIncome(ctx context.Context, userID, id int64, now time.Time) (
+	amount int64, at time.Time, note string, ok bool, err error,
+) {
+	const op = "sqlite.VoidIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	var (
+		atStr    string
+		noteNull sql.NullString
+	)
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			UPDATE incomes
+			SET voided_at = ?3
+			WHERE id = ?1 AND user_id = ?2 AND voided_at IS NULL
+			RETURNING amount, at, note
+		`, id, userID, ts(now)).Scan(&amount, &atStr, &noteNull)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			ok = true
+		}
+		return err
+	})
+	if err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
+	if at, err = parseDay(atStr); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if noteNull.Valid {
+		note = noteNull.String
+	}
+
+	return amount, at, note, true, nil
+}
+
+// SumIncomes returns the total amount (in minor units) for a user in [from..to] inclusive.
+// 'from' and 'to' are interpreted by their UTC calendar dates.
+func (s *Store) SumIncomes(ctx context.Context, userID int64, from, to time.Time) (int64, error) {
+	const op = "sqlite.SumIncomes"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var sum int64
+	if err := s.DB.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM incomes
+		WHERE user_id = ?1
+		  AND at BETWEEN ?2 AND ?3
+		  AND voided_at IS NULL
+	`, userID, day(from), day(to)).Scan(&sum); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	return sum, nil
+}
+
+// SumIncomesByMonth returns active incomes in [from..to] inclusive summed per calendar month, ordered by month.
+func (s *Store) SumIncomesByMonth(ctx context.Context, userID int64, from, to time.Time) ([]domain.MonthSum, error) {
+	const op = "sqlite.SumIncomesByMonth"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	// Days are stored as YYYY-MM-DD: the first seven characters name the month.
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT substr(at, 1, 7) || '-01' AS month, SUM(amount)
+		FROM incomes
+		WHERE user_id = ?1
+		  AND at BETWEEN ?2 AND ?3
+		  AND voided_at IS NULL
+		GROUP BY month
+		ORDER BY month
+	`, userID, day(from), day(to))
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.MonthSum
+
+	for rows.Next() {
+		var (
+			m     domain.MonthSum
+			month string
+		)
+
+		if err := rows.Scan(&month, &m.Sum); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		if m.Month, err = parseDay(month); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// IncomeStatsByBucket returns the count, sum and largest of the active incomes in [from..to]
+// inclusive per calendar month or ISO week, ordered by bucket start.
+func (s *Store) IncomeStatsByBucket(ctx context.Context, userID int64, bucket domain.ReportBucket, from, to time.Time) ([]domain.IncomeStats, error) {
+	const op = "sqlite.IncomeStatsByBucket"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateReportBucket(bucket); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	// Days are stored as YYYY-MM-DD. A month starts on its first day; an ISO week on the
+	// Monday that is (weekday + 6) % 7 days back, with %w counting from Sunday = 0.
+	start := `substr(at, 1, 7) || '-01'`
+	if bucket == domain.BucketWeek {
+		start = `date(at, '-' || ((CAST(strftime('%w', at) AS INTEGER) + 6) % 7) || ' days')`
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+start+` AS start, COUNT(*), SUM(amount), MAX(amount)
+		FROM incomes
+		WHERE user_id = ?1
+		  AND at BETWEEN ?2 AND ?3
+		  AND voided_at IS NULL
+		GROUP BY start
+		ORDER BY start
+	`, userID, day(from), day(to))
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.IncomeStats
+
+	for rows.Next() {
+		var (
+			b     domain.IncomeStats
+			start string
+		)
+
+		if err := rows.Scan(&start, &b.Count, &b.Sum, &b.Max); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		if b.Start, err = parseDay(start); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// ListIncomes returns active incomes for a user in [from..to] inclusive, ordered by (at, id).
+func (s *Store) ListIncomes(ctx context.Context, userID int64, from, to time.Time) ([]domain.Income, error) {
+	const op = "sqlite.ListIncomes"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT id, at, amount, COALESCE(note, '')
+		FROM incomes
+		WHERE user_id = ?1
+		  AND at BETWEEN ?2 AND ?3
+		  AND voided_at IS NULL
+		ORDER BY at, id
+	`, userID, day(from), day(to))
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.Income
+
+	for rows.Next() {
+		var (
+			it    domain.Income
+			atStr string
+		)
+
+		if err := rows.Scan(&it.ID, &atStr, &it.Amount, &it.Note); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		if it.At, err = parseDay(atStr); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// GetIncome returns an active income of userID; ok=false if there is none with this id.
+func (s *Store) GetIncome(ctx context.Context, userID, id int64) (domain.Income, bool, error) {
+	const op = "sqlite.GetIncome"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Income{}, false, validate.Wrap(op, err)
+	}
+
+	var (
+		it    domain.Income
+		atStr string
+	)
+
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT id, at, amount, COALESCE(note, '')
+		FROM incomes
+		WHERE id = ?1 AND user_id = ?2 AND voided_at IS NULL
+	`, id, userID).Scan(&it.ID, &atStr, &it.Amount, &it.Note)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Income{}, false, nil
+	}
+	if err != nil {
+		return domain.Income{}, false, validate.Wrap(op, err)
+	}
+
+	if it.At, err = parseDay(atStr); err != nil {
+		return domain.Income{}, false, validate.Wrap(op, err)
+	}
+
+	return it, true, nil
+}
@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// FindMessageEntry returns the entry userID created with the referenced message.
+// Hashes under older HMAC kids are tried too, so edits keep working after key rotation.
+func (s *Store) FindMessageEntry(ctx context.Context, userID int64, ref domain.MessageRef) (domain.MessageEntry, bool, error) {
+	const op = "sqlite.FindMessageEntry"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.MessageEntry{}, false, validate.Wrap(op, err)
+	}
+
+	for _, kid := range s.HMACKids() {
+		var e domain.MessageEntry
+
+		err := s.DB.QueryRowContext(ctx, `
+			SELECT kind, entry_id
+			  FROM message_entries
+			 WHERE transport = ?1 AND message_hash = ?2 AND hmac_kid = ?3 AND user_id = ?4
+		`, ref.Transport, s.ExternalHashForKid(kid, ref.Transport, ref.Key), kid, userID).Scan(&e.Kind, &e.ID)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+
+		if err != nil {
+			return domain.MessageEntry{}, false, validate.Wrap(op, err)
+		}
+
+		return e, true, nil
+	}
+
+	return domain.MessageEntry{}, false, nil
+}
+
+// recordMessageEntry links the message from ctx, if any, to the created entry within tx.
+func (s *Store) recordMessageEntry(ctx context.Context, tx *sql.Tx, userID int64, kind domain.EntryKind, id int64) error {
+	ref, ok := domain.MessageRefFrom(ctx)
+	if !ok {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO message_entries (transport, message_hash, hmac_kid, user_id, kind, entry_id)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		ON CONFLICT (transport, message_hash, hmac_kid) DO UPDATE
+		    SET user_id = excluded.user_id, kind = excluded.kind, entry_id = excluded.entry_id
+	`, ref.Transport, s.ExternalHash(ref.Transport, ref.Key), s.GetHMACKid(), userID, string(kind), id)
+
+	return err
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+	"github.com/tuor4eg/ip_accounting_bot/migrations"
+)
+
+// FS holds the SQLite schema; file names follow the same rules as the PostgreSQL migrations.
+//
+//go:embed sql/*.sql
+var FS embed.FS
+
+const migrationsDir = "sql"
+
+// migrate applies pending migrations, each in its own transaction. A SQLite file belongs to
+// one deployment, so unlike PostgreSQL it is migrated on open; like there, a changed or
+// missing applied migration is an error.
+func (s *Store) migrate(ctx context.Context) error {
+	const op = "sqlite.migrate"
+
+	if _, err := s.DB.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			checksum   TEXT    NOT NULL,
+			applied_at TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'))
+		)
+	`); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	all, err := migrations.ListUpMigrations(FS, migrationsDir)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	applied, err := s.appliedChecksums(ctx)
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	known := make(map[int64]struct{}, len(all))
+
+	for _, m := range all {
+		known[m.Version] = struct{}{}
+
+		sum, ok := applied[m.Version]
+
+		if ok {
+			if sum != m.Checksum {
+				return validate.Wrap(op, fmt.Errorf("%w: %s", migrations.ErrChecksumMismatch, m.Name))
+			}
+			continue
+		}
+
+		if err := s.apply(ctx, m); err != nil {
+			return validate.Wrap(op, fmt.Errorf("%s: %w", m.Name, err))
+		}
+	}
+
+	for v := range applied {
+		if _, ok := known[v]; !ok {
+			return validate.Wrap(op, fmt.Errorf("%w: version %d", migrations.ErrMigrationFileMissing, v))
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) appliedChecksums(ctx context.Context) (map[int64]string, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT version, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[int64]string)
+
+	for rows.Next() {
+		var (
+			v   int64
+			sum string
+		)
+
+		if err := rows.Scan(&v, &sum); err != nil {
+			return nil, err
+		}
+
+		out[v] = sum
+	}
+
+	return out, rows.Err()
+}
+
+func (s *Store) apply(ctx context.Context, m migrations.Migration) error {
+	b, err := fs.ReadFile(FS, m.Path)
+	if err != nil {
+		return err
+	}
+
+	return s.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, string(b)); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, checksum) VALUES (?1, ?2)`, m.Version, m.Checksum)
+
+		return err
+	})
+}
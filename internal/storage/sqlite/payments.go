@@ -0,0 +1,318 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+func (s *Store) InsertPayment(ctx context.Context, userID int64, at time.Time, amount int64, note string, paymentType domain.PaymentType) (int64, error) {
+	const op = "sqlite.InsertPayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateAmount(amount); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.OneOf(paymentType, domain.PaymentTypeContrib, domain.PaymentTypeAdvance); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(at, at); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var id int64
+	err := s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO payments (user_id, at, amount, note, type)
+			VALUES (?1, ?2, ?3, NULLIF(?4, ''), ?5)
+			RETURNING id
+		`, userID, day(at), amount, note, string(paymentType)).Scan(&id); err != nil {
+			return err
+		}
+
+		return s.recordMessageEntry(ctx, tx, userID, domain.EntryKind(paymentType), id)
+	})
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+// UpdatePayment replaces date, amount and note of an active payment owned by userID; the type is kept.
+// ok=false if there is no such payment or it was voided.
+func (s *Store) UpdatePayment(ctx context.Context, userID, id int64, at time.Time, amount int64, note string) (ok bool, err error) {
+	const op = "sqlite.UpdatePayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateAmount(amount); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(at, at); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE payments
+			   SET at = ?3, amount = ?4, note = NULLIF(?5, '')
+			 WHERE id = ?1 AND user_id = ?2 AND voided_at IS NULL
+		`, id, userID, day(at), amount, note)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		ok = n > 0
+		return err
+	})
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return ok, nil
+}
+
+func (s *Store) VoidLastPaymentInRange(ctx context.Context, userID int64, from, to, now time.Time, paymentType domain.PaymentType) (
+	amount int64, at time.Time, note string, pType domain.PaymentType, ok bool, err error,
+) {
+	const op = "sqlite.VoidLastPaymentInRange"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", domain.PaymentType(""), false, validate.Wrap(op, err)
+	}
+
+	if err := validate.OneOf(paymentType, domain.PaymentTypeContrib, domain.PaymentTypeAdvance); err != nil {
+		return 0, time.Time{}, "", domain.PaymentType(""), false, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return 0, time.Time{}, "", domain.PaymentType(""), false, validate.Wrap(op, err)
+	}
+
+	const q = `
+	UPDATE payments
+	SET voided_at = ?5
+	WHERE id = (
+		SELECT id
+		FROM payments
+		WHERE user_id = ?1
+		  AND at BETWEEN ?2 AND ?3
+		  AND type = ?4
+		  AND voided_at IS NULL
+		ORDER BY at DESC, created_at DESC, id DESC
+		LIMIT 1
+	)
+	RETURNING amount, at, note, type;
+	`
+
+	var (
+		atStr    string
+		noteNull sql.NullString
+	)
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, q, userID, day(from), day(to), string(paymentType), ts(now)).Scan(&amount, &atStr, &noteNull, &pType)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			ok = true
+		}
+		return err
+	})
+	if err != nil {
+		return 0, time.Time{}, "", domain.PaymentType(""), false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", domain.PaymentType(""), false, nil
+	}
+
+	if at, err = parseDay(atStr); err != nil {
+		return 0, time.Time{}, "", domain.PaymentType(""), false, validate.Wrap(op, err)
+	}
+
+	if noteNull.Valid {
+		note = noteNull.String
+	}
+	return amount, at, note, pType, true, nil
+}
+
+// VoidPayment marks the active payment id of userID as voided if it is of paymentType and
+// returns its (amount, at, note). ok=false if the user has no such active payment.
+func (s *Store) VoidPayment(ctx context.Context, userID, id int64, now time.Time, paymentType domain.PaymentType) (
+	amount int64, at time.Time, note string, ok bool, err error,
+) {
+	const op = "sqlite.VoidPayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if err := validate.OneOf(paymentType, domain.PaymentTypeContrib, domain.PaymentTypeAdvance); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	var (
+		atStr    string
+		noteNull sql.NullString
+	)
+
+	err = s.withIdempotency(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			UPDATE payments
+			SET voided_at = ?4
+			WHERE id = ?1 AND user_id = ?2 AND type = ?3 AND voided_at IS NULL
+			RETURNING amount, at, note
+		`, id, userID, string(paymentType), ts(now)).Scan(&amount, &atStr, &noteNull)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			ok = true
+		}
+		return err
+	})
+	if err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return 0, time.Time{}, "", false, nil
+	}
+
+	if at, err = parseDay(atStr); err != nil {
+		return 0, time.Time{}, "", false, validate.Wrap(op, err)
+	}
+
+	if noteNull.Valid {
+		note = noteNull.String
+	}
+
+	return amount, at, note, true, nil
+}
+
+// GetPayment returns an active payment of userID; ok=false if there is none with this id.
+func (s *Store) GetPayment(ctx context.Context, userID, id int64) (domain.Payment, bool, error) {
+	const op = "sqlite.GetPayment"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return domain.Payment{}, false, validate.Wrap(op, err)
+	}
+
+	var (
+		p     domain.Payment
+		atStr string
+	)
+
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT id, at, amount, COALESCE(note, ''), type
+		FROM payments
+		WHERE id = ?1 AND user_id = ?2 AND voided_at IS NULL
+	`, id, userID).Scan(&p.ID, &atStr, &p.Amount, &p.Note, &p.Type)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Payment{}, false, nil
+	}
+	if err != nil {
+		return domain.Payment{}, false, validate.Wrap(op, err)
+	}
+
+	if p.At, err = parseDay(atStr); err != nil {
+		return domain.Payment{}, false, validate.Wrap(op, err)
+	}
+
+	return p, true, nil
+}
+
+func (s *Store) SumPayments(ctx context.Context, userID int64, from, to time.Time) (int64, int64, error) {
+	const op = "sqlite.SumPayments"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+
+	sumContrib := int64(0)
+	sumAdvance := int64(0)
+
+	if err := s.DB.QueryRowContext(ctx, `
+	SELECT
+		COALESCE(SUM(amount) FILTER (WHERE type = ?1), 0) AS sum_contrib,
+		COALESCE(SUM(amount) FILTER (WHERE type = ?2), 0) AS sum_advance
+	FROM payments
+	WHERE user_id = ?3
+		AND at BETWEEN ?4 AND ?5
+		AND voided_at IS NULL
+	`, string(domain.PaymentTypeContrib), string(domain.PaymentTypeAdvance), userID, day(from), day(to)).Scan(&sumContrib, &sumAdvance); err != nil {
+		return 0, 0, validate.Wrap(op, err)
+	}
+	return sumContrib, sumAdvance, nil
+}
+
+// ListPayments returns active payments of both types in [from..to] inclusive, ordered by (at, id).
+func (s *Store) ListPayments(ctx context.Context, userID int64, from, to time.Time) ([]domain.Payment, error) {
+	const op = "sqlite.ListPayments"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT id, at, amount, COALESCE(note, ''), type
+		FROM payments
+		WHERE user_id = ?1
+		  AND at BETWEEN ?2 AND ?3
+		  AND voided_at IS NULL
+		ORDER BY at, id
+	`, userID, day(from), day(to))
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.Payment
+
+	for rows.Next() {
+		var (
+			it    domain.Payment
+			atStr string
+		)
+
+		if err := rows.Scan(&it.ID, &atStr, &it.Amount, &it.Note, &it.Type); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		if it.At, err = parseDay(atStr); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,330 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// CreateRecurringRule stores a rule of rule.UserID and returns its ID.
+func (s *Store) CreateRecurringRule(ctx context.Context, rule domain.RecurringRule) (int64, error) {
+	const op = "sqlite.CreateRecurringRule"
+
+	if err := validate.ValidateUserID(rule.UserID); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+	if err := validate.ValidateAmount(rule.Amount); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	var id int64
+	if err := s.DB.QueryRowContext(ctx, `
+		INSERT INTO recurring_rules (user_id, amount, note, period, day, confirm, next_due)
+		VALUES (?1, ?2, NULLIF(?3, ''), ?4, ?5, ?6, ?7)
+		RETURNING id
+	`, rule.UserID, rule.Amount, rule.Note, string(rule.Period), rule.Day, rule.Confirm, day(rule.NextDue)).Scan(&id); err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return id, nil
+}
+
+// ListRecurringRules returns the rules of userID ordered by id, with their pending counts.
+func (s *Store) ListRecurringRules(ctx context.Context, userID int64) ([]domain.RecurringRule, error) {
+	const op = "sqlite.ListRecurringRules"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	rules, err := s.queryRecurringRules(ctx, `
+		SELECT r.id, r.user_id, r.amount, COALESCE(r.note, ''), r.period, r.day, r.confirm,
+		       r.paused_at IS NOT NULL, r.next_due,
+		       (SELECT count(*) FROM recurring_runs p WHERE p.rule_id = r.id AND p.status = 'pending')
+		FROM recurring_rules r
+		WHERE r.user_id = ?1
+		ORDER BY r.id
+	`, userID)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return rules, nil
+}
+
+// DueRecurringRules returns active rules of all users with next_due on or before today.
+func (s *Store) DueRecurringRules(ctx context.Context, today time.Time) ([]domain.RecurringRule, error) {
+	const op = "sqlite.DueRecurringRules"
+
+	rules, err := s.queryRecurringRules(ctx, `
+		SELECT r.id, r.user_id, r.amount, COALESCE(r.note, ''), r.period, r.day, r.confirm,
+		       0, r.next_due, 0
+		FROM recurring_rules r
+		WHERE r.paused_at IS NULL AND r.next_due <= ?1
+		ORDER BY r.next_due, r.id
+	`, day(today))
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return rules, nil
+}
+
+func (s *Store) queryRecurringRules(ctx context.Context, q string, args ...any) ([]domain.RecurringRule, error) {
+	rows, err := s.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.RecurringRule
+
+	for rows.Next() {
+		var (
+			r       domain.RecurringRule
+			period  string
+			nextDue string
+		)
+
+		if err := rows.Scan(&r.ID, &r.UserID, &r.Amount, &r.Note, &period, &r.Day, &r.Confirm,
+			&r.Paused, &nextDue, &r.Pending); err != nil {
+			return nil, err
+		}
+
+		r.Period = domain.RecurringPeriod(period)
+
+		if r.NextDue, err = parseDay(nextDue); err != nil {
+			return nil, err
+		}
+
+		out = append(out, r)
+	}
+
+	return out, rows.Err()
+}
+
+// SetRecurringPaused pauses or resumes a rule of userID; a non-zero nextDue replaces next_due.
+func (s *Store) SetRecurringPaused(ctx context.Context, userID, ruleID int64, paused bool, nextDue time.Time) (bool, error) {
+	const op = "sqlite.SetRecurringPaused"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	var next sql.NullString
+	if !nextDue.IsZero() {
+		next = sql.NullString{String: day(nextDue), Valid: true}
+	}
+
+	res, err := s.DB.ExecContext(ctx, `
+		UPDATE recurring_rules
+		   SET paused_at = CASE WHEN ?3 THEN COALESCE(paused_at, ?5) END,
+		       next_due = COALESCE(?4, next_due)
+		 WHERE id = ?1 AND user_id = ?2
+	`, ruleID, userID, paused, next, ts(time.Now()))
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return n > 0, nil
+}
+
+// DeleteRecurringRule deletes a rule of userID and its occurrences; created incomes stay.
+func (s *Store) DeleteRecurringRule(ctx context.Context, userID, ruleID int64) (bool, error) {
+	const op = "sqlite.DeleteRecurringRule"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	res, err := s.DB.ExecContext(ctx, `DELETE FROM recurring_rules WHERE id = ?1 AND user_id = ?2`, ruleID, userID)
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return n > 0, nil
+}
+
+// RunRecurring runs the occurrence of ruleID on due in one transaction, see service.RecurringStore.
+// Moving next_due only while it still equals due claims the date, and the primary key
+// of recurring_runs rejects a second run of the same date.
+func (s *Store) RunRecurring(ctx context.Context, ruleID int64, due, next time.Time) (domain.RecurringRun, bool, error) {
+	const op = "sqlite.RunRecurring"
+
+	run := domain.RecurringRun{RuleID: ruleID, Due: due}
+	ok := false
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var confirm bool
+
+		err := tx.QueryRowContext(ctx, `
+			UPDATE recurring_rules
+			   SET next_due = ?3
+			 WHERE id = ?1 AND next_due = ?2 AND paused_at IS NULL
+			RETURNING user_id, amount, COALESCE(note, ''), confirm
+		`, ruleID, day(due), day(next)).Scan(&run.UserID, &run.Amount, &run.Note, &confirm)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		run.Status = domain.RecurringCreated
+		if confirm {
+			run.Status = domain.RecurringPending
+		}
+
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO recurring_runs (rule_id, due_on, status)
+			VALUES (?1, ?2, ?3)
+			ON CONFLICT DO NOTHING
+		`, ruleID, day(due), string(run.Status))
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+
+		if !confirm {
+			if run.IncomeID, err = insertRecurringIncome(ctx, tx, run); err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE recurring_runs SET income_id = ?3 WHERE rule_id = ?1 AND due_on = ?2
+			`, ruleID, day(due), run.IncomeID); err != nil {
+				return err
+			}
+		}
+
+		ok = true
+
+		return nil
+	})
+	if err != nil {
+		return domain.RecurringRun{}, false, validate.Wrap(op, err)
+	}
+
+	if !ok {
+		return domain.RecurringRun{}, false, nil
+	}
+
+	return run, true, nil
+}
+
+// ResolveRecurringRuns confirms (creating the incomes) or skips the pending occurrences of a rule of userID.
+func (s *Store) ResolveRecurringRuns(ctx context.Context, userID, ruleID int64, confirm bool) ([]domain.RecurringRun, error) {
+	const op = "sqlite.ResolveRecurringRuns"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	status := domain.RecurringSkipped
+	if confirm {
+		status = domain.RecurringCreated
+	}
+
+	var out []domain.RecurringRun
+
+	err := s.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var amount int64
+		var note string
+
+		err := tx.QueryRowContext(ctx, `
+			SELECT amount, COALESCE(note, '') FROM recurring_rules WHERE id = ?1 AND user_id = ?2
+		`, ruleID, userID).Scan(&amount, &note)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			SELECT due_on FROM recurring_runs WHERE rule_id = ?1 AND status = 'pending' ORDER BY due_on
+		`, ruleID)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			run := domain.RecurringRun{RuleID: ruleID, UserID: userID, Amount: amount, Note: note, Status: status}
+
+			var dueStr string
+			if err := rows.Scan(&dueStr); err != nil {
+				rows.Close()
+				return err
+			}
+
+			if run.Due, err = parseDay(dueStr); err != nil {
+				rows.Close()
+				return err
+			}
+
+			out = append(out, run)
+		}
+
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		now := ts(time.Now())
+
+		for i := range out {
+			if confirm {
+				if out[i].IncomeID, err = insertRecurringIncome(ctx, tx, out[i]); err != nil {
+					return err
+				}
+			}
+
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE recurring_runs
+				   SET status = ?3, income_id = NULLIF(?4, 0), resolved_at = ?5
+				 WHERE rule_id = ?1 AND due_on = ?2
+			`, ruleID, day(out[i].Due), string(status), out[i].IncomeID, now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// insertRecurringIncome creates the income of an occurrence, dated on its due date.
+func insertRecurringIncome(ctx context.Context, tx *sql.Tx, run domain.RecurringRun) (int64, error) {
+	var id int64
+
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO incomes (user_id, at, amount, note)
+		VALUES (?1, ?2, ?3, NULLIF(?4, ''))
+		RETURNING id
+	`, run.UserID, day(run.Due), run.Amount, run.Note).Scan(&id)
+
+	return id, err
+}
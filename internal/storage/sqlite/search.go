@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// SQLite's lower() and LIKE fold ASCII only; note_fold() brings domain.FoldNote into SQL
+// so that Cyrillic notes match the same way as in postgres.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("note_fold", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch v := args[0].(type) {
+		case string:
+			return domain.FoldNote(v), nil
+		case []byte:
+			return domain.FoldNote(string(v)), nil
+		default:
+			return v, nil
+		}
+	})
+}
+
+// likeEscaper escapes the LIKE wildcards of user input with the ESCAPE character '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchNotes returns up to limit active incomes and payments in [from..to] inclusive whose
+// folded note contains the folded query, newest first (by date, then ID).
+func (s *Store) SearchNotes(ctx context.Context, userID int64, query string, from, to time.Time, limit int) ([]domain.NoteMatch, error) {
+	const op = "sqlite.SearchNotes"
+
+	if err := validate.ValidateUserID(userID); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	if err := validate.ValidateDateRangeUTC(from, to); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	pattern := "%" + likeEscaper.Replace(domain.FoldNote(query)) + "%"
+
+	// Both sides are folded, so LIKE's own ASCII-only case folding changes nothing.
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT kind, id, at, amount, note
+		FROM (
+			SELECT 'income' AS kind, id, at, amount, note
+			FROM incomes
+			WHERE user_id = ?1
+			  AND at BETWEEN ?2 AND ?3
+			  AND voided_at IS NULL
+			  AND note IS NOT NULL
+			  AND note_fold(note) LIKE ?4 ESCAPE '\'
+			UNION ALL
+			SELECT type, id, at, amount, note
+			FROM payments
+			WHERE user_id = ?1
+			  AND at BETWEEN ?2 AND ?3
+			  AND voided_at IS NULL
+			  AND note IS NOT NULL
+			  AND note_fold(note) LIKE ?4 ESCAPE '\'
+		)
+		ORDER BY at DESC, id DESC, kind
+		LIMIT ?5
+	`, userID, day(from), day(to), pattern, limit)
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.NoteMatch
+
+	for rows.Next() {
+		var (
+			m  domain.NoteMatch
+			at string
+		)
+
+		if err := rows.Scan(&m.Kind, &m.ID, &at, &m.Amount, &m.Note); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		if m.At, err = parseDay(at); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,107 @@
+-- 0001_init.sql
+-- IP Accounting Bot — SQLite schema for single-user deployments.
+-- Mirrors the PostgreSQL schema (migrations/sql) for the tables the stores use:
+-- dates are 'YYYY-MM-DD' text (like DATE), timestamps are UTC text in a
+-- fixed-width format so that they compare in time order.
+
+CREATE TABLE users (
+    id                      INTEGER PRIMARY KEY AUTOINCREMENT,
+    tax_scheme              TEXT    NOT NULL DEFAULT 'usn_6' CHECK (tax_scheme IN ('usn_6','usn_dr')),
+    lang                    TEXT    NULL CHECK (lang GLOB '[a-z][a-z]'),
+    created_at              TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'))
+);
+
+-- external_hash = HMAC-SHA256(secret, transport || '|' || external_id)
+CREATE TABLE user_identities (
+    user_id       INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transport     TEXT    NOT NULL,
+    external_hash BLOB    NOT NULL CHECK (length(external_hash) = 32),
+    hmac_kid      INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (transport, external_hash, hmac_kid)
+);
+CREATE INDEX user_identities_user_id_idx ON user_identities(user_id);
+
+-- Same as pii.telegram: chat_id encrypted with AES-GCM, AAD = transport.
+CREATE TABLE pii_telegram (
+    user_id    INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    chat_enc   BLOB    NOT NULL,
+    enc_kid    INTEGER NOT NULL DEFAULT 1,
+    updated_at TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'))
+);
+
+CREATE TABLE incomes (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    at          TEXT    NOT NULL CHECK (at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    amount      INTEGER NOT NULL CHECK (amount > 0),      -- stored in kopecks
+    note        TEXT,
+    created_at  TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    voided_at   TEXT                                      -- soft-delete
+);
+CREATE INDEX incomes_user_at_active_idx
+  ON incomes (user_id, at)
+  WHERE voided_at IS NULL;
+
+CREATE TABLE payments (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    at          TEXT    NOT NULL CHECK (at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    type        TEXT    NOT NULL CHECK (type IN ('contrib','advance')),
+    amount      INTEGER NOT NULL CHECK (amount > 0),      -- stored in kopecks
+    note        TEXT,
+    created_at  TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    voided_at   TEXT                                      -- soft-delete
+);
+CREATE INDEX payments_user_type_at_active_idx
+  ON payments (user_id, type, at)
+  WHERE voided_at IS NULL;
+
+CREATE TABLE api_tokens (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash    BLOB    NOT NULL UNIQUE CHECK (length(token_hash) = 32),
+    name          TEXT,
+    created_at    TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    last_used_at  TEXT,
+    revoked_at    TEXT
+);
+CREATE INDEX api_tokens_user_active_idx
+  ON api_tokens (user_id)
+  WHERE revoked_at IS NULL;
+
+CREATE TABLE link_codes (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash   BLOB    NOT NULL UNIQUE CHECK (length(code_hash) = 32),
+    created_at  TEXT    NOT NULL,
+    expires_at  TEXT    NOT NULL,
+    used_at     TEXT,
+    CHECK (expires_at > created_at)
+);
+CREATE INDEX link_codes_user_idx ON link_codes (user_id);
+
+CREATE TABLE poll_offsets (
+    transport   TEXT    PRIMARY KEY,
+    "offset"    INTEGER NOT NULL CHECK ("offset" >= 0),
+    updated_at  TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'))
+);
+
+CREATE TABLE processed_updates (
+    transport     TEXT NOT NULL,
+    update_key    TEXT NOT NULL,
+    processed_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    PRIMARY KEY (transport, update_key)
+);
+CREATE INDEX processed_updates_processed_at_idx ON processed_updates (processed_at);
+
+CREATE TABLE message_entries (
+    transport    TEXT    NOT NULL,
+    message_hash BLOB    NOT NULL CHECK (length(message_hash) = 32),
+    hmac_kid     INTEGER NOT NULL,
+    user_id      INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind         TEXT    NOT NULL CHECK (kind IN ('income', 'contrib', 'advance')),
+    entry_id     INTEGER NOT NULL,
+    created_at   TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    PRIMARY KEY (transport, message_hash, hmac_kid)
+);
+CREATE INDEX message_entries_user_idx ON message_entries (user_id);
@@ -0,0 +1,33 @@
+-- 0002_recurring.sql
+-- Recurring incomes, mirrors migrations/sql/0007_recurring.up.sql.
+
+CREATE TABLE recurring_rules (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount      INTEGER NOT NULL CHECK (amount > 0),      -- stored in kopecks
+    note        TEXT,
+    period      TEXT    NOT NULL CHECK (period IN ('monthly', 'weekly')),
+    day         INTEGER NOT NULL CHECK (day BETWEEN 1 AND 31),
+    confirm     INTEGER NOT NULL DEFAULT 0 CHECK (confirm IN (0, 1)),
+    next_due    TEXT    NOT NULL CHECK (next_due GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    paused_at   TEXT,
+    created_at  TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    CHECK (period = 'monthly' OR day <= 7)
+);
+CREATE INDEX recurring_rules_user_idx ON recurring_rules (user_id);
+CREATE INDEX recurring_rules_due_active_idx
+  ON recurring_rules (next_due)
+  WHERE paused_at IS NULL;
+
+CREATE TABLE recurring_runs (
+    rule_id     INTEGER NOT NULL REFERENCES recurring_rules(id) ON DELETE CASCADE,
+    due_on      TEXT    NOT NULL,
+    status      TEXT    NOT NULL CHECK (status IN ('created', 'pending', 'skipped')),
+    income_id   INTEGER REFERENCES incomes(id) ON DELETE SET NULL,
+    created_at  TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    resolved_at TEXT,
+    PRIMARY KEY (rule_id, due_on)
+);
+CREATE INDEX recurring_runs_pending_idx
+  ON recurring_runs (rule_id)
+  WHERE status = 'pending';
@@ -0,0 +1,56 @@
+-- 0003_invoices.sql
+-- Invoices, mirrors migrations/sql/0009_invoices.up.sql. The PostgreSQL schema has
+-- counterparties since 0001; here the table is new, and name_norm is filled in by
+-- the store, as lower() of SQLite folds ASCII letters only.
+
+CREATE TABLE counterparties (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name        TEXT    NOT NULL,
+    name_norm   TEXT    NOT NULL,                         -- lower-cased, trimmed name
+    inn         TEXT    CHECK (length(inn) IN (10, 12) AND inn NOT GLOB '*[^0-9]*'),
+    kpp         TEXT    CHECK (length(kpp) = 9),
+    address     TEXT,
+    created_at  TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    archived_at TEXT
+);
+CREATE UNIQUE INDEX counterparties_user_norm_uq
+    ON counterparties(user_id, name_norm);
+
+CREATE TABLE user_requisites (
+    user_id      INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    name         TEXT    NOT NULL DEFAULT '',
+    inn          TEXT    NOT NULL DEFAULT '',
+    ogrnip       TEXT    NOT NULL DEFAULT '',
+    address      TEXT    NOT NULL DEFAULT '',
+    bank         TEXT    NOT NULL DEFAULT '',
+    bik          TEXT    NOT NULL DEFAULT '',
+    account      TEXT    NOT NULL DEFAULT '',
+    corr_account TEXT    NOT NULL DEFAULT '',
+    updated_at   TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'))
+);
+
+CREATE TABLE invoices (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year            INTEGER NOT NULL,                     -- numbers start over every year
+    number          INTEGER NOT NULL CHECK (number > 0),
+    counterparty_id INTEGER NOT NULL REFERENCES counterparties(id),
+    issued_on       TEXT    NOT NULL CHECK (issued_on GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    due_on          TEXT    NOT NULL CHECK (due_on GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    amount          INTEGER NOT NULL CHECK (amount > 0),  -- stored in kopecks
+    description     TEXT,
+    reminded_on     TEXT,                                 -- last overdue reminder
+    created_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    UNIQUE (user_id, year, number),
+    CHECK (year = CAST(substr(issued_on, 1, 4) AS INTEGER)),
+    CHECK (due_on >= issued_on)
+);
+CREATE INDEX invoices_due_idx ON invoices (due_on);
+
+-- An income pays at most one invoice.
+CREATE TABLE invoice_payments (
+    income_id  INTEGER PRIMARY KEY REFERENCES incomes(id) ON DELETE CASCADE,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE
+);
+CREATE INDEX invoice_payments_invoice_idx ON invoice_payments (invoice_id);
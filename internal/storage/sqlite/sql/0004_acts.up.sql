@@ -0,0 +1,23 @@
+-- 0004_acts.sql
+-- Acts of completed work, mirrors migrations/sql/0010_acts.up.sql.
+
+CREATE TABLE acts (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year            INTEGER NOT NULL,                     -- numbers start over every year
+    number          INTEGER NOT NULL CHECK (number > 0),
+    counterparty_id INTEGER NOT NULL REFERENCES counterparties(id),
+    issued_on       TEXT    NOT NULL CHECK (issued_on GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    amount          INTEGER NOT NULL CHECK (amount > 0),  -- stored in kopecks
+    description     TEXT,
+    invoice_id      INTEGER REFERENCES invoices(id),
+    income_id       INTEGER REFERENCES incomes(id),
+    created_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now')),
+    UNIQUE (user_id, year, number),
+    CHECK (year = CAST(substr(issued_on, 1, 4) AS INTEGER)),
+    CHECK ((invoice_id IS NULL) <> (income_id IS NULL))
+);
+
+-- At most one act per invoice and per income.
+CREATE UNIQUE INDEX acts_invoice_uq ON acts (invoice_id) WHERE invoice_id IS NOT NULL;
+CREATE UNIQUE INDEX acts_income_uq ON acts (income_id) WHERE income_id IS NOT NULL;
@@ -0,0 +1,156 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/sqlite"
+)
+
+const (
+	testHMACKey = "hmac-test"
+	testAEADKey = "0123456789abcdef0123456789abcdef"
+)
+
+func openStore(t *testing.T, dsn string) *sqlite.Store {
+	t.Helper()
+
+	s, err := sqlite.Open(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("Open(%q): %v", dsn, err)
+	}
+	t.Cleanup(func() { _ = s.Close(context.Background()) })
+
+	if err := s.SetCryptoKeys(testHMACKey, 1, testAEADKey, 1); err != nil {
+		t.Fatalf("SetCryptoKeys: %v", err)
+	}
+
+	return s
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestParseDSN(t *testing.T) {
+	cases := map[string]string{
+		"sqlite:bot.db":               "bot.db",
+		"sqlite://data/bot.db":        "data/bot.db",
+		"sqlite:///var/lib/ip/bot.db": "/var/lib/ip/bot.db",
+		"sqlite::memory:":             ":memory:",
+	}
+
+	for in, want := range cases {
+		got, err := sqlite.ParseDSN(in)
+		if err != nil || got != want {
+			t.Errorf("ParseDSN(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "postgres://u@h/db", "sqlite:", "sqlite:bot.db?mode=ro"} {
+		if _, err := sqlite.ParseDSN(in); err == nil {
+			t.Errorf("ParseDSN(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestOpen_PersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	dsn := "sqlite:" + filepath.Join(t.TempDir(), "bot.db")
+
+	s := openStore(t, dsn)
+
+	uid, err := s.UpsertIdentity(ctx, domain.TransportTelegram, "42", 42)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+	if _, err := s.InsertIncome(ctx, uid, day("2025-02-10"), 10000, "first"); err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+	_ = s.Close(ctx)
+
+	// Reopening applies no migrations twice and sees the same data.
+	s = openStore(t, dsn)
+
+	again, err := s.UpsertIdentity(ctx, domain.TransportTelegram, "42", 0)
+	if err != nil || again != uid {
+		t.Fatalf("UpsertIdentity after reopen = %d, %v; want %d", again, err, uid)
+	}
+
+	sum, err := s.SumIncomes(ctx, uid, day("2025-01-01"), day("2025-03-31"))
+	if err != nil || sum != 10000 {
+		t.Fatalf("SumIncomes after reopen = %d, %v; want 10000", sum, err)
+	}
+
+	scheme, err := s.GetUserScheme(ctx, uid)
+	if err != nil || scheme != domain.TaxSchemeUSN6 {
+		t.Fatalf("GetUserScheme = %q, %v", scheme, err)
+	}
+}
+
+func TestIncomes_DatesAndVoid(t *testing.T) {
+	ctx := context.Background()
+	s := openStore(t, "sqlite::memory:")
+
+	uid, err := s.UpsertIdentity(ctx, domain.TransportCLI, "local", 0)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	// Time of day is dropped, like the DATE cast in PostgreSQL.
+	at := time.Date(2025, 3, 31, 23, 30, 0, 0, time.UTC)
+	if _, err := s.InsertIncome(ctx, uid, at, 500, ""); err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+	if _, err := s.InsertIncome(ctx, uid, day("2025-03-31"), 700, "later"); err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+
+	list, err := s.ListIncomes(ctx, uid, day("2025-01-01"), day("2025-03-31"))
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListIncomes = %+v, %v", list, err)
+	}
+	if !list[0].At.Equal(day("2025-03-31")) {
+		t.Errorf("At = %v, want 2025-03-31 00:00 UTC", list[0].At)
+	}
+
+	// Same day: the later insert is voided first.
+	amount, _, note, ok, err := s.VoidLastIncomeInRange(ctx, uid, day("2025-01-01"), day("2025-03-31"), time.Now())
+	if err != nil || !ok || amount != 700 || note != "later" {
+		t.Fatalf("VoidLastIncomeInRange = %d, %q, %v, %v", amount, note, ok, err)
+	}
+
+	sum, err := s.SumIncomes(ctx, uid, day("2025-03-31"), day("2025-03-31"))
+	if err != nil || sum != 500 {
+		t.Fatalf("SumIncomes = %d, %v; want 500", sum, err)
+	}
+}
+
+func TestPayments_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	s := openStore(t, "sqlite::memory:")
+
+	uid, err := s.UpsertIdentity(ctx, domain.TransportCLI, "local", 0)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	kctx := domain.WithIdempotencyKey(ctx, domain.TransportTelegram, "update:1")
+
+	if _, err := s.InsertPayment(kctx, uid, day("2025-04-01"), 100, "", domain.PaymentTypeContrib); err != nil {
+		t.Fatalf("InsertPayment: %v", err)
+	}
+	if _, err := s.InsertPayment(kctx, uid, day("2025-04-01"), 100, "", domain.PaymentTypeContrib); !errors.Is(err, domain.ErrDuplicateUpdate) {
+		t.Fatalf("second InsertPayment err = %v, want ErrDuplicateUpdate", err)
+	}
+
+	contrib, advance, err := s.SumPayments(ctx, uid, day("2025-01-01"), day("2025-12-31"))
+	if err != nil || contrib != 100 || advance != 0 {
+		t.Fatalf("SumPayments = %d, %d, %v; want 100, 0", contrib, advance, err)
+	}
+}
@@ -0,0 +1,13 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/storage/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return openStore(t, "sqlite::memory:")
+	})
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Dates are stored as 'YYYY-MM-DD' (the calendar day in UTC, like a PostgreSQL DATE cast)
+// and timestamps as fixed-width UTC text, so both compare correctly as strings.
+const (
+	dayLayout = "2006-01-02"
+	tsLayout  = "2006-01-02T15:04:05.000000000Z"
+)
+
+func day(t time.Time) string {
+	return t.UTC().Format(dayLayout)
+}
+
+func ts(t time.Time) string {
+	return t.UTC().Format(tsLayout)
+}
+
+func parseDay(s string) (time.Time, error) {
+	t, err := time.Parse(dayLayout, s)
+	if err != nil {
+		return time.Time{}, ErrBadTime
+	}
+
+	return t, nil
+}
+
+func parseTS(s string) (time.Time, error) {
+	t, err := time.Parse(tsLayout, s)
+	if err != nil {
+		return time.Time{}, ErrBadTime
+	}
+
+	return t, nil
+}
+
+// parseNullTS returns the zero time for NULL.
+func parseNullTS(s sql.NullString) (time.Time, error) {
+	if !s.Valid {
+		return time.Time{}, nil
+	}
+
+	return parseTS(s.String)
+}
@@ -0,0 +1,13 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/cryptostore"
+)
+
+// Store provides SQLite storage with cryptographic capabilities
+type Store struct {
+	cryptostore.BaseCryptoStore // Embed crypto capabilities
+	DB                          *sql.DB
+}
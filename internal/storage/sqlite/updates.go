@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// GetPollOffset returns the persisted getUpdates offset of a transport (0 if none).
+func (s *Store) GetPollOffset(ctx context.Context, transport string) (int64, error) {
+	const op = "sqlite.GetPollOffset"
+
+	var offset int64
+
+	err := s.DB.QueryRowContext(ctx, `SELECT "offset" FROM poll_offsets WHERE transport = ?1`, transport).Scan(&offset)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return offset, nil
+}
+
+// SetPollOffset persists the getUpdates offset; it never moves backwards.
+func (s *Store) SetPollOffset(ctx context.Context, transport string, offset int64) error {
+	const op = "sqlite.SetPollOffset"
+
+	if _, err := s.DB.ExecContext(ctx, `
+		INSERT INTO poll_offsets (transport, "offset", updated_at)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (transport) DO UPDATE
+		    SET "offset" = MAX(poll_offsets."offset", excluded."offset"),
+		        updated_at = excluded.updated_at
+	`, transport, offset, ts(time.Now())); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// IsProcessed reports whether an update key was already recorded.
+func (s *Store) IsProcessed(ctx context.Context, transport, key string) (bool, error) {
+	const op = "sqlite.IsProcessed"
+
+	var ok bool
+
+	if err := s.DB.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM processed_updates WHERE transport = ?1 AND update_key = ?2)`,
+		transport, key,
+	).Scan(&ok); err != nil {
+		return false, validate.Wrap(op, err)
+	}
+
+	return ok, nil
+}
+
+// MarkProcessed records an update key; recording it twice is a no-op.
+func (s *Store) MarkProcessed(ctx context.Context, transport, key string) error {
+	const op = "sqlite.MarkProcessed"
+
+	if _, err := s.DB.ExecContext(ctx, `
+		INSERT INTO processed_updates (transport, update_key)
+		VALUES (?1, ?2)
+		ON CONFLICT DO NOTHING
+	`, transport, key); err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// PruneProcessed deletes update keys recorded before the given time.
+func (s *Store) PruneProcessed(ctx context.Context, before time.Time) (int64, error) {
+	const op = "sqlite.PruneProcessed"
+
+	res, err := s.DB.ExecContext(ctx, `DELETE FROM processed_updates WHERE processed_at < ?1`, ts(before))
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, validate.Wrap(op, err)
+	}
+
+	return n, nil
+}
+
+// withIdempotency runs fn in a transaction. If ctx carries an idempotency key, the key is
+// recorded in the same transaction first; an already recorded key yields domain.ErrDuplicateUpdate
+// and fn is not run.
+func (s *Store) withIdempotency(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	return s.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if k, ok := domain.IdempotencyKeyFrom(ctx); ok {
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO processed_updates (transport, update_key)
+				VALUES (?1, ?2)
+				ON CONFLICT DO NOTHING
+			`, k.Transport, k.Key)
+			if err != nil {
+				return err
+			}
+
+			if n, err := res.RowsAffected(); err != nil {
+				return err
+			} else if n == 0 {
+				return domain.ErrDuplicateUpdate
+			}
+		}
+
+		return fn(ctx, tx)
+	})
+}
@@ -0,0 +1,1105 @@
+// Package storetest is a conformance suite for storage backends: every store runs the
+// same table of behaviors, so that memstore, postgres and sqlite cannot drift apart.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
+	"github.com/tuor4eg/ip_accounting_bot/internal/service"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// Store is the part of a backend the suite exercises.
+type Store interface {
+	domain.IdentityStore
+	service.IncomeStore
+	service.PaymentStore
+	service.RecurringStore
+	service.ChartStore
+	service.ReportStore
+	service.SearchStore
+	service.InvoiceStore
+	service.ActStore
+	GetUserScheme(ctx context.Context, userID int64) (domain.TaxScheme, error)
+}
+
+// Factory returns a ready store (crypto keys set, schema migrated). Stores may be shared
+// between calls: every case works with users of its own.
+type Factory func(t *testing.T) Store
+
+// Run runs every case against stores from newStore.
+func Run(t *testing.T, newStore Factory) {
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newStore(t))
+		})
+	}
+}
+
+var cases = []struct {
+	name string
+	run  func(t *testing.T, s Store)
+}{
+	{"InclusiveBounds", testInclusiveBounds},
+	{"TimeOfDayIsDropped", testTimeOfDayIsDropped},
+	{"ListOrder", testListOrder},
+	{"VoidNewestFirst", testVoidNewestFirst},
+	{"VoidOrderingTies", testVoidOrderingTies},
+	{"VoidNothing", testVoidNothing},
+	{"SumsExcludeVoided", testSumsExcludeVoided},
+	{"VoidPaymentByType", testVoidPaymentByType},
+	{"VoidByID", testVoidByID},
+	{"UpdateVoided", testUpdateVoided},
+	{"UsersAreIsolated", testUsersAreIsolated},
+	{"IdentityRace", testIdentityRace},
+	{"RecurringRunsOnce", testRecurringRunsOnce},
+	{"RecurringConfirm", testRecurringConfirm},
+	{"IncomesByMonth", testIncomesByMonth},
+	{"IncomeStatsByBucket", testIncomeStatsByBucket},
+	{"SearchNotes", testSearchNotes},
+	{"Counterparties", testCounterparties},
+	{"InvoiceNumbers", testInvoiceNumbers},
+	{"PayInvoice", testPayInvoice},
+	{"OverdueInvoices", testOverdueInvoices},
+	{"Acts", testActs},
+	{"ActForIncome", testActForIncome},
+}
+
+var seq atomic.Int64
+
+// newUser creates a user with an identity no other case or run uses.
+func newUser(t *testing.T, s Store) int64 {
+	t.Helper()
+
+	externalID := fmt.Sprintf("storetest-%d-%d", time.Now().UnixNano(), seq.Add(1))
+
+	uid, err := s.UpsertIdentity(context.Background(), domain.TransportCLI, externalID, 0)
+	if err != nil {
+		t.Fatalf("UpsertIdentity: %v", err)
+	}
+
+	return uid
+}
+
+// day returns midnight UTC of an ISO date.
+func day(t *testing.T, iso string) time.Time {
+	t.Helper()
+
+	d, err := time.Parse("2006-01-02", iso)
+	if err != nil {
+		t.Fatalf("bad date %q: %v", iso, err)
+	}
+
+	return d
+}
+
+func addIncome(t *testing.T, s Store, uid int64, at time.Time, amount int64, note string) int64 {
+	t.Helper()
+
+	id, err := s.InsertIncome(context.Background(), uid, at, amount, note)
+	if err != nil {
+		t.Fatalf("InsertIncome(%s, %d): %v", at.Format(time.RFC3339), amount, err)
+	}
+
+	return id
+}
+
+func addPayment(t *testing.T, s Store, uid int64, at time.Time, amount int64, typ domain.PaymentType) int64 {
+	t.Helper()
+
+	id, err := s.InsertPayment(context.Background(), uid, at, amount, "", typ)
+	if err != nil {
+		t.Fatalf("InsertPayment(%s, %d, %s): %v", at.Format(time.RFC3339), amount, typ, err)
+	}
+
+	return id
+}
+
+func sumIncomes(t *testing.T, s Store, uid int64, from, to time.Time) int64 {
+	t.Helper()
+
+	sum, err := s.SumIncomes(context.Background(), uid, from, to)
+	if err != nil {
+		t.Fatalf("SumIncomes: %v", err)
+	}
+
+	return sum
+}
+
+// Bounds are calendar days: both ends are included, whatever their time of day.
+func testInclusiveBounds(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	addIncome(t, s, uid, day(t, "2025-03-31"), 1, "")
+	addIncome(t, s, uid, day(t, "2025-04-01"), 10, "")
+	addIncome(t, s, uid, day(t, "2025-06-30"), 100, "")
+	addIncome(t, s, uid, day(t, "2025-07-01"), 1000, "")
+
+	// The first day starts after midnight and the last one ends at midnight: both still count.
+	from := day(t, "2025-04-01").Add(15 * time.Hour)
+	to := day(t, "2025-06-30")
+
+	if got := sumIncomes(t, s, uid, from, to); got != 110 {
+		t.Errorf("SumIncomes = %d, want 110", got)
+	}
+
+	list, err := s.ListIncomes(ctx, uid, from, to)
+	if err != nil {
+		t.Fatalf("ListIncomes: %v", err)
+	}
+	if len(list) != 2 || list[0].Amount != 10 || list[1].Amount != 100 {
+		t.Errorf("ListIncomes = %+v, want amounts 10, 100", list)
+	}
+
+	addPayment(t, s, uid, day(t, "2025-04-01"), 5, domain.PaymentTypeContrib)
+	addPayment(t, s, uid, day(t, "2025-07-01"), 7, domain.PaymentTypeContrib)
+
+	contrib, _, err := s.SumPayments(ctx, uid, from, to)
+	if err != nil {
+		t.Fatalf("SumPayments: %v", err)
+	}
+	if contrib != 5 {
+		t.Errorf("SumPayments contrib = %d, want 5", contrib)
+	}
+
+	// Void sees the same range: the entry on the first day is in it.
+	amount, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, from, from, time.Now().UTC())
+	if err != nil || !ok || amount != 10 {
+		t.Errorf("VoidLastIncomeInRange(first day) = %d, %v, %v; want 10, true", amount, ok, err)
+	}
+}
+
+// Entries keep only their UTC calendar day.
+func testTimeOfDayIsDropped(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	addIncome(t, s, uid, time.Date(2025, 5, 5, 23, 59, 59, 0, time.UTC), 100, "")
+	addPayment(t, s, uid, time.Date(2025, 5, 5, 12, 30, 0, 0, time.UTC), 50, domain.PaymentTypeAdvance)
+
+	incomes, err := s.ListIncomes(ctx, uid, day(t, "2025-05-05"), day(t, "2025-05-05"))
+	if err != nil || len(incomes) != 1 {
+		t.Fatalf("ListIncomes = %+v, %v; want 1 income", incomes, err)
+	}
+	if want := day(t, "2025-05-05"); !incomes[0].At.Equal(want) {
+		t.Errorf("income At = %v, want %v", incomes[0].At, want)
+	}
+
+	payments, err := s.ListPayments(ctx, uid, day(t, "2025-05-05"), day(t, "2025-05-05"))
+	if err != nil || len(payments) != 1 {
+		t.Fatalf("ListPayments = %+v, %v; want 1 payment", payments, err)
+	}
+	if want := day(t, "2025-05-05"); !payments[0].At.Equal(want) {
+		t.Errorf("payment At = %v, want %v", payments[0].At, want)
+	}
+
+	_, at, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-05-05"), day(t, "2025-05-05"), time.Now().UTC())
+	if err != nil || !ok || !at.Equal(day(t, "2025-05-05")) {
+		t.Errorf("VoidLastIncomeInRange at = %v, %v, %v; want 2025-05-05", at, ok, err)
+	}
+}
+
+// Lists are ordered by (at, id), not by insertion.
+func testListOrder(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	b := addIncome(t, s, uid, day(t, "2025-02-02"), 2, "")
+	a := addIncome(t, s, uid, day(t, "2025-02-01"), 1, "")
+	c := addIncome(t, s, uid, day(t, "2025-02-02"), 3, "")
+
+	list, err := s.ListIncomes(ctx, uid, day(t, "2025-01-01"), day(t, "2025-12-31"))
+	if err != nil {
+		t.Fatalf("ListIncomes: %v", err)
+	}
+
+	var got []int64
+	for _, it := range list {
+		got = append(got, it.ID)
+	}
+
+	if len(got) != 3 || got[0] != a || got[1] != b || got[2] != c {
+		t.Errorf("ListIncomes ids = %v, want %v", got, []int64{a, b, c})
+	}
+
+	pb := addPayment(t, s, uid, day(t, "2025-02-02"), 2, domain.PaymentTypeAdvance)
+	pa := addPayment(t, s, uid, day(t, "2025-02-01"), 1, domain.PaymentTypeContrib)
+
+	payments, err := s.ListPayments(ctx, uid, day(t, "2025-01-01"), day(t, "2025-12-31"))
+	if err != nil {
+		t.Fatalf("ListPayments: %v", err)
+	}
+	if len(payments) != 2 || payments[0].ID != pa || payments[1].ID != pb {
+		t.Errorf("ListPayments = %+v, want ids %d, %d", payments, pa, pb)
+	}
+}
+
+// The newest entry by date is voided, not the last inserted one.
+func testVoidNewestFirst(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	addIncome(t, s, uid, day(t, "2025-02-20"), 20, "late")
+	addIncome(t, s, uid, day(t, "2025-02-10"), 10, "early")
+
+	amount, at, note, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-01-01"), day(t, "2025-03-31"), time.Now().UTC())
+	if err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+	if amount != 20 || note != "late" || !at.Equal(day(t, "2025-02-20")) {
+		t.Errorf("voided %d %q %v, want 20 \"late\" 2025-02-20", amount, note, at)
+	}
+}
+
+// On the same day the entry inserted last is voided first.
+func testVoidOrderingTies(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	now := time.Now().UTC()
+	from, to := day(t, "2025-01-01"), day(t, "2025-03-31")
+
+	addIncome(t, s, uid, day(t, "2025-03-01"), 1, "first")
+	addIncome(t, s, uid, day(t, "2025-03-01"), 2, "second")
+	addIncome(t, s, uid, day(t, "2025-03-01"), 3, "third")
+
+	for _, want := range []string{"third", "second", "first"} {
+		_, _, note, ok, err := s.VoidLastIncomeInRange(ctx, uid, from, to, now)
+		if err != nil || !ok || note != want {
+			t.Fatalf("VoidLastIncomeInRange = %q, %v, %v; want %q", note, ok, err, want)
+		}
+	}
+
+	addPayment(t, s, uid, day(t, "2025-03-01"), 1, domain.PaymentTypeContrib)
+	addPayment(t, s, uid, day(t, "2025-03-01"), 2, domain.PaymentTypeContrib)
+
+	amount, _, _, _, ok, err := s.VoidLastPaymentInRange(ctx, uid, from, to, now, domain.PaymentTypeContrib)
+	if err != nil || !ok || amount != 2 {
+		t.Errorf("VoidLastPaymentInRange = %d, %v, %v; want 2", amount, ok, err)
+	}
+}
+
+func testVoidNothing(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	now := time.Now().UTC()
+
+	addIncome(t, s, uid, day(t, "2025-01-15"), 100, "")
+
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-04-01"), day(t, "2025-06-30"), now); err != nil || ok {
+		t.Errorf("VoidLastIncomeInRange(empty range) = %v, %v; want false, nil", ok, err)
+	}
+
+	if _, _, _, _, ok, err := s.VoidLastPaymentInRange(ctx, uid, day(t, "2025-01-01"), day(t, "2025-12-31"), now, domain.PaymentTypeAdvance); err != nil || ok {
+		t.Errorf("VoidLastPaymentInRange(no payments) = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func testSumsExcludeVoided(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	now := time.Now().UTC()
+	from, to := day(t, "2025-01-01"), day(t, "2025-12-31")
+
+	addIncome(t, s, uid, day(t, "2025-01-10"), 100, "")
+	addIncome(t, s, uid, day(t, "2025-01-20"), 200, "")
+	addPayment(t, s, uid, day(t, "2025-01-10"), 30, domain.PaymentTypeContrib)
+	addPayment(t, s, uid, day(t, "2025-01-20"), 40, domain.PaymentTypeAdvance)
+
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, from, to, now); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+	if _, _, _, _, ok, err := s.VoidLastPaymentInRange(ctx, uid, from, to, now, domain.PaymentTypeAdvance); err != nil || !ok {
+		t.Fatalf("VoidLastPaymentInRange = %v, %v", ok, err)
+	}
+
+	if got := sumIncomes(t, s, uid, from, to); got != 100 {
+		t.Errorf("SumIncomes = %d, want 100", got)
+	}
+
+	contrib, advance, err := s.SumPayments(ctx, uid, from, to)
+	if err != nil || contrib != 30 || advance != 0 {
+		t.Errorf("SumPayments = %d, %d, %v; want 30, 0", contrib, advance, err)
+	}
+
+	incomes, err := s.ListIncomes(ctx, uid, from, to)
+	if err != nil || len(incomes) != 1 {
+		t.Errorf("ListIncomes = %+v, %v; want 1 income", incomes, err)
+	}
+
+	payments, err := s.ListPayments(ctx, uid, from, to)
+	if err != nil || len(payments) != 1 {
+		t.Errorf("ListPayments = %+v, %v; want 1 payment", payments, err)
+	}
+}
+
+// Voiding a payment type never touches the other type.
+func testVoidPaymentByType(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	addPayment(t, s, uid, day(t, "2025-03-01"), 10, domain.PaymentTypeContrib)
+	addPayment(t, s, uid, day(t, "2025-03-05"), 20, domain.PaymentTypeAdvance)
+
+	amount, _, _, typ, ok, err := s.VoidLastPaymentInRange(ctx, uid, day(t, "2025-01-01"), day(t, "2025-12-31"), time.Now().UTC(), domain.PaymentTypeContrib)
+	if err != nil || !ok || amount != 10 || typ != domain.PaymentTypeContrib {
+		t.Errorf("VoidLastPaymentInRange(contrib) = %d, %s, %v, %v; want 10 contrib", amount, typ, ok, err)
+	}
+}
+
+// Entries are voided by ID only for their owner, once, and payments only with their own type.
+func testVoidByID(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid, other := newUser(t, s), newUser(t, s)
+	now := time.Now().UTC()
+
+	id := addIncome(t, s, uid, day(t, "2024-03-01"), 10, "old")
+
+	if _, _, _, ok, err := s.VoidIncome(ctx, other, id, now); err != nil || ok {
+		t.Errorf("VoidIncome(other user) = %v, %v; want false, nil", ok, err)
+	}
+
+	amount, at, note, ok, err := s.VoidIncome(ctx, uid, id, now)
+	if err != nil || !ok || amount != 10 || !at.Equal(day(t, "2024-03-01")) || note != "old" {
+		t.Errorf("VoidIncome = %d, %v, %q, %v, %v; want 10 2024-03-01 old", amount, at, note, ok, err)
+	}
+
+	if _, _, _, ok, err := s.VoidIncome(ctx, uid, id, now); err != nil || ok {
+		t.Errorf("VoidIncome(voided) = %v, %v; want false, nil", ok, err)
+	}
+
+	pid := addPayment(t, s, uid, day(t, "2025-03-01"), 20, domain.PaymentTypeAdvance)
+
+	p, ok, err := s.GetPayment(ctx, uid, pid)
+	if err != nil || !ok || p.Amount != 20 || p.Type != domain.PaymentTypeAdvance || !p.At.Equal(day(t, "2025-03-01")) {
+		t.Errorf("GetPayment = %+v, %v, %v; want 20 advance", p, ok, err)
+	}
+
+	if _, ok, err := s.GetPayment(ctx, other, pid); err != nil || ok {
+		t.Errorf("GetPayment(other user) = %v, %v; want false, nil", ok, err)
+	}
+
+	if _, _, _, ok, err := s.VoidPayment(ctx, uid, pid, now, domain.PaymentTypeContrib); err != nil || ok {
+		t.Errorf("VoidPayment(wrong type) = %v, %v; want false, nil", ok, err)
+	}
+
+	if amount, _, _, ok, err := s.VoidPayment(ctx, uid, pid, now, domain.PaymentTypeAdvance); err != nil || !ok || amount != 20 {
+		t.Errorf("VoidPayment = %d, %v, %v; want 20", amount, ok, err)
+	}
+
+	if _, ok, err := s.GetPayment(ctx, uid, pid); err != nil || ok {
+		t.Errorf("GetPayment(voided) = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func testUpdateVoided(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	id := addIncome(t, s, uid, day(t, "2025-03-01"), 10, "")
+
+	ok, err := s.UpdateIncome(ctx, uid, id, day(t, "2025-03-02"), 15, "fixed")
+	if err != nil || !ok {
+		t.Fatalf("UpdateIncome = %v, %v; want true", ok, err)
+	}
+
+	if got := sumIncomes(t, s, uid, day(t, "2025-03-02"), day(t, "2025-03-02")); got != 15 {
+		t.Errorf("SumIncomes after update = %d, want 15", got)
+	}
+
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-01-01"), day(t, "2025-12-31"), time.Now().UTC()); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+
+	if ok, err := s.UpdateIncome(ctx, uid, id, day(t, "2025-03-03"), 20, ""); err != nil || ok {
+		t.Errorf("UpdateIncome(voided) = %v, %v; want false, nil", ok, err)
+	}
+
+	other := newUser(t, s)
+	pid := addPayment(t, s, uid, day(t, "2025-03-01"), 10, domain.PaymentTypeContrib)
+
+	if ok, err := s.UpdatePayment(ctx, other, pid, day(t, "2025-03-03"), 20, ""); err != nil || ok {
+		t.Errorf("UpdatePayment(other user) = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func testUsersAreIsolated(t *testing.T, s Store) {
+	ctx := context.Background()
+	a, b := newUser(t, s), newUser(t, s)
+
+	if a == b {
+		t.Fatalf("two identities got the same user %d", a)
+	}
+
+	addIncome(t, s, a, day(t, "2025-03-01"), 10, "")
+
+	if got := sumIncomes(t, s, b, day(t, "2025-01-01"), day(t, "2025-12-31")); got != 0 {
+		t.Errorf("SumIncomes(other user) = %d, want 0", got)
+	}
+
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, b, day(t, "2025-01-01"), day(t, "2025-12-31"), time.Now().UTC()); err != nil || ok {
+		t.Errorf("VoidLastIncomeInRange(other user) = %v, %v; want false, nil", ok, err)
+	}
+
+	scheme, err := s.GetUserScheme(ctx, a)
+	if err != nil || scheme != domain.TaxSchemeUSN6 {
+		t.Errorf("GetUserScheme(new user) = %q, %v; want %q", scheme, err, domain.TaxSchemeUSN6)
+	}
+}
+
+// Concurrent first contacts of one identity create exactly one user.
+func testIdentityRace(t *testing.T, s Store) {
+	const workers = 16
+
+	externalID := fmt.Sprintf("storetest-race-%d-%d", time.Now().UnixNano(), seq.Add(1))
+
+	var (
+		wg   sync.WaitGroup
+		uids [workers]int64
+		errs [workers]error
+	)
+
+	start := make(chan struct{})
+
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			uids[i], errs[i] = s.UpsertIdentity(context.Background(), domain.TransportTelegram, externalID, 0)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	for i := range workers {
+		if errs[i] != nil {
+			t.Fatalf("UpsertIdentity #%d: %v", i, errs[i])
+		}
+		if uids[i] != uids[0] {
+			t.Fatalf("UpsertIdentity returned users %d and %d for one identity", uids[0], uids[i])
+		}
+	}
+}
+
+func addRule(t *testing.T, s Store, uid int64, confirm bool, nextDue time.Time) int64 {
+	t.Helper()
+
+	id, err := s.CreateRecurringRule(context.Background(), domain.RecurringRule{
+		UserID:  uid,
+		Amount:  500,
+		Note:    "retainer",
+		Period:  domain.RecurringMonthly,
+		Day:     5,
+		Confirm: confirm,
+		NextDue: nextDue,
+	})
+	if err != nil {
+		t.Fatalf("CreateRecurringRule: %v", err)
+	}
+
+	return id
+}
+
+func testRecurringRunsOnce(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	id := addRule(t, s, uid, false, day(t, "2025-01-05"))
+
+	due, err := s.DueRecurringRules(ctx, day(t, "2025-01-05"))
+	if err != nil {
+		t.Fatalf("DueRecurringRules: %v", err)
+	}
+
+	found := false
+	for _, r := range due {
+		if r.ID == id {
+			found = r.NextDue.Equal(day(t, "2025-01-05"))
+		}
+	}
+	if !found {
+		t.Fatalf("DueRecurringRules = %+v, want rule %d due 2025-01-05", due, id)
+	}
+
+	run, ok, err := s.RunRecurring(ctx, id, day(t, "2025-01-05"), day(t, "2025-02-05"))
+	if err != nil || !ok {
+		t.Fatalf("RunRecurring = %v, %v; want true", ok, err)
+	}
+	if run.Status != domain.RecurringCreated || run.IncomeID == 0 || run.UserID != uid || run.Amount != 500 {
+		t.Errorf("RunRecurring = %+v, want a created income of 500", run)
+	}
+
+	// A second scheduler that read the same next_due loses the race.
+	if _, ok, err := s.RunRecurring(ctx, id, day(t, "2025-01-05"), day(t, "2025-02-05")); err != nil || ok {
+		t.Errorf("RunRecurring again = %v, %v; want false, nil", ok, err)
+	}
+
+	if got := sumIncomes(t, s, uid, day(t, "2025-01-01"), day(t, "2025-12-31")); got != 500 {
+		t.Errorf("SumIncomes = %d, want 500", got)
+	}
+
+	rules, err := s.ListRecurringRules(ctx, uid)
+	if err != nil || len(rules) != 1 || !rules[0].NextDue.Equal(day(t, "2025-02-05")) {
+		t.Fatalf("ListRecurringRules = %+v, %v; want next due 2025-02-05", rules, err)
+	}
+
+	if ok, err := s.SetRecurringPaused(ctx, uid, id, true, time.Time{}); err != nil || !ok {
+		t.Fatalf("SetRecurringPaused = %v, %v", ok, err)
+	}
+	if _, ok, err := s.RunRecurring(ctx, id, day(t, "2025-02-05"), day(t, "2025-03-05")); err != nil || ok {
+		t.Errorf("RunRecurring(paused) = %v, %v; want false, nil", ok, err)
+	}
+
+	if ok, err := s.DeleteRecurringRule(ctx, newUser(t, s), id); err != nil || ok {
+		t.Errorf("DeleteRecurringRule(other user) = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := s.DeleteRecurringRule(ctx, uid, id); err != nil || !ok {
+		t.Fatalf("DeleteRecurringRule = %v, %v", ok, err)
+	}
+
+	// Incomes already created stay.
+	if got := sumIncomes(t, s, uid, day(t, "2025-01-01"), day(t, "2025-12-31")); got != 500 {
+		t.Errorf("SumIncomes after delete = %d, want 500", got)
+	}
+}
+
+func testRecurringConfirm(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	id := addRule(t, s, uid, true, day(t, "2025-01-05"))
+
+	for _, d := range [][2]string{{"2025-01-05", "2025-02-05"}, {"2025-02-05", "2025-03-05"}} {
+		run, ok, err := s.RunRecurring(ctx, id, day(t, d[0]), day(t, d[1]))
+		if err != nil || !ok || run.Status != domain.RecurringPending || run.IncomeID != 0 {
+			t.Fatalf("RunRecurring(%s) = %+v, %v, %v; want pending", d[0], run, ok, err)
+		}
+	}
+
+	if got := sumIncomes(t, s, uid, day(t, "2025-01-01"), day(t, "2025-12-31")); got != 0 {
+		t.Errorf("SumIncomes before confirm = %d, want 0", got)
+	}
+
+	rules, err := s.ListRecurringRules(ctx, uid)
+	if err != nil || len(rules) != 1 || rules[0].Pending != 2 {
+		t.Fatalf("ListRecurringRules = %+v, %v; want 2 pending", rules, err)
+	}
+
+	if runs, err := s.ResolveRecurringRuns(ctx, newUser(t, s), id, true); err != nil || len(runs) != 0 {
+		t.Errorf("ResolveRecurringRuns(other user) = %+v, %v; want none", runs, err)
+	}
+
+	runs, err := s.ResolveRecurringRuns(ctx, uid, id, true)
+	if err != nil || len(runs) != 2 || !runs[0].Due.Equal(day(t, "2025-01-05")) || runs[1].IncomeID == 0 {
+		t.Fatalf("ResolveRecurringRuns = %+v, %v; want 2 created, oldest first", runs, err)
+	}
+
+	if got := sumIncomes(t, s, uid, day(t, "2025-01-05"), day(t, "2025-02-05")); got != 1000 {
+		t.Errorf("SumIncomes after confirm = %d, want 1000", got)
+	}
+
+	if runs, err := s.ResolveRecurringRuns(ctx, uid, id, false); err != nil || len(runs) != 0 {
+		t.Errorf("ResolveRecurringRuns again = %+v, %v; want none", runs, err)
+	}
+}
+
+// Monthly sums cover the range inclusively, skip voided incomes and empty months, and come in month order.
+func testIncomesByMonth(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	addIncome(t, s, uid, day(t, "2024-12-31"), 1, "")
+	addIncome(t, s, uid, day(t, "2025-03-31"), 10, "")
+	addIncome(t, s, uid, day(t, "2025-01-01"), 100, "")
+	addIncome(t, s, uid, day(t, "2025-01-31"), 1000, "")
+	addIncome(t, s, uid, day(t, "2025-12-31"), 10000, "")
+	addIncome(t, s, uid, day(t, "2025-02-10"), 100000, "")
+
+	// The newest entry (February) is voided: its month disappears.
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-02-01"), day(t, "2025-02-28"), time.Now()); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+
+	got, err := s.SumIncomesByMonth(ctx, uid, day(t, "2025-01-01"), day(t, "2025-12-31").Add(12*time.Hour))
+	if err != nil {
+		t.Fatalf("SumIncomesByMonth: %v", err)
+	}
+
+	want := []domain.MonthSum{
+		{Month: day(t, "2025-01-01"), Sum: 1100},
+		{Month: day(t, "2025-03-01"), Sum: 10},
+		{Month: day(t, "2025-12-01"), Sum: 10000},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("SumIncomesByMonth = %+v, want %+v", got, want)
+	}
+
+	for i := range want {
+		if !got[i].Month.Equal(want[i].Month) || got[i].Sum != want[i].Sum {
+			t.Errorf("SumIncomesByMonth[%d] = %s %d, want %s %d", i,
+				got[i].Month.Format(time.DateOnly), got[i].Sum, want[i].Month.Format(time.DateOnly), want[i].Sum)
+		}
+	}
+}
+
+func testIncomeStatsByBucket(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+
+	addIncome(t, s, uid, day(t, "2024-12-30"), 1, "") // Monday of ISO week 1 of 2025, before the range
+	addIncome(t, s, uid, day(t, "2025-01-01"), 300, "")
+	addIncome(t, s, uid, day(t, "2025-01-05"), 100, "") // Sunday: same week
+	addIncome(t, s, uid, day(t, "2025-01-06"), 50, "")  // Monday: next week
+	addIncome(t, s, uid, day(t, "2025-02-03"), 7, "")
+	addIncome(t, s, uid, day(t, "2025-02-04"), 100000, "")
+
+	// The newest entry is voided: it counts nowhere.
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-02-01"), day(t, "2025-02-28"), time.Now()); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+
+	from, to := day(t, "2025-01-01"), day(t, "2025-02-28")
+
+	for _, tc := range []struct {
+		bucket domain.ReportBucket
+		want   []domain.IncomeStats
+	}{
+		{domain.BucketMonth, []domain.IncomeStats{
+			{Start: day(t, "2025-01-01"), Count: 3, Sum: 450, Max: 300},
+			{Start: day(t, "2025-02-01"), Count: 1, Sum: 7, Max: 7},
+		}},
+		{domain.BucketWeek, []domain.IncomeStats{
+			{Start: day(t, "2024-12-30"), Count: 2, Sum: 400, Max: 300},
+			{Start: day(t, "2025-01-06"), Count: 1, Sum: 50, Max: 50},
+			{Start: day(t, "2025-02-03"), Count: 1, Sum: 7, Max: 7},
+		}},
+	} {
+		got, err := s.IncomeStatsByBucket(ctx, uid, tc.bucket, from, to)
+		if err != nil {
+			t.Fatalf("IncomeStatsByBucket(%s): %v", tc.bucket, err)
+		}
+
+		if len(got) != len(tc.want) {
+			t.Fatalf("IncomeStatsByBucket(%s) = %+v, want %+v", tc.bucket, got, tc.want)
+		}
+
+		for i, w := range tc.want {
+			g := got[i]
+			if !g.Start.Equal(w.Start) || g.Count != w.Count || g.Sum != w.Sum || g.Max != w.Max {
+				t.Errorf("IncomeStatsByBucket(%s)[%d] = %s %d/%d/%d, want %s %d/%d/%d", tc.bucket, i,
+					g.Start.Format(time.DateOnly), g.Count, g.Sum, g.Max, w.Start.Format(time.DateOnly), w.Count, w.Sum, w.Max)
+			}
+		}
+	}
+
+	if _, err := s.IncomeStatsByBucket(ctx, uid, "day", from, to); !errors.Is(err, validate.ErrInvalidBucket) {
+		t.Errorf("IncomeStatsByBucket(day) err = %v, want ErrInvalidBucket", err)
+	}
+}
+
+func testSearchNotes(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	other := newUser(t, s)
+
+	jan := addIncome(t, s, uid, day(t, "2025-01-10"), 100, "Заказ #42 от ООО Ёлка")
+	feb := addIncome(t, s, uid, day(t, "2025-02-01"), 200, "заказ #420")
+	pct := addIncome(t, s, uid, day(t, "2025-03-01"), 400, "order 100%")
+	addIncome(t, s, uid, day(t, "2025-03-02"), 500, "order 1000 axb")
+	addIncome(t, s, other, day(t, "2025-01-10"), 600, "заказ #42")
+	addIncome(t, s, uid, day(t, "2025-04-01"), 700, "заказ #42 отменён")
+
+	adv, err := s.InsertPayment(ctx, uid, day(t, "2025-02-05"), 300, "ЗАКАЗ #42, аванс", domain.PaymentTypeAdvance)
+	if err != nil {
+		t.Fatalf("InsertPayment: %v", err)
+	}
+
+	// The newest income (April) is voided: it is never found.
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-04-01"), day(t, "2025-04-30"), time.Now()); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+
+	type hit struct {
+		kind domain.EntryKind
+		id   int64
+	}
+
+	all := []hit{{domain.EntryKindAdvance, adv}, {domain.EntryKindIncome, feb}, {domain.EntryKindIncome, jan}}
+
+	cases := []struct {
+		query string
+		from  string
+		limit int
+		want  []hit
+	}{
+		// Any case, ё as е, incomes and payments together, newest first.
+		{"заказ #42", "2025-01-01", 10, all},
+		{"ЕЛКА", "2025-01-01", 10, all[2:]},
+		{"заказ #42", "2025-01-01", 2, all[:2]},
+		{"заказ #42", "2025-01-11", 10, all[:2]},
+		// LIKE wildcards in the query are literal.
+		{"100%", "2025-01-01", 10, []hit{{domain.EntryKindIncome, pct}}},
+		{"a_b", "2025-01-01", 10, []hit{}},
+	}
+
+	for _, tc := range cases {
+		got, err := s.SearchNotes(ctx, uid, tc.query, day(t, tc.from), day(t, "2025-12-31"), tc.limit)
+		if err != nil {
+			t.Fatalf("SearchNotes(%q): %v", tc.query, err)
+		}
+
+		hits := make([]hit, 0, len(got))
+		for _, m := range got {
+			hits = append(hits, hit{m.Kind, m.ID})
+		}
+
+		if fmt.Sprint(hits) != fmt.Sprint(tc.want) {
+			t.Errorf("SearchNotes(%q, from %s, limit %d) = %v, want %v", tc.query, tc.from, tc.limit, hits, tc.want)
+		}
+	}
+}
+
+// Client names are unique per user regardless of case, Cyrillic included.
+func testCounterparties(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	other := newUser(t, s)
+
+	id, err := s.CreateCounterparty(ctx, domain.Counterparty{UserID: uid, Name: "ООО Ёлка", INN: "7707083893"})
+	if err != nil {
+		t.Fatalf("CreateCounterparty: %v", err)
+	}
+
+	if _, err := s.CreateCounterparty(ctx, domain.Counterparty{UserID: uid, Name: " ооо ёлка "}); !errors.Is(err, domain.ErrClientExists) {
+		t.Errorf("CreateCounterparty(same name) err = %v, want ErrClientExists", err)
+	}
+
+	if _, err := s.CreateCounterparty(ctx, domain.Counterparty{UserID: other, Name: "ООО Ёлка"}); err != nil {
+		t.Errorf("CreateCounterparty(other user): %v", err)
+	}
+
+	upd := domain.Counterparty{ID: id, UserID: uid, KPP: "770701001", Address: "Москва"}
+
+	if ok, err := s.UpdateCounterparty(ctx, domain.Counterparty{ID: id, UserID: other}); err != nil || ok {
+		t.Errorf("UpdateCounterparty(other user) = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := s.UpdateCounterparty(ctx, upd); err != nil || !ok {
+		t.Fatalf("UpdateCounterparty = %v, %v", ok, err)
+	}
+
+	got, err := s.ListCounterparties(ctx, uid)
+	want := domain.Counterparty{ID: id, UserID: uid, Name: "ООО Ёлка", KPP: "770701001", Address: "Москва"}
+	if err != nil || len(got) != 1 || got[0] != want {
+		t.Fatalf("ListCounterparties = %+v, %v; want [%+v]", got, err, want)
+	}
+
+	if r, err := s.GetRequisites(ctx, uid); err != nil || r != (domain.Requisites{}) {
+		t.Errorf("GetRequisites(unset) = %+v, %v; want empty", r, err)
+	}
+
+	req := domain.Requisites{Name: "ИП Иванов И. И.", INN: "500100732259", Bank: "ПАО Сбербанк", BIK: "044525225"}
+	for _, r := range []domain.Requisites{{Name: "draft"}, req} {
+		if err := s.SetRequisites(ctx, uid, r); err != nil {
+			t.Fatalf("SetRequisites: %v", err)
+		}
+	}
+
+	if r, err := s.GetRequisites(ctx, uid); err != nil || r != req {
+		t.Errorf("GetRequisites = %+v, %v; want %+v", r, err, req)
+	}
+}
+
+func addClient(t *testing.T, s Store, uid int64) int64 {
+	t.Helper()
+
+	id, err := s.CreateCounterparty(context.Background(), domain.Counterparty{UserID: uid, Name: fmt.Sprintf("client %d", seq.Add(1))})
+	if err != nil {
+		t.Fatalf("CreateCounterparty: %v", err)
+	}
+
+	return id
+}
+
+func addInvoice(t *testing.T, s Store, uid, clientID int64, issued, due string, amount int64) (id, number int64) {
+	t.Helper()
+
+	id, number, err := s.CreateInvoice(context.Background(), domain.Invoice{
+		UserID: uid, Client: domain.Counterparty{ID: clientID}, IssuedOn: day(t, issued), DueOn: day(t, due), Amount: amount,
+	})
+	if err != nil {
+		t.Fatalf("CreateInvoice(%s, %d): %v", issued, amount, err)
+	}
+
+	return id, number
+}
+
+// Invoices are numbered from 1 every year, per user.
+func testInvoiceNumbers(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	other := newUser(t, s)
+	client := addClient(t, s, uid)
+
+	var numbers []int64
+	for _, issued := range []string{"2024-12-30", "2024-12-31", "2025-01-01", "2025-01-02"} {
+		_, n := addInvoice(t, s, uid, client, issued, "2025-02-01", 100)
+		numbers = append(numbers, n)
+	}
+	_, n := addInvoice(t, s, other, addClient(t, s, other), "2025-01-03", "2025-02-01", 100)
+	numbers = append(numbers, n)
+
+	if fmt.Sprint(numbers) != "[1 2 1 2 1]" {
+		t.Errorf("numbers = %v, want [1 2 1 2 1]", numbers)
+	}
+
+	// A client of another user is not found.
+	if _, _, err := s.CreateInvoice(ctx, domain.Invoice{
+		UserID: other, Client: domain.Counterparty{ID: client}, IssuedOn: day(t, "2025-01-03"), DueOn: day(t, "2025-01-03"), Amount: 100,
+	}); !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Errorf("CreateInvoice(other user's client) err = %v, want ErrEntryNotFound", err)
+	}
+
+	invoices, err := s.ListInvoices(ctx, uid, 0)
+	if err != nil || len(invoices) != 4 {
+		t.Fatalf("ListInvoices = %+v, %v; want 4", invoices, err)
+	}
+
+	first := invoices[0]
+	if first.Number != 2 || !first.IssuedOn.Equal(day(t, "2025-01-02")) || !first.DueOn.Equal(day(t, "2025-02-01")) ||
+		first.Client.ID != client || first.Paid != 0 || !first.RemindedOn.IsZero() {
+		t.Errorf("ListInvoices()[0] = %+v, want the newest, number 2 of 2025", first)
+	}
+
+	if got, err := s.ListInvoices(ctx, other, first.ID); err != nil || len(got) != 0 {
+		t.Errorf("ListInvoices(other user, %d) = %+v, %v; want none", first.ID, got, err)
+	}
+}
+
+// Payments create incomes linked to the invoice; voiding one makes the invoice unpaid again.
+func testPayInvoice(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	id, _ := addInvoice(t, s, uid, addClient(t, s, uid), "2025-03-01", "2025-03-15", 1000)
+
+	paid := func() int64 {
+		t.Helper()
+
+		invoices, err := s.ListInvoices(ctx, uid, id)
+		if err != nil || len(invoices) != 1 {
+			t.Fatalf("ListInvoices(%d) = %+v, %v", id, invoices, err)
+		}
+
+		return invoices[0].Paid
+	}
+
+	if _, err := s.PayInvoice(ctx, newUser(t, s), id, day(t, "2025-03-02"), 0, ""); !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Errorf("PayInvoice(other user) err = %v, want ErrEntryNotFound", err)
+	}
+
+	if _, err := s.PayInvoice(ctx, uid, id, day(t, "2025-03-02"), 300, "part"); err != nil {
+		t.Fatalf("PayInvoice(300): %v", err)
+	}
+
+	if _, err := s.PayInvoice(ctx, uid, id, day(t, "2025-03-03"), 800, ""); !errors.Is(err, domain.ErrOverpayment) {
+		t.Errorf("PayInvoice(800 of 700 left) err = %v, want ErrOverpayment", err)
+	}
+
+	rest, err := s.PayInvoice(ctx, uid, id, day(t, "2025-03-20"), 0, "rest")
+	if err != nil || rest == 0 {
+		t.Fatalf("PayInvoice(rest) = %d, %v", rest, err)
+	}
+
+	if got := paid(); got != 1000 {
+		t.Errorf("paid = %d, want 1000", got)
+	}
+
+	if _, err := s.PayInvoice(ctx, uid, id, day(t, "2025-03-21"), 0, ""); !errors.Is(err, domain.ErrInvoicePaid) {
+		t.Errorf("PayInvoice(paid) err = %v, want ErrInvoicePaid", err)
+	}
+
+	incomes, err := s.ListIncomes(ctx, uid, day(t, "2025-03-01"), day(t, "2025-03-31"))
+	if err != nil || len(incomes) != 2 || incomes[1].ID != rest || incomes[1].Amount != 700 || incomes[1].Note != "rest" {
+		t.Fatalf("ListIncomes = %+v, %v; want 300 and then 700", incomes, err)
+	}
+
+	// /undo voids the newest income, the rest of the payment.
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-03-01"), day(t, "2025-03-31"), time.Now()); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+
+	if got := paid(); got != 300 {
+		t.Errorf("paid after void = %d, want 300", got)
+	}
+}
+
+// Overdue invoices are past due, not paid in full and not reminded of since remindedBefore.
+func testOverdueInvoices(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	client := addClient(t, s, uid)
+
+	late, _ := addInvoice(t, s, uid, client, "2025-01-01", "2025-01-10", 100)
+	partial, _ := addInvoice(t, s, uid, client, "2025-01-01", "2025-01-05", 100)
+	paid, _ := addInvoice(t, s, uid, client, "2025-01-01", "2025-01-05", 100)
+	addInvoice(t, s, uid, client, "2025-01-01", "2025-01-20", 100) // due today
+	reminded, _ := addInvoice(t, s, uid, client, "2025-01-01", "2025-01-02", 100)
+
+	if _, err := s.PayInvoice(ctx, uid, partial, day(t, "2025-01-06"), 40, ""); err != nil {
+		t.Fatalf("PayInvoice(partial): %v", err)
+	}
+	if _, err := s.PayInvoice(ctx, uid, paid, day(t, "2025-01-06"), 0, ""); err != nil {
+		t.Fatalf("PayInvoice(paid): %v", err)
+	}
+	if err := s.MarkInvoiceReminded(ctx, reminded, day(t, "2025-01-15")); err != nil {
+		t.Fatalf("MarkInvoiceReminded: %v", err)
+	}
+
+	overdue := func(remindedBefore string) []int64 {
+		t.Helper()
+
+		got, err := s.OverdueInvoices(ctx, day(t, "2025-01-20"), day(t, remindedBefore))
+		if err != nil {
+			t.Fatalf("OverdueInvoices: %v", err)
+		}
+
+		var ids []int64
+		for _, inv := range got {
+			if inv.UserID == uid {
+				ids = append(ids, inv.ID)
+			}
+		}
+
+		return ids
+	}
+
+	// Ordered by due date.
+	if got, want := overdue("2025-01-13"), []int64{partial, late}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("OverdueInvoices = %v, want %v", got, want)
+	}
+
+	if got, want := overdue("2025-01-15"), []int64{reminded, partial, late}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("OverdueInvoices(reminded before 2025-01-15) = %v, want %v", got, want)
+	}
+}
+
+// Acts are numbered from 1 every year, per user, apart from invoices; an invoice has
+// at most one act.
+func testActs(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	client := addClient(t, s, uid)
+
+	inv, invNumber := addInvoice(t, s, uid, client, "2025-03-01", "2025-03-15", 1000)
+	next, _ := addInvoice(t, s, uid, client, "2025-03-02", "2025-03-15", 2000)
+
+	act := domain.Act{UserID: uid, Client: domain.Counterparty{ID: client}, IssuedOn: day(t, "2025-03-20"), Amount: 1000, Description: "site", InvoiceID: inv}
+
+	id, number, err := s.CreateAct(ctx, act)
+	if err != nil || number != 1 {
+		t.Fatalf("CreateAct = %d, %d, %v; want number 1", id, number, err)
+	}
+
+	if _, _, err := s.CreateAct(ctx, act); !errors.Is(err, domain.ErrActExists) {
+		t.Errorf("CreateAct(same invoice) err = %v, want ErrActExists", err)
+	}
+
+	other := newUser(t, s)
+	if _, _, err := s.CreateAct(ctx, domain.Act{UserID: other, Client: domain.Counterparty{ID: addClient(t, s, other)},
+		IssuedOn: day(t, "2025-03-20"), Amount: 2000, InvoiceID: next}); !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Errorf("CreateAct(other user's invoice) err = %v, want ErrEntryNotFound", err)
+	}
+
+	act.InvoiceID, act.Amount = next, 2000
+	if _, n, err := s.CreateAct(ctx, act); err != nil || n != 2 {
+		t.Errorf("CreateAct(second of 2025) = %d, %v; want number 2", n, err)
+	}
+
+	last, _ := addInvoice(t, s, uid, client, "2025-12-30", "2026-01-15", 3000)
+	act.InvoiceID, act.IssuedOn = last, day(t, "2026-01-10")
+	if _, n, err := s.CreateAct(ctx, act); err != nil || n != 1 {
+		t.Errorf("CreateAct(first of 2026) = %d, %v; want number 1", n, err)
+	}
+
+	acts, err := s.ListActs(ctx, uid, 0)
+	if err != nil || len(acts) != 3 || acts[0].IssuedOn.Year() != 2026 {
+		t.Fatalf("ListActs = %+v, %v; want 3, newest first", acts, err)
+	}
+
+	got, err := s.ListActs(ctx, uid, id)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("ListActs(%d) = %+v, %v", id, got, err)
+	}
+
+	want := domain.Act{
+		ID: id, UserID: uid, Number: 1, IssuedOn: day(t, "2025-03-20"), Amount: 1000, Description: "site",
+		InvoiceID: inv, InvoiceNumber: invNumber, InvoiceIssuedOn: day(t, "2025-03-01"),
+	}
+	if a := got[0]; a.Client.ID != client || a.Client.Name == "" {
+		t.Errorf("ListActs(%d) client = %+v, want #%d", id, a.Client, client)
+	} else if a.Client = (domain.Counterparty{}); a != want {
+		t.Errorf("ListActs(%d) = %+v, want %+v", id, a, want)
+	}
+
+	if got, err := s.ListActs(ctx, other, id); err != nil || len(got) != 0 {
+		t.Errorf("ListActs(other user, %d) = %+v, %v; want none", id, got, err)
+	}
+}
+
+// An act is issued for an active income of the same user, once.
+func testActForIncome(t *testing.T, s Store) {
+	ctx := context.Background()
+	uid := newUser(t, s)
+	client := addClient(t, s, uid)
+
+	incomeID, err := s.InsertIncome(ctx, uid, day(t, "2025-04-02"), 5000, "design")
+	if err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+
+	if income, ok, err := s.GetIncome(ctx, uid, incomeID); err != nil || !ok || income.Amount != 5000 || income.Note != "design" || !income.At.Equal(day(t, "2025-04-02")) {
+		t.Errorf("GetIncome = %+v, %v, %v", income, ok, err)
+	}
+	if _, ok, err := s.GetIncome(ctx, newUser(t, s), incomeID); err != nil || ok {
+		t.Errorf("GetIncome(other user) = %v, %v; want none", ok, err)
+	}
+
+	act := domain.Act{UserID: uid, Client: domain.Counterparty{ID: client}, IssuedOn: day(t, "2025-04-03"), Amount: 5000, IncomeID: incomeID}
+
+	id, number, err := s.CreateAct(ctx, act)
+	if err != nil || number != 1 {
+		t.Fatalf("CreateAct = %d, %d, %v; want number 1", id, number, err)
+	}
+
+	if _, _, err := s.CreateAct(ctx, act); !errors.Is(err, domain.ErrActExists) {
+		t.Errorf("CreateAct(same income) err = %v, want ErrActExists", err)
+	}
+
+	if acts, err := s.ListActs(ctx, uid, id); err != nil || len(acts) != 1 || acts[0].IncomeID != incomeID || acts[0].InvoiceID != 0 {
+		t.Errorf("ListActs(%d) = %+v, %v", id, acts, err)
+	}
+
+	voided, err := s.InsertIncome(ctx, uid, day(t, "2025-04-05"), 700, "")
+	if err != nil {
+		t.Fatalf("InsertIncome: %v", err)
+	}
+	if _, _, _, ok, err := s.VoidLastIncomeInRange(ctx, uid, day(t, "2025-04-05"), day(t, "2025-04-05"), time.Now()); err != nil || !ok {
+		t.Fatalf("VoidLastIncomeInRange = %v, %v", ok, err)
+	}
+
+	if _, ok, err := s.GetIncome(ctx, uid, voided); err != nil || ok {
+		t.Errorf("GetIncome(voided) = %v, %v; want none", ok, err)
+	}
+
+	act.IncomeID, act.Amount = voided, 700
+	if _, _, err := s.CreateAct(ctx, act); !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Errorf("CreateAct(voided income) err = %v, want ErrEntryNotFound", err)
+	}
+}
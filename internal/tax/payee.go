@@ -0,0 +1,17 @@
+package tax
+
+import "github.com/tuor4eg/ip_accounting_bot/internal/domain"
+
+// ENPPayee is where the single tax payment (ЕНП) goes since 2023: the treasury account
+// of the Federal Tax Service in Tula, whatever the region of the payer, with OKTMO 0.
+var ENPPayee = domain.TaxPayee{
+	Name:        "Казначейство России (ФНС России)",
+	INN:         "7727406020",
+	KPP:         "770801001",
+	Bank:        "ОКЦ № 7 ГУ Банка России по ЦФО//УФК по Тульской области, г Тула",
+	BIK:         "017003983",
+	Account:     "03100643000000018500",
+	CorrAccount: "40102810445370000059",
+	KBK:         "18201061201010000510",
+	OKTMO:       "0",
+}
@@ -46,3 +46,28 @@ func TestStaticProvider_ForDate(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultProvider_YearEdges(t *testing.T) {
+	p := tax.NewDefaultProvider()
+
+	tests := []struct {
+		date      time.Time
+		wantFixed int64
+	}{
+		{time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC), 0},
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 49_500_00},
+		{time.Date(2025, 12, 31, 23, 59, 59, 999, time.UTC), 53_658_00},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 57_390_00},
+		{time.Date(2031, 6, 1, 0, 0, 0, 0, time.UTC), 57_390_00},
+	}
+
+	for _, test := range tests {
+		got, err := p.ForDate(domain.TaxSchemeUSN6, test.date)
+		if err != nil {
+			t.Fatalf("ForDate(%s): %v", test.date, err)
+		}
+		if got.FixedContrib != test.wantFixed || got.BaseRateBP != 600 {
+			t.Errorf("ForDate(%s) = %+v, want FixedContrib %d", test.date, got, test.wantFixed)
+		}
+	}
+}
@@ -3,22 +3,48 @@ package tax
 
 import "time"
 
-// NewDefaultProvider returns a static Provider with a single open-ended policy
-// for scheme "usn_6": base rate 6% (600 bp), extra threshold 300_000 ₽,
-// extra rate 1% (100 bp). Boundaries are inclusive.
+// NewDefaultProvider returns a static Provider for scheme "usn_6": base rate 6%
+// (600 bp), 1% (100 bp) extra contribution above 300_000 ₽, plus the yearly figures
+// (fixed contributions, 1% cap, USN income limit, VAT threshold and the income up to
+// which the reduced 5% VAT rate applies instead of 7%) for 2024 onwards.
+// The last version is open-ended: add a new one when next year's figures are published.
+// Boundaries are inclusive.
 func NewDefaultProvider() Provider {
-	v := VersionedPolicy{
-		ValidFrom: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), // inclusive
-		ValidTo:   nil,                                         // open-ended
-		Policy: Policy{
-			BaseRateBP:      600,        // 6% in basis points
-			ExcessThreshold: 300_000_00, // 300,000 RUB in kopecks
-			ExcessRateBP:    100,        // 1% in basis points
-		},
+	base := Policy{
+		BaseRateBP:      600,        // 6% in basis points
+		ExcessThreshold: 300_000_00, // 300,000 RUB in kopecks
+		ExcessRateBP:    100,        // 1% in basis points
+	}
+
+	// endOf is the last instant of year y, so that times within 31 December still match.
+	endOf := func(y int) time.Time {
+		return time.Date(y+1, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+	}
+
+	year := func(y int, fixed, excessCap, limit, vat, vatRateLimit int64) VersionedPolicy {
+		to := endOf(y)
+		p := base
+		p.FixedContrib, p.ExcessCap, p.IncomeLimit = fixed, excessCap, limit
+		if vat > 0 {
+			p.VATThreshold, p.VATRateLimit = vat, vatRateLimit
+			p.VATRateBP, p.VATRateHighBP = 500, 700 // 5% and 7% in basis points
+		}
+		return VersionedPolicy{ValidFrom: time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC), ValidTo: &to, Policy: p}
 	}
+
+	before := endOf(2023)
+
+	latest := year(2026, 57_390_00, 321_818_00, 490_500_000_00, 20_000_000_00, 272_500_000_00)
+	latest.ValidTo = nil // open-ended
+
 	return NewStaticProvider(map[string][]VersionedPolicy{
 		// If you have a domain constant (e.g., domain.TaxSchemeUSN6),
 		// you can replace the string literal with it.
-		"usn_6": {v},
+		"usn_6": {
+			{ValidFrom: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), ValidTo: &before, Policy: base},
+			year(2024, 49_500_00, 277_571_00, 265_800_000_00, 0, 0), // USN payers paid no VAT before 2025
+			year(2025, 53_658_00, 300_888_00, 450_000_000_00, 60_000_000_00, 250_000_000_00),
+			latest,
+		},
 	})
 }
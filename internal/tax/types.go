@@ -6,11 +6,19 @@ import (
 	"github.com/tuor4eg/ip_accounting_bot/internal/domain"
 )
 
-// Policy defines tax rates and thresholds
+// Policy defines tax rates and thresholds. Amounts are in kopecks; 0 means the
+// figure is not known (or does not apply) for the period.
 type Policy struct {
 	BaseRateBP      int64
 	ExcessThreshold int64
 	ExcessRateBP    int64
+	ExcessCap       int64 // maximum 1% extra contribution for the year
+	FixedContrib    int64 // fixed insurance contributions for the year
+	IncomeLimit     int64 // USN income limit: above it the simplified system is lost
+	VATThreshold    int64 // USN income above which VAT has to be paid
+	VATRateLimit    int64 // USN income up to which VATRateBP applies; VATRateHighBP above it
+	VATRateBP       int64 // reduced VAT rate for USN payers
+	VATRateHighBP   int64 // VAT rate for USN payers above VATRateLimit
 }
 
 // Provider interface for getting tax policies
@@ -1,63 +1,138 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
-	"net/url"
+	"net/http/httptrace"
+	"sync/atomic"
 	"time"
 )
 
+// defaultRequestTimeout bounds a request whose ctx has no deadline; long polls get their hold time on top.
+const defaultRequestTimeout = 10 * time.Second
+
 type response[T any] struct {
-	Ok          bool   `json:"ok"`
-	Result      T      `json:"result"`
-	Description string `json:"description,omitempty"`
+	Ok          bool                `json:"ok"`
+	Result      T                   `json:"result"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
 }
 
 type Client struct {
 	token   string
 	baseURL string
 	http    *http.Client
+	retry   RetryPolicy
+	limiter *RateLimiter
+	obs     RequestObserver
 }
 
+// New creates a client with DefaultRetryPolicy and a rate limiter at DefaultSendRate / DefaultChatInterval.
+// The http client should not set a Timeout shorter than the long-poll hold time; requests
+// are bounded by their ctx instead.
 func New(token string, httpClient *http.Client) *Client {
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: 10 * time.Second,
-		}
+		httpClient = &http.Client{}
 	}
 
 	return &Client{
 		token:   token,
 		baseURL: "https://api.telegram.org",
 		http:    httpClient,
+		retry:   DefaultRetryPolicy(),
+		limiter: NewRateLimiter(DefaultSendRate, DefaultChatInterval),
 	}
 }
 
-func (c *Client) buildURL(method string, q url.Values) string {
-	u := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+// SetBaseURL overrides the Bot API endpoint (e.g. a local Bot API server or a test fake).
+func (c *Client) SetBaseURL(u string) *Client {
+	c.baseURL = u
+	return c
+}
 
-	if len(q) > 0 {
-		u += "?" + q.Encode()
-	}
+// SetRetryPolicy sets how failed requests are retried; MaxAttempts <= 1 disables retries.
+func (c *Client) SetRetryPolicy(p RetryPolicy) *Client {
+	c.retry = p
+	return c
+}
+
+// SetRateLimiter sets the limiter for outgoing messages; nil disables rate limiting.
+func (c *Client) SetRateLimiter(l *RateLimiter) *Client {
+	c.limiter = l
+	return c
+}
+
+// SetObserver sets where every request attempt is reported (e.g. metrics); nil disables it.
+func (c *Client) SetObserver(o RequestObserver) *Client {
+	c.obs = o
+	return c
+}
+
+func (c *Client) buildURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+}
 
-	return u
+// uploader is implemented by params that carry files: they are sent as multipart/form-data
+// instead of JSON.
+type uploader interface {
+	multipart() (body []byte, contentType string, err error)
 }
 
-func (c *Client) doRequest(ctx context.Context, method string, q url.Values) (*http.Response, error) {
-	url := c.buildURL(method, q)
+// doRequest POSTs params as a JSON body, or a multipart one for an uploader; nil params send an empty body.
+// A transport error before the request was written is returned as a *notSentError.
+func (c *Client) doRequest(ctx context.Context, method string, params any) (*http.Response, error) {
+	var body io.Reader
+	contentType := "application/json"
+
+	switch p := params.(type) {
+	case nil:
+	case uploader:
+		b, ct, err := p.multipart()
+		if err != nil {
+			return nil, err
+		}
+		body, contentType = bytes.NewReader(b), ct
+	default:
+		b, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	var written atomic.Bool
+
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				written.Store(true)
+			}
+		},
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodPost, c.buildURL(method), body)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
 
-	return c.http.Do(req)
+	res, err := c.http.Do(req)
+	if err != nil && !written.Load() {
+		return nil, &notSentError{err: err}
+	}
+
+	return res, err
 }
 
 func decodeJSON[T any](r io.Reader) (response[T], error) {
@@ -70,39 +145,160 @@ func decodeJSON[T any](r io.Reader) (response[T], error) {
 
 func parseAPIResponse[T any](res *http.Response) (T, error) {
 	var zero T
+
+	// Telegram describes errors in a JSON body even on non-2xx statuses.
+	out, err := decodeJSON[T](res.Body)
+
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return zero, &APIError{
+		apiErr := &APIError{
 			Status:      res.StatusCode,
 			Description: http.StatusText(res.StatusCode),
 		}
-	}
 
-	out, err := decodeJSON[T](res.Body)
+		if err == nil {
+			apiErr.fill(out.ErrorCode, out.Description, out.Parameters)
+		}
+
+		return zero, apiErr
+	}
 
 	if err != nil {
 		return zero, err
 	}
 
 	if !out.Ok {
-		description := out.Description
+		apiErr := &APIError{Description: "not ok"}
+		apiErr.fill(out.ErrorCode, out.Description, out.Parameters)
 
-		if description != "" {
-			description = "not ok"
-		}
-
-		return zero, &APIError{Description: description}
+		return zero, apiErr
 	}
 
 	return out.Result, nil
 }
 
-//--------------------------------PUBLIC METHODS--------------------------------
+// callOpts tunes a single API call.
+type callOpts struct {
+	chatID   int64         // non-zero: outgoing message, subject to the rate limiter and not idempotent
+	longPoll time.Duration // server-side hold time added to the default request timeout
+}
 
-func (c *Client) GetMe(ctx context.Context) (*User, error) {
-	res, err := c.doRequest(ctx, "getMe", nil)
+// call performs the request with retries: network errors and 5xx are retried with
+// jittered exponential backoff, 429 after the server's retry_after. Other API errors
+// are returned at once. An outgoing message may have been delivered despite a network
+// error or a 5xx, so it is only retried after a 429 or an error before it was written.
+func call[T any](ctx context.Context, c *Client, method string, params any, opts callOpts) (T, error) {
+	var zero T
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultRequestTimeout+opts.longPoll)
+		defer cancel()
+	}
+
+	attempts := max(c.retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		if opts.chatID != 0 && c.limiter != nil {
+			if err := c.limiter.Wait(ctx, opts.chatID); err != nil {
+				return zero, err
+			}
+		}
+
+		began := time.Now()
+		out, err := callOnce[T](ctx, c, method, params)
+		if c.obs != nil {
+			c.obs.ObserveRequest(method, time.Since(began), err)
+		}
+
+		if err == nil {
+			return out, nil
+		}
+
+		if ctx.Err() != nil || attempt >= attempts {
+			return zero, err
+		}
+
+		wait := c.retry.backoff(attempt)
+		send := opts.chatID != 0
+
+		var (
+			apiErr  *APIError
+			notSent *notSentError
+		)
+		switch {
+		case errors.As(err, &apiErr):
+			switch {
+			case apiErr.RetryAfter > 0:
+				wait = apiErr.RetryAfter
+				// Hold back every send to this chat, not just this one.
+				if send && c.limiter != nil {
+					c.limiter.Defer(opts.chatID, wait)
+					wait = 0
+				}
+			case apiErr.Status >= 500 && !send:
+			default:
+				return zero, err
+			}
+		case send && !errors.As(err, &notSent):
+			return zero, err
+		}
+
+		if err := sleepCtx(ctx, wait); err != nil {
+			return zero, err
+		}
+	}
+}
+
+func callOnce[T any](ctx context.Context, c *Client, method string, params any) (T, error) {
+	res, err := c.doRequest(ctx, method, params)
 	if err != nil {
-		return nil, err
+		var zero T
+		return zero, err
 	}
 	defer res.Body.Close()
-	return parseAPIResponse[*User](res)
+
+	return parseAPIResponse[T](res)
+}
+
+// backoff returns the delay before retry number attempt (1-based): exponential from
+// BaseDelay, capped at MaxDelay, with the upper half randomized.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+
+	return half + rand.N(half+1)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+//--------------------------------PUBLIC METHODS--------------------------------
+
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	return call[*User](ctx, c, "getMe", nil, callOpts{})
 }
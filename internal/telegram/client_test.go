@@ -0,0 +1,421 @@
+package telegram_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/telegram"
+)
+
+// fastRetry keeps backoff tests quick.
+var fastRetry = telegram.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// fakeBotAPI serves /bot<token>/<method>, handing the decoded JSON body and call number to fn.
+func fakeBotAPI(t *testing.T, fn func(method string, n int, body map[string]any) (int, string)) (*telegram.Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+
+		body := map[string]any{}
+		if r.ContentLength != 0 {
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+		}
+
+		n := int(calls.Add(1))
+		status, resp := fn(r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:], n, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := telegram.New("TOKEN", srv.Client()).SetBaseURL(srv.URL).SetRetryPolicy(fastRetry).SetRateLimiter(nil)
+
+	return c, &calls
+}
+
+const okMessage = `{"ok":true,"result":{"message_id":7,"date":1,"chat":{"id":%d,"type":"private"}}}`
+
+func TestSendMessage_PostsJSON(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fakeBotAPI(t, func(method string, n int, body map[string]any) (int, string) {
+		if method != "sendMessage" || body["chat_id"] != float64(42) || body["text"] != "привет & <b>" || body["parse_mode"] != "HTML" {
+			t.Errorf("unexpected request %s %v", method, body)
+		}
+		return http.StatusOK, strings.Replace(okMessage, "%d", "42", 1)
+	})
+
+	msg, err := c.SendMessage(context.Background(), telegram.SendMessageParams{ChatID: 42, Text: "привет & <b>", ParseMode: "HTML"})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	if msg.MessageID != 7 {
+		t.Fatalf("message id = %d", msg.MessageID)
+	}
+}
+
+func TestGetUpdates_PostsParams(t *testing.T) {
+	t.Parallel()
+
+	c, _ := fakeBotAPI(t, func(method string, n int, body map[string]any) (int, string) {
+		allowed, _ := body["allowed_updates"].([]any)
+		if method != "getUpdates" || body["offset"] != float64(5) || len(allowed) != 2 {
+			t.Errorf("unexpected request %s %v", method, body)
+		}
+		return http.StatusOK, `{"ok":true,"result":[{"update_id":5,"message":{"message_id":1,"date":1,"text":"/start","chat":{"id":1,"type":"private"}}}]}`
+	})
+
+	ups, err := c.GetUpdates(context.Background(), telegram.GetUpdatesParams{Offset: 5, AllowedUpdates: []string{"message", "edited_message"}})
+	if err != nil {
+		t.Fatalf("GetUpdates: %v", err)
+	}
+
+	if len(ups) != 1 || ups[0].UpdateID != 5 || ups[0].Message.Text != "/start" {
+		t.Fatalf("updates = %+v", ups)
+	}
+}
+
+func TestSendMessage_RetriesAfterFloodWait(t *testing.T) {
+	t.Parallel()
+
+	c, calls := fakeBotAPI(t, func(method string, n int, body map[string]any) (int, string) {
+		if n == 1 {
+			return http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`
+		}
+		return http.StatusOK, strings.Replace(okMessage, "%d", "42", 1)
+	})
+	c.SetRateLimiter(telegram.NewRateLimiter(telegram.DefaultSendRate, time.Millisecond))
+
+	start := time.Now()
+
+	if _, err := c.SendMessage(context.Background(), telegram.SendMessageParams{ChatID: 42, Text: "hi"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Fatalf("calls = %d, want 2", calls.Load())
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("retried after %v, want >= retry_after (1s)", elapsed)
+	}
+}
+
+func TestGetMe_RetriesServerErrors(t *testing.T) {
+	t.Parallel()
+
+	c, calls := fakeBotAPI(t, func(method string, n int, body map[string]any) (int, string) {
+		if n < 3 {
+			return http.StatusBadGateway, `bad gateway`
+		}
+		return http.StatusOK, `{"ok":true,"result":{"id":1,"is_bot":true,"username":"testbot"}}`
+	})
+
+	me, err := c.GetMe(context.Background())
+	if err != nil {
+		t.Fatalf("GetMe: %v", err)
+	}
+
+	if me.Username != "testbot" || calls.Load() != 3 {
+		t.Fatalf("me=%+v calls=%d", me, calls.Load())
+	}
+}
+
+func TestSendMessage_ClientErrorNotRetried(t *testing.T) {
+	t.Parallel()
+
+	c, calls := fakeBotAPI(t, func(method string, n int, body map[string]any) (int, string) {
+		return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+	})
+
+	_, err := c.SendMessage(context.Background(), telegram.SendMessageParams{ChatID: 1, Text: "hi"})
+
+	var apiErr *telegram.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 || apiErr.Description != "Bad Request: chat not found" {
+		t.Fatalf("err = %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestSendMessage_ServerErrorNotRetried(t *testing.T) {
+	t.Parallel()
+
+	// The message may have been delivered before the 5xx: repeating it could send it twice.
+	c, calls := fakeBotAPI(t, func(method string, n int, body map[string]any) (int, string) {
+		return http.StatusBadGateway, `bad gateway`
+	})
+
+	_, err := c.SendMessage(context.Background(), telegram.SendMessageParams{ChatID: 1, Text: "hi"})
+
+	var apiErr *telegram.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway {
+		t.Fatalf("err = %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestSendMessage_DroppedConnectionNotRetried(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		// Read the whole request, then drop the connection without an answer.
+		_, _ = io.Copy(io.Discard, r.Body)
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		_ = conn.Close()
+	}))
+	t.Cleanup(srv.Close)
+
+	c := telegram.New("TOKEN", srv.Client()).SetBaseURL(srv.URL).SetRetryPolicy(fastRetry).SetRateLimiter(nil)
+
+	if _, err := c.SendMessage(context.Background(), telegram.SendMessageParams{ChatID: 1, Text: "hi"}); err == nil {
+		t.Fatal("SendMessage: want an error")
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
+
+// failFirst fails the first round trip before anything is written.
+type failFirst struct {
+	next  http.RoundTripper
+	calls atomic.Int32
+}
+
+func (f *failFirst) RoundTrip(r *http.Request) (*http.Response, error) {
+	if f.calls.Add(1) == 1 {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+	return f.next.RoundTrip(r)
+}
+
+func TestSendMessage_RetriesUnsentRequest(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, okMessage, 1)
+	}))
+	t.Cleanup(srv.Close)
+
+	rt := &failFirst{next: srv.Client().Transport}
+	c := telegram.New("TOKEN", &http.Client{Transport: rt}).SetBaseURL(srv.URL).SetRetryPolicy(fastRetry).SetRateLimiter(nil)
+
+	if _, err := c.SendMessage(context.Background(), telegram.SendMessageParams{ChatID: 1, Text: "hi"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	if rt.calls.Load() != 2 {
+		t.Fatalf("round trips = %d, want 2", rt.calls.Load())
+	}
+}
+
+func TestSendMessage_FollowsChatMigration(t *testing.T) {
+	t.Parallel()
+
+	c, calls := fakeBotAPI(t, func(method string, n int, body map[string]any) (int, string) {
+		if body["chat_id"] == float64(-100) {
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-1001234}}`
+		}
+		if body["chat_id"] != float64(-1001234) {
+			t.Errorf("resent to chat %v", body["chat_id"])
+		}
+		return http.StatusOK, strings.Replace(okMessage, "%d", "-1001234", 1)
+	})
+
+	msg, err := c.SendMessage(context.Background(), telegram.SendMessageParams{ChatID: -100, Text: "hi"})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	if msg.Chat.ID != -1001234 || calls.Load() != 2 {
+		t.Fatalf("chat=%d calls=%d", msg.Chat.ID, calls.Load())
+	}
+}
+
+func TestRateLimiter_SpacesSendsPerChat(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := telegram.NewRateLimiter(0, 50*time.Millisecond)
+
+	start := time.Now()
+
+	for _, chat := range []int64{1, 2, 3} {
+		if err := l.Wait(ctx, chat); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Fatalf("different chats waited %v", elapsed)
+	}
+
+	if err := l.Wait(ctx, 1); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("second send to one chat after %v, want >= 50ms", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	l.Defer(2, time.Hour)
+
+	if err := l.Wait(cancelled, 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait on deferred chat err = %v", err)
+	}
+}
+
+func TestSendPhoto_UploadsMultipart(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sendPhoto") {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+		}
+
+		if got := r.FormValue("chat_id"); got != "42" {
+			t.Errorf("chat_id = %q", got)
+		}
+		if got := r.FormValue("caption"); got != "<b>Chart</b>" {
+			t.Errorf("caption = %q", got)
+		}
+		if got := r.FormValue("parse_mode"); got != "HTML" {
+			t.Errorf("parse_mode = %q", got)
+		}
+
+		f, h, err := r.FormFile("photo")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		defer f.Close()
+
+		var b strings.Builder
+		_, _ = io.Copy(&b, f)
+		if h.Filename != "chart.png" || b.String() != "PNGDATA" {
+			t.Errorf("photo = %q %q", h.Filename, b.String())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		// The first attempt hits flood control: the retry must upload the whole body again.
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`))
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, okMessage, 42)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := telegram.New("TOKEN", srv.Client()).SetBaseURL(srv.URL).SetRetryPolicy(fastRetry).SetRateLimiter(nil)
+
+	msg, err := c.SendPhoto(context.Background(), telegram.SendPhotoParams{
+		ChatID: 42, Photo: []byte("PNGDATA"), FileName: "chart.png", Caption: "<b>Chart</b>", ParseMode: "HTML",
+	})
+	if err != nil {
+		t.Fatalf("SendPhoto: %v", err)
+	}
+	if msg.Chat.ID != 42 || calls.Load() != 2 {
+		t.Errorf("chat = %d, calls = %d", msg.Chat.ID, calls.Load())
+	}
+}
+
+func TestSendDocument_FollowsMigration(t *testing.T) {
+	t.Parallel()
+
+	var chats []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sendDocument") {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+		}
+
+		f, h, err := r.FormFile("document")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		defer f.Close()
+
+		var b strings.Builder
+		_, _ = io.Copy(&b, f)
+		if h.Filename != "invoice-7.pdf" || b.String() != "%PDF-" {
+			t.Errorf("document = %q %q", h.Filename, b.String())
+		}
+
+		chats = append(chats, r.FormValue("chat_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if len(chats) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-100500}}`))
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, okMessage, -100500)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := telegram.New("TOKEN", srv.Client()).SetBaseURL(srv.URL).SetRetryPolicy(fastRetry).SetRateLimiter(nil)
+
+	msg, err := c.SendDocument(context.Background(), telegram.SendDocumentParams{
+		ChatID: -42, Document: []byte("%PDF-"), FileName: "invoice-7.pdf", Caption: "Invoice",
+	})
+	if err != nil {
+		t.Fatalf("SendDocument: %v", err)
+	}
+	if msg.Chat.ID != -100500 || strings.Join(chats, ",") != "-42,-100500" {
+		t.Errorf("chat = %d, chats = %v", msg.Chat.ID, chats)
+	}
+}
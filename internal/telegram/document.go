@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// SendDocument uploads a file with an optional caption, the same way as SendPhoto.
+func (c *Client) SendDocument(ctx context.Context, p SendDocumentParams) (*Message, error) {
+	const op = "telegram.Client.SendDocument"
+
+	msg, err := call[*Message](ctx, c, "sendDocument", p, callOpts{chatID: p.ChatID})
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.MigrateToChatID != 0 {
+		p.ChatID = apiErr.MigrateToChatID
+		msg, err = call[*Message](ctx, c, "sendDocument", p, callOpts{chatID: p.ChatID})
+	}
+
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	return msg, nil
+}
+
+// multipart encodes p as the form fields of sendDocument with the file as "document".
+func (p SendDocumentParams) multipart() ([]byte, string, error) {
+	name := p.FileName
+	if name == "" {
+		name = "document"
+	}
+
+	return uploadForm([][2]string{
+		{"chat_id", strconv.FormatInt(p.ChatID, 10)},
+		{"caption", p.Caption},
+		{"parse_mode", p.ParseMode},
+	}, "document", name, p.Document)
+}
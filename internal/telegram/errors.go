@@ -1,11 +1,19 @@
 package telegram
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // APIError represents a Telegram API error
 type APIError struct {
-	Status      int
+	Status      int // HTTP status, 0 if the HTTP request itself succeeded
+	Code        int // Bot API error_code
 	Description string
+	// RetryAfter is set on flood-control errors (429): how long to wait before repeating.
+	RetryAfter time.Duration
+	// MigrateToChatID is set when the group was upgraded to a supergroup with a new ID.
+	MigrateToChatID int64
 }
 
 func (e APIError) Error() string {
@@ -19,3 +27,31 @@ func (e APIError) Error() string {
 
 	return "Telegram API error"
 }
+
+// fill copies the error details of a decoded response.
+func (e *APIError) fill(code int, description string, params *ResponseParameters) {
+	e.Code = code
+
+	if description != "" {
+		e.Description = description
+	}
+
+	if params != nil {
+		e.RetryAfter = time.Duration(params.RetryAfter) * time.Second
+		e.MigrateToChatID = params.MigrateToChatID
+	}
+}
+
+// notSentError is a transport error that happened before the request was written, so
+// the server has not seen it and even an outgoing message can be repeated.
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *notSentError) Unwrap() error {
+	return e.err
+}
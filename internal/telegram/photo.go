@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// SendPhoto uploads a photo with an optional caption. Like SendMessage, it is rate
+// limited and follows a group's migration to a supergroup.
+func (c *Client) SendPhoto(ctx context.Context, p SendPhotoParams) (*Message, error) {
+	const op = "telegram.Client.SendPhoto"
+
+	msg, err := call[*Message](ctx, c, "sendPhoto", p, callOpts{chatID: p.ChatID})
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.MigrateToChatID != 0 {
+		p.ChatID = apiErr.MigrateToChatID
+		msg, err = call[*Message](ctx, c, "sendPhoto", p, callOpts{chatID: p.ChatID})
+	}
+
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+	return msg, nil
+}
+
+// multipart encodes p as the form fields of sendPhoto with the image as the "photo" file.
+func (p SendPhotoParams) multipart() ([]byte, string, error) {
+	name := p.FileName
+	if name == "" {
+		name = "photo"
+	}
+
+	return uploadForm([][2]string{
+		{"chat_id", strconv.FormatInt(p.ChatID, 10)},
+		{"caption", p.Caption},
+		{"parse_mode", p.ParseMode},
+	}, "photo", name, p.Photo)
+}
+
+// uploadForm encodes the non-empty fields and a file as multipart/form-data.
+func uploadForm(fields [][2]string, field, name string, data []byte) ([]byte, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	fw, err := w.CreateFormFile(field, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := fw.Write(data); err != nil {
+		return nil, "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
@@ -0,0 +1,72 @@
+package telegram
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultSendRate is the global limit of outgoing messages per second.
+	DefaultSendRate = 30
+	// DefaultChatInterval is the minimal gap between two messages to one chat.
+	DefaultChatInterval = time.Second
+
+	// chatPruneThreshold is the number of tracked chats above which idle entries are dropped.
+	chatPruneThreshold = 1024
+)
+
+// NewRateLimiter allows perSecond messages overall (0 means no global limit) and one
+// message per perChat interval in a single chat.
+func NewRateLimiter(perSecond int, perChat time.Duration) *RateLimiter {
+	var global time.Duration
+	if perSecond > 0 {
+		global = time.Second / time.Duration(perSecond)
+	}
+
+	return &RateLimiter{
+		global:  global,
+		perChat: perChat,
+		chats:   make(map[int64]time.Time),
+	}
+}
+
+// Wait reserves the next send slot for chatID and sleeps until it comes.
+func (l *RateLimiter) Wait(ctx context.Context, chatID int64) error {
+	return sleepCtx(ctx, time.Until(l.reserve(chatID, time.Now())))
+}
+
+// Defer holds back sends to chatID for d, e.g. after a 429 with retry_after.
+func (l *RateLimiter) Defer(chatID int64, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.chats[chatID]) {
+		l.chats[chatID] = until
+	}
+}
+
+func (l *RateLimiter) reserve(chatID int64, now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	at := now
+	if l.nextSend.After(at) {
+		at = l.nextSend
+	}
+	if next := l.chats[chatID]; next.After(at) {
+		at = next
+	}
+
+	l.nextSend = at.Add(l.global)
+	l.chats[chatID] = at.Add(l.perChat)
+
+	if len(l.chats) > chatPruneThreshold {
+		for id, next := range l.chats {
+			if next.Before(now) {
+				delete(l.chats, id)
+			}
+		}
+	}
+
+	return at
+}
@@ -1,5 +1,10 @@
 package telegram
 
+import (
+	"sync"
+	"time"
+)
+
 // User is a Telegram user or bot (minimal subset).
 type User struct {
 	ID        int64  `json:"id"`
@@ -7,6 +12,8 @@ type User struct {
 	Username  string `json:"username,omitempty"`
 	FirstName string `json:"first_name,omitempty"`
 	LastName  string `json:"last_name,omitempty"`
+	// LanguageCode is the IETF tag of the user's Telegram language, e.g. "en" or "pt-br".
+	LanguageCode string `json:"language_code,omitempty"`
 }
 
 // Chat identifies the conversation the message belongs to.
@@ -37,6 +44,7 @@ type Update struct {
 type GetUpdatesParams struct {
 	Offset         int64    `json:"offset,omitempty"`          // next update_id to receive
 	Timeout        int      `json:"timeout,omitempty"`         // seconds to hold the long poll
+	Limit          int      `json:"limit,omitempty"`           // max updates per batch (1..100, default 100)
 	AllowedUpdates []string `json:"allowed_updates,omitempty"` // e.g. []{"message"}
 }
 
@@ -48,3 +56,55 @@ type SendMessageParams struct {
 	DisableNotification bool   `json:"disable_notification,omitempty"`
 	ReplyToMessageID    int64  `json:"reply_to_message_id,omitempty"`
 }
+
+// SendPhotoParams are parameters for uploading a photo; they are sent as multipart/form-data.
+type SendPhotoParams struct {
+	ChatID    int64
+	Photo     []byte // image file, e.g. a PNG
+	FileName  string // e.g. "chart.png"; "photo" if empty
+	Caption   string // 0-1024 characters after entities parsing
+	ParseMode string
+}
+
+// SendDocumentParams are parameters for uploading a file; they are sent as multipart/form-data.
+type SendDocumentParams struct {
+	ChatID    int64
+	Document  []byte
+	FileName  string // e.g. "invoice-7.pdf"; "document" if empty
+	Caption   string // 0-1024 characters after entities parsing
+	ParseMode string
+}
+
+// ResponseParameters explain why a request failed and how it can be repeated.
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int   `json:"retry_after,omitempty"` // seconds
+}
+
+// RetryPolicy controls retries of failed requests (network errors, 5xx and 429); sends are
+// only retried after a 429 or an error before the request was written.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first one
+	BaseDelay   time.Duration // delay before the first retry, doubled for each next one
+	MaxDelay    time.Duration // backoff cap; 429 retry_after is honored even if longer
+}
+
+// DefaultRetryPolicy returns the policy used by New.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second}
+}
+
+// RateLimiter spaces outgoing messages globally and per chat, as the Bot API asks
+// (about 30 messages per second overall and one per second in a single chat).
+type RateLimiter struct {
+	mu       sync.Mutex
+	global   time.Duration
+	perChat  time.Duration
+	nextSend time.Time
+	chats    map[int64]time.Time // chat ID -> earliest next send
+}
+
+// RequestObserver is told about every Bot API request attempt, including retries.
+type RequestObserver interface {
+	ObserveRequest(method string, d time.Duration, err error)
+}
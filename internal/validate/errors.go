@@ -15,4 +15,7 @@ var (
 	ErrInvalidDate        = errors.New("invalid date")
 	ErrEmptyString        = errors.New("empty string")
 	ErrNotFound           = errors.New("not found")
+	ErrInvalidLang        = errors.New("invalid language code")
+	ErrInvalidBucket      = errors.New("invalid report bucket")
+	ErrInvalidRequisite   = errors.New("invalid requisite")
 )
@@ -0,0 +1,124 @@
+package validate
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ValidateINN checks the length and the check digits of a taxpayer number:
+// 10 digits for an organization, 12 for an individual entrepreneur.
+func ValidateINN(inn string) error {
+	if !digits(inn) {
+		return fmt.Errorf("%w: inn", ErrInvalidRequisite)
+	}
+
+	check := func(weights []int) int {
+		sum := 0
+		for i, w := range weights {
+			sum += w * int(inn[i]-'0')
+		}
+		return sum % 11 % 10
+	}
+
+	switch len(inn) {
+	case 10:
+		if check([]int{2, 4, 10, 3, 5, 9, 4, 6, 8}) == int(inn[9]-'0') {
+			return nil
+		}
+	case 12:
+		if check([]int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) == int(inn[10]-'0') &&
+			check([]int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) == int(inn[11]-'0') {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: inn", ErrInvalidRequisite)
+}
+
+// ValidateOGRNIP checks the 15 digits of a registration number of an individual
+// entrepreneur: the last one is the first 14 taken modulo 13, then modulo 10.
+func ValidateOGRNIP(ogrnip string) error {
+	if len(ogrnip) != 15 || !digits(ogrnip) {
+		return fmt.Errorf("%w: ogrnip", ErrInvalidRequisite)
+	}
+
+	n, err := strconv.ParseInt(ogrnip[:14], 10, 64)
+	if err != nil || n%13%10 != int64(ogrnip[14]-'0') {
+		return fmt.Errorf("%w: ogrnip", ErrInvalidRequisite)
+	}
+
+	return nil
+}
+
+// ValidateKPP checks that a tax registration reason code is 9 characters: digits,
+// with the 5th and 6th allowed to be Latin capitals.
+func ValidateKPP(kpp string) error {
+	if len(kpp) != 9 || !digits(kpp[:4]) || !digits(kpp[6:]) {
+		return fmt.Errorf("%w: kpp", ErrInvalidRequisite)
+	}
+
+	for _, c := range kpp[4:6] {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z') {
+			return fmt.Errorf("%w: kpp", ErrInvalidRequisite)
+		}
+	}
+
+	return nil
+}
+
+// ValidateBIK checks that a bank identification code is 9 digits.
+func ValidateBIK(bik string) error {
+	if len(bik) != 9 || !digits(bik) {
+		return fmt.Errorf("%w: bik", ErrInvalidRequisite)
+	}
+	return nil
+}
+
+// ValidateAccount checks the control key of a settlement account opened in the bank
+// with bik, which must be valid.
+func ValidateAccount(bik, account string) error {
+	if ValidateBIK(bik) != nil || !accountKey(bik[6:]+account) {
+		return fmt.Errorf("%w: account", ErrInvalidRequisite)
+	}
+	return nil
+}
+
+// ValidateCorrAccount checks the control key of the correspondent account of the bank with bik.
+func ValidateCorrAccount(bik, account string) error {
+	if ValidateBIK(bik) != nil || !accountKey("0"+bik[4:6]+account) {
+		return fmt.Errorf("%w: corr_account", ErrInvalidRequisite)
+	}
+	return nil
+}
+
+// accountKey checks the Bank of Russia control key of 3 digits of the BIK followed by a
+// 20-digit account: the digits weighted 7, 1, 3, ... sum up to a multiple of 10.
+func accountKey(s string) bool {
+	if len(s) != 23 || !digits(s) {
+		return false
+	}
+
+	weights := [3]int{7, 1, 3}
+	sum := 0
+
+	for i := range len(s) {
+		sum += int(s[i]-'0') * weights[i%3] % 10
+	}
+
+	return sum%10 == 0
+}
+
+// digits reports whether s is a non-empty string of ASCII digits.
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
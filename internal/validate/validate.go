@@ -44,6 +44,13 @@ func ValidatePaymentType(paymentType domain.PaymentType) error {
 	return nil
 }
 
+func ValidateReportBucket(bucket domain.ReportBucket) error {
+	if err := OneOf(bucket, domain.BucketMonth, domain.BucketWeek); err != nil {
+		return ErrInvalidBucket
+	}
+	return nil
+}
+
 func ValidateDateRangeUTC(from, to time.Time) error {
 	if from.IsZero() || to.IsZero() {
 		return ErrInvalidDateRange
@@ -86,3 +93,14 @@ func ValidateEmptyString(s string) error {
 	}
 	return nil
 }
+
+// ValidateLang accepts an ISO 639-1 code ("ru") or "" (no explicit choice).
+func ValidateLang(lang string) error {
+	if lang == "" {
+		return nil
+	}
+	if len(lang) != 2 || lang[0] < 'a' || lang[0] > 'z' || lang[1] < 'a' || lang[1] > 'z' {
+		return ErrInvalidLang
+	}
+	return nil
+}
@@ -5,4 +5,11 @@ import "errors"
 var (
 	ErrInvalidFS                 = errors.New("fs is nil")
 	ErrDuplicateMigrationVersion = errors.New("duplicate migration version")
+	ErrDownWithoutUp             = errors.New("down migration has no up migration")
+	ErrChecksumMismatch          = errors.New("applied migration file has changed")
+	ErrMigrationFileMissing      = errors.New("applied migration has no file")
+	ErrNoDownMigration           = errors.New("migration has no down file")
+	ErrNothingToRollBack         = errors.New("no applied migrations to roll back")
+	ErrInvalidSteps              = errors.New("steps must be positive")
+	ErrPendingMigrations         = errors.New("database has pending migrations")
 )
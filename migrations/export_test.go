@@ -0,0 +1,10 @@
+package migrations
+
+// Exported for tests in migrations_test.
+var (
+	Statuses = statuses
+	Verify   = verify
+	PlanUp   = planUp
+	PlanDown = planDown
+	UpToDate = upToDate
+)
@@ -0,0 +1,229 @@
+package migrations_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/migrations"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"sql/0001_init.up.sql":             {Data: []byte("CREATE TABLE a (id INT);")},
+		"sql/0001_init.down.sql":           {Data: []byte("DROP TABLE a;")},
+		"sql/0002_idx_concurrently.up.sql": {Data: []byte("CREATE INDEX CONCURRENTLY a_idx ON a (id);")},
+		"sql/0003_more.up.sql":             {Data: []byte("CREATE TABLE b (id INT);")},
+		"sql/0003_more.down.sql":           {Data: []byte("DROP TABLE b;")},
+		"sql/README.md":                    {Data: []byte("not a migration")},
+		"sql/0004_not_a_migration.sql":     {Data: []byte("SELECT 1;")},
+	}
+}
+
+func list(t *testing.T, fsys fstest.MapFS) []migrations.Migration {
+	t.Helper()
+
+	all, err := migrations.ListUpMigrations(fsys, "sql")
+	if err != nil {
+		t.Fatalf("ListUpMigrations: %v", err)
+	}
+
+	return all
+}
+
+func applied(all []migrations.Migration, n int) []migrations.AppliedMigration {
+	var out []migrations.AppliedMigration
+	for _, m := range all[:n] {
+		out = append(out, migrations.AppliedMigration{Version: m.Version, Checksum: m.Checksum, AppliedAt: time.Unix(0, 0)})
+	}
+	return out
+}
+
+func TestListUpMigrations_PairsDownFiles(t *testing.T) {
+	all := list(t, testFS())
+
+	if len(all) != 3 {
+		t.Fatalf("got %d migrations, want 3", len(all))
+	}
+
+	want := []struct {
+		version    int64
+		concurrent bool
+		down       string
+	}{
+		{1, false, "sql/0001_init.down.sql"},
+		{2, true, ""},
+		{3, false, "sql/0003_more.down.sql"},
+	}
+
+	for i, w := range want {
+		m := all[i]
+		if m.Version != w.version || m.Concurrent != w.concurrent || m.DownPath != w.down {
+			t.Errorf("migration %d = %+v, want version=%d concurrent=%v down=%q", i, m, w.version, w.concurrent, w.down)
+		}
+		if len(m.Checksum) != 64 {
+			t.Errorf("migration %d checksum = %q, want hex SHA-256", i, m.Checksum)
+		}
+	}
+}
+
+func TestListUpMigrations_ChecksumFollowsContent(t *testing.T) {
+	fsys := testFS()
+	before := list(t, fsys)
+
+	fsys["sql/0001_init.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id BIGINT);")}
+	after := list(t, fsys)
+
+	if before[0].Checksum == after[0].Checksum {
+		t.Error("checksum did not change with the file")
+	}
+	if before[1].Checksum != after[1].Checksum {
+		t.Error("checksum of an unchanged file changed")
+	}
+}
+
+func TestListUpMigrations_DownWithoutUp(t *testing.T) {
+	fsys := testFS()
+	fsys["sql/0009_orphan.down.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+
+	if _, err := migrations.ListUpMigrations(fsys, "sql"); !errors.Is(err, migrations.ErrDownWithoutUp) {
+		t.Fatalf("err = %v, want ErrDownWithoutUp", err)
+	}
+}
+
+func TestEmbeddedMigrationsHaveDownFiles(t *testing.T) {
+	all, err := migrations.ListUpMigrations(migrations.FS, "sql")
+	if err != nil {
+		t.Fatalf("ListUpMigrations: %v", err)
+	}
+
+	for _, m := range all {
+		if m.DownPath == "" {
+			t.Errorf("%s has no down migration", m.Name)
+		}
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	all := list(t, testFS())
+	rows := applied(all, 2)
+	rows[0].Checksum = ""                                        // recorded before checksums
+	rows[1].Checksum = "0000"                                    // file changed since
+	rows = append(rows, migrations.AppliedMigration{Version: 7}) // file deleted
+
+	got := migrations.Statuses(all, rows)
+
+	want := []migrations.State{
+		migrations.StateUnverified,
+		migrations.StateModified,
+		migrations.StatePending,
+		migrations.StateMissing,
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d statuses, want %d: %+v", len(got), len(want), got)
+	}
+
+	for i, w := range want {
+		if got[i].State != w {
+			t.Errorf("status %d (version %d) = %s, want %s", i, got[i].Version, got[i].State, w)
+		}
+	}
+
+	if !got[0].HasDown || got[1].HasDown {
+		t.Errorf("HasDown = %v, %v; want true, false", got[0].HasDown, got[1].HasDown)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	all := list(t, testFS())
+
+	rows := applied(all, 2)
+	if err := migrations.Verify(all, rows); err != nil {
+		t.Fatalf("Verify(clean) = %v", err)
+	}
+
+	rows[0].Checksum = ""
+	if err := migrations.Verify(all, rows); err != nil {
+		t.Fatalf("Verify(unverified) = %v, want nil", err)
+	}
+
+	rows[1].Checksum = "0000"
+	if err := migrations.Verify(all, rows); !errors.Is(err, migrations.ErrChecksumMismatch) {
+		t.Fatalf("Verify(modified) = %v, want ErrChecksumMismatch", err)
+	}
+
+	rows = append(applied(all, 1), migrations.AppliedMigration{Version: 7})
+	if err := migrations.Verify(all, rows); !errors.Is(err, migrations.ErrMigrationFileMissing) {
+		t.Fatalf("Verify(missing) = %v, want ErrMigrationFileMissing", err)
+	}
+}
+
+func TestPlanUp(t *testing.T) {
+	all := list(t, testFS())
+
+	if got := migrations.PlanUp(all, nil, 0); len(got) != 3 {
+		t.Errorf("PlanUp(all) = %d migrations, want 3", len(got))
+	}
+
+	got := migrations.PlanUp(all, applied(all, 1), 1)
+	if len(got) != 1 || got[0].Version != 2 {
+		t.Errorf("PlanUp(1) = %+v, want version 2", got)
+	}
+
+	if got := migrations.PlanUp(all, applied(all, 3), 0); len(got) != 0 {
+		t.Errorf("PlanUp(up to date) = %+v, want none", got)
+	}
+}
+
+func TestPlanDown(t *testing.T) {
+	all := list(t, testFS())
+
+	got, err := migrations.PlanDown(all, applied(all, 3), 1)
+	if err != nil || len(got) != 1 || got[0].Version != 3 {
+		t.Fatalf("PlanDown(1) = %+v, %v; want version 3", got, err)
+	}
+
+	// 0002 has no down file: rolling back past it fails before anything runs.
+	if _, err := migrations.PlanDown(all, applied(all, 3), 2); !errors.Is(err, migrations.ErrNoDownMigration) {
+		t.Fatalf("PlanDown(2) err = %v, want ErrNoDownMigration", err)
+	}
+
+	// Asking for more steps than applied rolls back everything there is.
+	got, err = migrations.PlanDown(all, applied(all, 1), 5)
+	if err != nil || len(got) != 1 || got[0].Version != 1 {
+		t.Fatalf("PlanDown(5) = %+v, %v; want version 1", got, err)
+	}
+
+	if _, err := migrations.PlanDown(all, nil, 1); !errors.Is(err, migrations.ErrNothingToRollBack) {
+		t.Fatalf("PlanDown(none applied) err = %v, want ErrNothingToRollBack", err)
+	}
+
+	if _, err := migrations.PlanDown(all, applied(all, 1), 0); !errors.Is(err, migrations.ErrInvalidSteps) {
+		t.Fatalf("PlanDown(0) err = %v, want ErrInvalidSteps", err)
+	}
+}
+
+func TestUpToDate(t *testing.T) {
+	all := list(t, testFS())
+
+	if pending, err := migrations.UpToDate(all, applied(all, 3)); err != nil || len(pending) != 0 {
+		t.Fatalf("UpToDate(all applied) = %+v, %v; want none, nil", pending, err)
+	}
+
+	pending, err := migrations.UpToDate(all, applied(all, 1))
+	if !errors.Is(err, migrations.ErrPendingMigrations) {
+		t.Fatalf("UpToDate(behind) err = %v, want ErrPendingMigrations", err)
+	}
+	if len(pending) != 2 || pending[0].Version != 2 {
+		t.Errorf("pending = %+v, want versions 2 and 3", pending)
+	}
+
+	// Drift is reported before pending migrations.
+	rows := applied(all, 1)
+	rows[0].Checksum = "0000"
+	if _, err := migrations.UpToDate(all, rows); !errors.Is(err, migrations.ErrChecksumMismatch) {
+		t.Fatalf("UpToDate(drift) err = %v, want ErrChecksumMismatch", err)
+	}
+}
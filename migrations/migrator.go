@@ -0,0 +1,310 @@
+package migrations
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// execer is satisfied by both *pgxpool.Pool and pgx.Tx.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// NewMigrator creates a Migrator for the migrations in dir of fsys.
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS, dir string) *Migrator {
+	return &Migrator{
+		pool:     pool,
+		fsys:     fsys,
+		dir:      dir,
+		lockName: lockName,
+	}
+}
+
+// SetDryRun makes Up, Down and Redo report what they would do without touching the database.
+func (m *Migrator) SetDryRun(dryRun bool) *Migrator {
+	m.dryRun = dryRun
+
+	return m
+}
+
+// Status reports every known migration: files on disk and rows of schema_migrations.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	const op = "migrations.Migrator.Status"
+
+	var out []MigrationStatus
+
+	err := m.locked(ctx, func(all []Migration, applied []AppliedMigration) error {
+		out = statuses(all, applied)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// Verify fails if an applied migration file has changed or no longer exists.
+func (m *Migrator) Verify(ctx context.Context) error {
+	const op = "migrations.Migrator.Verify"
+
+	err := m.locked(ctx, func(all []Migration, applied []AppliedMigration) error {
+		return verify(all, applied)
+	})
+
+	if err != nil {
+		return validate.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Check fails if the database is not in sync with the migration files: with
+// ErrPendingMigrations, returning the pending ones, or with a Verify error.
+func (m *Migrator) Check(ctx context.Context) ([]Migration, error) {
+	const op = "migrations.Migrator.Check"
+
+	var pending []Migration
+
+	err := m.locked(ctx, func(all []Migration, applied []AppliedMigration) error {
+		var err error
+		pending, err = upToDate(all, applied)
+
+		return err
+	})
+
+	if err != nil {
+		return pending, validate.Wrap(op, err)
+	}
+
+	return nil, nil
+}
+
+// Up applies up to n pending migrations (all if n <= 0) and returns those applied,
+// or in dry-run mode those that would be. Rows recorded before checksums were
+// introduced get the checksum of the current file.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	const op = "migrations.Migrator.Up"
+
+	var done []Migration
+
+	err := m.locked(ctx, func(all []Migration, applied []AppliedMigration) error {
+		if err := verify(all, applied); err != nil {
+			return err
+		}
+
+		plan := planUp(all, applied, n)
+
+		if m.dryRun {
+			done = plan
+			return nil
+		}
+
+		if err := m.adoptChecksums(ctx, all); err != nil {
+			return err
+		}
+
+		for _, mg := range plan {
+			if err := m.apply(ctx, mg); err != nil {
+				return err
+			}
+
+			done = append(done, mg)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return done, validate.Wrap(op, err)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the last n applied migrations, newest first, and returns those
+// rolled back, or in dry-run mode those that would be.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	const op = "migrations.Migrator.Down"
+
+	var done []Migration
+
+	err := m.locked(ctx, func(all []Migration, applied []AppliedMigration) error {
+		if err := verify(all, applied); err != nil {
+			return err
+		}
+
+		plan, err := planDown(all, applied, n)
+		if err != nil {
+			return err
+		}
+
+		if m.dryRun {
+			done = plan
+			return nil
+		}
+
+		for _, mg := range plan {
+			if err := m.rollback(ctx, mg); err != nil {
+				return err
+			}
+
+			done = append(done, mg)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return done, validate.Wrap(op, err)
+	}
+
+	return done, nil
+}
+
+// Redo rolls back the last applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (Migration, error) {
+	const op = "migrations.Migrator.Redo"
+
+	var done Migration
+
+	err := m.locked(ctx, func(all []Migration, applied []AppliedMigration) error {
+		if err := verify(all, applied); err != nil {
+			return err
+		}
+
+		plan, err := planDown(all, applied, 1)
+		if err != nil {
+			return err
+		}
+
+		done = plan[0]
+
+		if m.dryRun {
+			return nil
+		}
+
+		if err := m.rollback(ctx, done); err != nil {
+			return err
+		}
+
+		return m.apply(ctx, done)
+	})
+
+	if err != nil {
+		return done, validate.Wrap(op, err)
+	}
+
+	return done, nil
+}
+
+// locked runs fn under the advisory lock with the migration files and applied rows loaded.
+func (m *Migrator) locked(ctx context.Context, fn func(all []Migration, applied []AppliedMigration) error) error {
+	if m.pool == nil {
+		return ErrInvalidPool
+	}
+
+	if m.fsys == nil {
+		return ErrInvalidFS
+	}
+
+	// Serialization of migrations (global lock).
+	unlock, err := AcquireAdvisoryLock(ctx, m.pool, m.lockName)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = unlock(context.Background()) }()
+
+	if err := EnsureMigrationsTable(ctx, m.pool); err != nil {
+		return err
+	}
+
+	all, err := ListUpMigrations(m.fsys, m.dir)
+	if err != nil {
+		return err
+	}
+
+	applied, err := AppliedMigrations(ctx, m.pool)
+	if err != nil {
+		return err
+	}
+
+	return fn(all, applied)
+}
+
+// adoptChecksums records the checksum of the current file for rows applied without one.
+func (m *Migrator) adoptChecksums(ctx context.Context, all []Migration) error {
+	for _, mg := range all {
+		if _, err := m.pool.Exec(ctx, `
+			UPDATE schema_migrations SET checksum = $2
+			WHERE version = $1 AND checksum IS NULL
+		`, mg.Version, mg.Checksum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply runs an up file and records it in schema_migrations.
+func (m *Migrator) apply(ctx context.Context, mg Migration) error {
+	const record = `INSERT INTO schema_migrations(version, checksum) VALUES($1, $2)`
+
+	return m.run(ctx, mg.Path, mg.Concurrent, record, mg.Version, mg.Checksum)
+}
+
+// rollback runs a down file and removes the migration from schema_migrations.
+func (m *Migrator) rollback(ctx context.Context, mg Migration) error {
+	const record = `DELETE FROM schema_migrations WHERE version = $1`
+
+	return m.run(ctx, mg.DownPath, mg.Concurrent, record, mg.Version)
+}
+
+// run executes a migration file followed by its bookkeeping statement: in one
+// transaction, or one after the other for ... CONCURRENTLY migrations, which
+// cannot run inside a transaction.
+func (m *Migrator) run(ctx context.Context, path string, concurrent bool, record string, args ...any) error {
+	b, err := fs.ReadFile(m.fsys, path)
+	if err != nil {
+		return err
+	}
+
+	sql := strings.TrimSpace(string(b))
+
+	if concurrent {
+		return execBoth(ctx, m.pool, sql, record, args)
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := execBoth(ctx, tx, sql, record, args); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func execBoth(ctx context.Context, db execer, sql, record string, args []any) error {
+	if _, err := db.Exec(ctx, sql); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(ctx, record, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
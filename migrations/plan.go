@@ -0,0 +1,157 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
+)
+
+// statuses merges the migration files with the rows of schema_migrations, ordered by version.
+func statuses(all []Migration, applied []AppliedMigration) []MigrationStatus {
+	files := make(map[int64]Migration, len(all))
+	for _, m := range all {
+		files[m.Version] = m
+	}
+
+	rows := make(map[int64]AppliedMigration, len(applied))
+	for _, a := range applied {
+		rows[a.Version] = a
+	}
+
+	out := make([]MigrationStatus, 0, len(all)+len(applied))
+
+	for _, m := range all {
+		st := MigrationStatus{Version: m.Version, Name: m.Name, State: StatePending, HasDown: m.DownPath != ""}
+
+		if a, ok := rows[m.Version]; ok {
+			st.AppliedAt = a.AppliedAt
+
+			switch {
+			case a.Checksum == "":
+				st.State = StateUnverified
+			case a.Checksum != m.Checksum:
+				st.State = StateModified
+			default:
+				st.State = StateApplied
+			}
+		}
+
+		out = append(out, st)
+	}
+
+	for _, a := range applied {
+		if _, ok := files[a.Version]; !ok {
+			out = append(out, MigrationStatus{Version: a.Version, State: StateMissing, AppliedAt: a.AppliedAt})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+
+	return out
+}
+
+// verify fails on the first applied migration whose file has changed or is gone.
+// Unverified rows pass: there is nothing to compare them with.
+func verify(all []Migration, applied []AppliedMigration) error {
+	const op = "migrations.verify"
+
+	for _, st := range statuses(all, applied) {
+		switch st.State {
+		case StateModified:
+			return validate.Wrap(op, fmt.Errorf("%w: %s", ErrChecksumMismatch, st.Name))
+		case StateMissing:
+			return validate.Wrap(op, fmt.Errorf("%w: version %d", ErrMigrationFileMissing, st.Version))
+		}
+	}
+
+	return nil
+}
+
+// upToDate fails if an applied migration has drifted (see verify) or any migration is
+// pending; the pending ones are returned along with ErrPendingMigrations.
+func upToDate(all []Migration, applied []AppliedMigration) ([]Migration, error) {
+	const op = "migrations.upToDate"
+
+	if err := verify(all, applied); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	pending := planUp(all, applied, 0)
+
+	if len(pending) > 0 {
+		return pending, validate.Wrap(op, fmt.Errorf("%w: %d, next %s", ErrPendingMigrations, len(pending), pending[0].Name))
+	}
+
+	return nil, nil
+}
+
+// planUp returns the pending migrations in the order they are applied; n <= 0 means all of them.
+func planUp(all []Migration, applied []AppliedMigration, n int) []Migration {
+	done := make(map[int64]struct{}, len(applied))
+	for _, a := range applied {
+		done[a.Version] = struct{}{}
+	}
+
+	var out []Migration
+
+	for _, m := range all {
+		if n > 0 && len(out) == n {
+			break
+		}
+
+		if _, ok := done[m.Version]; !ok {
+			out = append(out, m)
+		}
+	}
+
+	return out
+}
+
+// planDown returns the last n applied migrations, newest first. Every one of them
+// must have a down file, so that a rollback never stops halfway for lack of one.
+func planDown(all []Migration, applied []AppliedMigration, n int) ([]Migration, error) {
+	const op = "migrations.planDown"
+
+	if n <= 0 {
+		return nil, validate.Wrap(op, ErrInvalidSteps)
+	}
+
+	if len(applied) == 0 {
+		return nil, validate.Wrap(op, ErrNothingToRollBack)
+	}
+
+	files := make(map[int64]Migration, len(all))
+	for _, m := range all {
+		files[m.Version] = m
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for _, a := range applied {
+		versions = append(versions, a.Version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	if n > len(versions) {
+		n = len(versions)
+	}
+
+	out := make([]Migration, 0, n)
+
+	for _, v := range versions[:n] {
+		m, ok := files[v]
+
+		if !ok {
+			return nil, validate.Wrap(op, fmt.Errorf("%w: version %d", ErrMigrationFileMissing, v))
+		}
+
+		if m.DownPath == "" {
+			return nil, validate.Wrap(op, fmt.Errorf("%w: %s", ErrNoDownMigration, m.Name))
+		}
+
+		out = append(out, m)
+	}
+
+	return out, nil
+}
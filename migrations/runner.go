@@ -2,6 +2,9 @@ package migrations
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io/fs"
 	"path/filepath"
 	"regexp"
@@ -13,7 +16,10 @@ import (
 	"github.com/tuor4eg/ip_accounting_bot/internal/validate"
 )
 
-var reMigration = regexp.MustCompile(`^(?P<ver>\d{4,})_(?P<name>[a-z0-9_]+?)(?P<conc>_concurrently)?\.up\.sql$`)
+var reMigration = regexp.MustCompile(`^(?P<ver>\d{4,})_(?P<name>[a-z0-9_]+?)(?P<conc>_concurrently)?\.(?P<dir>up|down)\.sql$`)
+
+// lockName is the advisory lock held while migrations run.
+const lockName = "ip_accounting_bot:migrations"
 
 func EnsureMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
 	const op = "migrations.EnsureMigrationsTable"
@@ -39,6 +45,12 @@ func EnsureMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
 		return validate.Wrap(op, err)
 	}
 
+	// Tables created before checksums were introduced get the column; their rows stay NULL
+	// until Migrator.Up records the checksum of the file on disk.
+	if _, err := tx.Exec(ctx, `ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT`); err != nil {
+		return validate.Wrap(op, err)
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return validate.Wrap(op, err)
 	}
@@ -83,6 +95,46 @@ func AppliedVersions(ctx context.Context, pool *pgxpool.Pool) ([]int64, error) {
 	return vs, nil
 }
 
+// AppliedMigrations returns the rows of schema_migrations, oldest version first.
+func AppliedMigrations(ctx context.Context, pool *pgxpool.Pool) ([]AppliedMigration, error) {
+	const op = "migrations.AppliedMigrations"
+
+	if pool == nil {
+		return nil, validate.Wrap(op, ErrInvalidPool)
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT version, COALESCE(checksum, ''), applied_at FROM schema_migrations
+		ORDER BY version ASC
+	`)
+
+	if err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	defer rows.Close()
+
+	var out []AppliedMigration
+
+	for rows.Next() {
+		var a AppliedMigration
+
+		if err := rows.Scan(&a.Version, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
+		out = append(out, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, validate.Wrap(op, err)
+	}
+
+	return out, nil
+}
+
+// ListUpMigrations returns the up migrations in dir ordered by version, each with its
+// checksum and, if present, the matching .down.sql file.
 func ListUpMigrations(fsys fs.FS, dir string) ([]Migration, error) {
 	const op = "migrations.ListUpMigrations"
 
@@ -98,6 +150,7 @@ func ListUpMigrations(fsys fs.FS, dir string) ([]Migration, error) {
 
 	var out []Migration
 	seen := make(map[int64]string)
+	downs := make(map[int64]string)
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -105,27 +158,52 @@ func ListUpMigrations(fsys fs.FS, dir string) ([]Migration, error) {
 		}
 
 		name := entry.Name()
+		path := filepath.ToSlash(filepath.Join(dir, name))
 
-		v, conc, ok := parseMigrationName(name)
+		v, conc, down, ok := parseMigrationName(name)
 
 		if !ok {
 			continue
 		}
 
+		if down {
+			if _, dup := downs[v]; dup {
+				return nil, validate.Wrap(op, fmt.Errorf("%w: %s", ErrDuplicateMigrationVersion, name))
+			}
+			downs[v] = path
+			continue
+		}
+
 		if _, dup := seen[v]; dup {
-			return nil, validate.Wrap(op, ErrDuplicateMigrationVersion)
+			return nil, validate.Wrap(op, fmt.Errorf("%w: %s", ErrDuplicateMigrationVersion, name))
 		}
 
 		seen[v] = name
 
+		sum, err := fileChecksum(fsys, path)
+		if err != nil {
+			return nil, validate.Wrap(op, err)
+		}
+
 		out = append(out, Migration{
 			Version:    v,
 			Concurrent: conc,
-			Path:       filepath.ToSlash(filepath.Join(dir, name)),
+			Path:       path,
 			Name:       name,
+			Checksum:   sum,
 		})
 	}
 
+	for v, path := range downs {
+		if _, ok := seen[v]; !ok {
+			return nil, validate.Wrap(op, fmt.Errorf("%w: %s", ErrDownWithoutUp, path))
+		}
+	}
+
+	for i := range out {
+		out[i].DownPath = downs[out[i].Version]
+	}
+
 	sort.Slice(out, func(i, j int) bool {
 		return out[i].Version < out[j].Version
 	})
@@ -133,111 +211,53 @@ func ListUpMigrations(fsys fs.FS, dir string) ([]Migration, error) {
 	return out, nil
 }
 
+// ApplyUp applies all pending migrations under the advisory lock and returns how many
+// were applied. It fails without applying anything if an applied file has changed.
 func ApplyUp(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, dir string) (int, error) {
 	const op = "migrations.ApplyUp"
 
-	if pool == nil {
-		return 0, validate.Wrap(op, ErrInvalidPool)
-	}
-	if fsys == nil {
-		return 0, validate.Wrap(op, ErrInvalidFS)
-	}
-
-	// 1) Serialization of migrations (global lock).
-	unlock, err := AcquireAdvisoryLock(ctx, pool, "ip_accounting_bot:migrations")
+	applied, err := NewMigrator(pool, fsys, dir).Up(ctx, 0)
 	if err != nil {
-		return 0, validate.Wrap(op, err)
+		return len(applied), validate.Wrap(op, err)
 	}
-	defer func() { _ = unlock(context.Background()) }()
 
-	// 2) Ensure that there is a service table.
-	if err := EnsureMigrationsTable(ctx, pool); err != nil {
-		return 0, validate.Wrap(op, err)
-	}
+	return len(applied), nil
+}
 
-	// 3) Collect the list of available up-migrations and already applied versions.
-	all, err := ListUpMigrations(fsys, dir)
+// fileChecksum returns the hex SHA-256 of a file.
+func fileChecksum(fsys fs.FS, path string) (string, error) {
+	b, err := fs.ReadFile(fsys, path)
 	if err != nil {
-		return 0, validate.Wrap(op, err)
+		return "", err
 	}
-	applied, err := AppliedVersions(ctx, pool)
-	if err != nil {
-		return 0, validate.Wrap(op, err)
-	}
-	appliedSet := make(map[int64]struct{}, len(applied))
-	for _, v := range applied {
-		appliedSet[v] = struct{}{}
-	}
-
-	// 4) Apply the missing ones.
-	appliedCount := 0
-	for _, m := range all {
-		if _, ok := appliedSet[m.Version]; ok {
-			continue // already applied
-		}
 
-		sqlBytes, rerr := fs.ReadFile(fsys, m.Path)
-		if rerr != nil {
-			return appliedCount, validate.Wrap(op, rerr)
-		}
-		sql := strings.TrimSpace(string(sqlBytes))
-
-		if m.Concurrent {
-			// 4a) Outside the transaction (for ... CONCURRENTLY).
-			if _, err := pool.Exec(ctx, sql); err != nil {
-				return appliedCount, validate.Wrap(op, err)
-			}
-			if _, err := pool.Exec(ctx, `INSERT INTO schema_migrations(version) VALUES($1) ON CONFLICT DO NOTHING`, m.Version); err != nil {
-				return appliedCount, validate.Wrap(op, err)
-			}
-		} else {
-			// 4b) In the transaction.
-			tx, err := pool.Begin(ctx)
-			if err != nil {
-				return appliedCount, validate.Wrap(op, err)
-			}
-			// just in case, if something goes wrong
-			defer func() { _ = tx.Rollback(ctx) }()
-
-			if _, err := tx.Exec(ctx, sql); err != nil {
-				_ = tx.Rollback(ctx)
-				return appliedCount, validate.Wrap(op, err)
-			}
-			if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations(version) VALUES($1)`, m.Version); err != nil {
-				_ = tx.Rollback(ctx)
-				return appliedCount, validate.Wrap(op, err)
-			}
-			if err := tx.Commit(ctx); err != nil {
-				return appliedCount, validate.Wrap(op, err)
-			}
-		}
-
-		appliedCount++
-	}
+	sum := sha256.Sum256(b)
 
-	return appliedCount, nil
+	return hex.EncodeToString(sum[:]), nil
 }
 
-// parseMigrationName extracts version and "concurrent" flag from a migration file name.
+// parseMigrationName extracts version, "concurrent" and "down" flags from a migration file name.
 // Valid examples:
 //
 //	0001_init.up.sql                        -> version=1,  concurrent=false
+//	0001_init.down.sql                      -> version=1,  concurrent=false, down=true
 //	0002_big_index_concurrently.up.sql      -> version=2,  concurrent=true
-func parseMigrationName(name string) (version int64, concurrent bool, ok bool) {
+func parseMigrationName(name string) (version int64, concurrent bool, down bool, ok bool) {
 	base := filepath.Base(name)
 	lower := strings.ToLower(base)
 
 	m := reMigration.FindStringSubmatch(lower)
 	if m == nil {
-		return 0, false, false
+		return 0, false, false, false
 	}
 
 	verStr := m[reMigration.SubexpIndex("ver")]
 	conc := m[reMigration.SubexpIndex("conc")] != ""
+	down = m[reMigration.SubexpIndex("dir")] == "down"
 
 	v, err := strconv.ParseInt(verStr, 10, 64)
 	if err != nil || v <= 0 {
-		return 0, false, false
+		return 0, false, false, false
 	}
-	return v, conc, true
+	return v, conc, down, true
 }
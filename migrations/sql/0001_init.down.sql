@@ -0,0 +1,13 @@
+-- 0001_init.sql (down)
+-- Drops the init schema. Tables are dropped in reverse dependency order.
+
+DROP TABLE IF EXISTS pii.telegram;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS incomes;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS counterparties;
+DROP TABLE IF EXISTS user_profile;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS users;
+
+DROP SCHEMA IF EXISTS pii;
@@ -0,0 +1,3 @@
+-- 0002_api_tokens.sql (down)
+
+DROP TABLE IF EXISTS api_tokens;
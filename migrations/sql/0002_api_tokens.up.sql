@@ -0,0 +1,17 @@
+-- 0002_api_tokens.sql
+-- Per-user API tokens for the REST runner.
+-- Only SHA-256(token) is stored; the owner is also bound in user_identities
+-- as transport 'api' with external_id = api_tokens.id.
+
+CREATE TABLE api_tokens (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash    BYTEA       NOT NULL UNIQUE CHECK (octet_length(token_hash) = 32),
+    name          TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at  TIMESTAMPTZ,
+    revoked_at    TIMESTAMPTZ
+);
+CREATE INDEX api_tokens_user_active_idx
+  ON api_tokens (user_id)
+  WHERE revoked_at IS NULL;
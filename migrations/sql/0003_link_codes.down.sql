@@ -0,0 +1,3 @@
+-- 0003_link_codes.sql (down)
+
+DROP TABLE IF EXISTS link_codes;
@@ -0,0 +1,14 @@
+-- 0003_link_codes.sql
+-- One-time codes for /link: bind another transport identity to an existing user.
+-- Only SHA-256(code) is stored; codes expire after a few minutes and are single-use.
+
+CREATE TABLE link_codes (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash   BYTEA       NOT NULL UNIQUE CHECK (octet_length(code_hash) = 32),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    CHECK (expires_at > created_at)
+);
+CREATE INDEX link_codes_user_idx ON link_codes (user_id);
@@ -0,0 +1,4 @@
+-- 0004_processed_updates.sql (down)
+
+DROP TABLE IF EXISTS processed_updates;
+DROP TABLE IF EXISTS poll_offsets;
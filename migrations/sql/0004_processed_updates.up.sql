@@ -0,0 +1,19 @@
+-- 0004_processed_updates.sql
+-- Persisted polling offset and processed update keys, so a redelivered update
+-- (e.g. after a crash before the offset was confirmed) is applied only once.
+
+CREATE TABLE poll_offsets (
+    transport   TEXT        PRIMARY KEY,
+    "offset"    BIGINT      NOT NULL CHECK ("offset" >= 0),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- update_key is transport-specific, e.g. 'update:123456' for Telegram update_id.
+-- Ledger mutations insert the key in their own transaction.
+CREATE TABLE processed_updates (
+    transport     TEXT        NOT NULL,
+    update_key    TEXT        NOT NULL,
+    processed_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (transport, update_key)
+);
+CREATE INDEX processed_updates_processed_at_idx ON processed_updates (processed_at);
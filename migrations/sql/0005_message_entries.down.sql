@@ -0,0 +1,3 @@
+-- 0005_message_entries.sql (down)
+
+DROP TABLE IF EXISTS message_entries;
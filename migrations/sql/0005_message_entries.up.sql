@@ -0,0 +1,16 @@
+-- 0005_message_entries.sql
+-- Links a transport message to the ledger entry it created, so that editing the
+-- message edits the entry. The message key (e.g. chat and message IDs) is stored
+-- only as HMAC-SHA256, like user_identities.external_hash.
+
+CREATE TABLE message_entries (
+    transport    TEXT        NOT NULL,
+    message_hash BYTEA       NOT NULL CHECK (octet_length(message_hash) = 32),
+    hmac_kid     SMALLINT    NOT NULL,
+    user_id      BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind         TEXT        NOT NULL CHECK (kind IN ('income', 'contrib', 'advance')),
+    entry_id     BIGINT      NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (transport, message_hash, hmac_kid)
+);
+CREATE INDEX message_entries_user_idx ON message_entries (user_id);
@@ -0,0 +1,3 @@
+-- 0006_user_lang.sql (down)
+
+ALTER TABLE users DROP COLUMN IF EXISTS lang;
@@ -0,0 +1,5 @@
+-- 0006_user_lang.sql
+-- Interface language chosen with /lang (ISO 639-1). NULL means "follow the
+-- transport", e.g. Telegram's language_code.
+
+ALTER TABLE users ADD COLUMN lang TEXT NULL CHECK (lang ~ '^[a-z]{2}$');
@@ -0,0 +1,4 @@
+-- 0007_recurring.sql (down)
+
+DROP TABLE IF EXISTS recurring_runs;
+DROP TABLE IF EXISTS recurring_rules;
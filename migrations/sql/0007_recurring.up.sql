@@ -0,0 +1,35 @@
+-- 0007_recurring.sql
+-- Recurring incomes (/recurring): a rule creates the same income on a schedule.
+-- Every occurrence is a row of recurring_runs keyed by (rule_id, due_on), so a
+-- date runs once even when a catch-up after downtime races a second instance.
+
+CREATE TABLE recurring_rules (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount      BIGINT      NOT NULL CHECK (amount > 0),  -- stored in kopecks
+    note        TEXT,
+    period      TEXT        NOT NULL CHECK (period IN ('monthly', 'weekly')),
+    day         SMALLINT    NOT NULL CHECK (day BETWEEN 1 AND 31),
+    confirm     BOOLEAN     NOT NULL DEFAULT false,       -- ask before creating the income
+    next_due    DATE        NOT NULL,
+    paused_at   TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (period = 'monthly' OR day <= 7)
+);
+CREATE INDEX recurring_rules_user_idx ON recurring_rules (user_id);
+CREATE INDEX recurring_rules_due_active_idx
+  ON recurring_rules (next_due)
+  WHERE paused_at IS NULL;
+
+CREATE TABLE recurring_runs (
+    rule_id     BIGINT      NOT NULL REFERENCES recurring_rules(id) ON DELETE CASCADE,
+    due_on      DATE        NOT NULL,
+    status      TEXT        NOT NULL CHECK (status IN ('created', 'pending', 'skipped')),
+    income_id   BIGINT      REFERENCES incomes(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    resolved_at TIMESTAMPTZ,
+    PRIMARY KEY (rule_id, due_on)
+);
+CREATE INDEX recurring_runs_pending_idx
+  ON recurring_runs (rule_id)
+  WHERE status = 'pending';
@@ -0,0 +1,6 @@
+-- 0008_note_search.sql (down)
+-- pg_trgm stays installed: other objects of the database may depend on it.
+
+DROP INDEX IF EXISTS payments_note_trgm_idx;
+DROP INDEX IF EXISTS incomes_note_trgm_idx;
+DROP FUNCTION IF EXISTS note_fold(TEXT);
@@ -0,0 +1,17 @@
+-- 0008_note_search.sql
+-- /find: case-insensitive substring search over the notes of incomes and payments.
+-- note_fold() lower-cases a note and reads ё as е (the same as domain.FoldNote);
+-- queries match note_fold(note) LIKE '%...%', which the trigram indexes serve.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE FUNCTION note_fold(note TEXT) RETURNS TEXT
+  LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+  AS $$ SELECT translate(lower(note), 'ё', 'е') $$;
+
+CREATE INDEX incomes_note_trgm_idx
+  ON incomes USING gin (note_fold(note) gin_trgm_ops)
+  WHERE note IS NOT NULL;
+CREATE INDEX payments_note_trgm_idx
+  ON payments USING gin (note_fold(note) gin_trgm_ops)
+  WHERE note IS NOT NULL;
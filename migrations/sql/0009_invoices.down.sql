@@ -0,0 +1,10 @@
+-- 0009_invoices.sql (down)
+
+DROP TABLE IF EXISTS invoice_payments;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS user_requisites;
+
+ALTER TABLE counterparties
+    DROP COLUMN IF EXISTS address,
+    DROP COLUMN IF EXISTS kpp,
+    DROP COLUMN IF EXISTS inn;
@@ -0,0 +1,49 @@
+-- 0009_invoices.sql
+-- Invoices (/invoice): bills issued to clients and paid by incomes linked to them.
+-- The clients are the counterparties of 0001, with the details an invoice prints.
+-- What an invoice has been paid is not stored but summed over its active incomes,
+-- so voiding such an income with /undo makes the invoice unpaid again.
+
+ALTER TABLE counterparties
+    ADD COLUMN inn     TEXT CHECK (inn ~ '^([0-9]{10}|[0-9]{12})$'),
+    ADD COLUMN kpp     TEXT CHECK (kpp ~ '^[0-9]{4}[0-9A-Z]{2}[0-9]{3}$'),
+    ADD COLUMN address TEXT;
+
+-- The user's own details printed on invoices; '' until set with /requisites.
+CREATE TABLE user_requisites (
+    user_id      BIGINT      PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL DEFAULT '',
+    inn          TEXT        NOT NULL DEFAULT '',
+    ogrnip       TEXT        NOT NULL DEFAULT '',
+    address      TEXT        NOT NULL DEFAULT '',
+    bank         TEXT        NOT NULL DEFAULT '',
+    bik          TEXT        NOT NULL DEFAULT '',
+    account      TEXT        NOT NULL DEFAULT '',
+    corr_account TEXT        NOT NULL DEFAULT '',
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE invoices (
+    id              BIGSERIAL   PRIMARY KEY,
+    user_id         BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year            INT         NOT NULL,                  -- numbers start over every year
+    number          INT         NOT NULL CHECK (number > 0),
+    counterparty_id BIGINT      NOT NULL REFERENCES counterparties(id),
+    issued_on       DATE        NOT NULL,
+    due_on          DATE        NOT NULL,
+    amount          BIGINT      NOT NULL CHECK (amount > 0), -- stored in kopecks
+    description     TEXT,
+    reminded_on     DATE,                                  -- last overdue reminder
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, year, number),
+    CHECK (year = extract(year FROM issued_on)),
+    CHECK (due_on >= issued_on)
+);
+CREATE INDEX invoices_due_idx ON invoices (due_on);
+
+-- An income pays at most one invoice.
+CREATE TABLE invoice_payments (
+    income_id  BIGINT PRIMARY KEY REFERENCES incomes(id) ON DELETE CASCADE,
+    invoice_id BIGINT NOT NULL REFERENCES invoices(id) ON DELETE CASCADE
+);
+CREATE INDEX invoice_payments_invoice_idx ON invoice_payments (invoice_id);
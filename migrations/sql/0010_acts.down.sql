@@ -0,0 +1,3 @@
+-- 0010_acts.sql (down)
+
+DROP TABLE IF EXISTS acts;
@@ -0,0 +1,25 @@
+-- 0010_acts.sql
+-- Acts of completed work (/act) issued for an invoice or for an income, numbered
+-- within the year like invoices. The rows are the registry of issued acts; the
+-- files are rendered again from them when an act is downloaded.
+
+CREATE TABLE acts (
+    id              BIGSERIAL   PRIMARY KEY,
+    user_id         BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year            INT         NOT NULL,                  -- numbers start over every year
+    number          INT         NOT NULL CHECK (number > 0),
+    counterparty_id BIGINT      NOT NULL REFERENCES counterparties(id),
+    issued_on       DATE        NOT NULL,
+    amount          BIGINT      NOT NULL CHECK (amount > 0), -- stored in kopecks
+    description     TEXT,
+    invoice_id      BIGINT      REFERENCES invoices(id),
+    income_id       BIGINT      REFERENCES incomes(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, year, number),
+    CHECK (year = extract(year FROM issued_on)),
+    CHECK ((invoice_id IS NULL) <> (income_id IS NULL))
+);
+
+-- At most one act per invoice and per income.
+CREATE UNIQUE INDEX acts_invoice_uq ON acts (invoice_id) WHERE invoice_id IS NOT NULL;
+CREATE UNIQUE INDEX acts_income_uq ON acts (income_id) WHERE income_id IS NOT NULL;
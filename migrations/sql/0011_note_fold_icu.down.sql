@@ -0,0 +1,8 @@
+-- 0011_note_fold_icu.sql (down)
+
+CREATE OR REPLACE FUNCTION note_fold(note TEXT) RETURNS TEXT
+  LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+  AS $$ SELECT translate(lower(note), 'ё', 'е') $$;
+
+REINDEX INDEX incomes_note_trgm_idx;
+REINDEX INDEX payments_note_trgm_idx;
@@ -0,0 +1,13 @@
+-- 0011_note_fold_icu.sql
+-- note_fold() of 0008 lower-cased with the collation of the database: on a C or POSIX
+-- LC_CTYPE lower() leaves Cyrillic as is, so /find disagreed with domain.FoldNote.
+-- The ICU root collation lower-cases every script whatever the locale of the database;
+-- it needs a PostgreSQL built with ICU (the official images and packages are).
+
+CREATE OR REPLACE FUNCTION note_fold(note TEXT) RETURNS TEXT
+  LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+  AS $$ SELECT translate(lower(note COLLATE "und-x-icu"), 'ё', 'е') $$;
+
+-- The indexes hold values of the old function.
+REINDEX INDEX incomes_note_trgm_idx;
+REINDEX INDEX payments_note_trgm_idx;
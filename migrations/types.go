@@ -1,9 +1,56 @@
 package migrations
 
+import (
+	"io/fs"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
 // Migration represents a database migration
 type Migration struct {
 	Version    int64
 	Concurrent bool
 	Path       string
 	Name       string
+	// DownPath is the matching .down.sql file; empty if the migration cannot be rolled back.
+	DownPath string
+	// Checksum is the hex SHA-256 of the up file, recorded in schema_migrations when applied.
+	Checksum string
+}
+
+// AppliedMigration is a row of schema_migrations.
+type AppliedMigration struct {
+	Version   int64
+	Checksum  string // empty for rows recorded before checksums were introduced
+	AppliedAt time.Time
+}
+
+// State is the status of a migration as reported by Migrator.Status.
+type State string
+
+const (
+	StatePending    State = "pending"
+	StateApplied    State = "applied"
+	StateModified   State = "modified"   // applied, but the file has changed since
+	StateUnverified State = "unverified" // applied before checksums were recorded
+	StateMissing    State = "missing"    // applied, but there is no file with this version
+)
+
+// MigrationStatus is one line of Migrator.Status.
+type MigrationStatus struct {
+	Version   int64
+	Name      string // file name; empty when State is StateMissing
+	State     State
+	AppliedAt time.Time // zero when pending
+	HasDown   bool
+}
+
+// Migrator applies and rolls back migrations from an fs under the advisory lock.
+type Migrator struct {
+	pool     *pgxpool.Pool
+	fsys     fs.FS
+	dir      string
+	dryRun   bool
+	lockName string
 }
@@ -0,0 +1,28 @@
+package period
+
+import "time"
+
+// MonthBounds returns the inclusive [start, end] date range for the calendar month of t,
+// at 00:00:00 UTC like QuarterBounds.
+func MonthBounds(t time.Time) (start time.Time, end time.Time) {
+	t = t.UTC()
+
+	start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end = start.AddDate(0, 1, -1)
+
+	return start, end
+}
+
+// ISOWeekBounds returns the inclusive [start, end] date range, Monday to Sunday, for the
+// ISO 8601 week of t. The week may span two years: t.ISOWeek() gives its number.
+func ISOWeekBounds(t time.Time) (start time.Time, end time.Time) {
+	d := Day(t)
+
+	// Weekday counts from Sunday (0); ISO weeks start on Monday.
+	back := (int(d.Weekday()) + 6) % 7
+
+	start = d.AddDate(0, 0, -back)
+	end = start.AddDate(0, 0, 6)
+
+	return start, end
+}
@@ -0,0 +1,112 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+func TestMonthBounds(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input         time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+		desc          string
+	}{
+		{
+			time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC),
+			time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			"leap February",
+		},
+		{
+			time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+			"February",
+		},
+		{
+			time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC),
+			time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			"December",
+		},
+		{
+			// 2025-05-01 01:00 in Moscow is still April in UTC.
+			time.Date(2025, 5, 1, 1, 0, 0, 0, time.FixedZone("MSK", 3*60*60)),
+			time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+			"normalized to UTC",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			start, end := period.MonthBounds(tc.input)
+			if !start.Equal(tc.expectedStart) || !end.Equal(tc.expectedEnd) {
+				t.Errorf("MonthBounds(%v) = %v..%v, want %v..%v", tc.input, start, end, tc.expectedStart, tc.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestISOWeekBounds(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input         time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+		week          int
+		desc          string
+	}{
+		{
+			time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC),
+			11,
+			"Monday",
+		},
+		{
+			time.Date(2025, 3, 16, 23, 59, 59, 0, time.UTC),
+			time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC),
+			11,
+			"Sunday",
+		},
+		{
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+			1,
+			"week 1 starts in the previous year",
+		},
+		{
+			time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+			53,
+			"January days in week 53",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			start, end := period.ISOWeekBounds(tc.input)
+			if !start.Equal(tc.expectedStart) || !end.Equal(tc.expectedEnd) {
+				t.Errorf("ISOWeekBounds(%v) = %v..%v, want %v..%v", tc.input, start, end, tc.expectedStart, tc.expectedEnd)
+			}
+
+			if _, week := start.ISOWeek(); week != tc.week {
+				t.Errorf("week of %v = %d, want %d", start, week, tc.week)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package period
+
+import "time"
+
+// Day returns midnight UTC of the calendar day of t.
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MonthlyOnOrAfter returns the first date on or after the day of from that falls on the
+// given day of month (1..31). In shorter months the day is the last day of the month,
+// so "monthly on 31" is Feb 28 (or 29), Apr 30 and so on.
+func MonthlyOnOrAfter(from time.Time, day int) time.Time {
+	from = Day(from)
+	y, m, _ := from.Date()
+
+	for {
+		d := time.Date(y, m, min(day, daysIn(y, m)), 0, 0, 0, 0, time.UTC)
+		if !d.Before(from) {
+			return d
+		}
+
+		m++
+		if m > time.December {
+			y, m = y+1, time.January
+		}
+	}
+}
+
+// WeeklyOnOrAfter returns the first date on or after the day of from that falls on weekday.
+func WeeklyOnOrAfter(from time.Time, weekday time.Weekday) time.Time {
+	from = Day(from)
+	shift := (int(weekday) - int(from.Weekday()) + 7) % 7
+
+	return from.AddDate(0, 0, shift)
+}
+
+// daysIn returns the number of days in month m of year y.
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
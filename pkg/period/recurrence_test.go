@@ -0,0 +1,64 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tuor4eg/ip_accounting_bot/pkg/period"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMonthlyOnOrAfter(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		from time.Time
+		day  int
+		want time.Time
+		desc string
+	}{
+		{date(2025, 3, 1), 5, date(2025, 3, 5), "later this month"},
+		{date(2025, 3, 5), 5, date(2025, 3, 5), "on the day itself"},
+		{time.Date(2025, 3, 5, 23, 59, 0, 0, time.UTC), 5, date(2025, 3, 5), "time of day is dropped"},
+		{date(2025, 3, 6), 5, date(2025, 4, 5), "next month"},
+		{date(2025, 12, 20), 5, date(2026, 1, 5), "next year"},
+		{date(2025, 2, 1), 31, date(2025, 2, 28), "clamped to February"},
+		{date(2024, 2, 1), 30, date(2024, 2, 29), "clamped to a leap February"},
+		{date(2025, 3, 1), 31, date(2025, 3, 31), "long month keeps the day"},
+		{date(2025, 4, 1), 31, date(2025, 4, 30), "clamped to April"},
+	}
+
+	for _, c := range cases {
+		if got := period.MonthlyOnOrAfter(c.from, c.day); !got.Equal(c.want) {
+			t.Errorf("%s: MonthlyOnOrAfter(%s, %d) = %s, want %s", c.desc, c.from.Format(time.DateOnly), c.day,
+				got.Format(time.DateOnly), c.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestWeeklyOnOrAfter(t *testing.T) {
+	t.Parallel()
+
+	// 2025-03-05 is a Wednesday.
+	cases := []struct {
+		from    time.Time
+		weekday time.Weekday
+		want    time.Time
+	}{
+		{date(2025, 3, 5), time.Wednesday, date(2025, 3, 5)},
+		{date(2025, 3, 5), time.Friday, date(2025, 3, 7)},
+		{date(2025, 3, 5), time.Monday, date(2025, 3, 10)},
+		{date(2025, 3, 5), time.Sunday, date(2025, 3, 9)},
+		{date(2025, 12, 30), time.Thursday, date(2026, 1, 1)},
+	}
+
+	for _, c := range cases {
+		if got := period.WeeklyOnOrAfter(c.from, c.weekday); !got.Equal(c.want) {
+			t.Errorf("WeeklyOnOrAfter(%s, %s) = %s, want %s", c.from.Format(time.DateOnly), c.weekday,
+				got.Format(time.DateOnly), c.want.Format(time.DateOnly))
+		}
+	}
+}